@@ -4,7 +4,7 @@
 // This package implements the CLI-Runtime pattern, providing helpers and utilities
 // for CLI operations without creating cobra commands directly. It offers:
 //
-//   - Operation handlers (get, create, apply, delete) that work with any k1s client
+//   - Operation handlers (get, create, apply, delete, describe) that work with any k1s client
 //   - Output formatters (table, JSON, YAML, name) for consistent kubectl-style output
 //   - Resource builders for fluent resource selection and filtering
 //   - Reusable flag sets for common CLI patterns
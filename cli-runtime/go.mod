@@ -4,9 +4,13 @@ go 1.25.1
 
 replace github.com/dtomasi/k1s/core => ../core
 
+replace github.com/dtomasi/k1s/storage/memory => ../storage/memory
+
 require (
 	github.com/dtomasi/k1s/core v0.0.0-00010101000000-000000000000
+	github.com/dtomasi/k1s/storage/memory v0.0.0-00010101000000-000000000000
 	github.com/spf13/pflag v1.0.10
+	k8s.io/api v0.34.0
 	k8s.io/apimachinery v0.34.0
 	sigs.k8s.io/yaml v1.6.0
 )
@@ -52,7 +56,7 @@ require (
 	google.golang.org/protobuf v1.36.8 // indirect
 	gopkg.in/inf.v0 v0.9.1 // indirect
 	gopkg.in/yaml.v3 v3.0.1 // indirect
-	k8s.io/api v0.34.0 // indirect
+	k8s.io/apiextensions-apiserver v0.34.0 // indirect
 	k8s.io/apiserver v0.34.0 // indirect
 	k8s.io/component-base v0.34.0 // indirect
 	k8s.io/klog/v2 v2.130.1 // indirect
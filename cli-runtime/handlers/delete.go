@@ -4,6 +4,8 @@ import (
 	"context"
 	"fmt"
 
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 
 	"github.com/dtomasi/k1s/core/client"
@@ -64,6 +66,12 @@ func (h *deleteHandler) Handle(ctx context.Context, req *DeleteRequest) (*Delete
 func (h *deleteHandler) createObjectForGVK(gvk schema.GroupVersionKind) (client.Object, error) {
 	scheme := h.client.Scheme()
 	obj, err := scheme.New(gvk)
+	if runtime.IsNotRegisteredError(err) {
+		// Types that are not compiled in are handled as unstructured objects
+		u := &unstructured.Unstructured{}
+		u.SetGroupVersionKind(gvk)
+		return u, nil
+	}
 	if err != nil {
 		return nil, fmt.Errorf("failed to create new object for GVK %s: %w", gvk, err)
 	}
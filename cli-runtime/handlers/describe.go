@@ -0,0 +1,363 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/duration"
+
+	"github.com/dtomasi/k1s/core/client"
+	typesv1 "github.com/dtomasi/k1s/core/types/v1"
+)
+
+// describeHandler implements DescribeHandler.
+type describeHandler struct {
+	getHandler
+	describers *DescriberRegistry
+}
+
+// Handle executes a describe operation.
+func (h *describeHandler) Handle(ctx context.Context, req *DescribeRequest) (*DescribeResponse, error) {
+	if req == nil {
+		return nil, fmt.Errorf("describe request cannot be nil")
+	}
+
+	obj, err := h.createObjectForGVK(req.ResourceType)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create object for GVK %s: %w", req.ResourceType, err)
+	}
+	if err := h.client.Get(ctx, req.Key, obj); err != nil {
+		return nil, fmt.Errorf("failed to get %s %s: %w", req.ResourceType.Kind, req.Key, err)
+	}
+
+	resp := &DescribeResponse{Object: obj}
+	if !req.SkipChildren {
+		resp.Children, err = h.children(ctx, req.ResourceType, obj, h.ownableTypes())
+		if err != nil {
+			return nil, fmt.Errorf("failed to find the children of %s %s: %w", req.ResourceType.Kind, req.Key, err)
+		}
+	}
+	if !req.SkipEvents {
+		resp.Events, err = h.events(ctx, req.ResourceType, obj)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list the events of %s %s: %w", req.ResourceType.Kind, req.Key, err)
+		}
+	}
+
+	var buf bytes.Buffer
+	tw := tabwriter.NewWriter(&buf, 0, 8, 2, ' ', 0)
+	w := &prefixWriter{out: tw}
+	describeMetadata(w, req.ResourceType, obj)
+	if err := h.describers.DescriberFor(req.ResourceType).Describe(ctx, h.client, obj, w); err != nil {
+		return nil, fmt.Errorf("failed to describe %s %s: %w", req.ResourceType.Kind, req.Key, err)
+	}
+	if !req.SkipChildren {
+		describeChildren(w, resp.Children)
+	}
+	if !req.SkipEvents {
+		describeEvents(w, resp.Events)
+	}
+	if err := tw.Flush(); err != nil {
+		return nil, fmt.Errorf("failed to write description: %w", err)
+	}
+	resp.Description = buf.String()
+
+	return resp, nil
+}
+
+// ownableTypes returns the types of the scheme that have a list type and
+// may thus be searched for children
+func (h *describeHandler) ownableTypes() []schema.GroupVersionKind {
+	scheme := h.client.Scheme()
+	var kinds []schema.GroupVersionKind
+	for gvk := range scheme.AllKnownTypes() {
+		if gvk.Version == runtime.APIVersionInternal || !strings.HasSuffix(gvk.Kind, "List") {
+			continue
+		}
+		itemGVK := gvk.GroupVersion().WithKind(strings.TrimSuffix(gvk.Kind, "List"))
+		// Events are shown in their own section
+		if itemGVK.Kind == "Event" || !scheme.Recognizes(itemGVK) {
+			continue
+		}
+		if _, err := h.createObjectForGVK(itemGVK); err != nil {
+			continue
+		}
+		kinds = append(kinds, itemGVK)
+	}
+	sort.Slice(kinds, func(i, j int) bool { return kinds[i].String() < kinds[j].String() })
+	return kinds
+}
+
+// ownedObject is an object that may be the child of another object
+type ownedObject struct {
+	gvk    schema.GroupVersionKind
+	object client.Object
+}
+
+// objectID identifies an object by its group, kind, namespace and name
+type objectID struct {
+	groupKind schema.GroupKind
+	namespace string
+	name      string
+}
+
+// ownerID identifies the owner an owner reference refers to
+type ownerID struct {
+	groupKind schema.GroupKind
+	name      string
+	uid       types.UID
+}
+
+// children returns the tree of objects owned by obj. Each type is listed
+// once and the tree is built in memory. Types that cannot be listed, e.g.
+// because they are not registered, are skipped.
+func (h *describeHandler) children(ctx context.Context, gvk schema.GroupVersionKind, obj client.Object,
+	kinds []schema.GroupVersionKind) ([]*OwnerTreeNode, error) {
+	if obj.GetUID() == "" {
+		return nil, nil
+	}
+
+	// Owner references cannot cross namespaces, so the children of a
+	// namespaced object are in its namespace
+	var opts []client.ListOption
+	if obj.GetNamespace() != "" {
+		opts = append(opts, client.InNamespace(obj.GetNamespace()))
+	}
+	byOwner := make(map[ownerID][]ownedObject)
+	for _, kind := range kinds {
+		list, err := h.createListForGVK(kind)
+		if err != nil {
+			return nil, err
+		}
+		if err := h.client.List(ctx, list, opts...); err != nil {
+			continue
+		}
+		items, err := h.extractObjects(list)
+		if err != nil {
+			return nil, err
+		}
+		for _, item := range items {
+			for _, ref := range item.GetOwnerReferences() {
+				owner := ownerID{groupKind: ownerGroupKind(ref), name: ref.Name, uid: ref.UID}
+				byOwner[owner] = append(byOwner[owner], ownedObject{gvk: kind, object: item})
+			}
+		}
+	}
+
+	visited := map[objectID]bool{idOf(gvk, obj): true}
+	var find func(gvk schema.GroupVersionKind, owner client.Object) []*OwnerTreeNode
+	find = func(gvk schema.GroupVersionKind, owner client.Object) []*OwnerTreeNode {
+		var nodes []*OwnerTreeNode
+		for _, child := range byOwner[ownerID{groupKind: gvk.GroupKind(), name: owner.GetName(), uid: owner.GetUID()}] {
+			// Namespaced owners only own objects in their namespace
+			if owner.GetNamespace() != "" && child.object.GetNamespace() != owner.GetNamespace() {
+				continue
+			}
+			id := idOf(child.gvk, child.object)
+			if visited[id] {
+				continue
+			}
+			visited[id] = true
+			nodes = append(nodes, &OwnerTreeNode{
+				GVK:      child.gvk,
+				Object:   child.object,
+				Children: find(child.gvk, child.object),
+			})
+		}
+		return nodes
+	}
+
+	return find(gvk, obj), nil
+}
+
+// ownerGroupKind returns the group and kind of the owner of an owner reference
+func ownerGroupKind(ref metav1.OwnerReference) schema.GroupKind {
+	gv, err := schema.ParseGroupVersion(ref.APIVersion)
+	if err != nil {
+		return schema.GroupKind{Kind: ref.Kind}
+	}
+	return gv.WithKind(ref.Kind).GroupKind()
+}
+
+// idOf returns the objectID of obj
+func idOf(gvk schema.GroupVersionKind, obj client.Object) objectID {
+	return objectID{groupKind: gvk.GroupKind(), namespace: obj.GetNamespace(), name: obj.GetName()}
+}
+
+// events returns the events whose involved object is obj, oldest first.
+// Events stored through the events.k8s.io/v1 API are included.
+func (h *describeHandler) events(ctx context.Context, gvk schema.GroupVersionKind, obj client.Object) ([]corev1.Event, error) {
+	scheme := h.client.Scheme()
+	var opts []client.ListOption
+	if obj.GetNamespace() != "" {
+		opts = append(opts, client.InNamespace(obj.GetNamespace()))
+	}
+
+	seen := make(map[types.NamespacedName]bool)
+	var result []corev1.Event
+	if scheme.Recognizes(typesv1.GetEventGVK()) {
+		list := &corev1.EventList{}
+		if err := h.client.List(ctx, list, opts...); err != nil {
+			return nil, err
+		}
+		for _, event := range list.Items {
+			if involves(event.InvolvedObject, gvk, obj) {
+				seen[types.NamespacedName{Namespace: event.Namespace, Name: event.Name}] = true
+				result = append(result, event)
+			}
+		}
+	}
+	if scheme.Recognizes(typesv1.GetEventsV1EventGVK()) {
+		list := &typesv1.EventsV1EventList{}
+		if err := h.client.List(ctx, list, opts...); err != nil {
+			return nil, err
+		}
+		for i := range list.Items {
+			event := typesv1.EventFromEventsV1(&list.Items[i])
+			key := types.NamespacedName{Namespace: event.Namespace, Name: event.Name}
+			if !seen[key] && involves(event.InvolvedObject, gvk, obj) {
+				result = append(result, *event)
+			}
+		}
+	}
+
+	sort.SliceStable(result, func(i, j int) bool {
+		return lastSeen(result[i]).Before(lastSeen(result[j]))
+	})
+	return result, nil
+}
+
+// involves reports whether ref refers to obj. If both have a UID, it must
+// match too, so events of a deleted object with the same name are skipped.
+func involves(ref corev1.ObjectReference, gvk schema.GroupVersionKind, obj client.Object) bool {
+	if ref.Kind != gvk.Kind || ref.Namespace != obj.GetNamespace() || ref.Name != obj.GetName() {
+		return false
+	}
+	if ref.APIVersion != "" {
+		if gv, err := schema.ParseGroupVersion(ref.APIVersion); err == nil && gv.Group != gvk.Group {
+			return false
+		}
+	}
+	return ref.UID == "" || obj.GetUID() == "" || ref.UID == obj.GetUID()
+}
+
+// lastSeen returns the time an event was last observed
+func lastSeen(event corev1.Event) time.Time {
+	switch {
+	case event.Series != nil:
+		return event.Series.LastObservedTime.Time
+	case !event.LastTimestamp.IsZero():
+		return event.LastTimestamp.Time
+	case !event.EventTime.IsZero():
+		return event.EventTime.Time
+	default:
+		return event.FirstTimestamp.Time
+	}
+}
+
+// describeMetadata writes the metadata and owner references of obj
+func describeMetadata(w DescriptionWriter, gvk schema.GroupVersionKind, obj client.Object) {
+	w.Write(0, "Name:\t%s\n", obj.GetName())
+	if obj.GetNamespace() != "" {
+		w.Write(0, "Namespace:\t%s\n", obj.GetNamespace())
+	}
+	w.Write(0, "Kind:\t%s\n", gvk.Kind)
+	w.Write(0, "API Version:\t%s\n", gvk.GroupVersion())
+	writeMap(w, "Labels", obj.GetLabels())
+	writeMap(w, "Annotations", obj.GetAnnotations())
+	if created := obj.GetCreationTimestamp(); !created.IsZero() {
+		w.Write(0, "Creation Timestamp:\t%s\n", created.Format(time.RFC1123Z))
+	}
+	if deleted := obj.GetDeletionTimestamp(); deleted != nil {
+		w.Write(0, "Deletion Timestamp:\t%s\n", deleted.Format(time.RFC1123Z))
+	}
+	if finalizers := obj.GetFinalizers(); len(finalizers) > 0 {
+		w.Write(0, "Finalizers:\t%s\n", strings.Join(finalizers, ", "))
+	}
+
+	refs := obj.GetOwnerReferences()
+	if controller := metav1.GetControllerOfNoCopy(obj); controller != nil {
+		w.Write(0, "Controlled By:\t%s/%s\n", controller.Kind, controller.Name)
+	}
+	if len(refs) == 0 {
+		w.Write(0, "Owner References:\t<none>\n")
+		return
+	}
+	w.Write(0, "Owner References:\n")
+	for _, ref := range refs {
+		w.Write(1, "%s/%s\t%s\n", ref.Kind, ref.Name, ref.APIVersion)
+	}
+}
+
+// writeMap writes labels or annotations, one per line
+func writeMap(w DescriptionWriter, name string, m map[string]string) {
+	if len(m) == 0 {
+		w.Write(0, "%s:\t<none>\n", name)
+		return
+	}
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for i, key := range keys {
+		label := ""
+		if i == 0 {
+			label = name + ":"
+		}
+		w.Write(0, "%s\t%s=%s\n", label, key, m[key])
+	}
+}
+
+// describeChildren writes the owner tree below the described object
+func describeChildren(w DescriptionWriter, children []*OwnerTreeNode) {
+	if len(children) == 0 {
+		w.Write(0, "Children:\t<none>\n")
+		return
+	}
+	w.Write(0, "Children:\n")
+	var write func(level int, nodes []*OwnerTreeNode)
+	write = func(level int, nodes []*OwnerTreeNode) {
+		for _, node := range nodes {
+			w.Write(level, "%s/%s\n", node.GVK.Kind, node.Object.GetName())
+			write(level+1, node.Children)
+		}
+	}
+	write(1, children)
+}
+
+// describeEvents writes the events of the described object
+func describeEvents(w DescriptionWriter, events []corev1.Event) {
+	if len(events) == 0 {
+		w.Write(0, "Events:\t<none>\n")
+		return
+	}
+	w.Write(0, "Events:\n")
+	w.Write(1, "Type\tReason\tAge\tFrom\tMessage\n")
+	w.Write(1, "----\t------\t---\t----\t-------\n")
+	for _, event := range events {
+		age := duration.HumanDuration(time.Since(lastSeen(event)))
+		count := event.Count
+		if event.Series != nil && event.Series.Count > count {
+			count = event.Series.Count
+		}
+		if count > 1 && !event.FirstTimestamp.IsZero() {
+			age = fmt.Sprintf("%s (x%d over %s)", age, count, duration.HumanDuration(time.Since(event.FirstTimestamp.Time)))
+		}
+		from := event.Source.Component
+		if from == "" {
+			from = event.ReportingController
+		}
+		w.Write(1, "%s\t%s\t%s\t%s\t%s\n", event.Type, event.Reason, age, from, strings.TrimSpace(event.Message))
+	}
+}
@@ -0,0 +1,206 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/dtomasi/k1s/core/client"
+)
+
+// Describer renders the resource specific part of a description, between the
+// object metadata and its owner tree and events.
+type Describer interface {
+	// Describe writes the description of obj to w
+	Describe(ctx context.Context, c client.Client, obj client.Object, w DescriptionWriter) error
+}
+
+// DescriberFunc is a function that implements Describer.
+type DescriberFunc func(ctx context.Context, c client.Client, obj client.Object, w DescriptionWriter) error
+
+// Describe implements Describer.
+func (f DescriberFunc) Describe(ctx context.Context, c client.Client, obj client.Object, w DescriptionWriter) error {
+	return f(ctx, c, obj, w)
+}
+
+// DescriptionWriter writes the indented lines of a description. Tab separated
+// columns are aligned.
+type DescriptionWriter interface {
+	// Write writes a formatted line at the given indentation level
+	Write(level int, format string, args ...interface{})
+}
+
+// prefixWriter implements DescriptionWriter with two spaces per level.
+type prefixWriter struct {
+	out io.Writer
+}
+
+// Write implements DescriptionWriter.
+func (w *prefixWriter) Write(level int, format string, args ...interface{}) {
+	_, _ = fmt.Fprintf(w.out, strings.Repeat("  ", level)+format, args...)
+}
+
+// DescriberRegistry holds the custom describers of resource types. Types
+// without a custom describer use the generic describer.
+type DescriberRegistry struct {
+	mu         sync.RWMutex
+	describers map[schema.GroupVersionKind]Describer
+}
+
+// NewDescriberRegistry creates an empty describer registry.
+func NewDescriberRegistry() *DescriberRegistry {
+	return &DescriberRegistry{
+		describers: make(map[schema.GroupVersionKind]Describer),
+	}
+}
+
+// Register registers the describer of the given GVK, replacing any
+// previously registered describer.
+func (r *DescriberRegistry) Register(gvk schema.GroupVersionKind, describer Describer) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.describers[gvk] = describer
+}
+
+// DescriberFor returns the describer of the given GVK.
+func (r *DescriberRegistry) DescriberFor(gvk schema.GroupVersionKind) Describer {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if describer, ok := r.describers[gvk]; ok {
+		return describer
+	}
+	return GenericDescriber
+}
+
+// GenericDescriber describes any object by its fields. Spec and data fields
+// are followed by the status, whose conditions are rendered as a table.
+var GenericDescriber Describer = DescriberFunc(describeGeneric)
+
+// describeGeneric implements GenericDescriber.
+func describeGeneric(_ context.Context, _ client.Client, obj client.Object, w DescriptionWriter) error {
+	content, err := toUnstructuredContent(obj)
+	if err != nil {
+		return err
+	}
+
+	for _, key := range sortedKeys(content) {
+		switch key {
+		case "apiVersion", "kind", "metadata", "status":
+			continue
+		}
+		writeField(w, 0, capitalize(key), content[key])
+	}
+
+	status, ok := content["status"].(map[string]interface{})
+	if !ok {
+		return nil
+	}
+	conditions, _ := status["conditions"].([]interface{})
+	delete(status, "conditions")
+	if len(status) > 0 {
+		writeField(w, 0, "Status", status)
+	}
+	if len(conditions) > 0 {
+		writeConditions(w, conditions)
+	}
+	return nil
+}
+
+// toUnstructuredContent returns the fields of obj
+func toUnstructuredContent(obj client.Object) (map[string]interface{}, error) {
+	if u, ok := obj.(*unstructured.Unstructured); ok {
+		return runtime.DeepCopyJSON(u.UnstructuredContent()), nil
+	}
+	content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert object: %w", err)
+	}
+	return content, nil
+}
+
+// writeField writes a field and its nested fields
+func writeField(w DescriptionWriter, level int, name string, value interface{}) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		if len(v) == 0 {
+			w.Write(level, "%s:\t<none>\n", name)
+			return
+		}
+		w.Write(level, "%s:\n", name)
+		for _, key := range sortedKeys(v) {
+			writeField(w, level+1, key, v[key])
+		}
+	case []interface{}:
+		if len(v) == 0 {
+			w.Write(level, "%s:\t<none>\n", name)
+			return
+		}
+		w.Write(level, "%s:\n", name)
+		for _, item := range v {
+			if fields, ok := item.(map[string]interface{}); ok {
+				keys := sortedKeys(fields)
+				for i, key := range keys {
+					prefix := "  "
+					if i == 0 {
+						prefix = "- "
+					}
+					writeField(w, level+1, prefix+key, fields[key])
+				}
+				continue
+			}
+			w.Write(level+1, "- %v\n", item)
+		}
+	case nil:
+		w.Write(level, "%s:\t<none>\n", name)
+	default:
+		w.Write(level, "%s:\t%v\n", name, v)
+	}
+}
+
+// writeConditions writes status conditions as a table
+func writeConditions(w DescriptionWriter, conditions []interface{}) {
+	w.Write(0, "Conditions:\n")
+	w.Write(1, "Type\tStatus\tReason\tMessage\n")
+	w.Write(1, "----\t------\t------\t-------\n")
+	for _, item := range conditions {
+		condition, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		w.Write(1, "%v\t%v\t%v\t%v\n",
+			condition["type"], condition["status"], valueOrEmpty(condition["reason"]), valueOrEmpty(condition["message"]))
+	}
+}
+
+// valueOrEmpty returns an empty string for missing values
+func valueOrEmpty(value interface{}) interface{} {
+	if value == nil {
+		return ""
+	}
+	return value
+}
+
+// sortedKeys returns the keys of m in sorted order
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// capitalize returns s with an upper case first letter
+func capitalize(s string) string {
+	if s == "" {
+		return s
+	}
+	return strings.ToUpper(s[:1]) + s[1:]
+}
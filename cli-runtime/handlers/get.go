@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"reflect"
 
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 
@@ -81,6 +82,12 @@ func (h *getHandler) getList(ctx context.Context, req *GetRequest) (*GetResponse
 func (h *getHandler) createObjectForGVK(gvk schema.GroupVersionKind) (client.Object, error) {
 	scheme := h.client.Scheme()
 	obj, err := scheme.New(gvk)
+	if runtime.IsNotRegisteredError(err) {
+		// Types that are not compiled in are handled as unstructured objects
+		u := &unstructured.Unstructured{}
+		u.SetGroupVersionKind(gvk)
+		return u, nil
+	}
 	if err != nil {
 		return nil, fmt.Errorf("failed to create new object for GVK %s: %w", gvk, err)
 	}
@@ -102,6 +109,11 @@ func (h *getHandler) createListForGVK(gvk schema.GroupVersionKind) (client.Objec
 	listGVK.Kind += "List"
 
 	obj, err := scheme.New(listGVK)
+	if runtime.IsNotRegisteredError(err) {
+		u := &unstructured.UnstructuredList{}
+		u.SetGroupVersionKind(listGVK)
+		return u, nil
+	}
 	if err != nil {
 		return nil, fmt.Errorf("failed to create new list for GVK %s: %w", listGVK, err)
 	}
@@ -144,7 +156,12 @@ func (h *getHandler) extractObjects(list client.ObjectList) ([]client.Object, er
 	if itemsField.Kind() == reflect.Slice {
 		objects := make([]client.Object, 0, itemsField.Len())
 		for i := 0; i < itemsField.Len(); i++ {
-			item := itemsField.Index(i).Interface()
+			itemValue := itemsField.Index(i)
+			if itemValue.Kind() == reflect.Struct {
+				// Typed and unstructured lists hold values, their pointers implement client.Object
+				itemValue = itemValue.Addr()
+			}
+			item := itemValue.Interface()
 			if clientObj, ok := item.(client.Object); ok {
 				objects = append(objects, clientObj)
 			}
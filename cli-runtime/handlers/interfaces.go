@@ -33,14 +33,30 @@ type DeleteHandler interface {
 	Handle(ctx context.Context, req *DeleteRequest) (*DeleteResponse, error)
 }
 
+// DescribeHandler handles DESCRIBE operations for resources.
+type DescribeHandler interface {
+	// Handle executes a describe operation based on the provided request
+	Handle(ctx context.Context, req *DescribeRequest) (*DescribeResponse, error)
+}
+
 // HandlerFactory creates handlers with a given client.
 type HandlerFactory struct {
-	client client.Client
+	client     client.Client
+	describers *DescriberRegistry
 }
 
 // NewHandlerFactory creates a new handler factory with the given client.
 func NewHandlerFactory(client client.Client) *HandlerFactory {
-	return &HandlerFactory{client: client}
+	return &HandlerFactory{
+		client:     client,
+		describers: NewDescriberRegistry(),
+	}
+}
+
+// Describers returns the registry of the custom describers used by the
+// DescribeHandler.
+func (f *HandlerFactory) Describers() *DescriberRegistry {
+	return f.describers
 }
 
 // Get creates a new GetHandler.
@@ -62,3 +78,11 @@ func (f *HandlerFactory) Apply() ApplyHandler {
 func (f *HandlerFactory) Delete() DeleteHandler {
 	return &deleteHandler{client: f.client}
 }
+
+// Describe creates a new DescribeHandler.
+func (f *HandlerFactory) Describe() DescribeHandler {
+	return &describeHandler{
+		getHandler: getHandler{client: f.client},
+		describers: f.describers,
+	}
+}
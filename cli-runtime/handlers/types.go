@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 
@@ -99,6 +100,40 @@ type DeleteResponse struct {
 	Deleted []client.Object
 }
 
+// DescribeRequest represents a request to describe a resource.
+type DescribeRequest struct {
+	// ResourceType specifies the GVK of the resource to describe
+	ResourceType schema.GroupVersionKind
+	// Key identifies the resource
+	Key client.ObjectKey
+	// SkipEvents omits the events of the resource
+	SkipEvents bool
+	// SkipChildren omits the objects owned by the resource
+	SkipChildren bool
+}
+
+// DescribeResponse contains the result of a describe operation.
+type DescribeResponse struct {
+	// Object is the described resource
+	Object client.Object
+	// Children contains the tree of objects owned by the resource
+	Children []*OwnerTreeNode
+	// Events contains the events of the resource, oldest first
+	Events []corev1.Event
+	// Description is the human-readable description of the resource
+	Description string
+}
+
+// OwnerTreeNode is an object in the owner tree of a described resource.
+type OwnerTreeNode struct {
+	// GVK is the GroupVersionKind of the object
+	GVK schema.GroupVersionKind
+	// Object is the owned object
+	Object client.Object
+	// Children contains the objects owned by this object
+	Children []*OwnerTreeNode
+}
+
 // OutputOptions control how operation responses should be formatted.
 type OutputOptions struct {
 	// Format specifies the output format (table, json, yaml, name)
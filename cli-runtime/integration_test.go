@@ -5,6 +5,7 @@ import (
 	"strings"
 	"testing"
 
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
@@ -15,6 +16,11 @@ import (
 	"github.com/dtomasi/k1s/cli-runtime/handlers"
 	"github.com/dtomasi/k1s/cli-runtime/options"
 	"github.com/dtomasi/k1s/cli-runtime/printers"
+	"github.com/dtomasi/k1s/core/client"
+	"github.com/dtomasi/k1s/core/registry"
+	k1sstorage "github.com/dtomasi/k1s/core/storage"
+	corev1types "github.com/dtomasi/k1s/core/types/v1"
+	memory "github.com/dtomasi/k1s/storage/memory"
 )
 
 // TestBasicIntegration tests the basic integration of CLI-Runtime components.
@@ -32,8 +38,9 @@ func TestBasicIntegration(t *testing.T) {
 	createHandler := factory.Create()
 	applyHandler := factory.Apply()
 	deleteHandler := factory.Delete()
+	describeHandler := factory.Describe()
 
-	if getHandler == nil || createHandler == nil || applyHandler == nil || deleteHandler == nil {
+	if getHandler == nil || createHandler == nil || applyHandler == nil || deleteHandler == nil || describeHandler == nil {
 		t.Fatal("Handlers should not be nil")
 	}
 
@@ -216,6 +223,125 @@ func TestBuilderIntegration(t *testing.T) {
 	}
 }
 
+// TestDescribeHandler tests describing an object with its owner tree and events.
+func TestDescribeHandler(t *testing.T) {
+	ctx := context.Background()
+	scheme := runtime.NewScheme()
+	if err := corev1types.AddToScheme(scheme); err != nil {
+		t.Fatalf("Failed to build scheme: %v", err)
+	}
+	reg := registry.NewRegistry()
+	if err := registry.RegisterCoreResources(reg); err != nil {
+		t.Fatalf("Failed to register core resources: %v", err)
+	}
+	c, err := client.NewClient(client.ClientOptions{
+		Scheme:   scheme,
+		Storage:  memory.NewMemoryStorage(k1sstorage.Config{}),
+		Registry: reg,
+	})
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+
+	owner := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "owner", Namespace: "default", Labels: map[string]string{"app": "test"}},
+		Data:       map[string]string{"key": "value"},
+	}
+	if err := c.Create(ctx, owner); err != nil {
+		t.Fatalf("Failed to create owner: %v", err)
+	}
+	isController := true
+	child := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{
+		Name: "child", Namespace: "default",
+		OwnerReferences: []metav1.OwnerReference{{
+			APIVersion: "v1", Kind: "ConfigMap", Name: owner.Name, UID: owner.UID, Controller: &isController,
+		}},
+	}}
+	if err := c.Create(ctx, child); err != nil {
+		t.Fatalf("Failed to create child: %v", err)
+	}
+	grandchild := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{
+		Name: "grandchild", Namespace: "default",
+		OwnerReferences: []metav1.OwnerReference{{APIVersion: "v1", Kind: "Secret", Name: child.Name, UID: child.UID}},
+	}}
+	if err := c.Create(ctx, grandchild); err != nil {
+		t.Fatalf("Failed to create grandchild: %v", err)
+	}
+	event := corev1types.NewWarningEvent("default", "owner.1", "SyncFailed", "sync failed", corev1.ObjectReference{
+		Kind: "ConfigMap", Namespace: "default", Name: owner.Name, UID: owner.UID,
+	})
+	event.Count = 3
+	if err := c.Create(ctx, event); err != nil {
+		t.Fatalf("Failed to create event: %v", err)
+	}
+
+	// A Secret with the name and, as if restored from a backup, the UID of the
+	// owner is another object: its children and events are not the owner's
+	namesake := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: owner.Name, Namespace: "default"}}
+	if err := c.Create(ctx, namesake); err != nil {
+		t.Fatalf("Failed to create namesake: %v", err)
+	}
+	namesakeChild := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{
+		Name: "namesake-child", Namespace: "default",
+		OwnerReferences: []metav1.OwnerReference{{APIVersion: "v1", Kind: "Secret", Name: owner.Name, UID: owner.UID}},
+	}}
+	if err := c.Create(ctx, namesakeChild); err != nil {
+		t.Fatalf("Failed to create namesake child: %v", err)
+	}
+	namesakeEvent := corev1types.NewWarningEvent("default", "owner.2", "NamesakeFailed", "failed", corev1.ObjectReference{
+		Kind: "Secret", Namespace: "default", Name: owner.Name, UID: owner.UID,
+	})
+	if err := c.Create(ctx, namesakeEvent); err != nil {
+		t.Fatalf("Failed to create namesake event: %v", err)
+	}
+
+	factory := handlers.NewHandlerFactory(c)
+	configMapGVK := corev1types.GetConfigMapGVK()
+	resp, err := factory.Describe().Handle(ctx, &handlers.DescribeRequest{
+		ResourceType: configMapGVK,
+		Key:          client.ObjectKey{Namespace: "default", Name: "owner"},
+	})
+	if err != nil {
+		t.Fatalf("Describe failed: %v", err)
+	}
+
+	if len(resp.Events) != 1 || resp.Events[0].Reason != "SyncFailed" {
+		t.Fatalf("Expected the SyncFailed event, got %v", resp.Events)
+	}
+	if len(resp.Children) != 1 || resp.Children[0].Object.GetName() != "child" ||
+		len(resp.Children[0].Children) != 1 || resp.Children[0].Children[0].Object.GetName() != "grandchild" {
+		t.Fatalf("Expected the owner tree owner -> child -> grandchild, got %v", resp.Children)
+	}
+	for _, expected := range []string{"Name:", "owner", "app=test", "Data:", "key:", "value", "Children:", "Secret/child", "ConfigMap/grandchild", "SyncFailed", "(x3 over"} {
+		if !strings.Contains(resp.Description, expected) {
+			t.Errorf("Description should contain %q:\n%s", expected, resp.Description)
+		}
+	}
+
+	// Custom describers replace the generic description of their type
+	factory.Describers().Register(corev1types.GetSecretGVK(), handlers.DescriberFunc(
+		func(_ context.Context, _ client.Client, obj client.Object, w handlers.DescriptionWriter) error {
+			w.Write(0, "Custom:\t%s\n", obj.GetName())
+			return nil
+		}))
+	resp, err = factory.Describe().Handle(ctx, &handlers.DescribeRequest{
+		ResourceType: corev1types.GetSecretGVK(),
+		Key:          client.ObjectKey{Namespace: "default", Name: "child"},
+		SkipEvents:   true,
+	})
+	if err != nil {
+		t.Fatalf("Describe failed: %v", err)
+	}
+	for _, expected := range []string{"Custom:", "Controlled By:", "ConfigMap/owner", "ConfigMap/grandchild"} {
+		if !strings.Contains(resp.Description, expected) {
+			t.Errorf("Description should contain %q:\n%s", expected, resp.Description)
+		}
+	}
+	if strings.Contains(resp.Description, "Events:") {
+		t.Errorf("Description should not contain events:\n%s", resp.Description)
+	}
+}
+
 // MockObject implements the necessary interfaces for testing.
 type MockObject struct {
 	Name      string
@@ -0,0 +1,47 @@
+package controller
+
+import (
+	"github.com/dtomasi/k1s/controller-runtime/pkg/builder"
+	"github.com/dtomasi/k1s/controller-runtime/pkg/log"
+	"github.com/dtomasi/k1s/controller-runtime/pkg/manager"
+	"github.com/dtomasi/k1s/controller-runtime/pkg/manager/signals"
+	"github.com/dtomasi/k1s/controller-runtime/pkg/reconcile"
+)
+
+// Builder builds a controller and registers it with a manager.
+type Builder = builder.Builder
+
+// Request contains the name and namespace of the object to reconcile.
+type Request = reconcile.Request
+
+// Result contains the result of a Reconcile invocation.
+type Result = reconcile.Result
+
+// Manager starts and stops controllers and provides them with shared dependencies.
+type Manager = manager.Manager
+
+// Options configures a manager.
+type Options = manager.Options
+
+var (
+	// NewControllerManagedBy returns a new controller builder for a manager.
+	NewControllerManagedBy = builder.ControllerManagedBy
+
+	// NewManager creates a manager for a k1s runtime.
+	NewManager = manager.New
+
+	// SetupSignalHandler returns a context that is cancelled on SIGINT and SIGTERM.
+	SetupSignalHandler = signals.SetupSignalHandler
+
+	// Log is the base logger of the controller runtime.
+	Log = log.Log
+
+	// LoggerFrom returns the logger stored in a context.
+	LoggerFrom = log.FromContext
+
+	// LoggerInto stores a logger in a context.
+	LoggerInto = log.IntoContext
+
+	// SetLogger sets the logger Log writes to.
+	SetLogger = log.SetLogger
+)
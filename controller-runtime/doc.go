@@ -2,11 +2,49 @@
 //
 // This package implements a lightweight controller-runtime that adapts
 // standard Kubernetes controller patterns for CLI-optimized environments.
+// Its packages mirror the layout of sigs.k8s.io/controller-runtime, so
+// reconcilers written for kubebuilder can be ported by changing imports:
+//
+//	import (
+//		ctrl "github.com/dtomasi/k1s/controller-runtime"
+//		"github.com/dtomasi/k1s/controller-runtime/pkg/client"
+//		"github.com/dtomasi/k1s/controller-runtime/pkg/log"
+//	)
 //
 // It includes:
-//   - Manager interface for controller lifecycle
-//   - Controller interface for resource watching
-//   - Reconciler interface for business logic
-//   - Builder API for fluent controller configuration
-//   - Event recorder integration
+//   - Manager interface for controller lifecycle (pkg/manager)
+//   - Controller interface for resource watching (pkg/controller)
+//   - Reconciler interface for business logic (pkg/reconcile)
+//   - Builder API for fluent controller configuration (pkg/builder)
+//   - Event handlers and sources (pkg/handler, pkg/source)
+//   - Event recorder integration via Manager.GetEventRecorderFor
+//
+// A manager runs on an initialized k1s runtime:
+//
+//	mgr, err := ctrl.NewManager(rt, ctrl.Options{})
+//	if err != nil {
+//		return err
+//	}
+//	if err := (&CategoryReconciler{Client: mgr.GetClient()}).SetupWithManager(mgr); err != nil {
+//		return err
+//	}
+//	return mgr.Start(ctrl.SetupSignalHandler())
+//
+// CLI commands that should not keep running can use RunOnce instead of
+// Start. It reconciles every object, or only the ones changed since a
+// previous run, processes the resulting work until all workqueues are empty
+// and returns:
+//
+//	err := mgr.RunOnce(ctx, manager.WithRequeueHorizon(5*time.Second))
+//
+// With a checkpoint store, each controller persists the requests it has not
+// finished and the last resourceVersion it observed when it stops, and the
+// next process resumes from there (pkg/checkpoint):
+//
+//	mgr, err := ctrl.NewManager(rt, ctrl.Options{
+//		Checkpoints: checkpoint.NewStorageStore(storageBackend),
+//	})
+//
+// Unlike controller-runtime, reconcile.Request.NamespacedName is the k1s
+// client.ObjectKey, so it can be passed to client.Get unchanged.
 package controller
@@ -2,4 +2,19 @@ module github.com/dtomasi/k1s/controller-runtime
 
 go 1.25.1
 
+require (
+	github.com/dtomasi/k1s/core v0.0.0
+	github.com/dtomasi/k1s/storage/memory v0.0.0
+	github.com/go-logr/logr v1.4.3
+	github.com/onsi/ginkgo/v2 v2.25.3
+	github.com/onsi/gomega v1.38.2
+	k8s.io/api v0.34.0
+	k8s.io/apimachinery v0.34.0
+	k8s.io/apiserver v0.34.0
+	k8s.io/client-go v0.34.0
+	k8s.io/utils v0.0.0-20250604170112-4c0f3b243397
+)
+
 replace github.com/dtomasi/k1s/core => ../core
+
+replace github.com/dtomasi/k1s/storage/memory => ../storage/memory
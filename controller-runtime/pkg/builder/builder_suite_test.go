@@ -0,0 +1,16 @@
+package builder_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/dtomasi/k1s/controller-runtime/pkg/log"
+)
+
+func TestBuilder(t *testing.T) {
+	RegisterFailHandler(Fail)
+	log.SetLogger(GinkgoLogr)
+	RunSpecs(t, "Builder Suite")
+}
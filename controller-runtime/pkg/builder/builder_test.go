@@ -0,0 +1,246 @@
+package builder_test
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/dtomasi/k1s/core/client"
+	"github.com/dtomasi/k1s/core/registry"
+	k1sruntime "github.com/dtomasi/k1s/core/runtime"
+	k1sstorage "github.com/dtomasi/k1s/core/storage"
+	corev1types "github.com/dtomasi/k1s/core/types/v1"
+	memory "github.com/dtomasi/k1s/storage/memory"
+
+	"github.com/dtomasi/k1s/controller-runtime/pkg/builder"
+	"github.com/dtomasi/k1s/controller-runtime/pkg/controller/controllerutil"
+	"github.com/dtomasi/k1s/controller-runtime/pkg/event"
+	"github.com/dtomasi/k1s/controller-runtime/pkg/handler"
+	"github.com/dtomasi/k1s/controller-runtime/pkg/manager"
+	"github.com/dtomasi/k1s/controller-runtime/pkg/predicate"
+	"github.com/dtomasi/k1s/controller-runtime/pkg/reconcile"
+	"github.com/dtomasi/k1s/controller-runtime/pkg/source"
+)
+
+// recorder is a reconciler that records the requests it receives
+type recorder struct {
+	mu       sync.Mutex
+	requests []reconcile.Request
+}
+
+func (r *recorder) Reconcile(_ context.Context, req reconcile.Request) (reconcile.Result, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.requests = append(r.requests, req)
+	return reconcile.Result{}, nil
+}
+
+func (r *recorder) Requests() []reconcile.Request {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]reconcile.Request(nil), r.requests...)
+}
+
+func (r *recorder) Reset() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.requests = nil
+}
+
+func request(namespace, name string) reconcile.Request {
+	return reconcile.Request{NamespacedName: client.ObjectKey{Namespace: namespace, Name: name}}
+}
+
+func newRuntime() k1sruntime.Runtime {
+	scheme := runtime.NewScheme()
+	Expect(corev1types.AddToScheme(scheme)).To(Succeed())
+
+	reg := registry.NewRegistry()
+	Expect(registry.RegisterCoreResources(reg)).To(Succeed())
+
+	c, err := client.NewClient(client.ClientOptions{
+		Scheme:   scheme,
+		Storage:  memory.NewMemoryStorage(k1sstorage.Config{}),
+		Registry: reg,
+	})
+	Expect(err).NotTo(HaveOccurred())
+
+	rt, err := k1sruntime.NewRuntimeWithOptions(k1sruntime.RuntimeOptions{Client: c, Scheme: scheme})
+	Expect(err).NotTo(HaveOccurred())
+	return rt
+}
+
+var _ = Describe("Builder", func() {
+	var (
+		ctx    context.Context
+		cancel context.CancelFunc
+		mgr    manager.Manager
+		c      client.Client
+		rec    *recorder
+		done   chan error
+	)
+
+	BeforeEach(func() {
+		ctx, cancel = context.WithCancel(context.Background())
+		var err error
+		mgr, err = manager.New(newRuntime(), manager.Options{})
+		Expect(err).NotTo(HaveOccurred())
+		c = mgr.GetClient()
+		rec = &recorder{}
+		done = make(chan error, 1)
+	})
+
+	AfterEach(func() {
+		cancel()
+		Eventually(done, 5*time.Second).Should(Receive(BeNil()))
+	})
+
+	start := func() {
+		go func() { done <- mgr.Start(ctx) }()
+	}
+
+	Describe("For", func() {
+		It("should reconcile existing and newly created objects", func() {
+			existing := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "existing", Namespace: "default"}}
+			Expect(c.Create(ctx, existing)).To(Succeed())
+
+			Expect(builder.ControllerManagedBy(mgr).For(&corev1.ConfigMap{}).Complete(rec)).To(Succeed())
+			start()
+
+			Eventually(rec.Requests).Should(ContainElement(request("default", "existing")))
+
+			created := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "created", Namespace: "default"}}
+			Expect(c.Create(ctx, created)).To(Succeed())
+			Eventually(rec.Requests).Should(ContainElement(request("default", "created")))
+		})
+
+		It("should reconcile deleted objects", func() {
+			Expect(builder.ControllerManagedBy(mgr).For(&corev1.ConfigMap{}).Complete(rec)).To(Succeed())
+			start()
+
+			obj := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "doomed", Namespace: "default"}}
+			Expect(c.Create(ctx, obj)).To(Succeed())
+			Eventually(rec.Requests).Should(ContainElement(request("default", "doomed")))
+
+			rec.Reset()
+			Expect(c.Delete(ctx, obj)).To(Succeed())
+			Eventually(rec.Requests).Should(ContainElement(request("default", "doomed")))
+		})
+
+		It("should reject For being called twice", func() {
+			err := builder.ControllerManagedBy(mgr).For(&corev1.ConfigMap{}).For(&corev1.Secret{}).Complete(rec)
+			Expect(err).To(HaveOccurred())
+			start()
+		})
+
+		It("should require a watch", func() {
+			err := builder.ControllerManagedBy(mgr).Named("nothing").Complete(rec)
+			Expect(err).To(HaveOccurred())
+			start()
+		})
+	})
+
+	Describe("Owns", func() {
+		It("should reconcile the controller owner when an owned object changes", func() {
+			owner := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "owner", Namespace: "default"}}
+			Expect(c.Create(ctx, owner)).To(Succeed())
+
+			Expect(builder.ControllerManagedBy(mgr).
+				For(&corev1.ConfigMap{}).
+				Owns(&corev1.Secret{}).
+				Complete(rec)).To(Succeed())
+			start()
+			Eventually(rec.Requests).Should(ContainElement(request("default", "owner")))
+			rec.Reset()
+
+			owned := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "owned", Namespace: "default"}}
+			Expect(controllerutil.SetControllerReference(owner, owned, mgr.GetScheme())).To(Succeed())
+			Expect(c.Create(ctx, owned)).To(Succeed())
+
+			Eventually(rec.Requests).Should(ContainElement(request("default", "owner")))
+			Expect(rec.Requests()).NotTo(ContainElement(request("default", "owned")))
+		})
+	})
+
+	Describe("Watches", func() {
+		It("should reconcile the requests returned by the map function", func() {
+			mapToTarget := handler.EnqueueRequestsFromMapFunc(func(_ context.Context, obj client.Object) []reconcile.Request {
+				return []reconcile.Request{request(obj.GetNamespace(), obj.GetLabels()["target"])}
+			})
+
+			Expect(builder.ControllerManagedBy(mgr).
+				For(&corev1.ConfigMap{}).
+				Watches(&corev1.Secret{}, mapToTarget).
+				Complete(rec)).To(Succeed())
+			start()
+
+			secret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{
+				Name: "secret", Namespace: "default", Labels: map[string]string{"target": "config"},
+			}}
+			Expect(c.Create(ctx, secret)).To(Succeed())
+			Eventually(rec.Requests).Should(ContainElement(request("default", "config")))
+		})
+
+		It("should reconcile events from raw sources", func() {
+			events := make(chan event.GenericEvent, 1)
+			Expect(builder.ControllerManagedBy(mgr).
+				Named("trigger").
+				WatchesRawSource(source.Channel(events, &handler.EnqueueRequestForObject{})).
+				Complete(rec)).To(Succeed())
+			start()
+
+			events <- event.GenericEvent{Object: &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "manual", Namespace: "ns"}}}
+			Eventually(rec.Requests).Should(ContainElement(request("ns", "manual")))
+		})
+	})
+
+	Describe("Predicates", func() {
+		It("should skip updates that do not change the generation", func() {
+			Expect(builder.ControllerManagedBy(mgr).
+				For(&corev1.ConfigMap{}).
+				WithEventFilter(predicate.GenerationChangedPredicate{}).
+				Complete(rec)).To(Succeed())
+			start()
+
+			cm := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "config", Namespace: "default"}}
+			Expect(c.Create(ctx, cm)).To(Succeed())
+			Eventually(rec.Requests).Should(ContainElement(request("default", "config")))
+			rec.Reset()
+
+			cm.Labels = map[string]string{"app": "k1s"}
+			Expect(c.Update(ctx, cm)).To(Succeed())
+			Consistently(rec.Requests, 300*time.Millisecond).Should(BeEmpty())
+
+			cm.Data = map[string]string{"key": "value"}
+			Expect(c.Update(ctx, cm)).To(Succeed())
+			Eventually(rec.Requests).Should(ContainElement(request("default", "config")))
+		})
+
+		It("should filter the events of a watched type", func() {
+			selected, err := predicate.LabelSelectorPredicate(metav1.LabelSelector{
+				MatchLabels: map[string]string{"app": "k1s"},
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(builder.ControllerManagedBy(mgr).
+				For(&corev1.ConfigMap{}, builder.WithPredicates(selected)).
+				Complete(rec)).To(Succeed())
+			start()
+
+			Expect(c.Create(ctx, &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{
+				Name: "other", Namespace: "default",
+			}})).To(Succeed())
+			Expect(c.Create(ctx, &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{
+				Name: "selected", Namespace: "default", Labels: map[string]string{"app": "k1s"},
+			}})).To(Succeed())
+			Eventually(rec.Requests).Should(ContainElement(request("default", "selected")))
+			Consistently(rec.Requests, 300*time.Millisecond).ShouldNot(ContainElement(request("default", "other")))
+		})
+	})
+})
@@ -0,0 +1,242 @@
+// Package builder wires controllers to their watched types. It mirrors
+// sigs.k8s.io/controller-runtime/pkg/builder, so SetupWithManager functions
+// written for controller-runtime work unchanged:
+//
+//	return ctrl.NewControllerManagedBy(mgr).
+//		For(&v1alpha1.Category{}).
+//		Owns(&v1alpha1.Item{}).
+//		Complete(r)
+package builder
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/dtomasi/k1s/core/client"
+
+	"github.com/dtomasi/k1s/controller-runtime/pkg/controller"
+	"github.com/dtomasi/k1s/controller-runtime/pkg/handler"
+	"github.com/dtomasi/k1s/controller-runtime/pkg/manager"
+	"github.com/dtomasi/k1s/controller-runtime/pkg/predicate"
+	"github.com/dtomasi/k1s/controller-runtime/pkg/reconcile"
+	"github.com/dtomasi/k1s/controller-runtime/pkg/source"
+)
+
+// Builder builds a controller and registers it with a manager.
+type Builder struct {
+	forInput     ForInput
+	ownsInput    []OwnsInput
+	watchesInput []WatchesInput
+	rawSources   []source.Source
+	globalPreds  []predicate.Predicate
+	mgr          manager.Manager
+	ctrl         controller.Controller
+	ctrlOptions  controller.Options
+	name         string
+}
+
+// ControllerManagedBy returns a new builder for a controller run by mgr.
+func ControllerManagedBy(m manager.Manager) *Builder {
+	return &Builder{mgr: m}
+}
+
+// ForInput describes the type the controller reconciles.
+type ForInput struct {
+	object     client.Object
+	predicates []predicate.Predicate
+	err        error
+}
+
+// For sets the type the controller reconciles. Events of objects of this
+// type enqueue a request for the object itself. For may be called only once.
+func (blder *Builder) For(object client.Object, opts ...ForOption) *Builder {
+	if blder.forInput.object != nil {
+		blder.forInput.err = errors.New("cannot call For(...) more than once, could not assign multiple objects for reconciliation")
+		return blder
+	}
+	input := ForInput{object: object}
+	for _, opt := range opts {
+		opt.ApplyToFor(&input)
+	}
+	blder.forInput = input
+	return blder
+}
+
+// OwnsInput describes an owned type the controller watches.
+type OwnsInput struct {
+	object          client.Object
+	predicates      []predicate.Predicate
+	matchEveryOwner bool
+}
+
+// Owns watches objects of the given type and enqueues a request for their
+// controller owner if it has the type passed to For. With MatchEveryOwner
+// all owner references of that type are considered.
+func (blder *Builder) Owns(object client.Object, opts ...OwnsOption) *Builder {
+	input := OwnsInput{object: object}
+	for _, opt := range opts {
+		opt.ApplyToOwns(&input)
+	}
+	blder.ownsInput = append(blder.ownsInput, input)
+	return blder
+}
+
+// WatchesInput describes an additional watched type.
+type WatchesInput struct {
+	object       client.Object
+	eventHandler handler.EventHandler
+	predicates   []predicate.Predicate
+}
+
+// Watches watches objects of the given type and maps their events to
+// requests with eventHandler, for example handler.EnqueueRequestsFromMapFunc.
+func (blder *Builder) Watches(object client.Object, eventHandler handler.EventHandler, opts ...WatchesOption) *Builder {
+	input := WatchesInput{object: object, eventHandler: eventHandler}
+	for _, opt := range opts {
+		opt.ApplyToWatches(&input)
+	}
+	blder.watchesInput = append(blder.watchesInput, input)
+	return blder
+}
+
+// WatchesRawSource adds a source that is not backed by an informer, such as
+// source.Channel.
+func (blder *Builder) WatchesRawSource(src source.Source) *Builder {
+	blder.rawSources = append(blder.rawSources, src)
+	return blder
+}
+
+// WithEventFilter filters the events of all For, Owns and Watches types
+// with the predicate, for example predicate.GenerationChangedPredicate{} to
+// ignore status updates. Raw sources are not filtered.
+func (blder *Builder) WithEventFilter(p predicate.Predicate) *Builder {
+	blder.globalPreds = append(blder.globalPreds, p)
+	return blder
+}
+
+// Named sets the name of the controller. It defaults to the lowercased kind
+// of the type passed to For.
+func (blder *Builder) Named(name string) *Builder {
+	blder.name = name
+	return blder
+}
+
+// WithOptions overrides the controller options.
+func (blder *Builder) WithOptions(options controller.Options) *Builder {
+	blder.ctrlOptions = options
+	return blder
+}
+
+// Complete builds the controller and registers it with the manager.
+func (blder *Builder) Complete(r reconcile.Reconciler) error {
+	_, err := blder.Build(r)
+	return err
+}
+
+// Build builds the controller, registers it with the manager and returns it.
+func (blder *Builder) Build(r reconcile.Reconciler) (controller.Controller, error) {
+	if r == nil {
+		return nil, errors.New("must provide a non-nil Reconciler")
+	}
+	if blder.mgr == nil {
+		return nil, errors.New("must provide a non-nil Manager")
+	}
+	if blder.forInput.err != nil {
+		return nil, blder.forInput.err
+	}
+
+	if err := blder.doController(r); err != nil {
+		return nil, err
+	}
+	if err := blder.doWatch(); err != nil {
+		return nil, err
+	}
+	return blder.ctrl, nil
+}
+
+// doController creates the controller
+func (blder *Builder) doController(r reconcile.Reconciler) error {
+	name, err := blder.getControllerName()
+	if err != nil {
+		return err
+	}
+
+	options := blder.ctrlOptions
+	options.Reconciler = r
+	if options.Logger.GetSink() == nil {
+		options.Logger = blder.mgr.GetLogger()
+	}
+	if blder.forInput.object != nil {
+		gvk, err := manager.GVKForObject(blder.forInput.object, blder.mgr.GetScheme())
+		if err != nil {
+			return err
+		}
+		options.Logger = options.Logger.WithValues("controllerGroup", gvk.Group, "controllerKind", gvk.Kind)
+	}
+
+	blder.ctrl, err = controller.New(name, blder.mgr, options)
+	return err
+}
+
+// doWatch registers the sources of the For, Owns and Watches types
+func (blder *Builder) doWatch() error {
+	if blder.forInput.object != nil {
+		preds := append(append([]predicate.Predicate{}, blder.globalPreds...), blder.forInput.predicates...)
+		src := source.Kind(blder.mgr, blder.forInput.object, &handler.EnqueueRequestForObject{}, preds...)
+		if err := blder.ctrl.Watch(src); err != nil {
+			return err
+		}
+	}
+
+	if len(blder.ownsInput) > 0 && blder.forInput.object == nil {
+		return errors.New("cannot use Owns() without For()")
+	}
+	for _, own := range blder.ownsInput {
+		opts := []handler.OwnerOption{}
+		if !own.matchEveryOwner {
+			opts = append(opts, handler.OnlyControllerOwner())
+		}
+		h := handler.EnqueueRequestForOwner(blder.mgr.GetScheme(), blder.mgr.GetRESTMapper(), blder.forInput.object, opts...)
+		preds := append(append([]predicate.Predicate{}, blder.globalPreds...), own.predicates...)
+		if err := blder.ctrl.Watch(source.Kind(blder.mgr, own.object, h, preds...)); err != nil {
+			return err
+		}
+	}
+
+	for _, w := range blder.watchesInput {
+		if w.eventHandler == nil {
+			return fmt.Errorf("event handler passed to Watches() for %T must not be nil", w.object)
+		}
+		preds := append(append([]predicate.Predicate{}, blder.globalPreds...), w.predicates...)
+		if err := blder.ctrl.Watch(source.Kind(blder.mgr, w.object, w.eventHandler, preds...)); err != nil {
+			return err
+		}
+	}
+
+	for _, src := range blder.rawSources {
+		if err := blder.ctrl.Watch(src); err != nil {
+			return err
+		}
+	}
+
+	if blder.forInput.object == nil && len(blder.watchesInput) == 0 && len(blder.rawSources) == 0 {
+		return errors.New("there are no watches configured, controller will never get triggered. Use For(), Owns(), Watches() or WatchesRawSource() to set them up")
+	}
+	return nil
+}
+
+// getControllerName returns the explicit name or the lowercased kind of the For type
+func (blder *Builder) getControllerName() (string, error) {
+	if blder.name != "" {
+		return blder.name, nil
+	}
+	if blder.forInput.object == nil {
+		return "", errors.New("one of For() or Named() must be called")
+	}
+	gvk, err := manager.GVKForObject(blder.forInput.object, blder.mgr.GetScheme())
+	if err != nil {
+		return "", err
+	}
+	return strings.ToLower(gvk.Kind), nil
+}
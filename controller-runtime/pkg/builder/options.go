@@ -0,0 +1,61 @@
+package builder
+
+import (
+	"github.com/dtomasi/k1s/controller-runtime/pkg/predicate"
+)
+
+// ForOption configures the For type of a builder.
+type ForOption interface {
+	ApplyToFor(*ForInput)
+}
+
+// OwnsOption configures an owned type of a builder.
+type OwnsOption interface {
+	ApplyToOwns(*OwnsInput)
+}
+
+// WatchesOption configures a watched type of a builder.
+type WatchesOption interface {
+	ApplyToWatches(*WatchesInput)
+}
+
+// MatchEveryOwner enqueues requests for every owner reference of the For
+// type instead of only the controller owner.
+var MatchEveryOwner = &matchEveryOwner{}
+
+type matchEveryOwner struct{}
+
+// ApplyToOwns implements OwnsOption.
+func (o matchEveryOwner) ApplyToOwns(opts *OwnsInput) {
+	opts.matchEveryOwner = true
+}
+
+// WithPredicates filters the events of a For, Owns or Watches type with the
+// given predicates, in addition to those set with WithEventFilter.
+func WithPredicates(predicates ...predicate.Predicate) Predicates {
+	return Predicates{predicates: predicates}
+}
+
+// Predicates filters the events of a watched type.
+type Predicates struct {
+	predicates []predicate.Predicate
+}
+
+// ApplyToFor implements ForOption.
+func (w Predicates) ApplyToFor(opts *ForInput) {
+	opts.predicates = w.predicates
+}
+
+// ApplyToOwns implements OwnsOption.
+func (w Predicates) ApplyToOwns(opts *OwnsInput) {
+	opts.predicates = w.predicates
+}
+
+// ApplyToWatches implements WatchesOption.
+func (w Predicates) ApplyToWatches(opts *WatchesInput) {
+	opts.predicates = w.predicates
+}
+
+var _ ForOption = Predicates{}
+var _ OwnsOption = Predicates{}
+var _ WatchesOption = Predicates{}
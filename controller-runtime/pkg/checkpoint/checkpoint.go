@@ -0,0 +1,106 @@
+// Package checkpoint persists the reconcile state of controllers between
+// invocations of short-lived processes such as CLI commands.
+//
+// A checkpoint records the requests a controller had not finished when it
+// stopped, the time their delayed requeues are due, and the highest
+// resourceVersion its sources observed. A controller configured with a Store
+// loads its checkpoint on start, re-queues the pending requests and skips
+// objects that have not changed since the recorded resourceVersion, and saves
+// a new checkpoint when it stops.
+//
+// Deletions that happen while no process is running are not detected on
+// resume, as the informers only list the objects that still exist.
+// Reconcilers that must react to deletions should use finalizers.
+package checkpoint
+
+import (
+	"context"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// SchemeGroupVersion is the group version of the internal checkpoint resource
+var SchemeGroupVersion = schema.GroupVersion{Group: "internal.k1s.io", Version: "v1"}
+
+// Kind is the kind of the internal checkpoint resource
+const Kind = "ControllerCheckpoint"
+
+// Checkpoint is the persisted reconcile state of a controller. Its name is
+// the name of the controller.
+type Checkpoint struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	// LastObservedResourceVersion is the highest resourceVersion the
+	// controller's sources delivered before it stopped.
+	LastObservedResourceVersion string `json:"lastObservedResourceVersion,omitempty"`
+
+	// Requests are the requests the controller had not finished.
+	Requests []PendingRequest `json:"requests,omitempty"`
+}
+
+// PendingRequest is a reconcile request that was pending when a controller stopped.
+type PendingRequest struct {
+	Namespace string `json:"namespace,omitempty"`
+	Name      string `json:"name"`
+
+	// NotBefore is when a delayed requeue of the request is due. Requests
+	// without it are reconciled as soon as the controller starts.
+	NotBefore *metav1.MicroTime `json:"notBefore,omitempty"`
+}
+
+// New returns an empty checkpoint for the named controller.
+func New(controllerName string) *Checkpoint {
+	return &Checkpoint{
+		TypeMeta:   metav1.TypeMeta{APIVersion: SchemeGroupVersion.String(), Kind: Kind},
+		ObjectMeta: metav1.ObjectMeta{Name: controllerName},
+	}
+}
+
+// DeepCopyInto copies the receiver into out
+func (c *Checkpoint) DeepCopyInto(out *Checkpoint) {
+	*out = *c
+	out.TypeMeta = c.TypeMeta
+	c.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	if c.Requests != nil {
+		out.Requests = make([]PendingRequest, len(c.Requests))
+		for i := range c.Requests {
+			c.Requests[i].DeepCopyInto(&out.Requests[i])
+		}
+	}
+}
+
+// DeepCopy returns a deep copy of the checkpoint
+func (c *Checkpoint) DeepCopy() *Checkpoint {
+	if c == nil {
+		return nil
+	}
+	out := new(Checkpoint)
+	c.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject implements runtime.Object
+func (c *Checkpoint) DeepCopyObject() runtime.Object {
+	return c.DeepCopy()
+}
+
+// DeepCopyInto copies the receiver into out
+func (r *PendingRequest) DeepCopyInto(out *PendingRequest) {
+	*out = *r
+	if r.NotBefore != nil {
+		out.NotBefore = r.NotBefore.DeepCopy()
+	}
+}
+
+// Store loads and saves controller checkpoints.
+type Store interface {
+	// Load returns the checkpoint of the named controller, or nil if the
+	// controller has none.
+	Load(ctx context.Context, controllerName string) (*Checkpoint, error)
+
+	// Save stores the checkpoint, replacing the previous one of the controller.
+	Save(ctx context.Context, checkpoint *Checkpoint) error
+}
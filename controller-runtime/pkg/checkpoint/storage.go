@@ -0,0 +1,68 @@
+package checkpoint
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	apistorage "k8s.io/apiserver/pkg/storage"
+
+	k1sstorage "github.com/dtomasi/k1s/core/storage"
+)
+
+// resource is the storage resource of checkpoints. Client lists use the keys
+// of registered resources only, so checkpoints stay hidden from them.
+const resource = "controllercheckpoints"
+
+// storageStore keeps checkpoints in a storage backend
+type storageStore struct {
+	storage k1sstorage.Interface
+}
+
+// NewStorageStore returns a Store that keeps checkpoints as an internal
+// resource in the given storage backend, next to the objects the controllers
+// reconcile.
+func NewStorageStore(storage k1sstorage.Interface) Store {
+	return &storageStore{storage: storage}
+}
+
+// Load returns the stored checkpoint of the named controller
+func (s *storageStore) Load(ctx context.Context, controllerName string) (*Checkpoint, error) {
+	checkpoint := &Checkpoint{}
+	err := s.storage.Get(ctx, storageKey(controllerName), apistorage.GetOptions{IgnoreNotFound: true}, checkpoint)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load checkpoint of controller %q: %w", controllerName, err)
+	}
+	if checkpoint.Name == "" {
+		return nil, nil
+	}
+	return checkpoint, nil
+}
+
+// Save creates or replaces the stored checkpoint of a controller
+func (s *storageStore) Save(ctx context.Context, checkpoint *Checkpoint) error {
+	if checkpoint == nil || checkpoint.Name == "" {
+		return errors.New("checkpoint must have a controller name")
+	}
+
+	err := s.storage.GuaranteedUpdate(ctx, storageKey(checkpoint.Name), &Checkpoint{}, true, nil,
+		func(existing runtime.Object, _ apistorage.ResponseMeta) (runtime.Object, *uint64, error) {
+			updated := checkpoint.DeepCopy()
+			updated.APIVersion = SchemeGroupVersion.String()
+			updated.Kind = Kind
+			if current, ok := existing.(*Checkpoint); ok {
+				updated.ResourceVersion = current.ResourceVersion
+			}
+			return updated, nil, nil
+		}, nil)
+	if err != nil {
+		return fmt.Errorf("failed to save checkpoint of controller %q: %w", checkpoint.Name, err)
+	}
+	return nil
+}
+
+// storageKey returns the storage key of a controller's checkpoint
+func storageKey(controllerName string) string {
+	return fmt.Sprintf("/%s/%s/%s/%s", SchemeGroupVersion.Group, SchemeGroupVersion.Version, resource, controllerName)
+}
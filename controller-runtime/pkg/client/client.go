@@ -0,0 +1,85 @@
+// Package client re-exports the k1s client under the import path kubebuilder
+// projects use for sigs.k8s.io/controller-runtime/pkg/client. The list
+// options are declared with the same underlying types as controller-runtime,
+// so literals like client.InNamespace("default") and
+// client.MatchingLabels{"app": "x"} keep compiling.
+package client
+
+import (
+	coreclient "github.com/dtomasi/k1s/core/client"
+)
+
+// Client, Reader, Writer and the object types are the k1s client types.
+type (
+	Client       = coreclient.Client
+	Reader       = coreclient.Reader
+	Writer       = coreclient.Writer
+	StatusClient = coreclient.StatusClient
+	StatusWriter = coreclient.StatusWriter
+	WithWatch    = coreclient.WithWatch
+	Object       = coreclient.Object
+	ObjectList   = coreclient.ObjectList
+	ObjectKey    = coreclient.ObjectKey
+	Patch        = coreclient.Patch
+)
+
+// Option types accepted by the client.
+type (
+	GetOption    = coreclient.GetOption
+	ListOption   = coreclient.ListOption
+	CreateOption = coreclient.CreateOption
+	UpdateOption = coreclient.UpdateOption
+	DeleteOption = coreclient.DeleteOption
+	PatchOption  = coreclient.PatchOption
+	WatchOption  = coreclient.WatchOption
+)
+
+var (
+	// ObjectKeyFromObject returns the ObjectKey of an object.
+	ObjectKeyFromObject = coreclient.ObjectKeyFromObject
+
+	// IgnoreNotFound returns nil on NotFound errors.
+	IgnoreNotFound = coreclient.IgnoreNotFound
+
+	// MergeFrom creates a JSON merge patch against the given original object.
+	MergeFrom = coreclient.MergeFrom
+)
+
+// InNamespace restricts list and watch operations to a namespace.
+type InNamespace string
+
+// ApplyToList implements ListOption.
+func (n InNamespace) ApplyToList(opts *coreclient.ListOptions) {
+	coreclient.InNamespace(string(n)).ApplyToList(opts)
+}
+
+// ApplyToWatch implements WatchOption.
+func (n InNamespace) ApplyToWatch(opts *coreclient.WatchOptions) {
+	coreclient.InNamespace(string(n)).ApplyToWatch(opts)
+}
+
+// MatchingLabels filters list and watch operations by labels.
+type MatchingLabels map[string]string
+
+// ApplyToList implements ListOption.
+func (m MatchingLabels) ApplyToList(opts *coreclient.ListOptions) {
+	coreclient.MatchingLabels(m).ApplyToList(opts)
+}
+
+// ApplyToWatch implements WatchOption.
+func (m MatchingLabels) ApplyToWatch(opts *coreclient.WatchOptions) {
+	coreclient.MatchingLabels(m).ApplyToWatch(opts)
+}
+
+// MatchingFields filters list and watch operations by fields.
+type MatchingFields map[string]string
+
+// ApplyToList implements ListOption.
+func (m MatchingFields) ApplyToList(opts *coreclient.ListOptions) {
+	coreclient.MatchingFields(m).ApplyToList(opts)
+}
+
+// ApplyToWatch implements WatchOption.
+func (m MatchingFields) ApplyToWatch(opts *coreclient.WatchOptions) {
+	coreclient.MatchingFields(m).ApplyToWatch(opts)
+}
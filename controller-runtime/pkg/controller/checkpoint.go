@@ -0,0 +1,120 @@
+package controller
+
+import (
+	"context"
+	"sort"
+	"strconv"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/dtomasi/k1s/controller-runtime/pkg/checkpoint"
+	"github.com/dtomasi/k1s/controller-runtime/pkg/reconcile"
+	"github.com/dtomasi/k1s/controller-runtime/pkg/source"
+)
+
+// restoreCheckpoint re-queues the pending requests of the stored checkpoint
+// and returns the context to start the sources with, which skips objects
+// that have not changed since the checkpoint's resourceVersion
+func (c *controller) restoreCheckpoint(ctx context.Context) (context.Context, error) {
+	if c.checkpoints == nil {
+		return ctx, nil
+	}
+
+	cp, err := c.checkpoints.Load(ctx, c.name)
+	if err != nil || cp == nil {
+		return ctx, err
+	}
+
+	now := time.Now()
+	for _, pending := range cp.Requests {
+		req := reconcile.Request{NamespacedName: reconcile.NamespacedName{Namespace: pending.Namespace, Name: pending.Name}}
+		if pending.NotBefore != nil && pending.NotBefore.After(now) {
+			c.requeueAfter(req, pending.NotBefore.Sub(now))
+			continue
+		}
+		c.queue.Add(req)
+	}
+
+	c.logger.Info("Resuming from checkpoint", "requests", len(cp.Requests),
+		"resourceVersion", cp.LastObservedResourceVersion)
+
+	c.lastObserved = cp.LastObservedResourceVersion
+	if cp.LastObservedResourceVersion != "" {
+		ctx = source.WithChangedSince(ctx, cp.LastObservedResourceVersion)
+	}
+	return ctx, nil
+}
+
+// keepPending remembers a request that was not reconciled before shutdown
+func (c *controller) keepPending(req reconcile.Request) {
+	c.stateMu.Lock()
+	defer c.stateMu.Unlock()
+
+	if c.pending == nil {
+		c.pending = make(map[reconcile.Request]struct{})
+	}
+	c.pending[req] = struct{}{}
+}
+
+// saveCheckpoint stores the unfinished requests and the last observed resourceVersion
+func (c *controller) saveCheckpoint(ctx context.Context) error {
+	if c.checkpoints == nil {
+		return nil
+	}
+
+	cp := checkpoint.New(c.name)
+	cp.LastObservedResourceVersion = c.lastObservedResourceVersion()
+
+	c.stateMu.Lock()
+	for req := range c.pending {
+		cp.Requests = append(cp.Requests, checkpoint.PendingRequest{Namespace: req.Namespace, Name: req.Name})
+	}
+	for req, due := range c.delayed {
+		if _, ok := c.pending[req]; ok {
+			continue
+		}
+		notBefore := metav1.NewMicroTime(due)
+		cp.Requests = append(cp.Requests, checkpoint.PendingRequest{Namespace: req.Namespace, Name: req.Name, NotBefore: &notBefore})
+	}
+	c.stateMu.Unlock()
+
+	sort.Slice(cp.Requests, func(i, j int) bool {
+		if cp.Requests[i].Namespace != cp.Requests[j].Namespace {
+			return cp.Requests[i].Namespace < cp.Requests[j].Namespace
+		}
+		return cp.Requests[i].Name < cp.Requests[j].Name
+	})
+
+	if err := c.checkpoints.Save(ctx, cp); err != nil {
+		return err
+	}
+	c.logger.Info("Saved checkpoint", "requests", len(cp.Requests),
+		"resourceVersion", cp.LastObservedResourceVersion)
+	return nil
+}
+
+// lastObservedResourceVersion returns the highest resourceVersion observed by
+// the sources of this or a previous run
+func (c *controller) lastObservedResourceVersion() string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	last, lastValue := c.lastObserved, parseResourceVersion(c.lastObserved)
+	for _, observer := range c.observers {
+		rv := observer.LastObservedResourceVersion()
+		if value := parseResourceVersion(rv); value > lastValue {
+			last, lastValue = rv, value
+		}
+	}
+	return last
+}
+
+// parseResourceVersion returns the numeric value of a resourceVersion, or 0
+func parseResourceVersion(resourceVersion string) uint64 {
+	rv, err := strconv.ParseUint(resourceVersion, 10, 64)
+	if err != nil {
+		return 0
+	}
+	return rv
+}
@@ -0,0 +1,386 @@
+// Package controller implements controllers that reconcile objects in
+// response to events from their watched sources. It mirrors
+// sigs.k8s.io/controller-runtime/pkg/controller.
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/go-logr/logr"
+	"k8s.io/client-go/util/workqueue"
+
+	"github.com/dtomasi/k1s/controller-runtime/pkg/checkpoint"
+	logf "github.com/dtomasi/k1s/controller-runtime/pkg/log"
+	"github.com/dtomasi/k1s/controller-runtime/pkg/manager"
+	"github.com/dtomasi/k1s/controller-runtime/pkg/reconcile"
+	"github.com/dtomasi/k1s/controller-runtime/pkg/source"
+)
+
+// DefaultCacheSyncTimeout is how long a controller waits for its sources to sync
+const DefaultCacheSyncTimeout = 2 * time.Minute
+
+// Controller reconciles the requests enqueued by its watched sources.
+type Controller interface {
+	// Reconciler is called for each request taken from the workqueue.
+	reconcile.Reconciler
+
+	// Watch starts delivering the events of src to the workqueue. Sources
+	// added before Start are started together with the controller.
+	Watch(src source.Source) error
+
+	// Start starts the sources and workers and blocks until ctx is done.
+	Start(ctx context.Context) error
+
+	// GetLogger returns the logger of the controller.
+	GetLogger() logr.Logger
+}
+
+// Options configures a controller.
+type Options struct {
+	// MaxConcurrentReconciles is the number of parallel workers. Defaults to 1.
+	MaxConcurrentReconciles int
+
+	// RateLimiter limits how fast failed requests are retried. Defaults to
+	// workqueue.DefaultTypedControllerRateLimiter.
+	RateLimiter workqueue.TypedRateLimiter[reconcile.Request]
+
+	// Reconciler reconciles the enqueued requests. Required.
+	Reconciler reconcile.Reconciler
+
+	// CacheSyncTimeout limits how long Start waits for the sources to sync.
+	// Defaults to DefaultCacheSyncTimeout.
+	CacheSyncTimeout time.Duration
+
+	// RecoverPanic converts panics in the reconciler into errors. Defaults to true.
+	RecoverPanic *bool
+
+	// Logger is the base logger of the controller. Defaults to the manager's logger.
+	Logger logr.Logger
+
+	// Checkpoints persists the requests the controller has not finished and
+	// the last resourceVersion its sources observed when it stops, and
+	// resumes from them when it starts again. Defaults to the manager's
+	// checkpoint store. Controllers without a store start from scratch.
+	Checkpoints checkpoint.Store
+}
+
+// New creates a controller and registers it with the manager, which starts
+// it together with its other runnables.
+func New(name string, mgr manager.Manager, options Options) (Controller, error) {
+	if options.Logger.GetSink() == nil {
+		options.Logger = mgr.GetLogger()
+	}
+	if options.Checkpoints == nil {
+		options.Checkpoints = mgr.GetCheckpointStore()
+	}
+
+	c, err := NewUnmanaged(name, options)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := mgr.Add(c); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// NewUnmanaged creates a controller that must be started by the caller.
+func NewUnmanaged(name string, options Options) (Controller, error) {
+	if name == "" {
+		return nil, errors.New("must specify name of controller")
+	}
+	if options.Reconciler == nil {
+		return nil, errors.New("must specify Reconciler")
+	}
+
+	if options.MaxConcurrentReconciles <= 0 {
+		options.MaxConcurrentReconciles = 1
+	}
+	if options.RateLimiter == nil {
+		options.RateLimiter = workqueue.DefaultTypedControllerRateLimiter[reconcile.Request]()
+	}
+	if options.CacheSyncTimeout <= 0 {
+		options.CacheSyncTimeout = DefaultCacheSyncTimeout
+	}
+	if options.RecoverPanic == nil {
+		recoverPanic := true
+		options.RecoverPanic = &recoverPanic
+	}
+	if options.Logger.GetSink() == nil {
+		options.Logger = logf.Log
+	}
+
+	return &controller{
+		name:                    name,
+		do:                      options.Reconciler,
+		maxConcurrentReconciles: options.MaxConcurrentReconciles,
+		rateLimiter:             options.RateLimiter,
+		cacheSyncTimeout:        options.CacheSyncTimeout,
+		recoverPanic:            *options.RecoverPanic,
+		logger:                  options.Logger.WithValues("controller", name),
+		checkpoints:             options.Checkpoints,
+	}, nil
+}
+
+var _ manager.Quiescer = &controller{}
+
+// controller implements Controller with a rate-limited workqueue
+type controller struct {
+	name                    string
+	do                      reconcile.Reconciler
+	maxConcurrentReconciles int
+	rateLimiter             workqueue.TypedRateLimiter[reconcile.Request]
+	cacheSyncTimeout        time.Duration
+	recoverPanic            bool
+	logger                  logr.Logger
+	checkpoints             checkpoint.Store
+
+	mu           sync.Mutex
+	started      bool
+	ctx          context.Context
+	queue        workqueue.TypedRateLimitingInterface[reconcile.Request]
+	watches      []source.Source
+	workers      sync.WaitGroup
+	observers    []source.ResourceVersionObserver
+	lastObserved string
+
+	// stateMu protects the work tracking used to detect quiescence
+	stateMu  sync.Mutex
+	synced   bool
+	inFlight int
+	delayed  map[reconcile.Request]time.Time
+	pending  map[reconcile.Request]struct{}
+}
+
+// Reconcile calls the reconciler, converting panics into errors if configured
+func (c *controller) Reconcile(ctx context.Context, req reconcile.Request) (_ reconcile.Result, err error) {
+	if c.recoverPanic {
+		defer func() {
+			if r := recover(); r != nil {
+				err = fmt.Errorf("panic: %v [recovered]", r)
+			}
+		}()
+	}
+	return c.do.Reconcile(ctx, req)
+}
+
+// Watch starts the source if the controller is running, or remembers it for Start
+func (c *controller) Watch(src source.Source) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if !c.started {
+		c.watches = append(c.watches, src)
+		return nil
+	}
+
+	c.logger.Info("Starting EventSource", "source", src)
+	if observer, ok := src.(source.ResourceVersionObserver); ok {
+		c.observers = append(c.observers, observer)
+	}
+	return src.Start(c.ctx, c.queue)
+}
+
+// GetLogger returns the logger of the controller
+func (c *controller) GetLogger() logr.Logger {
+	return c.logger
+}
+
+// Start starts the sources, waits for them to sync and processes the queue
+// until ctx is done. In-flight reconciles finish before Start returns.
+func (c *controller) Start(ctx context.Context) error {
+	c.mu.Lock()
+	if c.started {
+		c.mu.Unlock()
+		return errors.New("controller was started more than once. This is likely to be caused by being added to a manager multiple times")
+	}
+
+	c.queue = workqueue.NewTypedRateLimitingQueueWithConfig(c.rateLimiter,
+		workqueue.TypedRateLimitingQueueConfig[reconcile.Request]{Name: c.name})
+	go func() {
+		<-ctx.Done()
+		c.queue.ShutDown()
+	}()
+
+	sourceCtx, err := c.restoreCheckpoint(ctx)
+	if err != nil {
+		c.mu.Unlock()
+		return err
+	}
+	c.ctx = sourceCtx
+
+	if err := c.startWatches(sourceCtx); err != nil {
+		c.mu.Unlock()
+		return err
+	}
+
+	c.stateMu.Lock()
+	c.synced = true
+	c.stateMu.Unlock()
+
+	c.logger.Info("Starting workers", "worker count", c.maxConcurrentReconciles)
+	c.workers.Add(c.maxConcurrentReconciles)
+	for i := 0; i < c.maxConcurrentReconciles; i++ {
+		go func() {
+			defer c.workers.Done()
+			for c.processNextWorkItem(ctx) {
+			}
+		}()
+	}
+	c.started = true
+	c.mu.Unlock()
+
+	<-ctx.Done()
+	c.logger.Info("Shutdown signal received, waiting for all workers to finish")
+	c.workers.Wait()
+	c.logger.Info("All workers finished")
+
+	// The context is done, but the checkpoint must still be written
+	return c.saveCheckpoint(context.WithoutCancel(ctx))
+}
+
+// startWatches starts all sources and waits for the syncing ones
+func (c *controller) startWatches(ctx context.Context) error {
+	c.logger.Info("Starting Controller")
+
+	for _, src := range c.watches {
+		c.logger.Info("Starting EventSource", "source", src)
+		if err := src.Start(ctx, c.queue); err != nil {
+			return err
+		}
+		if observer, ok := src.(source.ResourceVersionObserver); ok {
+			c.observers = append(c.observers, observer)
+		}
+	}
+
+	for _, src := range c.watches {
+		syncing, ok := src.(source.SyncingSource)
+		if !ok {
+			continue
+		}
+
+		syncCtx, cancel := context.WithTimeout(ctx, c.cacheSyncTimeout)
+		err := syncing.WaitForSync(syncCtx)
+		cancel()
+		if err != nil {
+			c.logger.Error(err, "Could not wait for Cache to sync")
+			return fmt.Errorf("failed to wait for %s caches to sync: %w", c.name, err)
+		}
+	}
+
+	c.watches = nil
+	return nil
+}
+
+// processNextWorkItem reconciles one request and returns false once the queue is shut down
+func (c *controller) processNextWorkItem(ctx context.Context) bool {
+	req, shutdown := c.queue.Get()
+	if shutdown {
+		return false
+	}
+
+	// Requests left in the queue at shutdown are kept for the next run
+	// instead of being reconciled with a cancelled context
+	if c.checkpoints != nil && ctx.Err() != nil {
+		c.keepPending(req)
+		c.queue.Done(req)
+		return true
+	}
+
+	c.startWork(req)
+	defer c.finishWork()
+	defer c.queue.Done(req)
+
+	c.reconcileHandler(ctx, req)
+	return true
+}
+
+// reconcileHandler reconciles req and requeues it according to the result
+func (c *controller) reconcileHandler(ctx context.Context, req reconcile.Request) {
+	log := c.logger.WithValues("namespace", req.Namespace, "name", req.Name)
+	ctx = logr.NewContext(ctx, log)
+
+	result, err := c.Reconcile(ctx, req)
+	switch {
+	case err != nil:
+		if errors.Is(err, reconcile.TerminalError(nil)) {
+			c.queue.Forget(req)
+		} else {
+			c.requeueAfter(req, c.rateLimiter.When(req))
+		}
+		log.Error(err, "Reconciler error")
+	case result.RequeueAfter > 0:
+		// Requeue after the requested delay without increasing the backoff
+		c.queue.Forget(req)
+		c.requeueAfter(req, result.RequeueAfter)
+	case result.Requeue:
+		c.requeueAfter(req, c.rateLimiter.When(req))
+	default:
+		c.queue.Forget(req)
+	}
+}
+
+// requeueAfter adds req to the queue after the delay and remembers when it is due
+func (c *controller) requeueAfter(req reconcile.Request, delay time.Duration) {
+	c.stateMu.Lock()
+	if c.delayed == nil {
+		c.delayed = make(map[reconcile.Request]time.Time)
+	}
+	due := time.Now().Add(delay)
+	if current, ok := c.delayed[req]; !ok || due.Before(current) {
+		c.delayed[req] = due
+	}
+	c.stateMu.Unlock()
+
+	c.queue.AddAfter(req, delay)
+}
+
+// startWork marks req as in flight and drops its delayed requeue once it is due
+func (c *controller) startWork(req reconcile.Request) {
+	c.stateMu.Lock()
+	defer c.stateMu.Unlock()
+
+	c.inFlight++
+	if due, ok := c.delayed[req]; ok && !due.After(time.Now()) {
+		delete(c.delayed, req)
+	}
+}
+
+// finishWork marks a request as done
+func (c *controller) finishWork() {
+	c.stateMu.Lock()
+	defer c.stateMu.Unlock()
+	c.inFlight--
+}
+
+// Quiescent reports whether the controller has synced its sources and has
+// neither queued nor in-flight requests, and returns when the next delayed
+// requeue is due, or the zero time if there is none.
+func (c *controller) Quiescent() (bool, time.Time) {
+	c.stateMu.Lock()
+	defer c.stateMu.Unlock()
+
+	if !c.synced || c.inFlight > 0 {
+		return false, time.Time{}
+	}
+
+	var next time.Time
+	now := time.Now()
+	for _, due := range c.delayed {
+		// A due requeue may not have reached the queue yet
+		if !due.After(now) {
+			return false, time.Time{}
+		}
+		if next.IsZero() || due.Before(next) {
+			next = due
+		}
+	}
+
+	// The queue is set before the controller is marked as synced
+	return c.queue.Len() == 0, next
+}
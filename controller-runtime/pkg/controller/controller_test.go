@@ -0,0 +1,121 @@
+package controller_test
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/dtomasi/k1s/controller-runtime/pkg/controller"
+	"github.com/dtomasi/k1s/controller-runtime/pkg/event"
+	"github.com/dtomasi/k1s/controller-runtime/pkg/handler"
+	"github.com/dtomasi/k1s/controller-runtime/pkg/reconcile"
+	"github.com/dtomasi/k1s/controller-runtime/pkg/source"
+)
+
+var _ = Describe("Controller", func() {
+	var (
+		ctx    context.Context
+		cancel context.CancelFunc
+		events chan event.GenericEvent
+		calls  atomic.Int32
+		done   chan error
+	)
+
+	BeforeEach(func() {
+		ctx, cancel = context.WithCancel(context.Background())
+		events = make(chan event.GenericEvent, 1)
+		calls.Store(0)
+		done = make(chan error, 1)
+	})
+
+	AfterEach(func() {
+		cancel()
+		Eventually(done).Should(Receive(BeNil()))
+	})
+
+	// run starts a controller with the reconciler and triggers one request
+	run := func(r reconcile.Func) {
+		c, err := controller.NewUnmanaged("test", controller.Options{Reconciler: r})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(c.Watch(source.Channel(events, &handler.EnqueueRequestForObject{}))).To(Succeed())
+		go func() { done <- c.Start(ctx) }()
+
+		events <- event.GenericEvent{Object: &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "a", Namespace: "ns"}}}
+	}
+
+	It("should pass the request to the reconciler", func() {
+		received := make(chan reconcile.Request, 1)
+		run(func(_ context.Context, req reconcile.Request) (reconcile.Result, error) {
+			received <- req
+			return reconcile.Result{}, nil
+		})
+
+		var req reconcile.Request
+		Eventually(received).Should(Receive(&req))
+		Expect(req.Namespace).To(Equal("ns"))
+		Expect(req.Name).To(Equal("a"))
+	})
+
+	It("should retry failed requests", func() {
+		run(func(context.Context, reconcile.Request) (reconcile.Result, error) {
+			if calls.Add(1) < 3 {
+				return reconcile.Result{}, errors.New("transient")
+			}
+			return reconcile.Result{}, nil
+		})
+
+		Eventually(calls.Load).Should(BeNumerically("==", 3))
+		Consistently(calls.Load, 100*time.Millisecond).Should(BeNumerically("==", 3))
+	})
+
+	It("should not retry terminal errors", func() {
+		run(func(context.Context, reconcile.Request) (reconcile.Result, error) {
+			calls.Add(1)
+			return reconcile.Result{}, reconcile.TerminalError(errors.New("permanent"))
+		})
+
+		Eventually(calls.Load).Should(BeNumerically("==", 1))
+		Consistently(calls.Load, 100*time.Millisecond).Should(BeNumerically("==", 1))
+	})
+
+	It("should requeue after the requested delay", func() {
+		started := time.Now()
+		var second atomic.Int64
+		run(func(context.Context, reconcile.Request) (reconcile.Result, error) {
+			if calls.Add(1) == 1 {
+				return reconcile.Result{RequeueAfter: 50 * time.Millisecond}, nil
+			}
+			second.Store(int64(time.Since(started)))
+			return reconcile.Result{}, nil
+		})
+
+		Eventually(calls.Load).Should(BeNumerically("==", 2))
+		Expect(time.Duration(second.Load())).To(BeNumerically(">=", 50*time.Millisecond))
+	})
+
+	It("should recover panics in the reconciler", func() {
+		run(func(context.Context, reconcile.Request) (reconcile.Result, error) {
+			if calls.Add(1) == 1 {
+				panic("boom")
+			}
+			return reconcile.Result{}, nil
+		})
+
+		Eventually(calls.Load).Should(BeNumerically("==", 2))
+	})
+
+	It("should reject a missing reconciler or name", func() {
+		_, err := controller.NewUnmanaged("test", controller.Options{})
+		Expect(err).To(HaveOccurred())
+		_, err = controller.NewUnmanaged("", controller.Options{Reconciler: reconcile.Func(nil)})
+		Expect(err).To(HaveOccurred())
+		done <- nil
+	})
+})
@@ -0,0 +1,260 @@
+// Package controllerutil contains helpers for reconcilers: owner references,
+// finalizers and CreateOrUpdate. It mirrors
+// sigs.k8s.io/controller-runtime/pkg/controller/controllerutil.
+package controllerutil
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/utils/ptr"
+
+	"github.com/dtomasi/k1s/core/client"
+)
+
+// AlreadyOwnedError is returned if the object already has another controller.
+type AlreadyOwnedError struct {
+	Object metav1.Object
+	Owner  metav1.OwnerReference
+}
+
+// Error returns the error message
+func (e *AlreadyOwnedError) Error() string {
+	return fmt.Sprintf("object %s/%s is already owned by another %s controller %s",
+		e.Object.GetNamespace(), e.Object.GetName(), e.Owner.Kind, e.Owner.Name)
+}
+
+// SetControllerReference sets owner as the controller owner reference of
+// controlled. It fails if controlled already has a different controller.
+// Cross-namespace ownership is rejected.
+func SetControllerReference(owner, controlled metav1.Object, scheme *runtime.Scheme) error {
+	ref, err := ownerReference(owner, controlled, scheme)
+	if err != nil {
+		return err
+	}
+	ref.Controller = ptr.To(true)
+	ref.BlockOwnerDeletion = ptr.To(true)
+
+	if existing := metav1.GetControllerOf(controlled); existing != nil && !referSameObject(*existing, ref) {
+		return &AlreadyOwnedError{Object: controlled, Owner: *existing}
+	}
+
+	upsertOwnerRef(ref, controlled)
+	return nil
+}
+
+// SetOwnerReference adds owner as a non-controller owner reference of object.
+func SetOwnerReference(owner, object metav1.Object, scheme *runtime.Scheme) error {
+	ref, err := ownerReference(owner, object, scheme)
+	if err != nil {
+		return err
+	}
+	upsertOwnerRef(ref, object)
+	return nil
+}
+
+// RemoveOwnerReference removes the owner reference of owner from object.
+func RemoveOwnerReference(owner, object metav1.Object, scheme *runtime.Scheme) error {
+	ref, err := ownerReference(owner, object, scheme)
+	if err != nil {
+		return err
+	}
+
+	refs := object.GetOwnerReferences()
+	kept := make([]metav1.OwnerReference, 0, len(refs))
+	for _, existing := range refs {
+		if !referSameObject(existing, ref) {
+			kept = append(kept, existing)
+		}
+	}
+	if len(kept) == len(refs) {
+		return fmt.Errorf("%T does not have an owner reference for %s %s", object, ref.Kind, ref.Name)
+	}
+	object.SetOwnerReferences(kept)
+	return nil
+}
+
+// HasControllerReference returns true if object has a controller owner reference.
+func HasControllerReference(object metav1.Object) bool {
+	return metav1.GetControllerOfNoCopy(object) != nil
+}
+
+// ownerReference builds an owner reference to owner that may be set on object
+func ownerReference(owner, object metav1.Object, scheme *runtime.Scheme) (metav1.OwnerReference, error) {
+	ro, ok := owner.(runtime.Object)
+	if !ok {
+		return metav1.OwnerReference{}, fmt.Errorf("%T is not a runtime.Object, cannot call SetOwnerReference", owner)
+	}
+	if err := validateOwner(owner, object); err != nil {
+		return metav1.OwnerReference{}, err
+	}
+
+	gvk, err := gvkForObject(ro, scheme)
+	if err != nil {
+		return metav1.OwnerReference{}, err
+	}
+
+	return metav1.OwnerReference{
+		APIVersion: gvk.GroupVersion().String(),
+		Kind:       gvk.Kind,
+		Name:       owner.GetName(),
+		UID:        owner.GetUID(),
+	}, nil
+}
+
+// gvkForObject returns the kind of obj from its type meta or the scheme
+func gvkForObject(obj runtime.Object, scheme *runtime.Scheme) (schema.GroupVersionKind, error) {
+	if gvk := obj.GetObjectKind().GroupVersionKind(); !gvk.Empty() {
+		return gvk, nil
+	}
+	if scheme == nil {
+		return schema.GroupVersionKind{}, fmt.Errorf("cannot determine the kind of %T without a scheme", obj)
+	}
+	kinds, _, err := scheme.ObjectKinds(obj)
+	if err != nil {
+		return schema.GroupVersionKind{}, err
+	}
+	return kinds[0], nil
+}
+
+// validateOwner rejects owner references that cannot be resolved from object
+func validateOwner(owner, object metav1.Object) error {
+	ownerNs := owner.GetNamespace()
+	if ownerNs == "" {
+		return nil
+	}
+	objNs := object.GetNamespace()
+	if objNs == "" {
+		return fmt.Errorf("cluster-scoped resource must not have a namespace-scoped owner, owner's namespace %s", ownerNs)
+	}
+	if ownerNs != objNs {
+		return fmt.Errorf("cross-namespace owner references are disallowed, owner's namespace %s, obj's namespace %s", ownerNs, objNs)
+	}
+	return nil
+}
+
+// upsertOwnerRef replaces an existing reference to the same owner or appends ref
+func upsertOwnerRef(ref metav1.OwnerReference, object metav1.Object) {
+	refs := object.GetOwnerReferences()
+	for i, existing := range refs {
+		if referSameObject(existing, ref) {
+			refs[i] = ref
+			object.SetOwnerReferences(refs)
+			return
+		}
+	}
+	object.SetOwnerReferences(append(refs, ref))
+}
+
+// referSameObject returns true if both references point to the same object
+func referSameObject(a, b metav1.OwnerReference) bool {
+	aGV, err := schema.ParseGroupVersion(a.APIVersion)
+	if err != nil {
+		return false
+	}
+	bGV, err := schema.ParseGroupVersion(b.APIVersion)
+	if err != nil {
+		return false
+	}
+	return aGV.Group == bGV.Group && a.Kind == b.Kind && a.Name == b.Name
+}
+
+// AddFinalizer adds a finalizer to the object if it is not present yet and
+// reports whether the finalizers changed.
+func AddFinalizer(o client.Object, finalizer string) bool {
+	if ContainsFinalizer(o, finalizer) {
+		return false
+	}
+	o.SetFinalizers(append(o.GetFinalizers(), finalizer))
+	return true
+}
+
+// RemoveFinalizer removes a finalizer from the object and reports whether
+// the finalizers changed.
+func RemoveFinalizer(o client.Object, finalizer string) bool {
+	finalizers := o.GetFinalizers()
+	kept := make([]string, 0, len(finalizers))
+	for _, f := range finalizers {
+		if f != finalizer {
+			kept = append(kept, f)
+		}
+	}
+	if len(kept) == len(finalizers) {
+		return false
+	}
+	o.SetFinalizers(kept)
+	return true
+}
+
+// ContainsFinalizer checks whether the object has the finalizer.
+func ContainsFinalizer(o client.Object, finalizer string) bool {
+	for _, f := range o.GetFinalizers() {
+		if f == finalizer {
+			return true
+		}
+	}
+	return false
+}
+
+// OperationResult is the action taken by CreateOrUpdate.
+type OperationResult string
+
+const (
+	// OperationResultNone means the object was not changed.
+	OperationResultNone OperationResult = "unchanged"
+	// OperationResultCreated means the object was created.
+	OperationResultCreated OperationResult = "created"
+	// OperationResultUpdated means the object was updated.
+	OperationResultUpdated OperationResult = "updated"
+)
+
+// MutateFn sets the desired state on an object.
+type MutateFn func() error
+
+// CreateOrUpdate fetches obj by its key, applies f and creates or updates
+// the object in storage. f is called for both new and existing objects and
+// must not change the name or namespace.
+func CreateOrUpdate(ctx context.Context, c client.Client, obj client.Object, f MutateFn) (OperationResult, error) {
+	key := client.ObjectKeyFromObject(obj)
+	if err := c.Get(ctx, key, obj); err != nil {
+		if !apierrors.IsNotFound(err) {
+			return OperationResultNone, err
+		}
+		if err := mutate(f, key, obj); err != nil {
+			return OperationResultNone, err
+		}
+		if err := c.Create(ctx, obj); err != nil {
+			return OperationResultNone, err
+		}
+		return OperationResultCreated, nil
+	}
+
+	existing := obj.DeepCopyObject()
+	if err := mutate(f, key, obj); err != nil {
+		return OperationResultNone, err
+	}
+	if reflect.DeepEqual(existing, obj) {
+		return OperationResultNone, nil
+	}
+	if err := c.Update(ctx, obj); err != nil {
+		return OperationResultNone, err
+	}
+	return OperationResultUpdated, nil
+}
+
+// mutate applies f and verifies the key was not changed
+func mutate(f MutateFn, key client.ObjectKey, obj client.Object) error {
+	if err := f(); err != nil {
+		return err
+	}
+	if newKey := client.ObjectKeyFromObject(obj); key != newKey {
+		return errors.New("the MutateFn must not change the object name or namespace")
+	}
+	return nil
+}
@@ -0,0 +1,13 @@
+package controllerutil_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestControllerutil(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Controllerutil Suite")
+}
@@ -0,0 +1,86 @@
+package controllerutil_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	corev1types "github.com/dtomasi/k1s/core/types/v1"
+
+	"github.com/dtomasi/k1s/controller-runtime/pkg/controller/controllerutil"
+)
+
+var _ = Describe("Controllerutil", func() {
+	var (
+		scheme *runtime.Scheme
+		owner  *corev1.ConfigMap
+		owned  *corev1.Secret
+	)
+
+	BeforeEach(func() {
+		scheme = runtime.NewScheme()
+		Expect(corev1types.AddToScheme(scheme)).To(Succeed())
+		owner = &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "owner", Namespace: "ns", UID: "uid-1"}}
+		owned = &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "owned", Namespace: "ns"}}
+	})
+
+	Describe("SetControllerReference", func() {
+		It("should set a controller owner reference", func() {
+			Expect(controllerutil.SetControllerReference(owner, owned, scheme)).To(Succeed())
+
+			ref := metav1.GetControllerOf(owned)
+			Expect(ref).NotTo(BeNil())
+			Expect(ref.APIVersion).To(Equal("v1"))
+			Expect(ref.Kind).To(Equal("ConfigMap"))
+			Expect(ref.Name).To(Equal("owner"))
+			Expect(ref.UID).To(BeEquivalentTo("uid-1"))
+		})
+
+		It("should be idempotent", func() {
+			Expect(controllerutil.SetControllerReference(owner, owned, scheme)).To(Succeed())
+			Expect(controllerutil.SetControllerReference(owner, owned, scheme)).To(Succeed())
+			Expect(owned.GetOwnerReferences()).To(HaveLen(1))
+		})
+
+		It("should refuse a second controller", func() {
+			Expect(controllerutil.SetControllerReference(owner, owned, scheme)).To(Succeed())
+			other := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "other", Namespace: "ns"}}
+
+			err := controllerutil.SetControllerReference(other, owned, scheme)
+			var alreadyOwned *controllerutil.AlreadyOwnedError
+			Expect(err).To(BeAssignableToTypeOf(alreadyOwned))
+		})
+
+		It("should refuse cross-namespace owners", func() {
+			owner.Namespace = "other"
+			Expect(controllerutil.SetControllerReference(owner, owned, scheme)).NotTo(Succeed())
+		})
+	})
+
+	Describe("SetOwnerReference", func() {
+		It("should add and remove a non-controller owner reference", func() {
+			Expect(controllerutil.SetOwnerReference(owner, owned, scheme)).To(Succeed())
+			Expect(owned.GetOwnerReferences()).To(HaveLen(1))
+			Expect(controllerutil.HasControllerReference(owned)).To(BeFalse())
+
+			Expect(controllerutil.RemoveOwnerReference(owner, owned, scheme)).To(Succeed())
+			Expect(owned.GetOwnerReferences()).To(BeEmpty())
+			Expect(controllerutil.RemoveOwnerReference(owner, owned, scheme)).NotTo(Succeed())
+		})
+	})
+
+	Describe("Finalizers", func() {
+		It("should add, detect and remove finalizers", func() {
+			Expect(controllerutil.AddFinalizer(owned, "k1s.io/cleanup")).To(BeTrue())
+			Expect(controllerutil.AddFinalizer(owned, "k1s.io/cleanup")).To(BeFalse())
+			Expect(controllerutil.ContainsFinalizer(owned, "k1s.io/cleanup")).To(BeTrue())
+
+			Expect(controllerutil.RemoveFinalizer(owned, "k1s.io/cleanup")).To(BeTrue())
+			Expect(controllerutil.RemoveFinalizer(owned, "k1s.io/cleanup")).To(BeFalse())
+			Expect(owned.GetFinalizers()).To(BeEmpty())
+		})
+	})
+})
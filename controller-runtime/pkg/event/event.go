@@ -0,0 +1,38 @@
+// Package event contains the events a source passes to an event handler.
+// It mirrors sigs.k8s.io/controller-runtime/pkg/event.
+package event
+
+import (
+	"github.com/dtomasi/k1s/core/client"
+)
+
+// CreateEvent is an event where an object was created.
+type CreateEvent struct {
+	// Object is the object that was created.
+	Object client.Object
+}
+
+// UpdateEvent is an event where an object was updated.
+type UpdateEvent struct {
+	// ObjectOld is the object before the update.
+	ObjectOld client.Object
+
+	// ObjectNew is the object after the update.
+	ObjectNew client.Object
+}
+
+// DeleteEvent is an event where an object was deleted.
+type DeleteEvent struct {
+	// Object is the object that was deleted.
+	Object client.Object
+
+	// DeleteStateUnknown is true if the delete was missed and Object may be stale.
+	DeleteStateUnknown bool
+}
+
+// GenericEvent is an event of unknown type, for example one triggered by
+// something outside the cluster state.
+type GenericEvent struct {
+	// Object is the object the event is about.
+	Object client.Object
+}
@@ -0,0 +1,50 @@
+package handler
+
+import (
+	"context"
+
+	"github.com/dtomasi/k1s/core/client"
+
+	"github.com/dtomasi/k1s/controller-runtime/pkg/event"
+	"github.com/dtomasi/k1s/controller-runtime/pkg/reconcile"
+)
+
+var _ EventHandler = &EnqueueRequestForObject{}
+
+// EnqueueRequestForObject enqueues a request for the name and namespace of
+// the object of each event. It is used by For() to reconcile the controller's
+// own type.
+type EnqueueRequestForObject struct{}
+
+// Create implements EventHandler.
+func (e *EnqueueRequestForObject) Create(_ context.Context, evt event.CreateEvent, q Queue) {
+	enqueueObject(evt.Object, q)
+}
+
+// Update implements EventHandler.
+func (e *EnqueueRequestForObject) Update(_ context.Context, evt event.UpdateEvent, q Queue) {
+	switch {
+	case evt.ObjectNew != nil:
+		enqueueObject(evt.ObjectNew, q)
+	case evt.ObjectOld != nil:
+		enqueueObject(evt.ObjectOld, q)
+	}
+}
+
+// Delete implements EventHandler.
+func (e *EnqueueRequestForObject) Delete(_ context.Context, evt event.DeleteEvent, q Queue) {
+	enqueueObject(evt.Object, q)
+}
+
+// Generic implements EventHandler.
+func (e *EnqueueRequestForObject) Generic(_ context.Context, evt event.GenericEvent, q Queue) {
+	enqueueObject(evt.Object, q)
+}
+
+// enqueueObject adds a request for obj to the queue
+func enqueueObject(obj client.Object, q Queue) {
+	if obj == nil {
+		return
+	}
+	q.Add(reconcile.Request{NamespacedName: client.ObjectKeyFromObject(obj)})
+}
@@ -0,0 +1,60 @@
+package handler
+
+import (
+	"context"
+
+	"github.com/dtomasi/k1s/core/client"
+
+	"github.com/dtomasi/k1s/controller-runtime/pkg/event"
+	"github.com/dtomasi/k1s/controller-runtime/pkg/reconcile"
+)
+
+// MapFunc maps an object to the requests that should be reconciled because of it.
+type MapFunc func(context.Context, client.Object) []reconcile.Request
+
+// EnqueueRequestsFromMapFunc enqueues the requests returned by fn for the
+// object of each event. Update events are mapped for both the old and the new
+// object; duplicate requests are enqueued once.
+func EnqueueRequestsFromMapFunc(fn MapFunc) EventHandler {
+	return &enqueueRequestsFromMapFunc{toRequests: fn}
+}
+
+type enqueueRequestsFromMapFunc struct {
+	toRequests MapFunc
+}
+
+// Create implements EventHandler.
+func (e *enqueueRequestsFromMapFunc) Create(ctx context.Context, evt event.CreateEvent, q Queue) {
+	e.mapAndEnqueue(ctx, q, map[reconcile.Request]struct{}{}, evt.Object)
+}
+
+// Update implements EventHandler.
+func (e *enqueueRequestsFromMapFunc) Update(ctx context.Context, evt event.UpdateEvent, q Queue) {
+	seen := map[reconcile.Request]struct{}{}
+	e.mapAndEnqueue(ctx, q, seen, evt.ObjectOld)
+	e.mapAndEnqueue(ctx, q, seen, evt.ObjectNew)
+}
+
+// Delete implements EventHandler.
+func (e *enqueueRequestsFromMapFunc) Delete(ctx context.Context, evt event.DeleteEvent, q Queue) {
+	e.mapAndEnqueue(ctx, q, map[reconcile.Request]struct{}{}, evt.Object)
+}
+
+// Generic implements EventHandler.
+func (e *enqueueRequestsFromMapFunc) Generic(ctx context.Context, evt event.GenericEvent, q Queue) {
+	e.mapAndEnqueue(ctx, q, map[reconcile.Request]struct{}{}, evt.Object)
+}
+
+// mapAndEnqueue adds the requests for obj that have not been seen yet
+func (e *enqueueRequestsFromMapFunc) mapAndEnqueue(ctx context.Context, q Queue, seen map[reconcile.Request]struct{}, obj client.Object) {
+	if obj == nil {
+		return
+	}
+	for _, req := range e.toRequests(ctx, obj) {
+		if _, ok := seen[req]; ok {
+			continue
+		}
+		seen[req] = struct{}{}
+		q.Add(req)
+	}
+}
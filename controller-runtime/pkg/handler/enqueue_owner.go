@@ -0,0 +1,146 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/dtomasi/k1s/core/client"
+
+	"github.com/dtomasi/k1s/controller-runtime/pkg/event"
+	"github.com/dtomasi/k1s/controller-runtime/pkg/reconcile"
+)
+
+// OwnerOption modifies an EnqueueRequestForOwner handler.
+type OwnerOption func(e *enqueueRequestForOwner)
+
+// OnlyControllerOwner restricts the handler to the owner reference that has
+// Controller set to true.
+func OnlyControllerOwner() OwnerOption {
+	return func(e *enqueueRequestForOwner) {
+		e.isController = true
+	}
+}
+
+// EnqueueRequestForOwner enqueues requests for the owners of the object of
+// each event whose kind matches ownerType. The mapper decides whether the
+// owner is namespaced; without a mapper the owner is assumed to live in the
+// namespace of the owned object, as owner references require for namespaced
+// objects.
+func EnqueueRequestForOwner(scheme *runtime.Scheme, mapper meta.RESTMapper, ownerType client.Object, opts ...OwnerOption) EventHandler {
+	e := &enqueueRequestForOwner{mapper: mapper}
+	for _, opt := range opts {
+		opt(e)
+	}
+	if err := e.parseOwnerTypeGroupKind(scheme, ownerType); err != nil {
+		panic(err)
+	}
+	return e
+}
+
+type enqueueRequestForOwner struct {
+	// groupKind is the owner kind requests are enqueued for
+	groupKind schema.GroupKind
+
+	// isController restricts the handler to controller owner references
+	isController bool
+
+	// mapper determines whether the owner kind is namespaced
+	mapper meta.RESTMapper
+}
+
+// Create implements EventHandler.
+func (e *enqueueRequestForOwner) Create(_ context.Context, evt event.CreateEvent, q Queue) {
+	e.enqueue(q, map[reconcile.Request]struct{}{}, evt.Object)
+}
+
+// Update implements EventHandler.
+func (e *enqueueRequestForOwner) Update(_ context.Context, evt event.UpdateEvent, q Queue) {
+	seen := map[reconcile.Request]struct{}{}
+	e.enqueue(q, seen, evt.ObjectOld)
+	e.enqueue(q, seen, evt.ObjectNew)
+}
+
+// Delete implements EventHandler.
+func (e *enqueueRequestForOwner) Delete(_ context.Context, evt event.DeleteEvent, q Queue) {
+	e.enqueue(q, map[reconcile.Request]struct{}{}, evt.Object)
+}
+
+// Generic implements EventHandler.
+func (e *enqueueRequestForOwner) Generic(_ context.Context, evt event.GenericEvent, q Queue) {
+	e.enqueue(q, map[reconcile.Request]struct{}{}, evt.Object)
+}
+
+// parseOwnerTypeGroupKind resolves the group and kind of the owner type
+func (e *enqueueRequestForOwner) parseOwnerTypeGroupKind(scheme *runtime.Scheme, ownerType client.Object) error {
+	if scheme == nil {
+		return fmt.Errorf("scheme is required to resolve the owner type %T", ownerType)
+	}
+	kinds, _, err := scheme.ObjectKinds(ownerType)
+	if err != nil {
+		return err
+	}
+	if len(kinds) != 1 {
+		return fmt.Errorf("expected exactly 1 kind for owner type %T, but found %d kinds", ownerType, len(kinds))
+	}
+	e.groupKind = kinds[0].GroupKind()
+	return nil
+}
+
+// enqueue adds a request for every matching owner of obj that has not been seen yet
+func (e *enqueueRequestForOwner) enqueue(q Queue, seen map[reconcile.Request]struct{}, obj client.Object) {
+	if obj == nil {
+		return
+	}
+	for _, ref := range e.ownerReferences(obj) {
+		refGV, err := schema.ParseGroupVersion(ref.APIVersion)
+		if err != nil {
+			continue
+		}
+		if ref.Kind != e.groupKind.Kind || refGV.Group != e.groupKind.Group {
+			continue
+		}
+
+		req := reconcile.Request{NamespacedName: client.ObjectKey{Name: ref.Name}}
+		namespaced, err := e.isNamespaced(refGV.WithKind(ref.Kind))
+		if err != nil {
+			continue
+		}
+		if namespaced {
+			req.Namespace = obj.GetNamespace()
+		}
+
+		if _, ok := seen[req]; ok {
+			continue
+		}
+		seen[req] = struct{}{}
+		q.Add(req)
+	}
+}
+
+// ownerReferences returns the owner references the handler considers
+func (e *enqueueRequestForOwner) ownerReferences(obj client.Object) []metav1.OwnerReference {
+	if !e.isController {
+		return obj.GetOwnerReferences()
+	}
+	if ref := metav1.GetControllerOfNoCopy(obj); ref != nil {
+		return []metav1.OwnerReference{*ref}
+	}
+	return nil
+}
+
+// isNamespaced reports whether objects of the given kind are namespaced
+func (e *enqueueRequestForOwner) isNamespaced(gvk schema.GroupVersionKind) (bool, error) {
+	if e.mapper == nil {
+		return true, nil
+	}
+	mapping, err := e.mapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+	if err != nil {
+		return false, err
+	}
+	return mapping.Scope.Name() != meta.RESTScopeNameRoot, nil
+}
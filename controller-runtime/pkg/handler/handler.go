@@ -0,0 +1,68 @@
+// Package handler maps events of watched objects to reconcile requests.
+// It mirrors sigs.k8s.io/controller-runtime/pkg/handler.
+package handler
+
+import (
+	"context"
+
+	"k8s.io/client-go/util/workqueue"
+
+	"github.com/dtomasi/k1s/controller-runtime/pkg/event"
+	"github.com/dtomasi/k1s/controller-runtime/pkg/reconcile"
+)
+
+// Queue is the workqueue reconcile requests are added to.
+type Queue = workqueue.TypedRateLimitingInterface[reconcile.Request]
+
+// EventHandler enqueues reconcile requests in response to events.
+type EventHandler interface {
+	// Create is called in response to a create event.
+	Create(ctx context.Context, evt event.CreateEvent, q Queue)
+
+	// Update is called in response to an update event.
+	Update(ctx context.Context, evt event.UpdateEvent, q Queue)
+
+	// Delete is called in response to a delete event.
+	Delete(ctx context.Context, evt event.DeleteEvent, q Queue)
+
+	// Generic is called in response to an event of unknown type.
+	Generic(ctx context.Context, evt event.GenericEvent, q Queue)
+}
+
+var _ EventHandler = Funcs{}
+
+// Funcs implements EventHandler with functions. Events without a function are ignored.
+type Funcs struct {
+	CreateFunc  func(context.Context, event.CreateEvent, Queue)
+	UpdateFunc  func(context.Context, event.UpdateEvent, Queue)
+	DeleteFunc  func(context.Context, event.DeleteEvent, Queue)
+	GenericFunc func(context.Context, event.GenericEvent, Queue)
+}
+
+// Create implements EventHandler.
+func (h Funcs) Create(ctx context.Context, evt event.CreateEvent, q Queue) {
+	if h.CreateFunc != nil {
+		h.CreateFunc(ctx, evt, q)
+	}
+}
+
+// Update implements EventHandler.
+func (h Funcs) Update(ctx context.Context, evt event.UpdateEvent, q Queue) {
+	if h.UpdateFunc != nil {
+		h.UpdateFunc(ctx, evt, q)
+	}
+}
+
+// Delete implements EventHandler.
+func (h Funcs) Delete(ctx context.Context, evt event.DeleteEvent, q Queue) {
+	if h.DeleteFunc != nil {
+		h.DeleteFunc(ctx, evt, q)
+	}
+}
+
+// Generic implements EventHandler.
+func (h Funcs) Generic(ctx context.Context, evt event.GenericEvent, q Queue) {
+	if h.GenericFunc != nil {
+		h.GenericFunc(ctx, evt, q)
+	}
+}
@@ -0,0 +1,139 @@
+package handler_test
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/util/workqueue"
+	"k8s.io/utils/ptr"
+
+	"github.com/dtomasi/k1s/core/client"
+	corev1types "github.com/dtomasi/k1s/core/types/v1"
+
+	"github.com/dtomasi/k1s/controller-runtime/pkg/event"
+	"github.com/dtomasi/k1s/controller-runtime/pkg/handler"
+	"github.com/dtomasi/k1s/controller-runtime/pkg/reconcile"
+)
+
+func request(namespace, name string) reconcile.Request {
+	return reconcile.Request{NamespacedName: client.ObjectKey{Namespace: namespace, Name: name}}
+}
+
+// drain returns all requests currently in the queue
+func drain(q handler.Queue) []reconcile.Request {
+	var requests []reconcile.Request
+	for q.Len() > 0 {
+		req, _ := q.Get()
+		q.Done(req)
+		requests = append(requests, req)
+	}
+	return requests
+}
+
+var _ = Describe("Handlers", func() {
+	var (
+		ctx    context.Context
+		q      handler.Queue
+		scheme *runtime.Scheme
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		q = workqueue.NewTypedRateLimitingQueue(workqueue.DefaultTypedControllerRateLimiter[reconcile.Request]())
+		scheme = runtime.NewScheme()
+		Expect(corev1types.AddToScheme(scheme)).To(Succeed())
+	})
+
+	AfterEach(func() {
+		q.ShutDown()
+	})
+
+	Describe("EnqueueRequestForObject", func() {
+		It("should enqueue the object of every event type", func() {
+			h := &handler.EnqueueRequestForObject{}
+			obj := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "a", Namespace: "ns"}}
+
+			h.Create(ctx, event.CreateEvent{Object: obj}, q)
+			Expect(drain(q)).To(ConsistOf(request("ns", "a")))
+
+			h.Update(ctx, event.UpdateEvent{ObjectOld: obj, ObjectNew: obj}, q)
+			Expect(drain(q)).To(ConsistOf(request("ns", "a")))
+
+			h.Delete(ctx, event.DeleteEvent{Object: obj}, q)
+			Expect(drain(q)).To(ConsistOf(request("ns", "a")))
+
+			h.Generic(ctx, event.GenericEvent{Object: obj}, q)
+			Expect(drain(q)).To(ConsistOf(request("ns", "a")))
+		})
+
+		It("should ignore events without an object", func() {
+			(&handler.EnqueueRequestForObject{}).Create(ctx, event.CreateEvent{}, q)
+			Expect(q.Len()).To(BeZero())
+		})
+	})
+
+	Describe("EnqueueRequestsFromMapFunc", func() {
+		It("should enqueue the mapped requests of the old and new object once", func() {
+			h := handler.EnqueueRequestsFromMapFunc(func(_ context.Context, obj client.Object) []reconcile.Request {
+				return []reconcile.Request{request(obj.GetNamespace(), "parent"), request(obj.GetNamespace(), obj.GetName())}
+			})
+
+			oldObj := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "old", Namespace: "ns"}}
+			newObj := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "new", Namespace: "ns"}}
+			h.Update(ctx, event.UpdateEvent{ObjectOld: oldObj, ObjectNew: newObj}, q)
+
+			Expect(drain(q)).To(ConsistOf(request("ns", "parent"), request("ns", "old"), request("ns", "new")))
+		})
+	})
+
+	Describe("EnqueueRequestForOwner", func() {
+		var owned *corev1.Secret
+
+		BeforeEach(func() {
+			owned = &corev1.Secret{ObjectMeta: metav1.ObjectMeta{
+				Name:      "owned",
+				Namespace: "ns",
+				OwnerReferences: []metav1.OwnerReference{
+					{APIVersion: "v1", Kind: "ConfigMap", Name: "controller", Controller: ptr.To(true)},
+					{APIVersion: "v1", Kind: "ConfigMap", Name: "other"},
+					{APIVersion: "v1", Kind: "ServiceAccount", Name: "unrelated"},
+				},
+			}}
+		})
+
+		It("should enqueue all owners of the owner type", func() {
+			h := handler.EnqueueRequestForOwner(scheme, nil, &corev1.ConfigMap{})
+			h.Create(ctx, event.CreateEvent{Object: owned}, q)
+			Expect(drain(q)).To(ConsistOf(request("ns", "controller"), request("ns", "other")))
+		})
+
+		It("should only enqueue the controller with OnlyControllerOwner", func() {
+			h := handler.EnqueueRequestForOwner(scheme, nil, &corev1.ConfigMap{}, handler.OnlyControllerOwner())
+			h.Delete(ctx, event.DeleteEvent{Object: owned}, q)
+			Expect(drain(q)).To(ConsistOf(request("ns", "controller")))
+		})
+
+		It("should panic for owner types unknown to the scheme", func() {
+			Expect(func() {
+				handler.EnqueueRequestForOwner(runtime.NewScheme(), nil, &corev1.ConfigMap{})
+			}).To(Panic())
+		})
+	})
+
+	Describe("Funcs", func() {
+		It("should call the configured functions and ignore the others", func() {
+			called := false
+			h := handler.Funcs{
+				CreateFunc: func(context.Context, event.CreateEvent, handler.Queue) { called = true },
+			}
+			h.Create(ctx, event.CreateEvent{}, q)
+			h.Delete(ctx, event.DeleteEvent{}, q)
+			Expect(called).To(BeTrue())
+		})
+	})
+})
@@ -0,0 +1,358 @@
+// Package leaderelection elects a single leader among processes that share a
+// storage backend. It mirrors k8s.io/client-go/tools/leaderelection with a
+// coordination.k8s.io/v1 Lease as the lock, stored through the k1s client.
+//
+// The leader renews the lease every RetryPeriod. Other candidates take the
+// lease over once it has not been renewed for LeaseDuration. A leader that
+// cannot renew the lease within RenewDeadline stops leading. Lease updates
+// carry the resourceVersion the candidate observed, so of two candidates
+// racing for an expired lease only one succeeds.
+package leaderelection
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/go-logr/logr"
+	coordinationv1 "k8s.io/api/coordination/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/uuid"
+	"k8s.io/apimachinery/pkg/util/wait"
+
+	"github.com/dtomasi/k1s/core/client"
+	typesv1 "github.com/dtomasi/k1s/core/types/v1"
+
+	logf "github.com/dtomasi/k1s/controller-runtime/pkg/log"
+)
+
+const (
+	// DefaultLeaseDuration is the default duration candidates wait before
+	// taking over a lease that has not been renewed.
+	DefaultLeaseDuration = 15 * time.Second
+
+	// DefaultRenewDeadline is the default duration the leader retries
+	// renewing the lease before it stops leading.
+	DefaultRenewDeadline = 10 * time.Second
+
+	// DefaultRetryPeriod is the default interval between attempts to
+	// acquire or renew the lease.
+	DefaultRetryPeriod = 2 * time.Second
+)
+
+// ErrLeaderElectionLost is returned by Run when the leader could not renew
+// its lease in time.
+var ErrLeaderElectionLost = errors.New("leader election lost")
+
+// Config configures a LeaderElector.
+type Config struct {
+	// Client stores the lease. Its scheme and registry must know the
+	// coordination.k8s.io/v1 Lease type.
+	Client client.Client
+
+	// Namespace and Name identify the lease.
+	Namespace string
+	Name      string
+
+	// Identity is the holder identity the elector records in the lease.
+	// Defaults to the hostname and a unique suffix.
+	Identity string
+
+	// LeaseDuration is how long candidates wait before taking over a lease
+	// that has not been renewed. Defaults to DefaultLeaseDuration.
+	LeaseDuration time.Duration
+
+	// RenewDeadline is how long the leader retries renewing the lease before
+	// it stops leading. Must be less than LeaseDuration. Defaults to
+	// DefaultRenewDeadline.
+	RenewDeadline time.Duration
+
+	// RetryPeriod is the interval between attempts to acquire or renew the
+	// lease. Must be less than RenewDeadline. Defaults to DefaultRetryPeriod.
+	RetryPeriod time.Duration
+
+	// ReleaseOnCancel releases the lease when the context passed to Run is
+	// done, so other candidates do not have to wait for it to expire.
+	ReleaseOnCancel bool
+
+	// Logger defaults to log.Log.
+	Logger logr.Logger
+}
+
+// Callbacks are called by a LeaderElector as its leadership changes.
+type Callbacks struct {
+	// OnStartedLeading is called in a new goroutine when the elector
+	// acquired the lease. Its context is cancelled when leadership ends.
+	OnStartedLeading func(ctx context.Context)
+
+	// OnStoppedLeading is called when the elector stops leading, either
+	// because it lost the lease or because Run's context is done.
+	OnStoppedLeading func()
+
+	// OnNewLeader is called when the elector observes a new holder of the
+	// lease, including itself.
+	OnNewLeader func(identity string)
+}
+
+// LeaderElector acquires and renews a lease.
+type LeaderElector struct {
+	config    Config
+	callbacks Callbacks
+
+	mu             sync.Mutex
+	observedLeader string
+	leading        bool
+}
+
+// New returns a LeaderElector for the given configuration.
+func New(config Config, callbacks Callbacks) (*LeaderElector, error) {
+	if config.Client == nil {
+		return nil, errors.New("leader election requires a client")
+	}
+	if config.Name == "" {
+		return nil, errors.New("leader election requires a lease name")
+	}
+	if config.Identity == "" {
+		hostname, err := os.Hostname()
+		if err != nil {
+			return nil, fmt.Errorf("failed to determine leader election identity: %w", err)
+		}
+		config.Identity = hostname + "_" + string(uuid.NewUUID())
+	}
+	if config.LeaseDuration == 0 {
+		config.LeaseDuration = DefaultLeaseDuration
+	}
+	if config.RenewDeadline == 0 {
+		config.RenewDeadline = DefaultRenewDeadline
+	}
+	if config.RetryPeriod == 0 {
+		config.RetryPeriod = DefaultRetryPeriod
+	}
+	if config.LeaseDuration <= config.RenewDeadline {
+		return nil, errors.New("leaseDuration must be greater than renewDeadline")
+	}
+	if config.RenewDeadline <= config.RetryPeriod {
+		return nil, errors.New("renewDeadline must be greater than retryPeriod")
+	}
+	if config.Logger.GetSink() == nil {
+		config.Logger = logf.Log
+	}
+	config.Logger = config.Logger.WithValues("lease", config.Namespace+"/"+config.Name, "identity", config.Identity)
+
+	return &LeaderElector{config: config, callbacks: callbacks}, nil
+}
+
+// Identity returns the holder identity of the elector.
+func (le *LeaderElector) Identity() string {
+	return le.config.Identity
+}
+
+// IsLeader reports whether the elector currently holds the lease.
+func (le *LeaderElector) IsLeader() bool {
+	le.mu.Lock()
+	defer le.mu.Unlock()
+	return le.leading
+}
+
+// GetLeader returns the identity of the last observed holder of the lease.
+func (le *LeaderElector) GetLeader() string {
+	le.mu.Lock()
+	defer le.mu.Unlock()
+	return le.observedLeader
+}
+
+// Run acquires the lease, calls OnStartedLeading and renews the lease until
+// ctx is done or renewing fails. It returns nil when ctx is done and
+// ErrLeaderElectionLost when the lease could not be renewed.
+func (le *LeaderElector) Run(ctx context.Context) error {
+	if !le.acquire(ctx) {
+		return nil
+	}
+
+	leaderCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	if le.callbacks.OnStartedLeading != nil {
+		go le.callbacks.OnStartedLeading(leaderCtx)
+	}
+
+	err := le.renew(ctx)
+
+	le.setLeading(false)
+	cancel()
+	if le.callbacks.OnStoppedLeading != nil {
+		le.callbacks.OnStoppedLeading()
+	}
+	if err == nil && le.config.ReleaseOnCancel {
+		le.release()
+	}
+	return err
+}
+
+// acquire retries to acquire the lease until it succeeds or ctx is done
+func (le *LeaderElector) acquire(ctx context.Context) bool {
+	le.config.Logger.Info("Attempting to acquire leader lease")
+	for {
+		if le.tryAcquireOrRenew(ctx) && ctx.Err() == nil {
+			le.config.Logger.Info("Successfully acquired lease")
+			le.setLeading(true)
+			return true
+		}
+		select {
+		case <-ctx.Done():
+			return false
+		case <-time.After(wait.Jitter(le.config.RetryPeriod, 1.2)):
+		}
+	}
+}
+
+// renew renews the lease every RetryPeriod until ctx is done, or returns
+// ErrLeaderElectionLost once a renewal did not succeed within RenewDeadline
+func (le *LeaderElector) renew(ctx context.Context) error {
+	ticker := time.NewTicker(le.config.RetryPeriod)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+
+		err := wait.PollUntilContextTimeout(ctx, le.config.RetryPeriod, le.config.RenewDeadline, true,
+			func(ctx context.Context) (bool, error) {
+				return le.tryAcquireOrRenew(ctx), nil
+			})
+		if ctx.Err() != nil {
+			return nil
+		}
+		if err != nil {
+			le.config.Logger.Info("Failed to renew lease")
+			return ErrLeaderElectionLost
+		}
+	}
+}
+
+// tryAcquireOrRenew takes over the lease if it is free, expired or already
+// held by this elector, and reports whether the elector holds it afterwards
+func (le *LeaderElector) tryAcquireOrRenew(ctx context.Context) bool {
+	now := metav1.NewMicroTime(time.Now())
+	durationSeconds := int32(le.config.LeaseDuration.Round(time.Second) / time.Second)
+	if durationSeconds < 1 {
+		durationSeconds = 1
+	}
+
+	lease := &coordinationv1.Lease{}
+	err := le.config.Client.Get(ctx, client.ObjectKey{Namespace: le.config.Namespace, Name: le.config.Name}, lease)
+	if apierrors.IsNotFound(err) {
+		lease = typesv1.NewLease(le.config.Name, le.config.Namespace)
+		lease.Spec = coordinationv1.LeaseSpec{
+			HolderIdentity:       &le.config.Identity,
+			LeaseDurationSeconds: &durationSeconds,
+			AcquireTime:          &now,
+			RenewTime:            &now,
+			LeaseTransitions:     new(int32),
+		}
+		if err := le.config.Client.Create(ctx, lease); err != nil {
+			le.config.Logger.V(1).Info("Failed to create lease", "error", err.Error())
+			return false
+		}
+		le.observe(le.config.Identity)
+		return true
+	}
+	if err != nil {
+		le.config.Logger.V(1).Info("Failed to get lease", "error", err.Error())
+		return false
+	}
+
+	holder := ""
+	if lease.Spec.HolderIdentity != nil {
+		holder = *lease.Spec.HolderIdentity
+	}
+	if holder != "" {
+		le.observe(holder)
+	}
+	if holder != "" && holder != le.config.Identity && !expired(lease, now.Time) {
+		return false
+	}
+
+	if holder != le.config.Identity {
+		transitions := int32(0)
+		if lease.Spec.LeaseTransitions != nil {
+			transitions = *lease.Spec.LeaseTransitions
+		}
+		if holder != "" {
+			transitions++
+		}
+		lease.Spec.HolderIdentity = &le.config.Identity
+		lease.Spec.AcquireTime = &now
+		lease.Spec.LeaseTransitions = &transitions
+	}
+	lease.Spec.LeaseDurationSeconds = &durationSeconds
+	lease.Spec.RenewTime = &now
+
+	// The update fails with a conflict if another candidate changed the
+	// lease since it was read
+	if err := le.config.Client.Update(ctx, lease); err != nil {
+		le.config.Logger.V(1).Info("Failed to update lease", "error", err.Error())
+		return false
+	}
+	le.observe(le.config.Identity)
+	return true
+}
+
+// release clears the holder of the lease if the elector still holds it
+func (le *LeaderElector) release() {
+	ctx, cancel := context.WithTimeout(context.Background(), le.config.RenewDeadline)
+	defer cancel()
+
+	lease := &coordinationv1.Lease{}
+	if err := le.config.Client.Get(ctx, client.ObjectKey{Namespace: le.config.Namespace, Name: le.config.Name}, lease); err != nil {
+		le.config.Logger.Error(err, "Failed to get lease for release")
+		return
+	}
+	if lease.Spec.HolderIdentity == nil || *lease.Spec.HolderIdentity != le.config.Identity {
+		return
+	}
+
+	now := metav1.NewMicroTime(time.Now())
+	lease.Spec.HolderIdentity = nil
+	lease.Spec.LeaseDurationSeconds = new(int32)
+	lease.Spec.RenewTime = &now
+	if err := le.config.Client.Update(ctx, lease); err != nil {
+		le.config.Logger.Error(err, "Failed to release lease")
+		return
+	}
+	le.config.Logger.Info("Released lease")
+}
+
+// observe records the holder of the lease and reports new leaders
+func (le *LeaderElector) observe(identity string) {
+	le.mu.Lock()
+	changed := le.observedLeader != identity
+	le.observedLeader = identity
+	le.mu.Unlock()
+
+	if changed && le.callbacks.OnNewLeader != nil {
+		le.callbacks.OnNewLeader(identity)
+	}
+}
+
+// setLeading records whether the elector holds the lease
+func (le *LeaderElector) setLeading(leading bool) {
+	le.mu.Lock()
+	defer le.mu.Unlock()
+	le.leading = leading
+}
+
+// expired reports whether the lease was not renewed within its duration
+func expired(lease *coordinationv1.Lease, now time.Time) bool {
+	if lease.Spec.RenewTime == nil || lease.Spec.LeaseDurationSeconds == nil {
+		return true
+	}
+	duration := time.Duration(*lease.Spec.LeaseDurationSeconds) * time.Second
+	return !now.Before(lease.Spec.RenewTime.Add(duration))
+}
@@ -0,0 +1,197 @@
+package leaderelection_test
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	coordinationv1 "k8s.io/api/coordination/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/dtomasi/k1s/core/client"
+	"github.com/dtomasi/k1s/core/registry"
+	k1sstorage "github.com/dtomasi/k1s/core/storage"
+	corev1types "github.com/dtomasi/k1s/core/types/v1"
+	memory "github.com/dtomasi/k1s/storage/memory"
+
+	"github.com/dtomasi/k1s/controller-runtime/pkg/leaderelection"
+)
+
+// candidate runs a leader elector in the background and records its leadership
+type candidate struct {
+	elector *leaderelection.LeaderElector
+	cancel  context.CancelFunc
+	done    chan error
+
+	mu      sync.Mutex
+	started int
+	stopped int
+}
+
+func (c *candidate) counts() (int, int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.started, c.stopped
+}
+
+var _ = Describe("LeaderElector", func() {
+	var (
+		ctx context.Context
+		c   client.Client
+		key = client.ObjectKey{Namespace: "default", Name: "controllers"}
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+
+		scheme := runtime.NewScheme()
+		Expect(corev1types.AddToScheme(scheme)).To(Succeed())
+		reg := registry.NewRegistry()
+		Expect(registry.RegisterCoreResources(reg)).To(Succeed())
+
+		var err error
+		c, err = client.NewClient(client.ClientOptions{
+			Scheme:   scheme,
+			Storage:  memory.NewMemoryStorage(k1sstorage.Config{}),
+			Registry: reg,
+		})
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	// run starts a candidate with the given identity
+	run := func(identity string, releaseOnCancel bool) *candidate {
+		cand := &candidate{done: make(chan error, 1)}
+		elector, err := leaderelection.New(leaderelection.Config{
+			Client:          c,
+			Namespace:       key.Namespace,
+			Name:            key.Name,
+			Identity:        identity,
+			LeaseDuration:   time.Second,
+			RenewDeadline:   500 * time.Millisecond,
+			RetryPeriod:     50 * time.Millisecond,
+			ReleaseOnCancel: releaseOnCancel,
+		}, leaderelection.Callbacks{
+			OnStartedLeading: func(context.Context) {
+				cand.mu.Lock()
+				defer cand.mu.Unlock()
+				cand.started++
+			},
+			OnStoppedLeading: func() {
+				cand.mu.Lock()
+				defer cand.mu.Unlock()
+				cand.stopped++
+			},
+		})
+		Expect(err).NotTo(HaveOccurred())
+		cand.elector = elector
+
+		runCtx, cancel := context.WithCancel(ctx)
+		cand.cancel = cancel
+		go func() { cand.done <- elector.Run(runCtx) }()
+		return cand
+	}
+
+	isLeader := func(cand *candidate) func() bool {
+		return cand.elector.IsLeader
+	}
+
+	It("should validate its configuration", func() {
+		_, err := leaderelection.New(leaderelection.Config{Name: "lease"}, leaderelection.Callbacks{})
+		Expect(err).To(HaveOccurred())
+		_, err = leaderelection.New(leaderelection.Config{Client: c}, leaderelection.Callbacks{})
+		Expect(err).To(HaveOccurred())
+		_, err = leaderelection.New(leaderelection.Config{
+			Client: c, Name: "lease", LeaseDuration: time.Second, RenewDeadline: 2 * time.Second,
+		}, leaderelection.Callbacks{})
+		Expect(err).To(HaveOccurred())
+
+		elector, err := leaderelection.New(leaderelection.Config{Client: c, Name: "lease"}, leaderelection.Callbacks{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(elector.Identity()).NotTo(BeEmpty())
+	})
+
+	It("should elect a single leader and record it in the lease", func() {
+		first := run("first", true)
+		defer first.cancel()
+		Eventually(isLeader(first)).Should(BeTrue())
+
+		second := run("second", true)
+		defer second.cancel()
+		Eventually(second.elector.GetLeader).Should(Equal("first"))
+		Consistently(isLeader(second), 1500*time.Millisecond, 50*time.Millisecond).Should(BeFalse())
+		Expect(first.elector.IsLeader()).To(BeTrue())
+
+		lease := &coordinationv1.Lease{}
+		Expect(c.Get(ctx, key, lease)).To(Succeed())
+		Expect(*lease.Spec.HolderIdentity).To(Equal("first"))
+		Expect(*lease.Spec.LeaseDurationSeconds).To(Equal(int32(1)))
+		Expect(lease.Spec.RenewTime).NotTo(BeNil())
+	})
+
+	It("should hand the lease over when the leader releases it", func() {
+		first := run("first", true)
+		Eventually(isLeader(first)).Should(BeTrue())
+
+		second := run("second", true)
+		defer second.cancel()
+		Eventually(second.elector.GetLeader).Should(Equal("first"))
+
+		first.cancel()
+		Eventually(first.done).Should(Receive(BeNil()))
+		started, stopped := first.counts()
+		Expect(started).To(Equal(1))
+		Expect(stopped).To(Equal(1))
+
+		// A released lease is taken over well before it would expire
+		Eventually(isLeader(second), 500*time.Millisecond).Should(BeTrue())
+
+		lease := &coordinationv1.Lease{}
+		Expect(c.Get(ctx, key, lease)).To(Succeed())
+		Expect(*lease.Spec.HolderIdentity).To(Equal("second"))
+		Expect(*lease.Spec.LeaseTransitions).To(Equal(int32(0)))
+	})
+
+	It("should take over an expired lease", func() {
+		first := run("first", false)
+		Eventually(isLeader(first)).Should(BeTrue())
+		first.cancel()
+		Eventually(first.done).Should(Receive(BeNil()))
+
+		start := time.Now()
+		second := run("second", false)
+		defer second.cancel()
+		Eventually(isLeader(second), 3*time.Second).Should(BeTrue())
+		Expect(time.Since(start)).To(BeNumerically(">", 500*time.Millisecond))
+
+		lease := &coordinationv1.Lease{}
+		Expect(c.Get(ctx, key, lease)).To(Succeed())
+		Expect(*lease.Spec.HolderIdentity).To(Equal("second"))
+		Expect(*lease.Spec.LeaseTransitions).To(Equal(int32(1)))
+	})
+
+	It("should stop leading when another process took the lease", func() {
+		first := run("first", false)
+		defer first.cancel()
+		Eventually(isLeader(first)).Should(BeTrue())
+
+		// Another process overwrites the lease, e.g. after a clock jump. Its
+		// update conflicts with renewals that happen in between.
+		other := "other"
+		Eventually(func() error {
+			lease := &coordinationv1.Lease{}
+			if err := c.Get(ctx, key, lease); err != nil {
+				return err
+			}
+			lease.Spec.HolderIdentity = &other
+			return c.Update(ctx, lease)
+		}).Should(Succeed())
+
+		Eventually(first.done, 2*time.Second).Should(Receive(MatchError(leaderelection.ErrLeaderElectionLost)))
+		Expect(first.elector.IsLeader()).To(BeFalse())
+		_, stopped := first.counts()
+		Expect(stopped).To(Equal(1))
+	})
+})
@@ -0,0 +1,111 @@
+// Package log provides the logger used by controllers. It mirrors
+// sigs.k8s.io/controller-runtime/pkg/log: Log discards everything until a
+// logger is configured with SetLogger, after which all loggers derived from
+// Log write to it.
+package log
+
+import (
+	"context"
+	"sync"
+
+	"github.com/go-logr/logr"
+)
+
+// Log is the base logger. Loggers derived from it before SetLogger is called
+// write to the configured logger as well.
+var Log = logr.New(&delegatingSink{root: delegate})
+
+// delegate holds the logger set with SetLogger
+var delegate = &root{logger: logr.Discard()}
+
+// SetLogger sets the logger that Log and all loggers derived from it write to.
+func SetLogger(l logr.Logger) {
+	delegate.set(l)
+}
+
+// FromContext returns the logger stored in ctx, or Log if there is none,
+// with the given key/value pairs added.
+func FromContext(ctx context.Context, keysAndValues ...interface{}) logr.Logger {
+	l := Log
+	if ctx != nil {
+		if fromCtx, err := logr.FromContext(ctx); err == nil {
+			l = fromCtx
+		}
+	}
+	return l.WithValues(keysAndValues...)
+}
+
+// IntoContext returns a copy of ctx that carries the logger.
+func IntoContext(ctx context.Context, l logr.Logger) context.Context {
+	return logr.NewContext(ctx, l)
+}
+
+// root is the logger all delegating sinks resolve to
+type root struct {
+	mu     sync.RWMutex
+	logger logr.Logger
+}
+
+func (r *root) set(l logr.Logger) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.logger = l
+}
+
+func (r *root) get() logr.Logger {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.logger
+}
+
+// delegatingSink records names and values and applies them to the current
+// root logger on every call
+type delegatingSink struct {
+	root   *root
+	names  []string
+	values []interface{}
+}
+
+// resolve returns the root logger with the recorded names and values
+func (s *delegatingSink) resolve() logr.Logger {
+	l := s.root.get()
+	for _, name := range s.names {
+		l = l.WithName(name)
+	}
+	if len(s.values) > 0 {
+		l = l.WithValues(s.values...)
+	}
+	return l
+}
+
+// Init implements logr.LogSink.
+func (s *delegatingSink) Init(logr.RuntimeInfo) {}
+
+// Enabled implements logr.LogSink.
+func (s *delegatingSink) Enabled(level int) bool {
+	return s.resolve().V(level).Enabled()
+}
+
+// Info implements logr.LogSink.
+func (s *delegatingSink) Info(level int, msg string, keysAndValues ...interface{}) {
+	s.resolve().V(level).Info(msg, keysAndValues...)
+}
+
+// Error implements logr.LogSink.
+func (s *delegatingSink) Error(err error, msg string, keysAndValues ...interface{}) {
+	s.resolve().Error(err, msg, keysAndValues...)
+}
+
+// WithName implements logr.LogSink.
+func (s *delegatingSink) WithName(name string) logr.LogSink {
+	names := make([]string, len(s.names), len(s.names)+1)
+	copy(names, s.names)
+	return &delegatingSink{root: s.root, names: append(names, name), values: s.values}
+}
+
+// WithValues implements logr.LogSink.
+func (s *delegatingSink) WithValues(keysAndValues ...interface{}) logr.LogSink {
+	values := make([]interface{}, len(s.values), len(s.values)+len(keysAndValues))
+	copy(values, s.values)
+	return &delegatingSink{root: s.root, names: s.names, values: append(values, keysAndValues...)}
+}
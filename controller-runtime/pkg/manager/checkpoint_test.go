@@ -0,0 +1,106 @@
+package manager_test
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/dtomasi/k1s/core/client"
+	k1sruntime "github.com/dtomasi/k1s/core/runtime"
+	k1sstorage "github.com/dtomasi/k1s/core/storage"
+	memory "github.com/dtomasi/k1s/storage/memory"
+
+	"github.com/dtomasi/k1s/controller-runtime/pkg/builder"
+	"github.com/dtomasi/k1s/controller-runtime/pkg/checkpoint"
+	"github.com/dtomasi/k1s/controller-runtime/pkg/manager"
+	"github.com/dtomasi/k1s/controller-runtime/pkg/reconcile"
+)
+
+var _ = Describe("Checkpoints", func() {
+	var (
+		ctx   context.Context
+		rt    k1sruntime.Runtime
+		c     client.Client
+		store checkpoint.Store
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		storage := memory.NewMemoryStorage(k1sstorage.Config{})
+		rt = newRuntimeWithStorage(storage)
+		c = rt.GetClient()
+		store = checkpoint.NewStorageStore(storage)
+	})
+
+	// setup creates a manager with checkpoints and a ConfigMap controller running fn
+	setup := func(fn func(context.Context, reconcile.Request) (reconcile.Result, error)) manager.Manager {
+		mgr, err := manager.New(rt, manager.Options{Checkpoints: store})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(builder.ControllerManagedBy(mgr).
+			For(&corev1.ConfigMap{}).
+			Named("configmaps").
+			Complete(reconcile.Func(fn))).To(Succeed())
+		return mgr
+	}
+
+	It("should resume abandoned requeues in the next run", func() {
+		Expect(c.Create(ctx, configMap("later"))).To(Succeed())
+
+		first := setup(func(context.Context, reconcile.Request) (reconcile.Result, error) {
+			return reconcile.Result{RequeueAfter: 300 * time.Millisecond}, nil
+		})
+		Expect(first.RunOnce(ctx)).To(Succeed())
+
+		cp, err := store.Load(ctx, "configmaps")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(cp).NotTo(BeNil())
+		Expect(cp.LastObservedResourceVersion).NotTo(BeEmpty())
+		Expect(cp.Requests).To(HaveLen(1))
+		Expect(cp.Requests[0].Namespace).To(Equal("default"))
+		Expect(cp.Requests[0].Name).To(Equal("later"))
+		Expect(cp.Requests[0].NotBefore).NotTo(BeNil())
+
+		cnt := &counter{}
+		second := setup(func(_ context.Context, req reconcile.Request) (reconcile.Result, error) {
+			cnt.add(req.Name)
+			return reconcile.Result{}, nil
+		})
+		Expect(second.RunOnce(ctx, manager.WithRequeueHorizon(2*time.Second))).To(Succeed())
+		Expect(cnt.get()).To(Equal(map[string]int{"later": 1}))
+
+		cp, err = store.Load(ctx, "configmaps")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(cp.Requests).To(BeEmpty())
+	})
+
+	It("should only reconcile objects changed since the checkpoint", func() {
+		Expect(c.Create(ctx, configMap("old"))).To(Succeed())
+
+		first := setup(func(context.Context, reconcile.Request) (reconcile.Result, error) {
+			return reconcile.Result{}, nil
+		})
+		Expect(first.RunOnce(ctx)).To(Succeed())
+
+		Expect(c.Create(ctx, configMap("new"))).To(Succeed())
+
+		cnt := &counter{}
+		second := setup(func(_ context.Context, req reconcile.Request) (reconcile.Result, error) {
+			cnt.add(req.Name)
+			return reconcile.Result{}, nil
+		})
+		Expect(second.RunOnce(ctx)).To(Succeed())
+		Expect(cnt.get()).To(Equal(map[string]int{"new": 1}))
+	})
+
+	It("should keep checkpoints out of client lists", func() {
+		Expect(store.Save(ctx, checkpoint.New("configmaps"))).To(Succeed())
+
+		list := &corev1.ConfigMapList{}
+		Expect(c.List(ctx, list)).To(Succeed())
+		Expect(list.Items).To(BeEmpty())
+	})
+})
@@ -0,0 +1,125 @@
+package manager_test
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+
+	k1sruntime "github.com/dtomasi/k1s/core/runtime"
+	k1sstorage "github.com/dtomasi/k1s/core/storage"
+	memory "github.com/dtomasi/k1s/storage/memory"
+
+	"github.com/dtomasi/k1s/controller-runtime/pkg/builder"
+	"github.com/dtomasi/k1s/controller-runtime/pkg/manager"
+	"github.com/dtomasi/k1s/controller-runtime/pkg/reconcile"
+)
+
+// nonLeaderRunnable runs regardless of leader election
+type nonLeaderRunnable struct {
+	started chan struct{}
+}
+
+func (r *nonLeaderRunnable) Start(ctx context.Context) error {
+	close(r.started)
+	<-ctx.Done()
+	return nil
+}
+
+func (r *nonLeaderRunnable) NeedLeaderElection() bool {
+	return false
+}
+
+var _ = Describe("Leader election", func() {
+	var (
+		ctx     context.Context
+		storage k1sstorage.Interface
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		storage = memory.NewMemoryStorage(k1sstorage.Config{})
+	})
+
+	// process simulates a CLI process with its own runtime on the shared
+	// storage, running a ConfigMap controller that counts its reconciles
+	process := func(cnt *counter) (manager.Manager, k1sruntime.Runtime) {
+		rt := newRuntimeWithStorage(storage)
+		Expect(rt.Start(ctx)).To(Succeed())
+
+		mgr, err := manager.New(rt, manager.Options{
+			LeaderElection:                true,
+			LeaderElectionID:              "configmaps",
+			LeaseDuration:                 time.Second,
+			RenewDeadline:                 500 * time.Millisecond,
+			RetryPeriod:                   50 * time.Millisecond,
+			LeaderElectionReleaseOnCancel: true,
+		})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(builder.ControllerManagedBy(mgr).
+			For(&corev1.ConfigMap{}).
+			Complete(reconcile.Func(func(_ context.Context, req reconcile.Request) (reconcile.Result, error) {
+				cnt.add(req.Name)
+				return reconcile.Result{}, nil
+			}))).To(Succeed())
+		return mgr, rt
+	}
+
+	It("should require a lease name", func() {
+		_, err := manager.New(newRuntime(), manager.Options{LeaderElection: true})
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should be elected right away without leader election", func() {
+		mgr, err := manager.New(newRuntime(), manager.Options{})
+		Expect(err).NotTo(HaveOccurred())
+
+		runCtx, cancel := context.WithCancel(ctx)
+		defer cancel()
+		go func() { _ = mgr.Start(runCtx) }()
+		Eventually(mgr.Elected()).Should(BeClosed())
+	})
+
+	It("should run controllers in one process at a time", func() {
+		firstCnt, secondCnt := &counter{}, &counter{}
+		first, firstRT := process(firstCnt)
+		second, secondRT := process(secondCnt)
+		defer func() {
+			Expect(firstRT.Stop(ctx)).To(Succeed())
+			Expect(secondRT.Stop(ctx)).To(Succeed())
+		}()
+
+		background := &nonLeaderRunnable{started: make(chan struct{})}
+		Expect(second.Add(background)).To(Succeed())
+
+		firstCtx, cancelFirst := context.WithCancel(ctx)
+		firstDone := make(chan error, 1)
+		go func() { firstDone <- first.Start(firstCtx) }()
+		Eventually(first.Elected()).Should(BeClosed())
+
+		secondCtx, cancelSecond := context.WithCancel(ctx)
+		defer cancelSecond()
+		secondDone := make(chan error, 1)
+		go func() { secondDone <- second.Start(secondCtx) }()
+
+		Expect(first.GetClient().Create(ctx, configMap("one"))).To(Succeed())
+		Eventually(firstCnt.get).Should(HaveKeyWithValue("one", 1))
+
+		// Runnables that do not need leader election run in every process
+		Eventually(background.started).Should(BeClosed())
+		Consistently(second.Elected(), 500*time.Millisecond).ShouldNot(BeClosed())
+		Expect(secondCnt.get()).To(BeEmpty())
+
+		// The released lease is taken over before it would expire
+		cancelFirst()
+		Eventually(firstDone).Should(Receive(BeNil()))
+		Eventually(second.Elected(), 500*time.Millisecond).Should(BeClosed())
+		Eventually(secondCnt.get).Should(HaveKeyWithValue("one", 1))
+
+		cancelSecond()
+		Eventually(secondDone).Should(Receive(BeNil()))
+	})
+})
@@ -0,0 +1,517 @@
+// Package manager runs controllers on top of a k1s runtime. It mirrors
+// sigs.k8s.io/controller-runtime/pkg/manager: controllers and other
+// runnables are added to a Manager, which provides them with a client, a
+// scheme, shared informers and event recorders, and starts and stops them
+// together.
+package manager
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	toolscache "k8s.io/client-go/tools/cache"
+
+	"github.com/dtomasi/k1s/core/client"
+	"github.com/dtomasi/k1s/core/events"
+	"github.com/dtomasi/k1s/core/informers"
+	k1sruntime "github.com/dtomasi/k1s/core/runtime"
+
+	"github.com/dtomasi/k1s/controller-runtime/pkg/checkpoint"
+	"github.com/dtomasi/k1s/controller-runtime/pkg/leaderelection"
+	logf "github.com/dtomasi/k1s/controller-runtime/pkg/log"
+)
+
+// Manager starts and stops controllers and provides them with shared dependencies.
+type Manager interface {
+	// Add registers a runnable. Runnables added after Start are started immediately.
+	Add(Runnable) error
+
+	// Start starts the runtime, the shared informers and all runnables and
+	// blocks until ctx is done or a runnable fails.
+	Start(ctx context.Context) error
+
+	// RunOnce starts the manager like Start, but returns once all
+	// controllers have processed their work instead of running until ctx is
+	// done. See RunOnceOption for the available options.
+	RunOnce(ctx context.Context, opts ...RunOnceOption) error
+
+	// LastObservedResourceVersion returns the highest resourceVersion the
+	// shared informers have observed. Passed to WithChangedSince, it limits
+	// the next RunOnce to objects that changed in the meantime.
+	LastObservedResourceVersion() string
+
+	// GetClient returns the client of the runtime.
+	GetClient() client.Client
+
+	// GetScheme returns the scheme of the runtime's client.
+	GetScheme() *runtime.Scheme
+
+	// GetRESTMapper returns the REST mapper of the runtime's client, which may be nil.
+	GetRESTMapper() meta.RESTMapper
+
+	// GetEventRecorderFor returns an event recorder for the named component.
+	// Events recorded while the runtime's event system is not running are dropped.
+	GetEventRecorderFor(name string) events.EventRecorder
+
+	// GetInformer returns the shared informer for the type of obj. Informers
+	// requested after Start are started immediately.
+	GetInformer(ctx context.Context, obj client.Object) (toolscache.SharedIndexInformer, error)
+
+	// GetInformerFactory returns the shared informer factory.
+	GetInformerFactory() informers.SharedInformerFactory
+
+	// GetRuntime returns the k1s runtime the manager runs on.
+	GetRuntime() k1sruntime.Runtime
+
+	// GetLogger returns the logger of the manager.
+	GetLogger() logr.Logger
+
+	// GetCheckpointStore returns the store controllers persist their
+	// reconcile state in, or nil if checkpoints are disabled.
+	GetCheckpointStore() checkpoint.Store
+
+	// Elected is closed when the manager acquired the leader lease and
+	// started the runnables that need leader election, or right on Start if
+	// leader election is disabled.
+	Elected() <-chan struct{}
+}
+
+// Runnable is started by the manager and must block until ctx is done.
+type Runnable interface {
+	Start(ctx context.Context) error
+}
+
+// RunnableFunc implements Runnable with a function.
+type RunnableFunc func(context.Context) error
+
+// Start implements Runnable.
+func (r RunnableFunc) Start(ctx context.Context) error {
+	return r(ctx)
+}
+
+// LeaderElectionRunnable is implemented by runnables that declare whether
+// they need leader election. Runnables that do not implement it, such as
+// controllers, only run in the process that holds the leader lease.
+type LeaderElectionRunnable interface {
+	// NeedLeaderElection reports whether the runnable only runs while the
+	// manager is the leader.
+	NeedLeaderElection() bool
+}
+
+// Quiescer is implemented by runnables that process queued work, such as
+// controllers. RunOnce uses it to detect when all work is done.
+type Quiescer interface {
+	// Quiescent reports whether the runnable is ready and has no queued or
+	// in-flight work, and returns when its next delayed work is due, or the
+	// zero time if there is none.
+	Quiescent() (bool, time.Time)
+}
+
+// Options configures a manager.
+type Options struct {
+	// Logger is the base logger of the manager and its controllers.
+	// Defaults to log.Log.
+	Logger logr.Logger
+
+	// SyncPeriod is the resync period of the shared informers. Defaults to
+	// no periodic resync.
+	SyncPeriod time.Duration
+
+	// Namespace restricts the shared informers to a single namespace.
+	Namespace string
+
+	// Checkpoints persists the pending requests and the last observed
+	// resourceVersion of each controller, so the next process resumes where
+	// this one stopped. Typically checkpoint.NewStorageStore with the
+	// runtime's storage backend. Defaults to no checkpoints.
+	Checkpoints checkpoint.Store
+
+	// LeaderElection makes the manager hold a coordination.k8s.io/v1 Lease
+	// while it runs the runnables that need leader election, so only one of
+	// the processes sharing a storage backend runs them at a time. The
+	// runtime's scheme and registry must know the Lease type.
+	LeaderElection bool
+
+	// LeaderElectionID is the name of the lease. Required with LeaderElection.
+	LeaderElectionID string
+
+	// LeaderElectionNamespace is the namespace of the lease. Defaults to "default".
+	LeaderElectionNamespace string
+
+	// LeaseDuration is how long other processes wait before taking over a
+	// lease that has not been renewed. Defaults to leaderelection.DefaultLeaseDuration.
+	LeaseDuration time.Duration
+
+	// RenewDeadline is how long the leader retries renewing the lease before
+	// it stops. Defaults to leaderelection.DefaultRenewDeadline.
+	RenewDeadline time.Duration
+
+	// RetryPeriod is the interval between attempts to acquire or renew the
+	// lease. Defaults to leaderelection.DefaultRetryPeriod.
+	RetryPeriod time.Duration
+
+	// LeaderElectionReleaseOnCancel releases the lease once all runnables
+	// stopped, so the next process does not wait for the lease to expire.
+	LeaderElectionReleaseOnCancel bool
+}
+
+// New creates a manager for the runtime. The runtime's client must support
+// watches, which the shared informers rely on.
+func New(rt k1sruntime.Runtime, options Options) (Manager, error) {
+	if rt == nil {
+		return nil, errors.New("must specify a runtime")
+	}
+	c := rt.GetClient()
+	if c == nil {
+		return nil, errors.New("runtime does not provide a client")
+	}
+	if _, err := client.NewWatchClient(c); err != nil {
+		return nil, fmt.Errorf("runtime client does not support watches: %w", err)
+	}
+
+	if options.Logger.GetSink() == nil {
+		options.Logger = logf.Log
+	}
+
+	factoryOptions := []informers.SharedInformerFactoryOption{}
+	if options.Namespace != "" {
+		factoryOptions = append(factoryOptions, informers.WithNamespace(options.Namespace))
+	}
+
+	m := &controllerManager{
+		runtime:     rt,
+		client:      c,
+		informers:   informers.NewSharedInformerFactoryWithOptions(c, options.SyncPeriod, factoryOptions...),
+		logger:      options.Logger,
+		checkpoints: options.Checkpoints,
+		elected:     make(chan struct{}),
+	}
+
+	if options.LeaderElection {
+		if options.LeaderElectionID == "" {
+			return nil, errors.New("LeaderElectionID must be set when leader election is enabled")
+		}
+		namespace := options.LeaderElectionNamespace
+		if namespace == "" {
+			namespace = "default"
+		}
+		elector, err := leaderelection.New(leaderelection.Config{
+			Client:          c,
+			Namespace:       namespace,
+			Name:            options.LeaderElectionID,
+			LeaseDuration:   options.LeaseDuration,
+			RenewDeadline:   options.RenewDeadline,
+			RetryPeriod:     options.RetryPeriod,
+			ReleaseOnCancel: options.LeaderElectionReleaseOnCancel,
+			Logger:          options.Logger.WithName("leaderelection"),
+		}, leaderelection.Callbacks{
+			OnStartedLeading: func(context.Context) { m.startLeaderElectionRunnables() },
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to set up leader election: %w", err)
+		}
+		m.elector = elector
+	}
+
+	return m, nil
+}
+
+// controllerManager implements Manager
+type controllerManager struct {
+	runtime     k1sruntime.Runtime
+	client      client.Client
+	informers   informers.SharedInformerFactory
+	logger      logr.Logger
+	checkpoints checkpoint.Store
+	elector     *leaderelection.LeaderElector
+
+	mu          sync.Mutex
+	runnables   []Runnable
+	quiescers   []Quiescer
+	started     bool
+	ctx         context.Context
+	errCh       chan error
+	wg          sync.WaitGroup
+	informerSet map[schema.GroupVersionResource]toolscache.SharedIndexInformer
+
+	// leaderRunnables wait for the leader lease; elected is closed once
+	// the manager holds it
+	leaderRunnables []Runnable
+	elected         chan struct{}
+	isElected       bool
+}
+
+// Add registers a runnable or starts it if the manager is running
+func (m *controllerManager) Add(r Runnable) error {
+	if r == nil {
+		return errors.New("must specify a runnable")
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if q, ok := r.(Quiescer); ok {
+		m.quiescers = append(m.quiescers, q)
+	}
+
+	if !m.started {
+		m.runnables = append(m.runnables, r)
+		return nil
+	}
+	if m.needsToWaitForLeader(r) {
+		m.leaderRunnables = append(m.leaderRunnables, r)
+		return nil
+	}
+	m.startRunnable(r)
+	return nil
+}
+
+// needsToWaitForLeader reports whether r may only start once the manager is
+// the leader and the manager is not yet
+func (m *controllerManager) needsToWaitForLeader(r Runnable) bool {
+	if m.elector == nil || m.isElected {
+		return false
+	}
+	if ler, ok := r.(LeaderElectionRunnable); ok {
+		return ler.NeedLeaderElection()
+	}
+	return true
+}
+
+// startLeaderElectionRunnables starts the runnables that waited for the
+// leader lease
+func (m *controllerManager) startLeaderElectionRunnables() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if !m.started || m.isElected || m.ctx.Err() != nil {
+		return
+	}
+
+	m.isElected = true
+	close(m.elected)
+	for _, r := range m.leaderRunnables {
+		m.startRunnable(r)
+	}
+	m.leaderRunnables = nil
+}
+
+// Start runs all runnables until ctx is done or one of them fails
+func (m *controllerManager) Start(ctx context.Context) error {
+	return m.run(ctx, func() error {
+		select {
+		case <-ctx.Done():
+			return nil
+		case err := <-m.errCh:
+			return err
+		}
+	})
+}
+
+// run starts the runtime, the informers and all runnables, blocks in wait
+// and stops everything again
+func (m *controllerManager) run(ctx context.Context, wait func() error) (err error) {
+	m.mu.Lock()
+	if m.started {
+		m.mu.Unlock()
+		return errors.New("manager already started")
+	}
+
+	// The runtime is only stopped again if the manager started it
+	if !m.runtime.IsStarted() {
+		if err := m.runtime.Start(ctx); err != nil {
+			m.mu.Unlock()
+			return fmt.Errorf("failed to start runtime: %w", err)
+		}
+		defer func() {
+			if stopErr := m.runtime.Stop(context.Background()); stopErr != nil && err == nil {
+				err = fmt.Errorf("failed to stop runtime: %w", stopErr)
+			}
+		}()
+	}
+
+	runCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	m.ctx = runCtx
+	m.errCh = make(chan error, 1)
+	m.started = true
+
+	m.informers.Start(runCtx.Done())
+	for _, r := range m.runnables {
+		if m.needsToWaitForLeader(r) {
+			m.leaderRunnables = append(m.leaderRunnables, r)
+			continue
+		}
+		m.startRunnable(r)
+	}
+	m.runnables = nil
+	if m.elector == nil {
+		m.isElected = true
+		close(m.elected)
+	}
+	m.mu.Unlock()
+
+	// The election outlives the runnables, so a released lease is only
+	// taken over once they stopped
+	electionCtx, cancelElection := context.WithCancel(context.Background())
+	defer cancelElection()
+	electionDone := make(chan struct{})
+	if m.elector != nil {
+		go func() {
+			defer close(electionDone)
+			if err := m.elector.Run(electionCtx); err != nil {
+				m.logger.Error(err, "Leader election lost")
+				select {
+				case m.errCh <- err:
+				default:
+				}
+			}
+		}()
+	} else {
+		close(electionDone)
+	}
+
+	err = wait()
+
+	m.logger.Info("Stopping and waiting for runnables")
+	// Cancel under the lock, so no runnable is started once the wait begins
+	m.mu.Lock()
+	cancel()
+	m.mu.Unlock()
+	m.wg.Wait()
+	m.informers.Shutdown()
+	cancelElection()
+	<-electionDone
+
+	// Report errors of runnables that failed while stopping, such as a
+	// controller that could not save its checkpoint
+	if err == nil {
+		select {
+		case err = <-m.errCh:
+		default:
+		}
+	}
+	m.logger.Info("Stopped all runnables")
+
+	return err
+}
+
+// startRunnable runs r in the background and reports its first error
+func (m *controllerManager) startRunnable(r Runnable) {
+	m.wg.Add(1)
+	go func() {
+		defer m.wg.Done()
+		if err := r.Start(m.ctx); err != nil {
+			select {
+			case m.errCh <- err:
+			default:
+			}
+		}
+	}()
+}
+
+// GetClient returns the client of the runtime
+func (m *controllerManager) GetClient() client.Client {
+	return m.client
+}
+
+// GetScheme returns the scheme of the runtime's client
+func (m *controllerManager) GetScheme() *runtime.Scheme {
+	return m.client.Scheme()
+}
+
+// GetRESTMapper returns the REST mapper of the runtime's client
+func (m *controllerManager) GetRESTMapper() meta.RESTMapper {
+	return m.client.RESTMapper()
+}
+
+// GetEventRecorderFor returns a recorder that records through the runtime's event system
+func (m *controllerManager) GetEventRecorderFor(name string) events.EventRecorder {
+	return &lazyRecorder{runtime: m.runtime, component: name}
+}
+
+// GetInformer returns the shared informer for the type of obj
+func (m *controllerManager) GetInformer(_ context.Context, obj client.Object) (toolscache.SharedIndexInformer, error) {
+	gvr, err := m.resourceFor(obj)
+	if err != nil {
+		return nil, err
+	}
+
+	informer := m.informers.InformerFor(gvr)
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.informerSet == nil {
+		m.informerSet = make(map[schema.GroupVersionResource]toolscache.SharedIndexInformer)
+	}
+	m.informerSet[gvr] = informer
+	if m.started {
+		m.informers.Start(m.ctx.Done())
+	}
+	return informer, nil
+}
+
+// GetInformerFactory returns the shared informer factory
+func (m *controllerManager) GetInformerFactory() informers.SharedInformerFactory {
+	return m.informers
+}
+
+// GetRuntime returns the runtime of the manager
+func (m *controllerManager) GetRuntime() k1sruntime.Runtime {
+	return m.runtime
+}
+
+// Elected is closed once the manager runs the runnables that need leader election
+func (m *controllerManager) Elected() <-chan struct{} {
+	return m.elected
+}
+
+// GetLogger returns the logger of the manager
+func (m *controllerManager) GetLogger() logr.Logger {
+	return m.logger
+}
+
+// GetCheckpointStore returns the checkpoint store of the manager
+func (m *controllerManager) GetCheckpointStore() checkpoint.Store {
+	return m.checkpoints
+}
+
+// resourceFor returns the resource of the type of obj, using the REST mapper
+// if available and the conventional plural of the kind otherwise
+func (m *controllerManager) resourceFor(obj client.Object) (schema.GroupVersionResource, error) {
+	gvk, err := GVKForObject(obj, m.GetScheme())
+	if err != nil {
+		return schema.GroupVersionResource{}, err
+	}
+
+	if mapper := m.GetRESTMapper(); mapper != nil {
+		if mapping, err := mapper.RESTMapping(gvk.GroupKind(), gvk.Version); err == nil {
+			return mapping.Resource, nil
+		}
+	}
+
+	gvr, _ := meta.UnsafeGuessKindToResource(gvk)
+	return gvr, nil
+}
+
+// GVKForObject returns the kind of obj as registered with the scheme.
+func GVKForObject(obj runtime.Object, scheme *runtime.Scheme) (schema.GroupVersionKind, error) {
+	if scheme == nil {
+		return schema.GroupVersionKind{}, fmt.Errorf("cannot determine the kind of %T without a scheme", obj)
+	}
+	kinds, _, err := scheme.ObjectKinds(obj)
+	if err != nil {
+		return schema.GroupVersionKind{}, err
+	}
+	if len(kinds) != 1 {
+		return schema.GroupVersionKind{}, fmt.Errorf("expected exactly 1 kind for %T, but found %d kinds", obj, len(kinds))
+	}
+	return kinds[0], nil
+}
@@ -0,0 +1,60 @@
+package manager
+
+import (
+	"sync"
+
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/dtomasi/k1s/core/events"
+	k1sruntime "github.com/dtomasi/k1s/core/runtime"
+)
+
+// lazyRecorder resolves the runtime's recorder when an event is recorded,
+// since the event broadcaster only exists while the runtime is started
+type lazyRecorder struct {
+	runtime   k1sruntime.Runtime
+	component string
+
+	mu          sync.Mutex
+	broadcaster events.EventBroadcaster
+	recorder    events.EventRecorder
+}
+
+var _ events.EventRecorder = &lazyRecorder{}
+
+// Event records an event
+func (r *lazyRecorder) Event(object runtime.Object, eventtype, reason, message string) {
+	if rec := r.get(); rec != nil {
+		rec.Event(object, eventtype, reason, message)
+	}
+}
+
+// Eventf records an event with a formatted message
+func (r *lazyRecorder) Eventf(object runtime.Object, eventtype, reason, messageFmt string, args ...interface{}) {
+	if rec := r.get(); rec != nil {
+		rec.Eventf(object, eventtype, reason, messageFmt, args...)
+	}
+}
+
+// AnnotatedEventf records an event with annotations and a formatted message
+func (r *lazyRecorder) AnnotatedEventf(object runtime.Object, annotations map[string]string, eventtype, reason, messageFmt string, args ...interface{}) {
+	if rec := r.get(); rec != nil {
+		rec.AnnotatedEventf(object, annotations, eventtype, reason, messageFmt, args...)
+	}
+}
+
+// get returns the recorder of the current broadcaster, or nil if events are not running
+func (r *lazyRecorder) get() events.EventRecorder {
+	broadcaster := r.runtime.GetEventBroadcaster()
+	if broadcaster == nil {
+		return nil
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.broadcaster != broadcaster {
+		r.broadcaster = broadcaster
+		r.recorder = r.runtime.GetEventRecorder(r.component)
+	}
+	return r.recorder
+}
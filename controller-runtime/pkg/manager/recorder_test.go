@@ -0,0 +1,121 @@
+package manager_test
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	eventsv1 "k8s.io/api/events/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/dtomasi/k1s/core/events"
+	k1sruntime "github.com/dtomasi/k1s/core/runtime"
+	k1sstorage "github.com/dtomasi/k1s/core/storage"
+	memory "github.com/dtomasi/k1s/storage/memory"
+
+	"github.com/dtomasi/k1s/controller-runtime/pkg/manager"
+)
+
+var _ = Describe("Event recorder", func() {
+	It("should store repeated events as one event with a count", func() {
+		ctx := context.Background()
+		rt := newRuntimeWithOptions(memory.NewMemoryStorage(k1sstorage.Config{}), k1sruntime.RuntimeOptions{
+			EnableEvents: true,
+		})
+		Expect(rt.Start(ctx)).To(Succeed())
+		defer func() { Expect(rt.Stop(ctx)).To(Succeed()) }()
+
+		mgr, err := manager.New(rt, manager.Options{})
+		Expect(err).NotTo(HaveOccurred())
+
+		cm := configMap("failing")
+		Expect(mgr.GetClient().Create(ctx, cm)).To(Succeed())
+
+		recorder := mgr.GetEventRecorderFor("test-controller")
+		for i := 0; i < 500; i++ {
+			recorder.Event(cm, corev1.EventTypeWarning, "ReconcileError", "reconcile failed")
+		}
+
+		failures := func() []corev1.Event {
+			list := &corev1.EventList{}
+			Expect(mgr.GetClient().List(ctx, list)).To(Succeed())
+			var result []corev1.Event
+			for _, event := range list.Items {
+				if event.Reason == "ReconcileError" {
+					result = append(result, event)
+				}
+			}
+			return result
+		}
+		Eventually(failures).Should(ConsistOf(HaveField("Count", int32(500))))
+	})
+
+	It("should store events in both APIs", func() {
+		ctx := context.Background()
+		rt := newRuntimeWithOptions(memory.NewMemoryStorage(k1sstorage.Config{}), k1sruntime.RuntimeOptions{
+			EnableEvents: true,
+			EventAPI:     events.EventAPIBoth,
+		})
+		Expect(rt.Start(ctx)).To(Succeed())
+		defer func() { Expect(rt.Stop(ctx)).To(Succeed()) }()
+
+		cm := configMap("failing")
+		Expect(rt.GetClient().Create(ctx, cm)).To(Succeed())
+
+		recorder := rt.GetEventBroadcaster().NewEventsRecorder(rt.GetClient().Scheme(), events.NewEventSource("test-controller"))
+		for i := 0; i < 5; i++ {
+			recorder.Eventf(cm, nil, corev1.EventTypeWarning, "ReconcileError", "Reconcile", "reconcile failed")
+		}
+
+		series := func() []eventsv1.Event {
+			list := &eventsv1.EventList{}
+			Expect(rt.GetClient().List(ctx, list)).To(Succeed())
+			return list.Items
+		}
+		Eventually(series).Should(ConsistOf(And(
+			HaveField("ReportingController", "test-controller"),
+			HaveField("Action", "Reconcile"),
+			HaveField("Series.Count", int32(5)),
+		)))
+
+		list := &corev1.EventList{}
+		Expect(rt.GetClient().List(ctx, list)).To(Succeed())
+		Expect(list.Items).To(ConsistOf(HaveField("Count", int32(5))))
+	})
+
+	It("should prune stale events when the runtime starts", func() {
+		ctx := context.Background()
+		rt := newRuntimeWithOptions(memory.NewMemoryStorage(k1sstorage.Config{}), k1sruntime.RuntimeOptions{
+			EnableEvents: true,
+			EventBroadcasterOptions: events.EventBroadcasterOptions{
+				Retention: events.EventRetentionOptions{Enabled: true},
+			},
+		})
+
+		for name, age := range map[string]time.Duration{"stale": 2 * time.Hour, "fresh": time.Minute} {
+			event := &corev1.Event{
+				ObjectMeta:     metav1.ObjectMeta{Name: name, Namespace: "default"},
+				InvolvedObject: corev1.ObjectReference{Kind: "ConfigMap", Namespace: "default", Name: "config"},
+				LastTimestamp:  metav1.NewTime(time.Now().Add(-age)),
+			}
+			Expect(rt.GetClient().Create(ctx, event)).To(Succeed())
+		}
+
+		Expect(rt.Start(ctx)).To(Succeed())
+		defer func() { Expect(rt.Stop(ctx)).To(Succeed()) }()
+
+		names := func() []string {
+			list := &corev1.EventList{}
+			Expect(rt.GetClient().List(ctx, list)).To(Succeed())
+			var result []string
+			for _, event := range list.Items {
+				result = append(result, event.Name)
+			}
+			return result
+		}
+		Eventually(names).Should(ConsistOf("fresh"))
+	})
+})
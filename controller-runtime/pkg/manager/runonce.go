@@ -0,0 +1,170 @@
+package manager
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/dtomasi/k1s/controller-runtime/pkg/source"
+)
+
+const (
+	// DefaultQuietPeriod is how long all controllers must stay quiescent
+	// before RunOnce considers the work done. It covers the delay between a
+	// reconciler's write and the resulting watch event.
+	DefaultQuietPeriod = 100 * time.Millisecond
+
+	// quiescencePollInterval is how often RunOnce checks the controllers
+	quiescencePollInterval = 10 * time.Millisecond
+)
+
+// RunOnceOption configures Manager.RunOnce.
+type RunOnceOption func(*runOnceOptions)
+
+type runOnceOptions struct {
+	requeueHorizon time.Duration
+	quietPeriod    time.Duration
+	changedSince   string
+}
+
+// WithRequeueHorizon makes RunOnce wait for delayed requeues, such as a
+// reconcile.Result with RequeueAfter or the backoff after an error, that are
+// due within d after RunOnce was called. Later requeues are abandoned. By
+// default RunOnce does not wait for delayed requeues.
+func WithRequeueHorizon(d time.Duration) RunOnceOption {
+	return func(o *runOnceOptions) {
+		o.requeueHorizon = d
+	}
+}
+
+// WithQuietPeriod sets how long all controllers must stay quiescent before
+// RunOnce returns. Defaults to DefaultQuietPeriod.
+func WithQuietPeriod(d time.Duration) RunOnceOption {
+	return func(o *runOnceOptions) {
+		o.quietPeriod = d
+	}
+}
+
+// WithChangedSince limits the initial reconciles to objects whose
+// resourceVersion is newer than resourceVersion, typically the value of
+// LastObservedResourceVersion after the previous run. Objects that changed
+// while RunOnce is running are always reconciled. An empty or non-numeric
+// resourceVersion reconciles every object.
+func WithChangedSince(resourceVersion string) RunOnceOption {
+	return func(o *runOnceOptions) {
+		o.changedSince = resourceVersion
+	}
+}
+
+// RunOnce starts the runtime, the shared informers and all runnables, and
+// returns once every controller has synced, its workqueue is empty, no
+// reconcile is in flight and no delayed requeue is due within the requeue
+// horizon. Runnables that do not implement Quiescer are stopped together
+// with the controllers. RunOnce returns the first error of a runnable, or
+// ctx.Err() if ctx is done before the work is finished.
+//
+// This allows CLI commands to run controllers synchronously:
+//
+//	if err := mgr.RunOnce(cmd.Context(), manager.WithRequeueHorizon(5*time.Second)); err != nil {
+//		return err
+//	}
+func (m *controllerManager) RunOnce(ctx context.Context, opts ...RunOnceOption) error {
+	options := runOnceOptions{quietPeriod: DefaultQuietPeriod}
+	for _, opt := range opts {
+		opt(&options)
+	}
+	deadline := time.Now().Add(options.requeueHorizon)
+
+	if options.changedSince != "" {
+		ctx = source.WithChangedSince(ctx, options.changedSince)
+	}
+
+	return m.run(ctx, func() error {
+		return m.waitForQuiescence(ctx, deadline, options.quietPeriod)
+	})
+}
+
+// waitForQuiescence blocks until all quiescers stay quiescent for the quiet period
+func (m *controllerManager) waitForQuiescence(ctx context.Context, deadline time.Time, quietPeriod time.Duration) error {
+	ticker := time.NewTicker(quiescencePollInterval)
+	defer ticker.Stop()
+
+	var quietSince time.Time
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case err := <-m.errCh:
+			return err
+		case now := <-ticker.C:
+			quiescent, next := m.quiescent(deadline)
+			if !quiescent {
+				quietSince = time.Time{}
+				continue
+			}
+			if quietSince.IsZero() {
+				quietSince = now
+			}
+			if now.Sub(quietSince) < quietPeriod {
+				continue
+			}
+
+			if !next.IsZero() {
+				m.logger.Info("Abandoning requeues due after the requeue horizon", "next", next)
+			}
+			return nil
+		}
+	}
+}
+
+// quiescent reports whether all quiescers are done with the work due before
+// the deadline, and returns the earliest delayed work due after it
+func (m *controllerManager) quiescent(deadline time.Time) (bool, time.Time) {
+	m.mu.Lock()
+	quiescers := append([]Quiescer(nil), m.quiescers...)
+	m.mu.Unlock()
+
+	var next time.Time
+	for _, q := range quiescers {
+		quiescent, due := q.Quiescent()
+		if !quiescent {
+			return false, time.Time{}
+		}
+		if due.IsZero() {
+			continue
+		}
+		if !due.After(deadline) {
+			return false, time.Time{}
+		}
+		if next.IsZero() || due.Before(next) {
+			next = due
+		}
+	}
+	return true, next
+}
+
+// LastObservedResourceVersion returns the highest resourceVersion the informers have observed
+func (m *controllerManager) LastObservedResourceVersion() string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var last uint64
+	for _, informer := range m.informerSet {
+		if rv := parseResourceVersion(informer.LastSyncResourceVersion()); rv > last {
+			last = rv
+		}
+	}
+	if last == 0 {
+		return ""
+	}
+	return strconv.FormatUint(last, 10)
+}
+
+// parseResourceVersion returns the numeric value of a resourceVersion, or 0
+func parseResourceVersion(resourceVersion string) uint64 {
+	rv, err := strconv.ParseUint(resourceVersion, 10, 64)
+	if err != nil {
+		return 0
+	}
+	return rv
+}
@@ -0,0 +1,204 @@
+package manager_test
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/dtomasi/k1s/core/client"
+	"github.com/dtomasi/k1s/core/registry"
+	k1sruntime "github.com/dtomasi/k1s/core/runtime"
+	k1sstorage "github.com/dtomasi/k1s/core/storage"
+	corev1types "github.com/dtomasi/k1s/core/types/v1"
+	memory "github.com/dtomasi/k1s/storage/memory"
+
+	"github.com/dtomasi/k1s/controller-runtime/pkg/builder"
+	"github.com/dtomasi/k1s/controller-runtime/pkg/manager"
+	"github.com/dtomasi/k1s/controller-runtime/pkg/reconcile"
+)
+
+func newRuntime() k1sruntime.Runtime {
+	return newRuntimeWithStorage(memory.NewMemoryStorage(k1sstorage.Config{}))
+}
+
+func newRuntimeWithStorage(storage k1sstorage.Interface) k1sruntime.Runtime {
+	return newRuntimeWithOptions(storage, k1sruntime.RuntimeOptions{})
+}
+
+// newRuntimeWithOptions creates a runtime with a client on the storage
+func newRuntimeWithOptions(storage k1sstorage.Interface, options k1sruntime.RuntimeOptions) k1sruntime.Runtime {
+	scheme := runtime.NewScheme()
+	Expect(corev1types.AddToScheme(scheme)).To(Succeed())
+
+	reg := registry.NewRegistry()
+	Expect(registry.RegisterCoreResources(reg)).To(Succeed())
+
+	c, err := client.NewClient(client.ClientOptions{
+		Scheme:   scheme,
+		Storage:  storage,
+		Registry: reg,
+	})
+	Expect(err).NotTo(HaveOccurred())
+
+	options.Client = c
+	options.Scheme = scheme
+	rt, err := k1sruntime.NewRuntimeWithOptions(options)
+	Expect(err).NotTo(HaveOccurred())
+	return rt
+}
+
+// counter counts the reconciles per object name
+type counter struct {
+	mu     sync.Mutex
+	counts map[string]int
+}
+
+func (c *counter) add(name string) int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.counts == nil {
+		c.counts = map[string]int{}
+	}
+	c.counts[name]++
+	return c.counts[name]
+}
+
+func (c *counter) get() map[string]int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	out := map[string]int{}
+	for k, v := range c.counts {
+		out[k] = v
+	}
+	return out
+}
+
+func configMap(name string) *corev1.ConfigMap {
+	return &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"}}
+}
+
+var _ = Describe("RunOnce", func() {
+	var (
+		ctx context.Context
+		rt  k1sruntime.Runtime
+		c   client.Client
+		cnt *counter
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		rt = newRuntime()
+		c = rt.GetClient()
+		cnt = &counter{}
+	})
+
+	// setup creates a manager with a ConfigMap controller running fn
+	setup := func(fn func(context.Context, reconcile.Request) (reconcile.Result, error)) manager.Manager {
+		mgr, err := manager.New(rt, manager.Options{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(builder.ControllerManagedBy(mgr).
+			For(&corev1.ConfigMap{}).
+			Complete(reconcile.Func(fn))).To(Succeed())
+		return mgr
+	}
+
+	It("should reconcile every object and return", func() {
+		Expect(c.Create(ctx, configMap("a"))).To(Succeed())
+		Expect(c.Create(ctx, configMap("b"))).To(Succeed())
+
+		mgr := setup(func(_ context.Context, req reconcile.Request) (reconcile.Result, error) {
+			cnt.add(req.Name)
+			return reconcile.Result{}, nil
+		})
+
+		Expect(mgr.RunOnce(ctx)).To(Succeed())
+		Expect(cnt.get()).To(Equal(map[string]int{"a": 1, "b": 1}))
+		Expect(rt.IsStarted()).To(BeFalse())
+	})
+
+	It("should process work triggered by reconcilers before returning", func() {
+		Expect(c.Create(ctx, configMap("step-1"))).To(Succeed())
+
+		mgr := setup(func(ctx context.Context, req reconcile.Request) (reconcile.Result, error) {
+			cnt.add(req.Name)
+			if req.Name == "step-1" {
+				err := c.Create(ctx, configMap("step-2"))
+				if err != nil && !apierrors.IsAlreadyExists(err) {
+					return reconcile.Result{}, err
+				}
+			}
+			return reconcile.Result{}, nil
+		})
+
+		Expect(mgr.RunOnce(ctx)).To(Succeed())
+		Expect(cnt.get()).To(HaveKeyWithValue("step-2", 1))
+	})
+
+	It("should wait for requeues within the horizon only", func() {
+		Expect(c.Create(ctx, configMap("soon"))).To(Succeed())
+		Expect(c.Create(ctx, configMap("later"))).To(Succeed())
+
+		mgr := setup(func(_ context.Context, req reconcile.Request) (reconcile.Result, error) {
+			if cnt.add(req.Name) > 1 {
+				return reconcile.Result{}, nil
+			}
+			if req.Name == "soon" {
+				return reconcile.Result{RequeueAfter: 50 * time.Millisecond}, nil
+			}
+			return reconcile.Result{RequeueAfter: time.Hour}, nil
+		})
+
+		started := time.Now()
+		Expect(mgr.RunOnce(ctx, manager.WithRequeueHorizon(time.Second))).To(Succeed())
+		Expect(time.Since(started)).To(BeNumerically("<", 5*time.Second))
+		Expect(cnt.get()).To(Equal(map[string]int{"soon": 2, "later": 1}))
+	})
+
+	It("should only reconcile objects changed since the last run", func() {
+		Expect(c.Create(ctx, configMap("old"))).To(Succeed())
+
+		first := setup(func(_ context.Context, req reconcile.Request) (reconcile.Result, error) {
+			return reconcile.Result{}, nil
+		})
+		Expect(first.RunOnce(ctx)).To(Succeed())
+		lastRV := first.LastObservedResourceVersion()
+		Expect(lastRV).NotTo(BeEmpty())
+
+		Expect(c.Create(ctx, configMap("new"))).To(Succeed())
+
+		second := setup(func(_ context.Context, req reconcile.Request) (reconcile.Result, error) {
+			cnt.add(req.Name)
+			return reconcile.Result{}, nil
+		})
+		Expect(second.RunOnce(ctx, manager.WithChangedSince(lastRV))).To(Succeed())
+		Expect(cnt.get()).To(Equal(map[string]int{"new": 1}))
+	})
+
+	It("should return the context error when cancelled", func() {
+		Expect(c.Create(ctx, configMap("stuck"))).To(Succeed())
+
+		mgr := setup(func(context.Context, reconcile.Request) (reconcile.Result, error) {
+			return reconcile.Result{RequeueAfter: 10 * time.Millisecond}, nil
+		})
+
+		cancelCtx, cancel := context.WithTimeout(ctx, 200*time.Millisecond)
+		defer cancel()
+		Expect(mgr.RunOnce(cancelCtx, manager.WithRequeueHorizon(time.Hour))).To(MatchError(context.DeadlineExceeded))
+	})
+
+	It("should not start a manager twice", func() {
+		mgr := setup(func(context.Context, reconcile.Request) (reconcile.Result, error) {
+			return reconcile.Result{}, nil
+		})
+		Expect(mgr.RunOnce(ctx)).To(Succeed())
+		Expect(mgr.RunOnce(ctx)).NotTo(Succeed())
+	})
+})
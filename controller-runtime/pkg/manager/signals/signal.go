@@ -0,0 +1,26 @@
+// Package signals provides a context that is cancelled on SIGINT and SIGTERM.
+package signals
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"syscall"
+)
+
+// SetupSignalHandler returns a context that is cancelled on the first SIGINT
+// or SIGTERM. A second signal terminates the program with exit code 1.
+func SetupSignalHandler() context.Context {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	c := make(chan os.Signal, 2)
+	signal.Notify(c, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		<-c
+		cancel()
+		<-c
+		os.Exit(1)
+	}()
+
+	return ctx
+}
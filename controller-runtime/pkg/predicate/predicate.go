@@ -0,0 +1,296 @@
+// Package predicate filters the events a source passes to its event handler.
+// It mirrors sigs.k8s.io/controller-runtime/pkg/predicate.
+//
+// The client increments metadata.generation only when an update changes the
+// object outside of its metadata and status, so GenerationChangedPredicate
+// lets reconcilers that only care about the spec ignore status updates.
+package predicate
+
+import (
+	"maps"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+
+	"github.com/dtomasi/k1s/core/client"
+
+	"github.com/dtomasi/k1s/controller-runtime/pkg/event"
+)
+
+// Predicate filters events before they are passed to an event handler.
+type Predicate interface {
+	// Create returns true if the create event should be processed.
+	Create(event.CreateEvent) bool
+
+	// Delete returns true if the delete event should be processed.
+	Delete(event.DeleteEvent) bool
+
+	// Update returns true if the update event should be processed.
+	Update(event.UpdateEvent) bool
+
+	// Generic returns true if the generic event should be processed.
+	Generic(event.GenericEvent) bool
+}
+
+var _ Predicate = Funcs{}
+var _ Predicate = ResourceVersionChangedPredicate{}
+var _ Predicate = GenerationChangedPredicate{}
+var _ Predicate = AnnotationChangedPredicate{}
+var _ Predicate = LabelChangedPredicate{}
+var _ Predicate = or{}
+var _ Predicate = and{}
+var _ Predicate = not{}
+
+// Funcs implements Predicate with functions. Events without a function are
+// processed.
+type Funcs struct {
+	// CreateFunc filters create events.
+	CreateFunc func(event.CreateEvent) bool
+
+	// DeleteFunc filters delete events.
+	DeleteFunc func(event.DeleteEvent) bool
+
+	// UpdateFunc filters update events.
+	UpdateFunc func(event.UpdateEvent) bool
+
+	// GenericFunc filters generic events.
+	GenericFunc func(event.GenericEvent) bool
+}
+
+// Create implements Predicate.
+func (p Funcs) Create(e event.CreateEvent) bool {
+	if p.CreateFunc != nil {
+		return p.CreateFunc(e)
+	}
+	return true
+}
+
+// Delete implements Predicate.
+func (p Funcs) Delete(e event.DeleteEvent) bool {
+	if p.DeleteFunc != nil {
+		return p.DeleteFunc(e)
+	}
+	return true
+}
+
+// Update implements Predicate.
+func (p Funcs) Update(e event.UpdateEvent) bool {
+	if p.UpdateFunc != nil {
+		return p.UpdateFunc(e)
+	}
+	return true
+}
+
+// Generic implements Predicate.
+func (p Funcs) Generic(e event.GenericEvent) bool {
+	if p.GenericFunc != nil {
+		return p.GenericFunc(e)
+	}
+	return true
+}
+
+// NewPredicateFuncs returns a predicate that processes the events whose
+// object passes filter. Update events are filtered by their new object.
+func NewPredicateFuncs(filter func(object client.Object) bool) Funcs {
+	return Funcs{
+		CreateFunc: func(e event.CreateEvent) bool {
+			return filter(e.Object)
+		},
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			return filter(e.ObjectNew)
+		},
+		DeleteFunc: func(e event.DeleteEvent) bool {
+			return filter(e.Object)
+		},
+		GenericFunc: func(e event.GenericEvent) bool {
+			return filter(e.Object)
+		},
+	}
+}
+
+// ResourceVersionChangedPredicate skips update events whose resourceVersion
+// did not change, such as the periodic resyncs of informers.
+type ResourceVersionChangedPredicate struct {
+	Funcs
+}
+
+// Update implements Predicate.
+func (ResourceVersionChangedPredicate) Update(e event.UpdateEvent) bool {
+	if e.ObjectOld == nil || e.ObjectNew == nil {
+		return false
+	}
+	return e.ObjectNew.GetResourceVersion() != e.ObjectOld.GetResourceVersion()
+}
+
+// GenerationChangedPredicate skips update events whose metadata.generation
+// did not change. The client only increments the generation for changes of
+// the spec, so updates of the status or metadata are skipped.
+//
+// Updates of the metadata that a reconciler must see, such as a set
+// deletionTimestamp or changed finalizers, are skipped as well; combine it
+// with other predicates with Or if needed.
+type GenerationChangedPredicate struct {
+	Funcs
+}
+
+// Update implements Predicate.
+func (GenerationChangedPredicate) Update(e event.UpdateEvent) bool {
+	if e.ObjectOld == nil || e.ObjectNew == nil {
+		return false
+	}
+	return e.ObjectNew.GetGeneration() != e.ObjectOld.GetGeneration()
+}
+
+// AnnotationChangedPredicate skips update events whose annotations did not change.
+type AnnotationChangedPredicate struct {
+	Funcs
+}
+
+// Update implements Predicate.
+func (AnnotationChangedPredicate) Update(e event.UpdateEvent) bool {
+	if e.ObjectOld == nil || e.ObjectNew == nil {
+		return false
+	}
+	return !maps.Equal(e.ObjectNew.GetAnnotations(), e.ObjectOld.GetAnnotations())
+}
+
+// LabelChangedPredicate skips update events whose labels did not change.
+type LabelChangedPredicate struct {
+	Funcs
+}
+
+// Update implements Predicate.
+func (LabelChangedPredicate) Update(e event.UpdateEvent) bool {
+	if e.ObjectOld == nil || e.ObjectNew == nil {
+		return false
+	}
+	return !maps.Equal(e.ObjectNew.GetLabels(), e.ObjectOld.GetLabels())
+}
+
+// And returns a predicate that processes the events all predicates process.
+func And(predicates ...Predicate) Predicate {
+	return and{predicates}
+}
+
+type and struct {
+	predicates []Predicate
+}
+
+func (a and) Create(e event.CreateEvent) bool {
+	for _, p := range a.predicates {
+		if !p.Create(e) {
+			return false
+		}
+	}
+	return true
+}
+
+func (a and) Update(e event.UpdateEvent) bool {
+	for _, p := range a.predicates {
+		if !p.Update(e) {
+			return false
+		}
+	}
+	return true
+}
+
+func (a and) Delete(e event.DeleteEvent) bool {
+	for _, p := range a.predicates {
+		if !p.Delete(e) {
+			return false
+		}
+	}
+	return true
+}
+
+func (a and) Generic(e event.GenericEvent) bool {
+	for _, p := range a.predicates {
+		if !p.Generic(e) {
+			return false
+		}
+	}
+	return true
+}
+
+// Or returns a predicate that processes the events any of the predicates
+// processes.
+func Or(predicates ...Predicate) Predicate {
+	return or{predicates}
+}
+
+type or struct {
+	predicates []Predicate
+}
+
+func (o or) Create(e event.CreateEvent) bool {
+	for _, p := range o.predicates {
+		if p.Create(e) {
+			return true
+		}
+	}
+	return false
+}
+
+func (o or) Update(e event.UpdateEvent) bool {
+	for _, p := range o.predicates {
+		if p.Update(e) {
+			return true
+		}
+	}
+	return false
+}
+
+func (o or) Delete(e event.DeleteEvent) bool {
+	for _, p := range o.predicates {
+		if p.Delete(e) {
+			return true
+		}
+	}
+	return false
+}
+
+func (o or) Generic(e event.GenericEvent) bool {
+	for _, p := range o.predicates {
+		if p.Generic(e) {
+			return true
+		}
+	}
+	return false
+}
+
+// Not returns a predicate that processes the events the predicate skips.
+func Not(predicate Predicate) Predicate {
+	return not{predicate}
+}
+
+type not struct {
+	predicate Predicate
+}
+
+func (n not) Create(e event.CreateEvent) bool {
+	return !n.predicate.Create(e)
+}
+
+func (n not) Update(e event.UpdateEvent) bool {
+	return !n.predicate.Update(e)
+}
+
+func (n not) Delete(e event.DeleteEvent) bool {
+	return !n.predicate.Delete(e)
+}
+
+func (n not) Generic(e event.GenericEvent) bool {
+	return !n.predicate.Generic(e)
+}
+
+// LabelSelectorPredicate returns a predicate that processes the events of
+// objects whose labels match the selector.
+func LabelSelectorPredicate(s metav1.LabelSelector) (Predicate, error) {
+	selector, err := metav1.LabelSelectorAsSelector(&s)
+	if err != nil {
+		return Funcs{}, err
+	}
+	return NewPredicateFuncs(func(o client.Object) bool {
+		return selector.Matches(labels.Set(o.GetLabels()))
+	}), nil
+}
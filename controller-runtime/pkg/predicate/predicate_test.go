@@ -0,0 +1,125 @@
+package predicate_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/dtomasi/k1s/core/client"
+
+	"github.com/dtomasi/k1s/controller-runtime/pkg/event"
+	"github.com/dtomasi/k1s/controller-runtime/pkg/predicate"
+)
+
+// update returns an update event from a ConfigMap to a modified copy of it
+func update(modify func(cm *corev1.ConfigMap)) event.UpdateEvent {
+	old := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{
+		Name:            "config",
+		Namespace:       "default",
+		ResourceVersion: "1",
+		Generation:      1,
+		Labels:          map[string]string{"app": "k1s"},
+		Annotations:     map[string]string{"note": "one"},
+	}}
+	updated := old.DeepCopy()
+	modify(updated)
+	return event.UpdateEvent{ObjectOld: old, ObjectNew: updated}
+}
+
+var _ = Describe("Predicates", func() {
+	var (
+		matching = &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "a", Labels: map[string]string{"app": "k1s"}}}
+		other    = &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "b"}}
+		never    = predicate.Funcs{
+			CreateFunc:  func(event.CreateEvent) bool { return false },
+			UpdateFunc:  func(event.UpdateEvent) bool { return false },
+			DeleteFunc:  func(event.DeleteEvent) bool { return false },
+			GenericFunc: func(event.GenericEvent) bool { return false },
+		}
+		always = predicate.Funcs{}
+	)
+
+	It("should process all events without functions", func() {
+		Expect(always.Create(event.CreateEvent{Object: other})).To(BeTrue())
+		Expect(always.Update(event.UpdateEvent{ObjectOld: other, ObjectNew: other})).To(BeTrue())
+		Expect(always.Delete(event.DeleteEvent{Object: other})).To(BeTrue())
+		Expect(always.Generic(event.GenericEvent{Object: other})).To(BeTrue())
+	})
+
+	It("should filter all events by the object", func() {
+		p := predicate.NewPredicateFuncs(func(o client.Object) bool { return o.GetName() == "a" })
+		Expect(p.Create(event.CreateEvent{Object: matching})).To(BeTrue())
+		Expect(p.Create(event.CreateEvent{Object: other})).To(BeFalse())
+		Expect(p.Update(event.UpdateEvent{ObjectOld: matching, ObjectNew: other})).To(BeFalse())
+		Expect(p.Delete(event.DeleteEvent{Object: matching})).To(BeTrue())
+		Expect(p.Generic(event.GenericEvent{Object: other})).To(BeFalse())
+	})
+
+	DescribeTable("update predicates",
+		func(p predicate.Predicate, modify func(cm *corev1.ConfigMap), expected bool) {
+			Expect(p.Update(update(modify))).To(Equal(expected))
+		},
+		Entry("resourceVersion changed", predicate.ResourceVersionChangedPredicate{},
+			func(cm *corev1.ConfigMap) { cm.ResourceVersion = "2" }, true),
+		Entry("resourceVersion unchanged", predicate.ResourceVersionChangedPredicate{},
+			func(*corev1.ConfigMap) {}, false),
+		Entry("generation changed", predicate.GenerationChangedPredicate{},
+			func(cm *corev1.ConfigMap) { cm.Generation = 2 }, true),
+		Entry("generation unchanged", predicate.GenerationChangedPredicate{},
+			func(cm *corev1.ConfigMap) { cm.ResourceVersion = "2" }, false),
+		Entry("annotations changed", predicate.AnnotationChangedPredicate{},
+			func(cm *corev1.ConfigMap) { cm.Annotations["note"] = "two" }, true),
+		Entry("annotations unchanged", predicate.AnnotationChangedPredicate{},
+			func(cm *corev1.ConfigMap) { cm.Labels["app"] = "other" }, false),
+		Entry("labels changed", predicate.LabelChangedPredicate{},
+			func(cm *corev1.ConfigMap) { cm.Labels = nil }, true),
+		Entry("labels unchanged", predicate.LabelChangedPredicate{},
+			func(cm *corev1.ConfigMap) { cm.Annotations = nil }, false),
+	)
+
+	It("should skip update events without both objects", func() {
+		e := event.UpdateEvent{ObjectNew: other}
+		Expect(predicate.ResourceVersionChangedPredicate{}.Update(e)).To(BeFalse())
+		Expect(predicate.GenerationChangedPredicate{}.Update(e)).To(BeFalse())
+		Expect(predicate.AnnotationChangedPredicate{}.Update(e)).To(BeFalse())
+		Expect(predicate.LabelChangedPredicate{}.Update(e)).To(BeFalse())
+	})
+
+	It("should process the other events of update predicates", func() {
+		p := predicate.GenerationChangedPredicate{}
+		Expect(p.Create(event.CreateEvent{Object: other})).To(BeTrue())
+		Expect(p.Delete(event.DeleteEvent{Object: other})).To(BeTrue())
+		Expect(p.Generic(event.GenericEvent{Object: other})).To(BeTrue())
+	})
+
+	It("should combine predicates", func() {
+		e := event.CreateEvent{Object: other}
+		Expect(predicate.And(always, always).Create(e)).To(BeTrue())
+		Expect(predicate.And(always, never).Create(e)).To(BeFalse())
+		Expect(predicate.And().Create(e)).To(BeTrue())
+		Expect(predicate.Or(never, always).Create(e)).To(BeTrue())
+		Expect(predicate.Or(never, never).Create(e)).To(BeFalse())
+		Expect(predicate.Or().Create(e)).To(BeFalse())
+		Expect(predicate.Not(never).Create(e)).To(BeTrue())
+		Expect(predicate.Not(always).Delete(event.DeleteEvent{Object: other})).To(BeFalse())
+
+		changed := update(func(cm *corev1.ConfigMap) { cm.Labels = nil })
+		spec := predicate.Or(predicate.GenerationChangedPredicate{}, predicate.LabelChangedPredicate{})
+		Expect(spec.Update(changed)).To(BeTrue())
+		Expect(predicate.And(predicate.GenerationChangedPredicate{}, predicate.LabelChangedPredicate{}).Update(changed)).To(BeFalse())
+	})
+
+	It("should filter by a label selector", func() {
+		p, err := predicate.LabelSelectorPredicate(metav1.LabelSelector{MatchLabels: map[string]string{"app": "k1s"}})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(p.Create(event.CreateEvent{Object: matching})).To(BeTrue())
+		Expect(p.Create(event.CreateEvent{Object: other})).To(BeFalse())
+
+		_, err = predicate.LabelSelectorPredicate(metav1.LabelSelector{MatchExpressions: []metav1.LabelSelectorRequirement{{
+			Key: "app", Operator: "Unknown",
+		}}})
+		Expect(err).To(HaveOccurred())
+	})
+})
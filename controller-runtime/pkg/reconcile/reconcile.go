@@ -0,0 +1,87 @@
+// Package reconcile defines the Reconciler interface implemented by k1s
+// controllers. It mirrors sigs.k8s.io/controller-runtime/pkg/reconcile so that
+// existing reconcilers can be ported by changing their imports.
+package reconcile
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/dtomasi/k1s/core/client"
+)
+
+// NamespacedName identifies the object to reconcile. It is the k1s client's
+// ObjectKey, so req.NamespacedName can be passed to client.Get directly.
+type NamespacedName = client.ObjectKey
+
+// Request contains the information necessary to reconcile an object:
+// its name and namespace.
+type Request struct {
+	// NamespacedName is the name and namespace of the object to reconcile.
+	NamespacedName
+}
+
+// Result contains the result of a Reconcile invocation.
+type Result struct {
+	// Requeue tells the controller to requeue the reconcile key using the rate limiter.
+	Requeue bool
+
+	// RequeueAfter, if greater than 0, tells the controller to requeue the
+	// reconcile key after the duration. It takes precedence over Requeue.
+	RequeueAfter time.Duration
+}
+
+// IsZero returns true if this result is empty.
+func (r *Result) IsZero() bool {
+	if r == nil {
+		return true
+	}
+	return *r == Result{}
+}
+
+// Reconciler implements the business logic of a controller. Reconcile is
+// called with the key of an object whenever the object or something it
+// depends on changes, and drives the actual state towards the desired state.
+type Reconciler interface {
+	Reconcile(ctx context.Context, req Request) (Result, error)
+}
+
+// Func is a function that implements the Reconciler interface.
+type Func func(context.Context, Request) (Result, error)
+
+var _ Reconciler = Func(nil)
+
+// Reconcile implements Reconciler.
+func (r Func) Reconcile(ctx context.Context, req Request) (Result, error) {
+	return r(ctx, req)
+}
+
+// TerminalError wraps an error that retrying cannot fix. The controller
+// reports it but does not requeue the request.
+func TerminalError(wrapped error) error {
+	return &terminalError{err: wrapped}
+}
+
+type terminalError struct {
+	err error
+}
+
+// Unwrap returns the wrapped error
+func (te *terminalError) Unwrap() error {
+	return te.err
+}
+
+// Error returns the error message
+func (te *terminalError) Error() string {
+	if te.err == nil {
+		return "nil terminal error"
+	}
+	return "terminal error: " + te.err.Error()
+}
+
+// Is matches any terminal error
+func (te *terminalError) Is(target error) bool {
+	tp := &terminalError{}
+	return errors.As(target, &tp)
+}
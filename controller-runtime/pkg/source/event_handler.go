@@ -0,0 +1,73 @@
+package source
+
+import (
+	"context"
+
+	toolscache "k8s.io/client-go/tools/cache"
+
+	"github.com/dtomasi/k1s/core/client"
+
+	"github.com/dtomasi/k1s/controller-runtime/pkg/event"
+	"github.com/dtomasi/k1s/controller-runtime/pkg/handler"
+	"github.com/dtomasi/k1s/controller-runtime/pkg/predicate"
+)
+
+// EventHandler adapts an EventHandler to the informer's ResourceEventHandler
+// interface. Objects that are not a client.Object and events that one of the
+// predicates skips are ignored.
+func EventHandler(ctx context.Context, h handler.EventHandler, queue handler.Queue,
+	predicates ...predicate.Predicate) toolscache.ResourceEventHandler {
+	return toolscache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			o, ok := obj.(client.Object)
+			if !ok {
+				return
+			}
+			evt := event.CreateEvent{Object: o}
+			for _, p := range predicates {
+				if !p.Create(evt) {
+					return
+				}
+			}
+			h.Create(ctx, evt, queue)
+		},
+		UpdateFunc: func(oldObj, newObj interface{}) {
+			o, ok := oldObj.(client.Object)
+			if !ok {
+				return
+			}
+			n, ok := newObj.(client.Object)
+			if !ok {
+				return
+			}
+			evt := event.UpdateEvent{ObjectOld: o, ObjectNew: n}
+			for _, p := range predicates {
+				if !p.Update(evt) {
+					return
+				}
+			}
+			h.Update(ctx, evt, queue)
+		},
+		DeleteFunc: func(obj interface{}) {
+			evt := event.DeleteEvent{}
+
+			// The informer may have missed the deletion and only knows the last state
+			if tombstone, ok := obj.(toolscache.DeletedFinalStateUnknown); ok {
+				evt.DeleteStateUnknown = true
+				obj = tombstone.Obj
+			}
+
+			o, ok := obj.(client.Object)
+			if !ok {
+				return
+			}
+			evt.Object = o
+			for _, p := range predicates {
+				if !p.Delete(evt) {
+					return
+				}
+			}
+			h.Delete(ctx, evt, queue)
+		},
+	}
+}
@@ -0,0 +1,103 @@
+package source
+
+import (
+	"context"
+	"strconv"
+	"sync/atomic"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	toolscache "k8s.io/client-go/tools/cache"
+
+	"github.com/dtomasi/k1s/controller-runtime/pkg/handler"
+)
+
+// ResourceVersionObserver is implemented by sources that track the highest
+// resourceVersion of the objects they have delivered.
+type ResourceVersionObserver interface {
+	// LastObservedResourceVersion returns the highest resourceVersion
+	// delivered so far, or an empty string if none was delivered.
+	LastObservedResourceVersion() string
+}
+
+// changedSinceKey is the context key of the changed-since resourceVersion
+type changedSinceKey struct{}
+
+// WithChangedSince returns a copy of ctx that makes Kind sources started with
+// it skip the objects of the informer's initial list whose resourceVersion
+// is not newer than resourceVersion. Controllers use it to resume from the
+// resourceVersion observed by a previous process. Objects changed after the
+// source started are always delivered.
+func WithChangedSince(ctx context.Context, resourceVersion string) context.Context {
+	return context.WithValue(ctx, changedSinceKey{}, resourceVersion)
+}
+
+// changedSinceFrom returns the changed-since resourceVersion of ctx, or 0
+func changedSinceFrom(ctx context.Context) uint64 {
+	resourceVersion, _ := ctx.Value(changedSinceKey{}).(string)
+	return parseResourceVersion(resourceVersion)
+}
+
+// observingHandler filters unchanged objects of the initial list and records
+// the highest resourceVersion of the delivered events. Events delivered after
+// the queue was shut down may have been dropped and are not recorded.
+type observingHandler struct {
+	toolscache.ResourceEventHandler
+	queue    handler.Queue
+	since    uint64
+	observed *atomic.Uint64
+}
+
+// OnAdd forwards the add unless it belongs to the initial list and is unchanged
+func (h *observingHandler) OnAdd(obj interface{}, isInInitialList bool) {
+	rv := objectResourceVersion(obj)
+	if !isInInitialList || h.since == 0 || rv == 0 || rv > h.since {
+		h.ResourceEventHandler.OnAdd(obj, isInInitialList)
+	}
+	h.observe(rv)
+}
+
+// OnUpdate forwards the update
+func (h *observingHandler) OnUpdate(oldObj, newObj interface{}) {
+	h.ResourceEventHandler.OnUpdate(oldObj, newObj)
+	h.observe(objectResourceVersion(newObj))
+}
+
+// OnDelete forwards the delete
+func (h *observingHandler) OnDelete(obj interface{}) {
+	h.ResourceEventHandler.OnDelete(obj)
+	if tombstone, ok := obj.(toolscache.DeletedFinalStateUnknown); ok {
+		obj = tombstone.Obj
+	}
+	h.observe(objectResourceVersion(obj))
+}
+
+// observe raises the observed resourceVersion to rv
+func (h *observingHandler) observe(rv uint64) {
+	if h.queue.ShuttingDown() {
+		return
+	}
+	for {
+		current := h.observed.Load()
+		if rv <= current || h.observed.CompareAndSwap(current, rv) {
+			return
+		}
+	}
+}
+
+// objectResourceVersion returns the numeric resourceVersion of obj, or 0
+func objectResourceVersion(obj interface{}) uint64 {
+	accessor, err := meta.Accessor(obj)
+	if err != nil {
+		return 0
+	}
+	return parseResourceVersion(accessor.GetResourceVersion())
+}
+
+// parseResourceVersion returns the numeric value of a resourceVersion, or 0
+func parseResourceVersion(resourceVersion string) uint64 {
+	rv, err := strconv.ParseUint(resourceVersion, 10, 64)
+	if err != nil {
+		return 0
+	}
+	return rv
+}
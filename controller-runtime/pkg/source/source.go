@@ -0,0 +1,181 @@
+// Package source provides the event streams a controller watches. It mirrors
+// sigs.k8s.io/controller-runtime/pkg/source.
+package source
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+	"sync/atomic"
+
+	toolscache "k8s.io/client-go/tools/cache"
+
+	"github.com/dtomasi/k1s/core/client"
+
+	"github.com/dtomasi/k1s/controller-runtime/pkg/event"
+	"github.com/dtomasi/k1s/controller-runtime/pkg/handler"
+	"github.com/dtomasi/k1s/controller-runtime/pkg/predicate"
+)
+
+// Source is a source of events, such as create, update and delete operations
+// on objects, that is passed to an EventHandler to enqueue reconcile requests.
+type Source interface {
+	// Start begins delivering events to the queue. It must not block.
+	Start(ctx context.Context, queue handler.Queue) error
+}
+
+// SyncingSource is a Source whose initial state can be waited for.
+type SyncingSource interface {
+	Source
+
+	// WaitForSync blocks until the source has delivered its initial state.
+	WaitForSync(ctx context.Context) error
+}
+
+// InformerGetter returns the shared informer for the type of an object.
+// The controller manager implements it.
+type InformerGetter interface {
+	GetInformer(ctx context.Context, obj client.Object) (toolscache.SharedIndexInformer, error)
+}
+
+// Kind returns a source that delivers the events of the shared informer for
+// the type of obj that pass all predicates to the handler. Started with a
+// context from WithChangedSince, it skips unchanged objects of the initial
+// list. The returned source implements ResourceVersionObserver.
+func Kind(informers InformerGetter, obj client.Object, h handler.EventHandler, predicates ...predicate.Predicate) SyncingSource {
+	return &kind{informers: informers, obj: obj, handler: h, predicates: predicates}
+}
+
+var _ ResourceVersionObserver = &kind{}
+
+type kind struct {
+	informers  InformerGetter
+	obj        client.Object
+	handler    handler.EventHandler
+	predicates []predicate.Predicate
+
+	mu           sync.Mutex
+	registration toolscache.ResourceEventHandlerRegistration
+	observed     atomic.Uint64
+}
+
+// String returns the watched type
+func (ks *kind) String() string {
+	return fmt.Sprintf("kind source: %T", ks.obj)
+}
+
+// Start registers the handler with the informer. The handler is removed when ctx is done.
+func (ks *kind) Start(ctx context.Context, queue handler.Queue) error {
+	if ks.informers == nil || ks.obj == nil {
+		return errors.New("must create Kind with a non-nil informer getter and object")
+	}
+	if ks.handler == nil {
+		return errors.New("must create Kind with a non-nil handler")
+	}
+
+	ks.mu.Lock()
+	defer ks.mu.Unlock()
+	if ks.registration != nil {
+		return fmt.Errorf("%s was already started", ks)
+	}
+
+	informer, err := ks.informers.GetInformer(ctx, ks.obj)
+	if err != nil {
+		return fmt.Errorf("failed to get informer for %T: %w", ks.obj, err)
+	}
+
+	registration, err := informer.AddEventHandler(&observingHandler{
+		ResourceEventHandler: EventHandler(ctx, ks.handler, queue, ks.predicates...),
+		queue:                queue,
+		since:                changedSinceFrom(ctx),
+		observed:             &ks.observed,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to add event handler for %T: %w", ks.obj, err)
+	}
+	ks.registration = registration
+
+	go func() {
+		<-ctx.Done()
+		_ = informer.RemoveEventHandler(registration)
+	}()
+
+	return nil
+}
+
+// WaitForSync blocks until the handler has received the informer's initial list
+func (ks *kind) WaitForSync(ctx context.Context) error {
+	ks.mu.Lock()
+	registration := ks.registration
+	ks.mu.Unlock()
+
+	if registration == nil {
+		return fmt.Errorf("%s was not started", ks)
+	}
+	if !toolscache.WaitForCacheSync(ctx.Done(), registration.HasSynced) {
+		return fmt.Errorf("timed out waiting for cache of %T to be synced", ks.obj)
+	}
+	return nil
+}
+
+// LastObservedResourceVersion returns the highest resourceVersion delivered to the handler
+func (ks *kind) LastObservedResourceVersion() string {
+	rv := ks.observed.Load()
+	if rv == 0 {
+		return ""
+	}
+	return strconv.FormatUint(rv, 10)
+}
+
+// Channel returns a source that delivers the generic events received from ch
+// to the handler. It allows triggering reconciles from outside the storage,
+// for example from a CLI command.
+func Channel(ch <-chan event.GenericEvent, h handler.EventHandler) Source {
+	return &channel{source: ch, handler: h}
+}
+
+type channel struct {
+	source  <-chan event.GenericEvent
+	handler handler.EventHandler
+}
+
+// String returns a description of the source
+func (cs *channel) String() string {
+	return fmt.Sprintf("channel source: %p", cs)
+}
+
+// Start forwards events until ctx is done or the channel is closed
+func (cs *channel) Start(ctx context.Context, queue handler.Queue) error {
+	if cs.source == nil {
+		return errors.New("must create Channel with a non-nil source")
+	}
+	if cs.handler == nil {
+		return errors.New("must create Channel with a non-nil handler")
+	}
+
+	go func() {
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case evt, ok := <-cs.source:
+				if !ok {
+					return
+				}
+				cs.handler.Generic(ctx, evt, queue)
+			}
+		}
+	}()
+
+	return nil
+}
+
+// Func is a function that implements Source.
+type Func func(context.Context, handler.Queue) error
+
+// Start implements Source.
+func (f Func) Start(ctx context.Context, queue handler.Queue) error {
+	return f(ctx, queue)
+}
@@ -0,0 +1,127 @@
+package admission
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	apiadmission "k8s.io/apiserver/pkg/admission"
+)
+
+// AllResources matches every resource when used as the GroupVersionResource
+// of a registration. Each field of a registered GroupVersionResource may
+// also be "*" to match any group, version or resource.
+var AllResources = schema.GroupVersionResource{Group: "*", Version: "*", Resource: "*"}
+
+// registration is a plugin registered for a resource
+type registration[T any] struct {
+	resource schema.GroupVersionResource
+	plugin   T
+}
+
+// Chain is an ordered chain of admission plugins. Plugins run in the order
+// they were registered, all mutating plugins before the validating ones.
+// A Chain is safe for concurrent use, and plugins may be registered while
+// the client is in use.
+type Chain struct {
+	mu         sync.RWMutex
+	mutating   []registration[MutatingAdmission]
+	validating []registration[ValidatingAdmission]
+}
+
+// NewChain returns an empty admission chain.
+func NewChain() *Chain {
+	return &Chain{}
+}
+
+// RegisterMutating appends a mutating plugin for the resource.
+func (c *Chain) RegisterMutating(resource schema.GroupVersionResource, plugin MutatingAdmission) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.mutating = append(c.mutating, registration[MutatingAdmission]{resource: resource, plugin: plugin})
+}
+
+// RegisterValidating appends a validating plugin for the resource.
+func (c *Chain) RegisterValidating(resource schema.GroupVersionResource, plugin ValidatingAdmission) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.validating = append(c.validating, registration[ValidatingAdmission]{resource: resource, plugin: plugin})
+}
+
+// Admit runs the mutating plugins registered for the resource of the
+// request. The first error stops the chain and is returned as a Forbidden
+// error unless it already is an API status error.
+func (c *Chain) Admit(ctx context.Context, a Attributes) error {
+	if c == nil {
+		return nil
+	}
+
+	c.mu.RLock()
+	mutating := append([]registration[MutatingAdmission](nil), c.mutating...)
+	c.mu.RUnlock()
+
+	for _, r := range mutating {
+		if !matches(r.resource, a) || !handles(r.plugin, a.GetOperation()) {
+			continue
+		}
+		if err := r.plugin.Admit(ctx, a); err != nil {
+			return reject(a, err)
+		}
+	}
+	return nil
+}
+
+// Validate runs the validating plugins registered for the resource of the
+// request. The first error stops the chain and is returned as a Forbidden
+// error unless it already is an API status error.
+func (c *Chain) Validate(ctx context.Context, a Attributes) error {
+	if c == nil {
+		return nil
+	}
+
+	c.mu.RLock()
+	validating := append([]registration[ValidatingAdmission](nil), c.validating...)
+	c.mu.RUnlock()
+
+	for _, r := range validating {
+		if !matches(r.resource, a) || !handles(r.plugin, a.GetOperation()) {
+			continue
+		}
+		if err := r.plugin.Validate(ctx, a); err != nil {
+			return reject(a, err)
+		}
+	}
+	return nil
+}
+
+// matches reports whether a registration for resource applies to the request
+func matches(resource schema.GroupVersionResource, a Attributes) bool {
+	requested := a.GetResource()
+	return matchesField(resource.Group, requested.Group) &&
+		matchesField(resource.Version, requested.Version) &&
+		matchesField(resource.Resource, requested.Resource)
+}
+
+// matchesField reports whether a registered field matches the requested one
+func matchesField(registered, requested string) bool {
+	return registered == "*" || registered == requested
+}
+
+// handles reports whether the plugin handles the operation
+func handles(plugin interface{}, operation Operation) bool {
+	if handler, ok := plugin.(OperationHandler); ok {
+		return handler.Handles(operation)
+	}
+	return true
+}
+
+// reject converts a plugin error into the error returned to the caller
+func reject(a Attributes, err error) error {
+	var status apierrors.APIStatus
+	if errors.As(err, &status) {
+		return err
+	}
+	return apiadmission.NewForbidden(a, err)
+}
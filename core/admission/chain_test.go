@@ -0,0 +1,105 @@
+package admission_test
+
+import (
+	"context"
+	"errors"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/dtomasi/k1s/core/admission"
+)
+
+// createOnly only handles create requests
+type createOnly struct {
+	admission.ValidatingFunc
+}
+
+func (createOnly) Handles(operation admission.Operation) bool {
+	return operation == admission.Create
+}
+
+var _ = Describe("Chain", func() {
+	var (
+		ctx           context.Context
+		chain         *admission.Chain
+		configMapsGVR = schema.GroupVersionResource{Version: "v1", Resource: "configmaps"}
+		secretsGVR    = schema.GroupVersionResource{Version: "v1", Resource: "secrets"}
+	)
+
+	attributes := func(gvr schema.GroupVersionResource, operation admission.Operation) admission.Attributes {
+		obj := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "config", Namespace: "default"}}
+		return admission.NewAttributesRecord(obj, nil, schema.GroupVersionKind{Version: "v1", Kind: "ConfigMap"},
+			"default", "config", gvr, "", operation, &metav1.CreateOptions{}, false, nil)
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		chain = admission.NewChain()
+	})
+
+	It("should run plugins in registration order and stop at the first error", func() {
+		var calls []string
+		record := func(name string, err error) admission.MutatingFunc {
+			return func(context.Context, admission.Attributes) error {
+				calls = append(calls, name)
+				return err
+			}
+		}
+		chain.RegisterMutating(configMapsGVR, record("first", nil))
+		chain.RegisterMutating(configMapsGVR, record("second", errors.New("denied")))
+		chain.RegisterMutating(configMapsGVR, record("third", nil))
+
+		err := chain.Admit(ctx, attributes(configMapsGVR, admission.Create))
+		Expect(apierrors.IsForbidden(err)).To(BeTrue())
+		Expect(calls).To(Equal([]string{"first", "second"}))
+	})
+
+	It("should match registrations by resource and wildcard", func() {
+		var calls []string
+		chain.RegisterValidating(secretsGVR, admission.ValidatingFunc(func(context.Context, admission.Attributes) error {
+			calls = append(calls, "secrets")
+			return nil
+		}))
+		chain.RegisterValidating(schema.GroupVersionResource{Group: "*", Version: "*", Resource: "configmaps"},
+			admission.ValidatingFunc(func(context.Context, admission.Attributes) error {
+				calls = append(calls, "configmaps")
+				return nil
+			}))
+		chain.RegisterValidating(admission.AllResources, admission.ValidatingFunc(func(context.Context, admission.Attributes) error {
+			calls = append(calls, "all")
+			return nil
+		}))
+
+		Expect(chain.Validate(ctx, attributes(configMapsGVR, admission.Create))).To(Succeed())
+		Expect(calls).To(Equal([]string{"configmaps", "all"}))
+	})
+
+	It("should only call plugins for the operations they handle", func() {
+		chain.RegisterValidating(configMapsGVR, createOnly{admission.ValidatingFunc(func(context.Context, admission.Attributes) error {
+			return errors.New("no creates")
+		})})
+
+		Expect(chain.Validate(ctx, attributes(configMapsGVR, admission.Update))).To(Succeed())
+		Expect(chain.Validate(ctx, attributes(configMapsGVR, admission.Create))).NotTo(Succeed())
+	})
+
+	It("should keep API status errors of plugins", func() {
+		chain.RegisterValidating(configMapsGVR, admission.ValidatingFunc(func(context.Context, admission.Attributes) error {
+			return apierrors.NewConflict(schema.GroupResource{Resource: "configmaps"}, "config", errors.New("busy"))
+		}))
+
+		err := chain.Validate(ctx, attributes(configMapsGVR, admission.Create))
+		Expect(apierrors.IsConflict(err)).To(BeTrue())
+	})
+
+	It("should admit everything when nil", func() {
+		var nilChain *admission.Chain
+		Expect(nilChain.Admit(ctx, attributes(configMapsGVR, admission.Create))).To(Succeed())
+		Expect(nilChain.Validate(ctx, attributes(configMapsGVR, admission.Create))).To(Succeed())
+	})
+})
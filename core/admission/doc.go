@@ -0,0 +1,21 @@
+// Package admission provides an ordered admission chain for the k1s client.
+//
+// Admission plugins intercept Create, Update, Patch and Delete requests
+// after defaulting. Mutating plugins run first and may modify the object,
+// then validating plugins may reject the request. Plugins are registered per
+// GroupVersionResource and receive the Kubernetes admission.Attributes of
+// the request, so policies can be written like admission webhooks without
+// forking the client:
+//
+//	chain := admission.NewChain()
+//	chain.RegisterValidating(itemsGVR, admission.ValidatingFunc(
+//		func(ctx context.Context, a admission.Attributes) error {
+//			item := a.GetObject().(*v1alpha1.Item)
+//			if item.Spec.Category == "restricted" && item.Labels["approved-by"] == "" {
+//				return errors.New("items in category restricted need an approved-by label")
+//			}
+//			return nil
+//		}))
+//
+//	c, err := client.NewClient(client.ClientOptions{..., Admission: chain})
+package admission
@@ -0,0 +1,76 @@
+package admission
+
+import (
+	"context"
+
+	apiadmission "k8s.io/apiserver/pkg/admission"
+	"k8s.io/apiserver/pkg/authentication/user"
+)
+
+// Attributes describes an admission request: the operation, the requesting
+// user, the new and old object, the resource and subresource and whether the
+// request is a dry run. It is the Kubernetes admission.Attributes.
+type Attributes = apiadmission.Attributes
+
+// Operation is the type of request being admitted.
+type Operation = apiadmission.Operation
+
+// Operations handled by the admission chain.
+const (
+	Create = apiadmission.Create
+	Update = apiadmission.Update
+	Delete = apiadmission.Delete
+)
+
+// NewAttributesRecord returns the attributes of an admission request.
+var NewAttributesRecord = apiadmission.NewAttributesRecord
+
+// MutatingAdmission may modify the object of a request before it is validated and stored.
+type MutatingAdmission interface {
+	// Admit mutates a.GetObject() in place. An error rejects the request.
+	Admit(ctx context.Context, a Attributes) error
+}
+
+// ValidatingAdmission may reject a request without modifying its object.
+type ValidatingAdmission interface {
+	// Validate returns an error to reject the request.
+	Validate(ctx context.Context, a Attributes) error
+}
+
+// OperationHandler is implemented by plugins that only handle some operations.
+// Plugins that do not implement it handle all operations.
+type OperationHandler interface {
+	// Handles returns true if the plugin handles the operation.
+	Handles(operation Operation) bool
+}
+
+// MutatingFunc implements MutatingAdmission with a function.
+type MutatingFunc func(ctx context.Context, a Attributes) error
+
+// Admit implements MutatingAdmission.
+func (f MutatingFunc) Admit(ctx context.Context, a Attributes) error {
+	return f(ctx, a)
+}
+
+// ValidatingFunc implements ValidatingAdmission with a function.
+type ValidatingFunc func(ctx context.Context, a Attributes) error
+
+// Validate implements ValidatingAdmission.
+func (f ValidatingFunc) Validate(ctx context.Context, a Attributes) error {
+	return f(ctx, a)
+}
+
+// userKey is the context key of the requesting user
+type userKey struct{}
+
+// WithUser returns a copy of ctx that carries the requesting user. The
+// client passes it to the admission plugins of the requests made with ctx.
+func WithUser(ctx context.Context, u user.Info) context.Context {
+	return context.WithValue(ctx, userKey{}, u)
+}
+
+// UserFrom returns the requesting user of ctx, or nil if there is none.
+func UserFrom(ctx context.Context) user.Info {
+	u, _ := ctx.Value(userKey{}).(user.Info)
+	return u
+}
@@ -0,0 +1,16 @@
+// Package lifecycle enforces the lifecycle of namespaces in the k1s client.
+//
+// The Lifecycle admission plugin mirrors the Kubernetes NamespaceLifecycle
+// plugin and namespace controller. New namespaces get the kubernetes
+// finalizer and the Active phase. Objects can only be created in namespaces
+// that exist and are not terminating. Deleting a namespace sets its phase to
+// Terminating, deletes the objects of every registered namespaced resource
+// in it and removes the finalizer before the namespace itself is removed, so
+// no objects are left behind under its key prefix.
+//
+// The runtime registers the plugin with its admission chain and creates the
+// default namespace when it starts:
+//
+//	lifecycle.Register(chain, c, resourceRegistry)
+//	err := lifecycle.EnsureNamespace(ctx, c, metav1.NamespaceDefault)
+package lifecycle
@@ -0,0 +1,226 @@
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/dtomasi/k1s/core/admission"
+	"github.com/dtomasi/k1s/core/client"
+	"github.com/dtomasi/k1s/core/registry"
+	typesv1 "github.com/dtomasi/k1s/core/types/v1"
+)
+
+// Lifecycle is an admission plugin that enforces the lifecycle of namespaces.
+type Lifecycle struct {
+	client   client.Client
+	registry registry.Registry
+}
+
+var (
+	_ admission.MutatingAdmission   = &Lifecycle{}
+	_ admission.ValidatingAdmission = &Lifecycle{}
+	_ admission.OperationHandler    = &Lifecycle{}
+)
+
+// New returns a Lifecycle that reads and deletes namespaces and their
+// content with the client. The registry provides the resources whose objects
+// are deleted with a namespace.
+func New(c client.Client, r registry.Registry) *Lifecycle {
+	return &Lifecycle{client: c, registry: r}
+}
+
+// Register registers a Lifecycle with the admission chain: as mutating plugin
+// for namespaces and as validating plugin for all resources.
+func Register(chain *admission.Chain, c client.Client, r registry.Registry) {
+	l := New(c, r)
+	chain.RegisterMutating(typesv1.NamespaceGVR, l)
+	chain.RegisterValidating(admission.AllResources, l)
+}
+
+// Handles returns true for creates and deletes.
+func (l *Lifecycle) Handles(operation admission.Operation) bool {
+	return operation == admission.Create || operation == admission.Delete
+}
+
+// Admit adds the kubernetes finalizer to created namespaces and finalizes
+// deleted namespaces before they are removed.
+func (l *Lifecycle) Admit(ctx context.Context, a admission.Attributes) error {
+	if !l.enabled() || a.GetResource().GroupResource() != typesv1.NamespaceGVR.GroupResource() {
+		return nil
+	}
+
+	switch a.GetOperation() {
+	case admission.Create:
+		return initialize(a.GetObject())
+	case admission.Delete:
+		// The client does not delete the namespace of a dry run, so its
+		// content must be kept as well
+		if a.IsDryRun() {
+			return nil
+		}
+		return l.Terminate(ctx, a.GetName())
+	}
+	return nil
+}
+
+// Validate rejects the creation of objects in namespaces that do not exist
+// or are terminating.
+func (l *Lifecycle) Validate(ctx context.Context, a admission.Attributes) error {
+	if !l.enabled() || a.GetOperation() != admission.Create || a.GetNamespace() == "" ||
+		a.GetResource().GroupResource() == typesv1.NamespaceGVR.GroupResource() {
+		return nil
+	}
+
+	ns := &corev1.Namespace{}
+	if err := l.client.Get(ctx, client.ObjectKey{Name: a.GetNamespace()}, ns); err != nil {
+		if apierrors.IsNotFound(err) {
+			return apierrors.NewNotFound(typesv1.NamespaceGVR.GroupResource(), a.GetNamespace())
+		}
+		return fmt.Errorf("failed to get namespace %s: %w", a.GetNamespace(), err)
+	}
+	if ns.Status.Phase != corev1.NamespaceTerminating {
+		return nil
+	}
+
+	message := fmt.Sprintf("unable to create new content in namespace %s because it is being terminated", ns.Name)
+	err := apierrors.NewForbidden(a.GetResource().GroupResource(), a.GetName(), errors.New(message))
+	err.ErrStatus.Details.Causes = append(err.ErrStatus.Details.Causes, metav1.StatusCause{
+		Type:    corev1.NamespaceTerminatingCause,
+		Message: message,
+		Field:   "metadata.namespace",
+	})
+	return err
+}
+
+// Terminate finalizes a namespace: it sets its phase to Terminating, deletes
+// the objects of all namespaced resources in it and then removes the
+// kubernetes finalizer. k1s has no namespace controller, so namespaces are
+// finalized within the delete request. A namespace whose content could not be
+// deleted stays terminating, and deleting it again resumes the finalization.
+func (l *Lifecycle) Terminate(ctx context.Context, name string) error {
+	ns := &corev1.Namespace{}
+	if err := l.client.Get(ctx, client.ObjectKey{Name: name}, ns); err != nil {
+		return err
+	}
+	if ns.Status.Phase != corev1.NamespaceTerminating {
+		ns.Status.Phase = corev1.NamespaceTerminating
+		if err := l.client.Status().Update(ctx, ns); err != nil {
+			return fmt.Errorf("failed to mark namespace %s as terminating: %w", name, err)
+		}
+	}
+
+	if err := l.deleteContent(ctx, name); err != nil {
+		return fmt.Errorf("failed to delete the content of namespace %s: %w", name, err)
+	}
+
+	if err := l.client.Get(ctx, client.ObjectKey{Name: name}, ns); err != nil {
+		return err
+	}
+	finalizers := removeFinalizer(ns.Spec.Finalizers)
+	if len(finalizers) == len(ns.Spec.Finalizers) {
+		return nil
+	}
+	ns.Spec.Finalizers = finalizers
+	if err := l.client.Update(ctx, ns); err != nil {
+		return fmt.Errorf("failed to remove the finalizer of namespace %s: %w", name, err)
+	}
+	return nil
+}
+
+// deleteContent deletes the objects of all registered namespaced resources in
+// the namespace
+func (l *Lifecycle) deleteContent(ctx context.Context, namespace string) error {
+	var errs []error
+	for _, gvr := range l.registry.ListResources() {
+		config, err := l.registry.GetResourceConfig(gvr)
+		if err != nil || !config.Namespaced {
+			continue
+		}
+		listKind := config.ListKind
+		if listKind == "" {
+			listKind = config.Kind + "List"
+		}
+
+		list := &unstructured.UnstructuredList{}
+		list.SetGroupVersionKind(gvr.GroupVersion().WithKind(listKind))
+		if err := l.client.List(ctx, list, client.InNamespace(namespace)); err != nil {
+			errs = append(errs, fmt.Errorf("failed to list %s: %w", gvr.Resource, err))
+			continue
+		}
+		for i := range list.Items {
+			item := &list.Items[i]
+			if err := l.client.Delete(ctx, item); err != nil && !apierrors.IsNotFound(err) {
+				errs = append(errs, fmt.Errorf("failed to delete %s %s: %w", gvr.Resource, item.GetName(), err))
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// enabled reports whether the client can store namespaces
+func (l *Lifecycle) enabled() bool {
+	return l.client.Scheme().Recognizes(typesv1.NamespaceGVK)
+}
+
+// EnsureNamespace creates the namespace if it does not exist.
+func EnsureNamespace(ctx context.Context, c client.Client, name string) error {
+	if err := c.Create(ctx, typesv1.NewNamespace(name)); err != nil && !apierrors.IsAlreadyExists(err) {
+		return fmt.Errorf("failed to create namespace %s: %w", name, err)
+	}
+	return nil
+}
+
+// initialize adds the kubernetes finalizer to a new namespace and sets its
+// phase to Active
+func initialize(obj runtime.Object) error {
+	switch ns := obj.(type) {
+	case *corev1.Namespace:
+		if !hasFinalizer(ns.Spec.Finalizers) {
+			ns.Spec.Finalizers = append(ns.Spec.Finalizers, corev1.FinalizerKubernetes)
+		}
+		ns.Status.Phase = corev1.NamespaceActive
+	case *unstructured.Unstructured:
+		finalizers, _, err := unstructured.NestedStringSlice(ns.Object, "spec", "finalizers")
+		if err != nil {
+			return fmt.Errorf("invalid namespace finalizers: %w", err)
+		}
+		if !hasFinalizer(finalizers) {
+			finalizers = append(finalizers, string(corev1.FinalizerKubernetes))
+			if err := unstructured.SetNestedStringSlice(ns.Object, finalizers, "spec", "finalizers"); err != nil {
+				return err
+			}
+		}
+		if err := unstructured.SetNestedField(ns.Object, string(corev1.NamespaceActive), "status", "phase"); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// hasFinalizer reports whether the kubernetes finalizer is in finalizers
+func hasFinalizer[T ~string](finalizers []T) bool {
+	for _, finalizer := range finalizers {
+		if string(finalizer) == string(corev1.FinalizerKubernetes) {
+			return true
+		}
+	}
+	return false
+}
+
+// removeFinalizer returns finalizers without the kubernetes finalizer
+func removeFinalizer(finalizers []corev1.FinalizerName) []corev1.FinalizerName {
+	var result []corev1.FinalizerName
+	for _, finalizer := range finalizers {
+		if finalizer != corev1.FinalizerKubernetes {
+			result = append(result, finalizer)
+		}
+	}
+	return result
+}
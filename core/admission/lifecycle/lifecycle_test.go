@@ -0,0 +1,236 @@
+package lifecycle_test
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	k8sstorage "k8s.io/apiserver/pkg/storage"
+
+	"github.com/dtomasi/k1s/core/admission"
+	"github.com/dtomasi/k1s/core/admission/lifecycle"
+	"github.com/dtomasi/k1s/core/client"
+	"github.com/dtomasi/k1s/core/registry"
+	"github.com/dtomasi/k1s/core/storage"
+	typesv1 "github.com/dtomasi/k1s/core/types/v1"
+)
+
+// jsonStorage stores objects as JSON keyed by the client's storage keys, the
+// way the storage backends do
+type jsonStorage struct {
+	storage.Interface
+	mu   sync.Mutex
+	rv   uint64
+	data map[string][]byte
+}
+
+func (s *jsonStorage) Versioner() k8sstorage.Versioner {
+	return storage.SimpleVersioner{}
+}
+
+func (s *jsonStorage) Create(_ context.Context, key string, obj, out runtime.Object, _ uint64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, exists := s.data[key]; exists {
+		return apierrors.NewAlreadyExists(schema.GroupResource{}, key)
+	}
+	return s.store(key, obj, out)
+}
+
+func (s *jsonStorage) Get(_ context.Context, key string, _ k8sstorage.GetOptions, out runtime.Object) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	data, exists := s.data[key]
+	if !exists {
+		return apierrors.NewNotFound(schema.GroupResource{}, key)
+	}
+	return json.Unmarshal(data, out)
+}
+
+func (s *jsonStorage) Delete(_ context.Context, key string, out runtime.Object, _ *k8sstorage.Preconditions,
+	_ k8sstorage.ValidateObjectFunc, _ runtime.Object) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	data, exists := s.data[key]
+	if !exists {
+		return apierrors.NewNotFound(schema.GroupResource{}, key)
+	}
+	delete(s.data, key)
+	return json.Unmarshal(data, out)
+}
+
+func (s *jsonStorage) GuaranteedUpdate(_ context.Context, key string, destination runtime.Object, _ bool,
+	_ *k8sstorage.Preconditions, tryUpdate k8sstorage.UpdateFunc, _ runtime.Object) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, exists := s.data[key]; !exists {
+		return apierrors.NewNotFound(schema.GroupResource{}, key)
+	}
+	updated, _, err := tryUpdate(destination, k8sstorage.ResponseMeta{})
+	if err != nil {
+		return err
+	}
+	return s.store(key, updated, destination)
+}
+
+// List decodes the stored objects below key into the items of the list
+func (s *jsonStorage) List(_ context.Context, key string, _ k8sstorage.ListOptions, list runtime.Object) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var keys []string
+	for k := range s.data {
+		if strings.HasPrefix(k, key) {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+
+	items := make([]json.RawMessage, 0, len(keys))
+	for _, k := range keys {
+		items = append(items, s.data[k])
+	}
+	data, err := json.Marshal(map[string]interface{}{"items": items})
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, list)
+}
+
+// store stores obj with a new resource version and decodes it into out
+func (s *jsonStorage) store(key string, obj, out runtime.Object) error {
+	accessor, err := meta.Accessor(obj)
+	if err != nil {
+		return err
+	}
+	s.rv++
+	accessor.SetResourceVersion(strconv.FormatUint(s.rv, 10))
+	data, err := json.Marshal(obj)
+	if err != nil {
+		return err
+	}
+	s.data[key] = data
+	return json.Unmarshal(data, out)
+}
+
+var _ = Describe("Lifecycle", func() {
+	var (
+		ctx   context.Context
+		c     client.Client
+		store *jsonStorage
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+
+		scheme := runtime.NewScheme()
+		Expect(typesv1.AddToScheme(scheme)).To(Succeed())
+		reg := registry.NewRegistry()
+		Expect(registry.RegisterCoreResources(reg)).To(Succeed())
+
+		store = &jsonStorage{data: map[string][]byte{}}
+		chain := admission.NewChain()
+		var err error
+		c, err = client.NewClient(client.ClientOptions{
+			Scheme:    scheme,
+			Storage:   store,
+			Registry:  reg,
+			Admission: chain,
+		})
+		Expect(err).NotTo(HaveOccurred())
+		lifecycle.Register(chain, c, reg)
+	})
+
+	getNamespace := func(name string) *corev1.Namespace {
+		ns := &corev1.Namespace{}
+		Expect(c.Get(ctx, client.ObjectKey{Name: name}, ns)).To(Succeed())
+		return ns
+	}
+
+	It("should add the kubernetes finalizer to new namespaces", func() {
+		Expect(c.Create(ctx, typesv1.NewNamespace("team-a"))).To(Succeed())
+
+		ns := getNamespace("team-a")
+		Expect(ns.Spec.Finalizers).To(ConsistOf(corev1.FinalizerKubernetes))
+		Expect(ns.Status.Phase).To(Equal(corev1.NamespaceActive))
+	})
+
+	It("should reject creates in namespaces that do not exist", func() {
+		err := c.Create(ctx, typesv1.NewConfigMap("config", "missing"))
+		Expect(apierrors.IsNotFound(err)).To(BeTrue(), "unexpected error: %v", err)
+		Expect(err.Error()).To(ContainSubstring(`namespaces "missing" not found`))
+
+		Expect(c.Create(ctx, typesv1.NewClusterRole("reader"))).To(Succeed())
+	})
+
+	It("should reject creates in terminating namespaces", func() {
+		Expect(c.Create(ctx, typesv1.NewNamespace("team-a"))).To(Succeed())
+		ns := getNamespace("team-a")
+		ns.Status.Phase = corev1.NamespaceTerminating
+		Expect(c.Status().Update(ctx, ns)).To(Succeed())
+
+		err := c.Create(ctx, typesv1.NewConfigMap("config", "team-a"))
+		Expect(apierrors.IsForbidden(err)).To(BeTrue(), "unexpected error: %v", err)
+		Expect(apierrors.HasStatusCause(err, corev1.NamespaceTerminatingCause)).To(BeTrue())
+	})
+
+	It("should delete the content of deleted namespaces", func() {
+		for _, name := range []string{"team-a", "team-b"} {
+			Expect(c.Create(ctx, typesv1.NewNamespace(name))).To(Succeed())
+			Expect(c.Create(ctx, typesv1.NewConfigMap("config", name))).To(Succeed())
+			Expect(c.Create(ctx, typesv1.NewOpaqueSecret("secret", name))).To(Succeed())
+			Expect(c.Create(ctx, typesv1.NewRole("reader", name))).To(Succeed())
+		}
+		Expect(c.Create(ctx, typesv1.NewClusterRole("reader"))).To(Succeed())
+
+		Expect(c.Delete(ctx, typesv1.NewNamespace("team-a"))).To(Succeed())
+
+		var keys []string
+		for key := range store.data {
+			keys = append(keys, key)
+		}
+		Expect(keys).To(ConsistOf(
+			"//v1/namespaces/team-b",
+			"//v1/configmaps/team-b/config",
+			"//v1/secrets/team-b/secret",
+			"/rbac.authorization.k8s.io/v1/roles/team-b/reader",
+			"/rbac.authorization.k8s.io/v1/clusterroles/reader",
+		))
+	})
+
+	It("should keep namespaces and their content on dry-run deletes", func() {
+		Expect(c.Create(ctx, typesv1.NewNamespace("team-a"))).To(Succeed())
+		Expect(c.Create(ctx, typesv1.NewConfigMap("config", "team-a"))).To(Succeed())
+
+		Expect(c.Delete(ctx, typesv1.NewNamespace("team-a"), dryRunAll{})).To(Succeed())
+
+		ns := getNamespace("team-a")
+		Expect(ns.Status.Phase).To(Equal(corev1.NamespaceActive))
+		Expect(ns.Spec.Finalizers).To(ConsistOf(corev1.FinalizerKubernetes))
+		Expect(c.Get(ctx, client.ObjectKey{Namespace: "team-a", Name: "config"}, &corev1.ConfigMap{})).To(Succeed())
+	})
+
+	It("should create a namespace only once", func() {
+		Expect(lifecycle.EnsureNamespace(ctx, c, "default")).To(Succeed())
+		Expect(lifecycle.EnsureNamespace(ctx, c, "default")).To(Succeed())
+
+		Expect(getNamespace("default").Spec.Finalizers).To(ConsistOf(corev1.FinalizerKubernetes))
+	})
+})
+
+// dryRunAll marks a delete as dry run
+type dryRunAll struct{}
+
+func (dryRunAll) ApplyToDelete(opts *client.DeleteOptions) {
+	opts.DryRun = []string{metav1.DryRunAll}
+}
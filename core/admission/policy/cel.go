@@ -0,0 +1,92 @@
+package policy
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/google/cel-go/cel"
+	"github.com/google/cel-go/common/types"
+	"github.com/google/cel-go/common/types/ref"
+
+	"github.com/dtomasi/k1s/core/validation"
+)
+
+// compiler compiles and caches the CEL expressions of policies
+type compiler struct {
+	mu       sync.RWMutex
+	env      *cel.Env
+	envErr   error
+	programs map[string]cel.Program
+}
+
+func newCompiler() *compiler {
+	env, err := validation.NewCELEnv("object", "oldObject", "request", "params", "namespaceObject", "variables")
+	return &compiler{env: env, envErr: err, programs: make(map[string]cel.Program)}
+}
+
+// program returns the compiled program of an expression
+func (c *compiler) program(expression string) (cel.Program, error) {
+	if c.envErr != nil {
+		return nil, fmt.Errorf("failed to create CEL environment: %w", c.envErr)
+	}
+
+	c.mu.RLock()
+	prg, ok := c.programs[expression]
+	c.mu.RUnlock()
+	if ok {
+		return prg, nil
+	}
+
+	ast, issues := c.env.Compile(expression)
+	if issues != nil && issues.Err() != nil {
+		return nil, fmt.Errorf("compilation failed: %w", issues.Err())
+	}
+	prg, err := c.env.Program(ast)
+	if err != nil {
+		return nil, fmt.Errorf("program creation failed: %w", err)
+	}
+
+	c.mu.Lock()
+	c.programs[expression] = prg
+	c.mu.Unlock()
+	return prg, nil
+}
+
+// eval evaluates an expression with the given variables
+func (c *compiler) eval(expression string, vars map[string]interface{}) (ref.Val, error) {
+	prg, err := c.program(expression)
+	if err != nil {
+		return nil, err
+	}
+	result, _, err := prg.Eval(vars)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// evalBool evaluates an expression that must result in a bool
+func (c *compiler) evalBool(expression string, vars map[string]interface{}) (bool, error) {
+	result, err := c.eval(expression, vars)
+	if err != nil {
+		return false, err
+	}
+	value, ok := result.(types.Bool)
+	if !ok {
+		return false, fmt.Errorf("expression must evaluate to bool, got %s", result.Type().TypeName())
+	}
+	return bool(value), nil
+}
+
+// evalString evaluates an expression that must result in a string
+func (c *compiler) evalString(expression string, vars map[string]interface{}) (string, error) {
+	result, err := c.eval(expression, vars)
+	if err != nil {
+		return "", err
+	}
+	value, ok := result.(types.String)
+	if !ok {
+		return "", fmt.Errorf("expression must evaluate to string, got %s", result.Type().TypeName())
+	}
+	return string(value), nil
+}
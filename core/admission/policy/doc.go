@@ -0,0 +1,17 @@
+// Package policy evaluates ValidatingAdmissionPolicies in the k1s client.
+//
+// Policies and their bindings are stored as admissionregistration.k8s.io/v1
+// ValidatingAdmissionPolicy and ValidatingAdmissionPolicyBinding objects, so
+// they can be changed without rebuilding the CLI that embeds k1s. The
+// Validator is a validating admission plugin that evaluates every bound
+// policy whose match constraints select the request, using the CEL
+// environment of core/validation with the variables object, oldObject,
+// request, params, namespaceObject and variables.
+//
+// Supported are match constraints on resources, operations, resource names,
+// namespace and object selectors, match conditions, parameters by name or
+// selector, variables, validations with messages and message expressions,
+// and failure policies. Deny rejects a request with the reason of the failed
+// validation; Warn and Audit record the failure as an annotation of the
+// request. Audit annotations of policies are not evaluated.
+package policy
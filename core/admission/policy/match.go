@@ -0,0 +1,441 @@
+package policy
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/dtomasi/k1s/core/admission"
+	"github.com/dtomasi/k1s/core/client"
+	typesv1 "github.com/dtomasi/k1s/core/types/v1"
+)
+
+// request is an admission request evaluated against the bound policies. It
+// caches the namespace of the request, which all bindings share.
+type request struct {
+	client     client.Client
+	attributes admission.Attributes
+
+	namespaceLoaded bool
+	namespace       *typesv1.Namespace
+}
+
+func newRequest(c client.Client, a admission.Attributes) *request {
+	return &request{client: c, attributes: a}
+}
+
+// matches reports whether the request is selected by the match resources of
+// a policy or binding. Empty resource rules of a binding select everything
+// the policy selects, empty resource rules of a policy select nothing.
+func (r *request) matches(ctx context.Context, mr *admissionregistrationv1.MatchResources, binding bool) (bool, error) {
+	if len(mr.ResourceRules) == 0 && !binding {
+		return false, nil
+	}
+	if len(mr.ResourceRules) > 0 && !r.matchesAnyRule(mr.ResourceRules) {
+		return false, nil
+	}
+	if r.matchesAnyRule(mr.ExcludeResourceRules) {
+		return false, nil
+	}
+
+	matches, err := r.matchesNamespaceSelector(ctx, mr.NamespaceSelector)
+	if err != nil || !matches {
+		return false, err
+	}
+	return r.matchesObjectSelector(mr.ObjectSelector)
+}
+
+// matchesAnyRule reports whether one of the rules selects the request
+func (r *request) matchesAnyRule(rules []admissionregistrationv1.NamedRuleWithOperations) bool {
+	for _, rule := range rules {
+		if r.matchesRule(rule) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchesRule reports whether a rule selects the request
+func (r *request) matchesRule(rule admissionregistrationv1.NamedRuleWithOperations) bool {
+	a := r.attributes
+	gvr := a.GetResource()
+
+	operations := make([]string, 0, len(rule.Operations))
+	for _, op := range rule.Operations {
+		operations = append(operations, string(op))
+	}
+	if !matchesValue(operations, string(a.GetOperation())) ||
+		!matchesValue(rule.APIGroups, gvr.Group) ||
+		!matchesValue(rule.APIVersions, gvr.Version) ||
+		!matchesResource(rule.Resources, gvr.Resource, a.GetSubresource()) ||
+		!r.matchesScope(rule.Scope) {
+		return false
+	}
+	return len(rule.ResourceNames) == 0 || matchesValue(rule.ResourceNames, a.GetName())
+}
+
+// matchesScope reports whether the scope of a rule selects the request
+func (r *request) matchesScope(scope *admissionregistrationv1.ScopeType) bool {
+	if scope == nil || *scope == admissionregistrationv1.AllScopes {
+		return true
+	}
+	if r.clusterScoped() {
+		return *scope == admissionregistrationv1.ClusterScope
+	}
+	return *scope == admissionregistrationv1.NamespacedScope
+}
+
+// clusterScoped reports whether the request is for a cluster-scoped resource
+func (r *request) clusterScoped() bool {
+	return r.attributes.GetNamespace() == ""
+}
+
+// matchesValue reports whether value is one of values or values contains "*"
+func matchesValue(values []string, value string) bool {
+	for _, v := range values {
+		if v == "*" || v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// matchesResource reports whether one of the resource patterns selects the
+// resource and subresource. "*" selects all resources without subresources,
+// "*/*" all resources and subresources, "pods/*" all subresources of pods and
+// "*/status" the status subresource of all resources.
+func matchesResource(patterns []string, resource, subresource string) bool {
+	for _, pattern := range patterns {
+		res, sub, hasSub := strings.Cut(pattern, "/")
+		if res != "*" && res != resource {
+			continue
+		}
+		if !hasSub && subresource == "" {
+			return true
+		}
+		if hasSub && (sub == "*" || sub == subresource) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchesNamespaceSelector reports whether the namespace of the request is
+// selected. Requests for namespaces are selected by their own labels and
+// requests for other cluster-scoped resources are always selected.
+func (r *request) matchesNamespaceSelector(ctx context.Context, selector *metav1.LabelSelector) (bool, error) {
+	sel, err := labelSelector(selector)
+	if err != nil || sel.Empty() {
+		return err == nil, err
+	}
+
+	gvr := r.attributes.GetResource()
+	if gvr.Group == "" && gvr.Resource == "namespaces" {
+		return r.matchesLabels(sel)
+	}
+	if r.clusterScoped() {
+		return true, nil
+	}
+
+	namespace, err := r.namespaceObject(ctx)
+	if err != nil {
+		return false, err
+	}
+	var namespaceLabels labels.Set
+	if namespace != nil {
+		namespaceLabels = namespace.Labels
+	}
+	return sel.Matches(namespaceLabels), nil
+}
+
+// matchesObjectSelector reports whether the new or the old object is selected
+func (r *request) matchesObjectSelector(selector *metav1.LabelSelector) (bool, error) {
+	sel, err := labelSelector(selector)
+	if err != nil || sel.Empty() {
+		return err == nil, err
+	}
+	return r.matchesLabels(sel)
+}
+
+// matchesLabels reports whether the labels of the new or the old object are selected
+func (r *request) matchesLabels(sel labels.Selector) (bool, error) {
+	for _, obj := range []runtime.Object{r.attributes.GetObject(), r.attributes.GetOldObject()} {
+		if obj == nil {
+			continue
+		}
+		accessor, err := meta.Accessor(obj)
+		if err != nil {
+			return false, err
+		}
+		if sel.Matches(labels.Set(accessor.GetLabels())) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// labelSelector converts a label selector, where nil selects everything
+func labelSelector(selector *metav1.LabelSelector) (labels.Selector, error) {
+	if selector == nil {
+		return labels.Everything(), nil
+	}
+	sel, err := metav1.LabelSelectorAsSelector(selector)
+	if err != nil {
+		return nil, fmt.Errorf("invalid label selector: %w", err)
+	}
+	return sel, nil
+}
+
+// namespaceObject returns the namespace of a namespaced request, or nil if
+// the request is cluster-scoped or the namespace does not exist
+func (r *request) namespaceObject(ctx context.Context) (*typesv1.Namespace, error) {
+	if r.clusterScoped() {
+		return nil, nil
+	}
+	if r.namespaceLoaded {
+		return r.namespace, nil
+	}
+
+	namespace := &typesv1.Namespace{}
+	err := r.client.Get(ctx, client.ObjectKey{Name: r.attributes.GetNamespace()}, namespace)
+	switch {
+	case apierrors.IsNotFound(err):
+		namespace = nil
+	case err != nil:
+		return nil, fmt.Errorf("failed to get namespace %s: %w", r.attributes.GetNamespace(), err)
+	}
+	r.namespace, r.namespaceLoaded = namespace, true
+	return namespace, nil
+}
+
+// params returns the parameter objects a binding refers to. A policy without
+// parameters is evaluated once with null params; nil is returned if no
+// parameters were found and the binding allows that.
+func (r *request) params(ctx context.Context, paramKind *admissionregistrationv1.ParamKind,
+	paramRef *admissionregistrationv1.ParamRef) ([]runtime.Object, error) {
+	if paramKind == nil {
+		return []runtime.Object{nil}, nil
+	}
+	if paramRef == nil {
+		return nil, fmt.Errorf("policy has a paramKind but the binding has no paramRef")
+	}
+
+	gv, err := schema.ParseGroupVersion(paramKind.APIVersion)
+	if err != nil {
+		return nil, fmt.Errorf("invalid paramKind apiVersion %q: %w", paramKind.APIVersion, err)
+	}
+	gvk := gv.WithKind(paramKind.Kind)
+
+	namespace := paramRef.Namespace
+	if namespace == "" && !r.clusterScopedKind(gvk) {
+		namespace = r.attributes.GetNamespace()
+	}
+
+	var params []runtime.Object
+	if paramRef.Name != "" {
+		params, err = r.paramByName(ctx, gvk, namespace, paramRef.Name)
+	} else {
+		params, err = r.paramsBySelector(ctx, gvk, namespace, paramRef.Selector)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if len(params) == 0 {
+		if paramRef.ParameterNotFoundAction != nil && *paramRef.ParameterNotFoundAction == admissionregistrationv1.AllowAction {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("no params found for policy binding with `Deny` parameterNotFoundAction")
+	}
+	return params, nil
+}
+
+// clusterScopedKind reports whether the rest mapper of the client knows gvk as cluster-scoped
+func (r *request) clusterScopedKind(gvk schema.GroupVersionKind) bool {
+	mapper := r.client.RESTMapper()
+	if mapper == nil {
+		return false
+	}
+	mapping, err := mapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+	return err == nil && mapping.Scope.Name() == meta.RESTScopeNameRoot
+}
+
+// paramByName returns the named parameter object, if it exists
+func (r *request) paramByName(ctx context.Context, gvk schema.GroupVersionKind, namespace, name string) ([]runtime.Object, error) {
+	obj, err := r.client.Scheme().New(gvk)
+	if err != nil {
+		return nil, fmt.Errorf("unknown paramKind %s: %w", gvk, err)
+	}
+	param, ok := obj.(client.Object)
+	if !ok {
+		return nil, fmt.Errorf("paramKind %s is not an object", gvk)
+	}
+
+	err = r.client.Get(ctx, client.ObjectKey{Namespace: namespace, Name: name}, param)
+	switch {
+	case apierrors.IsNotFound(err):
+		return nil, nil
+	case err != nil:
+		return nil, fmt.Errorf("failed to get params %s: %w", name, err)
+	}
+	return []runtime.Object{param}, nil
+}
+
+// paramsBySelector returns the parameter objects matching the selector
+func (r *request) paramsBySelector(ctx context.Context, gvk schema.GroupVersionKind, namespace string,
+	selector *metav1.LabelSelector) ([]runtime.Object, error) {
+	sel, err := labelSelector(selector)
+	if err != nil {
+		return nil, err
+	}
+
+	listGVK := gvk.GroupVersion().WithKind(gvk.Kind + "List")
+	obj, err := r.client.Scheme().New(listGVK)
+	if err != nil {
+		return nil, fmt.Errorf("unknown paramKind list %s: %w", listGVK, err)
+	}
+	list, ok := obj.(client.ObjectList)
+	if !ok {
+		return nil, fmt.Errorf("paramKind list %s is not a list", listGVK)
+	}
+
+	var opts []client.ListOption
+	if namespace != "" {
+		opts = append(opts, client.InNamespace(namespace))
+	}
+	if err := r.client.List(ctx, list, opts...); err != nil {
+		return nil, fmt.Errorf("failed to list params: %w", err)
+	}
+	items, err := meta.ExtractList(list)
+	if err != nil {
+		return nil, err
+	}
+
+	// The client does not filter lists by label
+	params := make([]runtime.Object, 0, len(items))
+	for _, item := range items {
+		accessor, err := meta.Accessor(item)
+		if err != nil {
+			return nil, err
+		}
+		if sel.Matches(labels.Set(accessor.GetLabels())) {
+			params = append(params, item)
+		}
+	}
+	return params, nil
+}
+
+// activation returns the CEL variables of the request for a parameter object
+func (r *request) activation(ctx context.Context, param runtime.Object) (map[string]interface{}, error) {
+	a := r.attributes
+	gvk := a.GetKind()
+
+	object, err := toUnstructured(a.GetObject(), gvk)
+	if err != nil {
+		return nil, err
+	}
+	oldObject, err := toUnstructured(a.GetOldObject(), gvk)
+	if err != nil {
+		return nil, err
+	}
+	var params interface{}
+	if param != nil {
+		params, err = toUnstructured(param, param.GetObjectKind().GroupVersionKind())
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var namespaceObject interface{}
+	namespace, err := r.namespaceObject(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if namespace != nil {
+		namespaceObject, err = toUnstructured(namespace, typesv1.NamespaceGVK)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return map[string]interface{}{
+		"object":          object,
+		"oldObject":       oldObject,
+		"request":         r.requestObject(),
+		"params":          params,
+		"namespaceObject": namespaceObject,
+		"variables":       map[string]interface{}{},
+	}, nil
+}
+
+// requestObject returns the request variable, modelled on admission.k8s.io/v1 AdmissionRequest
+func (r *request) requestObject() map[string]interface{} {
+	a := r.attributes
+	gvk, gvr := a.GetKind(), a.GetResource()
+
+	kind := map[string]interface{}{"group": gvk.Group, "version": gvk.Version, "kind": gvk.Kind}
+	resource := map[string]interface{}{"group": gvr.Group, "version": gvr.Version, "resource": gvr.Resource}
+
+	userInfo := map[string]interface{}{}
+	if u := a.GetUserInfo(); u != nil {
+		extra := map[string]interface{}{}
+		for key, values := range u.GetExtra() {
+			extra[key] = values
+		}
+		userInfo = map[string]interface{}{
+			"username": u.GetName(),
+			"uid":      u.GetUID(),
+			"groups":   u.GetGroups(),
+			"extra":    extra,
+		}
+	}
+
+	var options interface{}
+	if opts := a.GetOperationOptions(); opts != nil {
+		if converted, err := runtime.DefaultUnstructuredConverter.ToUnstructured(opts); err == nil {
+			options = converted
+		}
+	}
+
+	return map[string]interface{}{
+		"kind":               kind,
+		"resource":           resource,
+		"subResource":        a.GetSubresource(),
+		"requestKind":        kind,
+		"requestResource":    resource,
+		"requestSubResource": a.GetSubresource(),
+		"name":               a.GetName(),
+		"namespace":          a.GetNamespace(),
+		"operation":          string(a.GetOperation()),
+		"userInfo":           userInfo,
+		"dryRun":             a.IsDryRun(),
+		"options":            options,
+	}
+}
+
+// toUnstructured converts an object into its unstructured form with type
+// information, or returns nil for a nil object
+func toUnstructured(obj runtime.Object, gvk schema.GroupVersionKind) (interface{}, error) {
+	if obj == nil {
+		return nil, nil
+	}
+	content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert object: %w", err)
+	}
+	if _, ok := content["apiVersion"]; !ok && !gvk.Empty() {
+		content["apiVersion"] = gvk.GroupVersion().String()
+	}
+	if _, ok := content["kind"]; !ok && !gvk.Empty() {
+		content["kind"] = gvk.Kind
+	}
+	return content, nil
+}
@@ -0,0 +1,254 @@
+package policy
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/dtomasi/k1s/core/admission"
+	"github.com/dtomasi/k1s/core/client"
+	typesv1 "github.com/dtomasi/k1s/core/types/v1"
+)
+
+// ValidationFailureAnnotation is the request annotation that records the
+// failures of bindings with the Warn or Audit validation action.
+const ValidationFailureAnnotation = "validation.policy.admission.k8s.io/validation_failure"
+
+// Validator evaluates the stored ValidatingAdmissionPolicies on every write.
+type Validator struct {
+	client   client.Client
+	compiler *compiler
+}
+
+var _ admission.ValidatingAdmission = &Validator{}
+
+// New returns a Validator that reads policies, bindings, parameters and
+// namespaces with the client.
+func New(c client.Client) *Validator {
+	return &Validator{client: c, compiler: newCompiler()}
+}
+
+// Register registers a Validator for all resources with the admission chain.
+func Register(chain *admission.Chain, c client.Client) {
+	chain.RegisterValidating(admission.AllResources, New(c))
+}
+
+// Validate evaluates the bound policies that match the request and rejects
+// it if a binding with the Deny action fails.
+func (v *Validator) Validate(ctx context.Context, a admission.Attributes) error {
+	// Clients without the policy types cannot store policies
+	if !v.client.Scheme().Recognizes(typesv1.ValidatingAdmissionPolicyGVK) {
+		return nil
+	}
+
+	policies := &typesv1.ValidatingAdmissionPolicyList{}
+	if err := v.client.List(ctx, policies); err != nil {
+		return fmt.Errorf("failed to list validating admission policies: %w", err)
+	}
+	if len(policies.Items) == 0 {
+		return nil
+	}
+	byName := make(map[string]*typesv1.ValidatingAdmissionPolicy, len(policies.Items))
+	for i := range policies.Items {
+		byName[policies.Items[i].Name] = &policies.Items[i]
+	}
+
+	bindings := &typesv1.ValidatingAdmissionPolicyBindingList{}
+	if err := v.client.List(ctx, bindings); err != nil {
+		return fmt.Errorf("failed to list validating admission policy bindings: %w", err)
+	}
+	sort.Slice(bindings.Items, func(i, j int) bool {
+		return bindings.Items[i].Name < bindings.Items[j].Name
+	})
+
+	req := newRequest(v.client, a)
+	for i := range bindings.Items {
+		binding := &bindings.Items[i]
+		policy, ok := byName[binding.Spec.PolicyName]
+		if !ok {
+			continue
+		}
+		if err := v.evaluate(ctx, policy, binding, req); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// failure is a failed validation of a policy binding
+type failure struct {
+	message string
+	reason  metav1.StatusReason
+}
+
+// evaluate evaluates a policy for a binding and applies the binding's
+// validation actions to the failures
+func (v *Validator) evaluate(ctx context.Context, policy *typesv1.ValidatingAdmissionPolicy,
+	binding *typesv1.ValidatingAdmissionPolicyBinding, req *request) error {
+	failures, err := v.failures(ctx, policy, binding, req)
+	if err != nil {
+		if policy.Spec.FailurePolicy != nil && *policy.Spec.FailurePolicy == admissionregistrationv1.Ignore {
+			return nil
+		}
+		failures = []failure{{message: err.Error(), reason: metav1.StatusReasonInvalid}}
+	}
+	if len(failures) == 0 {
+		return nil
+	}
+
+	for _, action := range binding.Spec.ValidationActions {
+		switch action {
+		case admissionregistrationv1.Deny:
+			return denied(policy.Name, binding.Name, failures[0])
+		case admissionregistrationv1.Warn, admissionregistrationv1.Audit:
+			messages := make([]string, 0, len(failures))
+			for _, f := range failures {
+				messages = append(messages, f.message)
+			}
+			// An annotation of an earlier binding is kept
+			_ = req.attributes.AddAnnotation(ValidationFailureAnnotation,
+				fmt.Sprintf("%s/%s: %s", policy.Name, binding.Name, strings.Join(messages, "; ")))
+		}
+	}
+	return nil
+}
+
+// failures returns the failed validations of a policy for a binding, or an
+// error if the policy could not be evaluated
+func (v *Validator) failures(ctx context.Context, policy *typesv1.ValidatingAdmissionPolicy,
+	binding *typesv1.ValidatingAdmissionPolicyBinding, req *request) ([]failure, error) {
+	// A policy without match constraints matches nothing
+	if policy.Spec.MatchConstraints == nil {
+		return nil, nil
+	}
+	matches, err := req.matches(ctx, policy.Spec.MatchConstraints, false)
+	if err != nil || !matches {
+		return nil, err
+	}
+	if binding.Spec.MatchResources != nil {
+		matches, err := req.matches(ctx, binding.Spec.MatchResources, true)
+		if err != nil || !matches {
+			return nil, err
+		}
+	}
+
+	params, err := req.params(ctx, policy.Spec.ParamKind, binding.Spec.ParamRef)
+	if err != nil {
+		return nil, err
+	}
+	if params == nil {
+		// No parameters were found and the binding allows that
+		return nil, nil
+	}
+
+	var failures []failure
+	for _, param := range params {
+		vars, err := req.activation(ctx, param)
+		if err != nil {
+			return nil, err
+		}
+
+		matched, err := v.matchConditions(policy, vars)
+		if err != nil || !matched {
+			if err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		if err := v.evaluateVariables(policy, vars); err != nil {
+			return nil, err
+		}
+
+		for _, validation := range policy.Spec.Validations {
+			valid, err := v.compiler.evalBool(validation.Expression, vars)
+			if err != nil {
+				return nil, fmt.Errorf("expression '%s' resulted in error: %w", validation.Expression, err)
+			}
+			if !valid {
+				failures = append(failures, failure{
+					message: v.message(validation, vars),
+					reason:  reason(validation.Reason),
+				})
+			}
+		}
+	}
+	return failures, nil
+}
+
+// matchConditions reports whether all match conditions of the policy are true
+func (v *Validator) matchConditions(policy *typesv1.ValidatingAdmissionPolicy, vars map[string]interface{}) (bool, error) {
+	for _, condition := range policy.Spec.MatchConditions {
+		matched, err := v.compiler.evalBool(condition.Expression, vars)
+		if err != nil {
+			return false, fmt.Errorf("match condition '%s' resulted in error: %w", condition.Name, err)
+		}
+		if !matched {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// evaluateVariables evaluates the variables of the policy in order, so each
+// variable may refer to the ones before it
+func (v *Validator) evaluateVariables(policy *typesv1.ValidatingAdmissionPolicy, vars map[string]interface{}) error {
+	variables := map[string]interface{}{}
+	vars["variables"] = variables
+	for _, variable := range policy.Spec.Variables {
+		value, err := v.compiler.eval(variable.Expression, vars)
+		if err != nil {
+			return fmt.Errorf("variable '%s' resulted in error: %w", variable.Name, err)
+		}
+		variables[variable.Name] = value
+	}
+	return nil
+}
+
+// message returns the message of a failed validation
+func (v *Validator) message(validation admissionregistrationv1.Validation, vars map[string]interface{}) string {
+	if validation.MessageExpression != "" {
+		if message, err := v.compiler.evalString(validation.MessageExpression, vars); err == nil && message != "" {
+			return message
+		}
+	}
+	if validation.Message != "" {
+		return validation.Message
+	}
+	return fmt.Sprintf("failed expression: %s", validation.Expression)
+}
+
+// reason returns the status reason of a failed validation, which defaults to Invalid
+func reason(r *metav1.StatusReason) metav1.StatusReason {
+	if r == nil || *r == "" {
+		return metav1.StatusReasonInvalid
+	}
+	return *r
+}
+
+// denied returns the error that rejects a request because of a failed validation
+func denied(policyName, bindingName string, f failure) error {
+	code := int32(http.StatusUnprocessableEntity)
+	switch f.reason {
+	case metav1.StatusReasonForbidden:
+		code = http.StatusForbidden
+	case metav1.StatusReasonUnauthorized:
+		code = http.StatusUnauthorized
+	case metav1.StatusReasonRequestEntityTooLarge:
+		code = http.StatusRequestEntityTooLarge
+	}
+
+	return &apierrors.StatusError{ErrStatus: metav1.Status{
+		Status: metav1.StatusFailure,
+		Code:   code,
+		Reason: f.reason,
+		Message: fmt.Sprintf("ValidatingAdmissionPolicy '%s' with binding '%s' denied request: %s",
+			policyName, bindingName, f.message),
+	}}
+}
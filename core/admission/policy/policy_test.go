@@ -0,0 +1,295 @@
+package policy_test
+
+import (
+	"context"
+	"reflect"
+	"sort"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	k8sstorage "k8s.io/apiserver/pkg/storage"
+
+	"github.com/dtomasi/k1s/core/admission"
+	"github.com/dtomasi/k1s/core/admission/policy"
+	"github.com/dtomasi/k1s/core/client"
+	"github.com/dtomasi/k1s/core/registry"
+	"github.com/dtomasi/k1s/core/storage"
+	typesv1 "github.com/dtomasi/k1s/core/types/v1"
+)
+
+// mapStorage is a minimal in-memory storage keyed by the client's storage keys
+type mapStorage struct {
+	storage.Interface
+	objects map[string]runtime.Object
+}
+
+func (s *mapStorage) Versioner() k8sstorage.Versioner {
+	return storage.SimpleVersioner{}
+}
+
+func (s *mapStorage) Create(_ context.Context, key string, obj, out runtime.Object, _ uint64) error {
+	if _, exists := s.objects[key]; exists {
+		return apierrors.NewAlreadyExists(schema.GroupResource{}, key)
+	}
+	s.objects[key] = obj.DeepCopyObject()
+	copyInto(obj, out)
+	return nil
+}
+
+func (s *mapStorage) Get(_ context.Context, key string, _ k8sstorage.GetOptions, out runtime.Object) error {
+	obj, exists := s.objects[key]
+	if !exists {
+		return apierrors.NewNotFound(schema.GroupResource{}, key)
+	}
+	copyInto(obj, out)
+	return nil
+}
+
+func (s *mapStorage) Delete(_ context.Context, key string, out runtime.Object, _ *k8sstorage.Preconditions,
+	_ k8sstorage.ValidateObjectFunc, _ runtime.Object) error {
+	obj, exists := s.objects[key]
+	if !exists {
+		return apierrors.NewNotFound(schema.GroupResource{}, key)
+	}
+	delete(s.objects, key)
+	copyInto(obj, out)
+	return nil
+}
+
+func (s *mapStorage) List(_ context.Context, key string, _ k8sstorage.ListOptions, list runtime.Object) error {
+	keys := make([]string, 0, len(s.objects))
+	for k := range s.objects {
+		if strings.HasPrefix(k, key) {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+
+	items := make([]runtime.Object, 0, len(keys))
+	for _, k := range keys {
+		items = append(items, s.objects[k].DeepCopyObject())
+	}
+	return meta.SetList(list, items)
+}
+
+func copyInto(obj, out runtime.Object) {
+	if out != nil {
+		reflect.ValueOf(out).Elem().Set(reflect.ValueOf(obj.DeepCopyObject()).Elem())
+	}
+}
+
+// recordingAttributes records the annotations added to the request
+type recordingAttributes struct {
+	admission.Attributes
+	annotations map[string]string
+}
+
+func (a *recordingAttributes) AddAnnotation(key, value string) error {
+	if a.annotations == nil {
+		a.annotations = map[string]string{}
+	}
+	a.annotations[key] = value
+	return nil
+}
+
+var _ = Describe("Validator", func() {
+	var (
+		ctx context.Context
+		c   client.Client
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+
+		scheme := runtime.NewScheme()
+		Expect(typesv1.AddToScheme(scheme)).To(Succeed())
+		reg := registry.NewRegistry()
+		Expect(registry.RegisterCoreResources(reg)).To(Succeed())
+
+		chain := admission.NewChain()
+		var err error
+		c, err = client.NewClient(client.ClientOptions{
+			Scheme:    scheme,
+			Storage:   &mapStorage{objects: map[string]runtime.Object{}},
+			Registry:  reg,
+			Admission: chain,
+		})
+		Expect(err).NotTo(HaveOccurred())
+		policy.Register(chain, c)
+	})
+
+	configMapRules := []admissionregistrationv1.NamedRuleWithOperations{{
+		RuleWithOperations: admissionregistrationv1.RuleWithOperations{
+			Operations: []admissionregistrationv1.OperationType{admissionregistrationv1.Create, admissionregistrationv1.Update},
+			Rule: admissionregistrationv1.Rule{
+				APIGroups:   []string{""},
+				APIVersions: []string{"v1"},
+				Resources:   []string{"configmaps"},
+			},
+		},
+	}}
+
+	createPolicy := func(name string, mutate func(*typesv1.ValidatingAdmissionPolicy)) {
+		p := typesv1.NewValidatingAdmissionPolicy(name)
+		p.Spec.MatchConstraints = &admissionregistrationv1.MatchResources{ResourceRules: configMapRules}
+		if mutate != nil {
+			mutate(p)
+		}
+		Expect(c.Create(ctx, p)).To(Succeed())
+		Expect(c.Create(ctx, typesv1.NewValidatingAdmissionPolicyBinding(name+"-binding", name))).To(Succeed())
+	}
+
+	requireOwner := func(p *typesv1.ValidatingAdmissionPolicy) {
+		p.Spec.Validations = []admissionregistrationv1.Validation{{
+			Expression: "has(object.metadata.labels) && 'owner' in object.metadata.labels",
+			Message:    "configmaps must have an owner label",
+		}}
+	}
+
+	newConfigMap := func(name string, labels map[string]string) *typesv1.ConfigMap {
+		cm := typesv1.NewConfigMap(name, "default")
+		cm.Labels = labels
+		return cm
+	}
+
+	It("should deny requests that fail a validation", func() {
+		createPolicy("require-owner", requireOwner)
+
+		err := c.Create(ctx, newConfigMap("unowned", nil))
+		Expect(apierrors.IsInvalid(err)).To(BeTrue(), "unexpected error: %v", err)
+		Expect(err.Error()).To(ContainSubstring(
+			"ValidatingAdmissionPolicy 'require-owner' with binding 'require-owner-binding' denied request: configmaps must have an owner label"))
+
+		Expect(c.Create(ctx, newConfigMap("owned", map[string]string{"owner": "team-a"}))).To(Succeed())
+	})
+
+	It("should not evaluate policies for unmatched resources", func() {
+		createPolicy("require-owner", requireOwner)
+
+		Expect(c.Create(ctx, typesv1.NewOpaqueSecret("unowned", "default"))).To(Succeed())
+	})
+
+	It("should use the reason and message expression of a validation", func() {
+		createPolicy("max-keys", func(p *typesv1.ValidatingAdmissionPolicy) {
+			forbidden := metav1.StatusReasonForbidden
+			p.Spec.Variables = []admissionregistrationv1.Variable{{
+				Name: "keys", Expression: "has(object.data) ? size(object.data) : 0",
+			}}
+			p.Spec.Validations = []admissionregistrationv1.Validation{{
+				Expression:        "variables.keys <= 1",
+				MessageExpression: "'too many keys: ' + string(variables.keys)",
+				Reason:            &forbidden,
+			}}
+		})
+
+		cm := newConfigMap("big", nil)
+		cm.Data = map[string]string{"a": "1", "b": "2"}
+		err := c.Create(ctx, cm)
+		Expect(apierrors.IsForbidden(err)).To(BeTrue(), "unexpected error: %v", err)
+		Expect(err.Error()).To(ContainSubstring("too many keys: 2"))
+	})
+
+	It("should evaluate parameters referenced by the binding", func() {
+		p := typesv1.NewValidatingAdmissionPolicy("allowed-values")
+		p.Spec.MatchConstraints = &admissionregistrationv1.MatchResources{ResourceRules: configMapRules}
+		p.Spec.ParamKind = &admissionregistrationv1.ParamKind{APIVersion: "v1", Kind: "ConfigMap"}
+		p.Spec.MatchConditions = []admissionregistrationv1.MatchCondition{{
+			Name: "not-params", Expression: "object.metadata.name != 'limits'",
+		}}
+		p.Spec.Validations = []admissionregistrationv1.Validation{{
+			Expression: "!has(object.data) || !('mode' in object.data) || object.data.mode == params.data.mode",
+		}}
+		Expect(c.Create(ctx, p)).To(Succeed())
+
+		limits := newConfigMap("limits", nil)
+		limits.Data = map[string]string{"mode": "strict"}
+		Expect(c.Create(ctx, limits)).To(Succeed())
+
+		b := typesv1.NewValidatingAdmissionPolicyBinding("allowed-values-binding", "allowed-values")
+		b.Spec.ParamRef = &admissionregistrationv1.ParamRef{Name: "limits"}
+		Expect(c.Create(ctx, b)).To(Succeed())
+
+		cm := newConfigMap("settings", nil)
+		cm.Data = map[string]string{"mode": "lax"}
+		err := c.Create(ctx, cm)
+		Expect(apierrors.IsInvalid(err)).To(BeTrue(), "unexpected error: %v", err)
+		Expect(err.Error()).To(ContainSubstring("failed expression"))
+
+		cm.Data["mode"] = "strict"
+		Expect(c.Create(ctx, cm)).To(Succeed())
+
+		// Without parameters the binding denies by default
+		missing := typesv1.NewValidatingAdmissionPolicyBinding("missing-values-binding", "allowed-values")
+		missing.Spec.ParamRef = &admissionregistrationv1.ParamRef{Name: "missing"}
+		Expect(c.Create(ctx, missing)).To(Succeed())
+		err = c.Create(ctx, newConfigMap("other", nil))
+		Expect(err).To(MatchError(ContainSubstring("no params found")))
+	})
+
+	It("should only match namespaces selected by the binding", func() {
+		createPolicy("require-owner", requireOwner)
+
+		b := &typesv1.ValidatingAdmissionPolicyBinding{}
+		Expect(c.Get(ctx, client.ObjectKey{Name: "require-owner-binding"}, b)).To(Succeed())
+		Expect(c.Delete(ctx, b)).To(Succeed())
+		b = typesv1.NewValidatingAdmissionPolicyBinding("require-owner-binding", "require-owner")
+		b.Spec.MatchResources = &admissionregistrationv1.MatchResources{
+			NamespaceSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"env": "prod"}},
+		}
+		Expect(c.Create(ctx, b)).To(Succeed())
+
+		// The namespace does not exist yet, so it has no labels
+		Expect(c.Create(ctx, newConfigMap("first", nil))).To(Succeed())
+
+		ns := typesv1.NewNamespace("default")
+		ns.Labels = map[string]string{"env": "prod"}
+		Expect(c.Create(ctx, ns)).To(Succeed())
+
+		Expect(apierrors.IsInvalid(c.Create(ctx, newConfigMap("second", nil)))).To(BeTrue())
+	})
+
+	It("should deny requests whose evaluation fails", func() {
+		createPolicy("broken", func(p *typesv1.ValidatingAdmissionPolicy) {
+			p.Spec.Validations = []admissionregistrationv1.Validation{{Expression: "object.data.missing == 'x'"}}
+		})
+
+		err := c.Create(ctx, newConfigMap("failing", nil))
+		Expect(apierrors.IsInvalid(err)).To(BeTrue(), "unexpected error: %v", err)
+		Expect(err.Error()).To(ContainSubstring("resulted in error"))
+	})
+
+	It("should ignore evaluation errors with the Ignore failure policy", func() {
+		createPolicy("broken", func(p *typesv1.ValidatingAdmissionPolicy) {
+			ignore := admissionregistrationv1.Ignore
+			p.Spec.FailurePolicy = &ignore
+			p.Spec.Validations = []admissionregistrationv1.Validation{{Expression: "object.data.missing == 'x'"}}
+		})
+
+		Expect(c.Create(ctx, newConfigMap("ignored", nil))).To(Succeed())
+	})
+
+	It("should record failures of Warn bindings as annotations", func() {
+		p := typesv1.NewValidatingAdmissionPolicy("require-owner")
+		p.Spec.MatchConstraints = &admissionregistrationv1.MatchResources{ResourceRules: configMapRules}
+		requireOwner(p)
+		Expect(c.Create(ctx, p)).To(Succeed())
+		b := typesv1.NewValidatingAdmissionPolicyBinding("require-owner-binding", "require-owner")
+		b.Spec.ValidationActions = []admissionregistrationv1.ValidationAction{admissionregistrationv1.Warn}
+		Expect(c.Create(ctx, b)).To(Succeed())
+
+		cm := newConfigMap("unowned", nil)
+		attrs := &recordingAttributes{Attributes: admission.NewAttributesRecord(cm, nil, typesv1.ConfigMapGVK,
+			"default", cm.Name, typesv1.ConfigMapGVR, "", admission.Create, &metav1.CreateOptions{}, false, nil)}
+		Expect(policy.New(c).Validate(ctx, attrs)).To(Succeed())
+		Expect(attrs.annotations).To(HaveKeyWithValue(policy.ValidationFailureAnnotation,
+			"require-owner/require-owner-binding: configmaps must have an owner label"))
+		Expect(c.Create(ctx, cm)).To(Succeed())
+	})
+})
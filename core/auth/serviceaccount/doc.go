@@ -0,0 +1,26 @@
+// Package serviceaccount issues and verifies ServiceAccount tokens for the
+// k1s client.
+//
+// The TokenManager mirrors the token subresource of ServiceAccounts and the
+// service account token authenticator of the API server. CreateToken mints
+// a JWT for a ServiceAccount from an authentication.k8s.io/v1 TokenRequest,
+// bound to the requested audiences and expiration. AuthenticateToken verifies
+// such a token and resolves it to the user.Info of the ServiceAccount, named
+// system:serviceaccount:<namespace>:<name>. Tokens of deleted or recreated
+// ServiceAccounts are rejected.
+//
+// Tokens are signed with an ECDSA P-256 key (ES256) that is stored as a
+// Secret, by default kube-system/k1s-service-account-signing-key, and that is
+// created on first use. Every process that uses the same storage therefore
+// accepts the tokens of the others.
+//
+// Automation authenticates to the runtime by passing the user of its token
+// to the requests of the client:
+//
+//	tokens := serviceaccount.NewTokenManager(c, serviceaccount.TokenManagerOptions{})
+//	resp, ok, err := tokens.AuthenticateToken(ctx, token)
+//	if err != nil || !ok {
+//		return fmt.Errorf("unauthorized: %v", err)
+//	}
+//	ctx = admission.WithUser(ctx, resp.User)
+package serviceaccount
@@ -0,0 +1,139 @@
+package serviceaccount
+
+import (
+	"crypto/ecdsa"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+// signingAlgorithm is the JWS algorithm of the tokens
+const signingAlgorithm = "ES256"
+
+// es256KeySize is the size of the r and s values of an ES256 signature
+const es256KeySize = 32
+
+// header is the JOSE header of a token
+type header struct {
+	Algorithm string `json:"alg"`
+	Type      string `json:"typ,omitempty"`
+	KeyID     string `json:"kid,omitempty"`
+}
+
+// claims are the claims of a token, with the private claims of Kubernetes
+// ServiceAccount tokens under kubernetes.io
+type claims struct {
+	Issuer     string           `json:"iss"`
+	Subject    string           `json:"sub"`
+	Audience   []string         `json:"aud"`
+	Expiry     int64            `json:"exp"`
+	NotBefore  int64            `json:"nbf"`
+	IssuedAt   int64            `json:"iat"`
+	ID         string           `json:"jti,omitempty"`
+	Kubernetes kubernetesClaims `json:"kubernetes.io"`
+}
+
+// kubernetesClaims are the private claims that identify the ServiceAccount
+type kubernetesClaims struct {
+	Namespace      string `json:"namespace"`
+	ServiceAccount ref    `json:"serviceaccount"`
+}
+
+// ref references an object by name and UID
+type ref struct {
+	Name string `json:"name"`
+	UID  string `json:"uid"`
+}
+
+// keyID returns the key ID of a public key: the hash of its DER encoding
+func keyID(key *ecdsa.PublicKey) (string, error) {
+	der, err := x509.MarshalPKIXPublicKey(key)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal public key: %w", err)
+	}
+	sum := sha256.Sum256(der)
+	return base64.RawURLEncoding.EncodeToString(sum[:]), nil
+}
+
+// sign returns the compact serialization of a JWT with the claims, signed
+// with ES256
+func sign(key *ecdsa.PrivateKey, kid string, c *claims) (string, error) {
+	headerJSON, err := json.Marshal(header{Algorithm: signingAlgorithm, Type: "JWT", KeyID: kid})
+	if err != nil {
+		return "", err
+	}
+	claimsJSON, err := json.Marshal(c)
+	if err != nil {
+		return "", err
+	}
+
+	signingInput := base64.RawURLEncoding.EncodeToString(headerJSON) + "." +
+		base64.RawURLEncoding.EncodeToString(claimsJSON)
+	digest := sha256.Sum256([]byte(signingInput))
+	r, s, err := ecdsa.Sign(rand.Reader, key, digest[:])
+	if err != nil {
+		return "", fmt.Errorf("failed to sign token: %w", err)
+	}
+
+	signature := make([]byte, 2*es256KeySize)
+	r.FillBytes(signature[:es256KeySize])
+	s.FillBytes(signature[es256KeySize:])
+	return signingInput + "." + base64.RawURLEncoding.EncodeToString(signature), nil
+}
+
+// errNotJWT is returned by parse for tokens that are not JWTs
+var errNotJWT = errors.New("token is not a JWT")
+
+// parse decodes the header and the claims of a compact serialized JWT
+// without verifying its signature
+func parse(token string) (*header, *claims, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, nil, errNotJWT
+	}
+
+	h := &header{}
+	if err := decodeSegment(parts[0], h); err != nil {
+		return nil, nil, errNotJWT
+	}
+	c := &claims{}
+	if err := decodeSegment(parts[1], c); err != nil {
+		return nil, nil, fmt.Errorf("invalid token claims: %w", err)
+	}
+	return h, c, nil
+}
+
+// verify verifies the ES256 signature of a compact serialized JWT
+func verify(key *ecdsa.PublicKey, token string) error {
+	i := strings.LastIndex(token, ".")
+	if i < 0 {
+		return errNotJWT
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(token[i+1:])
+	if err != nil || len(signature) != 2*es256KeySize {
+		return errors.New("invalid token signature")
+	}
+
+	digest := sha256.Sum256([]byte(token[:i]))
+	r := new(big.Int).SetBytes(signature[:es256KeySize])
+	s := new(big.Int).SetBytes(signature[es256KeySize:])
+	if !ecdsa.Verify(key, digest[:], r, s) {
+		return errors.New("invalid token signature")
+	}
+	return nil
+}
+
+// decodeSegment decodes a base64url encoded JSON segment of a JWT into v
+func decodeSegment(segment string, v interface{}) error {
+	data, err := base64.RawURLEncoding.DecodeString(segment)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
@@ -0,0 +1,97 @@
+package serviceaccount
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+
+	"github.com/dtomasi/k1s/core/admission/lifecycle"
+	"github.com/dtomasi/k1s/core/client"
+	typesv1 "github.com/dtomasi/k1s/core/types/v1"
+)
+
+const (
+	// DefaultSigningKeySecretName is the name of the Secret of the signing key
+	DefaultSigningKeySecretName = "k1s-service-account-signing-key"
+
+	// SigningKeySecretKey is the key of the PEM encoded signing key in its Secret
+	SigningKeySecretKey = "key.pem"
+
+	// privateKeyPEMType is the PEM block type of PKCS #8 private keys
+	privateKeyPEMType = "PRIVATE KEY"
+)
+
+// LoadSigningKey returns the signing key stored in the Secret with the given
+// namespace and name. The error is a NotFound error if the Secret does not
+// exist.
+func LoadSigningKey(ctx context.Context, c client.Client, namespace, name string) (*ecdsa.PrivateKey, error) {
+	secret := &corev1.Secret{}
+	if err := c.Get(ctx, client.ObjectKey{Namespace: namespace, Name: name}, secret); err != nil {
+		return nil, fmt.Errorf("failed to get signing key secret %s/%s: %w", namespace, name, err)
+	}
+	return decodeSigningKey(secret)
+}
+
+// LoadOrCreateSigningKey returns the signing key stored in the Secret with
+// the given namespace and name. If the Secret does not exist, a new key is
+// generated and stored in an immutable Secret, creating the namespace if
+// needed.
+func LoadOrCreateSigningKey(ctx context.Context, c client.Client, namespace, name string) (*ecdsa.PrivateKey, error) {
+	key, err := LoadSigningKey(ctx, c, namespace, name)
+	if !apierrors.IsNotFound(err) {
+		return key, err
+	}
+
+	key, err = ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate signing key: %w", err)
+	}
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal signing key: %w", err)
+	}
+
+	if c.Scheme().Recognizes(typesv1.NamespaceGVK) {
+		if err := lifecycle.EnsureNamespace(ctx, c, namespace); err != nil {
+			return nil, err
+		}
+	}
+	immutable := true
+	secret := typesv1.NewSecretWithData(name, namespace, corev1.SecretTypeOpaque, map[string][]byte{
+		SigningKeySecretKey: pem.EncodeToMemory(&pem.Block{Type: privateKeyPEMType, Bytes: der}),
+	})
+	secret.Immutable = &immutable
+	if err := c.Create(ctx, secret); err != nil {
+		if apierrors.IsAlreadyExists(err) {
+			// Another process stored its key first
+			return LoadOrCreateSigningKey(ctx, c, namespace, name)
+		}
+		return nil, fmt.Errorf("failed to create signing key secret %s/%s: %w", namespace, name, err)
+	}
+	return key, nil
+}
+
+// decodeSigningKey decodes the signing key of its Secret
+func decodeSigningKey(secret *corev1.Secret) (*ecdsa.PrivateKey, error) {
+	block, _ := pem.Decode(secret.Data[SigningKeySecretKey])
+	if block == nil || block.Type != privateKeyPEMType {
+		return nil, fmt.Errorf("signing key secret %s/%s has no PEM encoded private key in %s",
+			secret.Namespace, secret.Name, SigningKeySecretKey)
+	}
+	parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse signing key of secret %s/%s: %w", secret.Namespace, secret.Name, err)
+	}
+	key, ok := parsed.(*ecdsa.PrivateKey)
+	if !ok || key.Curve != elliptic.P256() {
+		return nil, fmt.Errorf("signing key of secret %s/%s must be an ECDSA P-256 key", secret.Namespace, secret.Name)
+	}
+	return key, nil
+}
@@ -0,0 +1,13 @@
+package serviceaccount_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestServiceAccount(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "ServiceAccount Tokens Suite")
+}
@@ -0,0 +1,258 @@
+package serviceaccount
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	authenticationv1 "k8s.io/api/authentication/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/uuid"
+	"k8s.io/apiserver/pkg/authentication/authenticator"
+	apiserviceaccount "k8s.io/apiserver/pkg/authentication/serviceaccount"
+	"k8s.io/utils/clock"
+
+	"github.com/dtomasi/k1s/core/client"
+)
+
+const (
+	// DefaultIssuer is the issuer of the tokens
+	DefaultIssuer = "https://k1s.local"
+
+	// DefaultExpiration is the expiration of tokens whose request does not set one
+	DefaultExpiration = time.Hour
+
+	// MinExpiration is the minimum expiration of tokens
+	MinExpiration = 10 * time.Minute
+
+	// DefaultMaxExpiration is the default maximum expiration of tokens
+	DefaultMaxExpiration = 24 * time.Hour
+)
+
+// TokenManagerOptions configures a TokenManager.
+type TokenManagerOptions struct {
+	// Issuer is the iss claim of the tokens. Defaults to DefaultIssuer.
+	Issuer string
+
+	// Audiences are the audiences of tokens whose request sets none, and the
+	// audiences accepted when the context carries none. Defaults to the issuer.
+	Audiences []string
+
+	// Namespace is the namespace of the signing key Secret. Defaults to kube-system.
+	Namespace string
+
+	// SigningKeySecretName is the name of the signing key Secret. Defaults to
+	// DefaultSigningKeySecretName.
+	SigningKeySecretName string
+
+	// MaxExpiration caps the requested expiration. Defaults to DefaultMaxExpiration.
+	MaxExpiration time.Duration
+
+	// Clock allows injection of a custom clock for testing
+	Clock clock.PassiveClock
+}
+
+// TokenManager issues and verifies ServiceAccount tokens.
+type TokenManager struct {
+	client  client.Client
+	options TokenManagerOptions
+
+	mu    sync.Mutex
+	key   *ecdsa.PrivateKey
+	keyID string
+}
+
+var _ authenticator.Token = &TokenManager{}
+
+// NewTokenManager creates a TokenManager that looks up ServiceAccounts and
+// the signing key with the client.
+func NewTokenManager(c client.Client, options TokenManagerOptions) *TokenManager {
+	if options.Issuer == "" {
+		options.Issuer = DefaultIssuer
+	}
+	if len(options.Audiences) == 0 {
+		options.Audiences = []string{options.Issuer}
+	}
+	if options.Namespace == "" {
+		options.Namespace = metav1.NamespaceSystem
+	}
+	if options.SigningKeySecretName == "" {
+		options.SigningKeySecretName = DefaultSigningKeySecretName
+	}
+	if options.MaxExpiration <= 0 {
+		options.MaxExpiration = DefaultMaxExpiration
+	}
+	if options.Clock == nil {
+		options.Clock = clock.RealClock{}
+	}
+	return &TokenManager{client: c, options: options}
+}
+
+// CreateToken issues a token for the ServiceAccount with the given namespace
+// and name, like the token subresource of ServiceAccounts. The expiration of
+// the request defaults to DefaultExpiration and is capped at the maximum
+// expiration, and its audiences default to the audiences of the manager. It
+// returns a copy of the request with the token and its expiration in the
+// status.
+func (m *TokenManager) CreateToken(ctx context.Context, namespace, name string,
+	request *authenticationv1.TokenRequest) (*authenticationv1.TokenRequest, error) {
+	if request == nil {
+		request = &authenticationv1.TokenRequest{}
+	}
+	if request.Spec.BoundObjectRef != nil {
+		return nil, apierrors.NewBadRequest("bound object references are not supported")
+	}
+	expiration := DefaultExpiration
+	if request.Spec.ExpirationSeconds != nil {
+		expiration = time.Duration(*request.Spec.ExpirationSeconds) * time.Second
+	}
+	if expiration < MinExpiration {
+		return nil, apierrors.NewBadRequest(fmt.Sprintf("expirationSeconds may not be less than %d",
+			int64(MinExpiration/time.Second)))
+	}
+	expiration = min(expiration, m.options.MaxExpiration)
+
+	sa := &corev1.ServiceAccount{}
+	if err := m.client.Get(ctx, client.ObjectKey{Namespace: namespace, Name: name}, sa); err != nil {
+		return nil, err
+	}
+	key, kid, err := m.signingKey(ctx, true)
+	if err != nil {
+		return nil, err
+	}
+
+	audiences := request.Spec.Audiences
+	if len(audiences) == 0 {
+		audiences = m.options.Audiences
+	}
+	now := m.options.Clock.Now()
+	expires := now.Add(expiration)
+	token, err := sign(key, kid, &claims{
+		Issuer:    m.options.Issuer,
+		Subject:   apiserviceaccount.MakeUsername(namespace, name),
+		Audience:  audiences,
+		Expiry:    expires.Unix(),
+		NotBefore: now.Unix(),
+		IssuedAt:  now.Unix(),
+		ID:        string(uuid.NewUUID()),
+		Kubernetes: kubernetesClaims{
+			Namespace:      namespace,
+			ServiceAccount: ref{Name: name, UID: string(sa.UID)},
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result := request.DeepCopy()
+	expirationSeconds := int64(expiration / time.Second)
+	result.Spec.Audiences = audiences
+	result.Spec.ExpirationSeconds = &expirationSeconds
+	result.Status = authenticationv1.TokenRequestStatus{
+		Token:               token,
+		ExpirationTimestamp: metav1.NewTime(time.Unix(expires.Unix(), 0)),
+	}
+	return result, nil
+}
+
+// AuthenticateToken verifies a token and resolves it to the user of its
+// ServiceAccount. The token must be signed with the signing key, issued by
+// the issuer, not be expired, be intended for one of the audiences of the
+// context or else of the manager, and its ServiceAccount must still exist.
+// Tokens that are not JWTs of the issuer are not authenticated, without an
+// error.
+func (m *TokenManager) AuthenticateToken(ctx context.Context, token string) (*authenticator.Response, bool, error) {
+	h, c, err := parse(token)
+	if errors.Is(err, errNotJWT) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	if c.Issuer != m.options.Issuer {
+		return nil, false, nil
+	}
+
+	key, kid, err := m.signingKey(ctx, false)
+	if apierrors.IsNotFound(err) {
+		// No token has been issued yet
+		return nil, false, errors.New("token is not signed with the signing key")
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	if h.Algorithm != signingAlgorithm || h.KeyID != kid {
+		return nil, false, errors.New("token is not signed with the signing key")
+	}
+	if err := verify(&key.PublicKey, token); err != nil {
+		return nil, false, err
+	}
+
+	now := m.options.Clock.Now().Unix()
+	if now >= c.Expiry {
+		return nil, false, errors.New("token has expired")
+	}
+	if now < c.NotBefore {
+		return nil, false, errors.New("token is not valid yet")
+	}
+
+	audiences, ok := authenticator.AudiencesFrom(ctx)
+	if !ok || len(audiences) == 0 {
+		audiences = m.options.Audiences
+	}
+	accepted := audiences.Intersect(c.Audience)
+	if len(accepted) == 0 {
+		return nil, false, errors.New("token audiences are invalid")
+	}
+
+	namespace, name := c.Kubernetes.Namespace, c.Kubernetes.ServiceAccount.Name
+	if c.Subject != apiserviceaccount.MakeUsername(namespace, name) {
+		return nil, false, errors.New("token subject does not match its service account")
+	}
+	sa := &corev1.ServiceAccount{}
+	if err := m.client.Get(ctx, client.ObjectKey{Namespace: namespace, Name: name}, sa); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, false, fmt.Errorf("service account %s/%s has been deleted", namespace, name)
+		}
+		return nil, false, err
+	}
+	if string(sa.UID) != c.Kubernetes.ServiceAccount.UID {
+		return nil, false, fmt.Errorf("service account %s/%s has been recreated", namespace, name)
+	}
+
+	return &authenticator.Response{
+		Audiences: accepted,
+		User:      apiserviceaccount.UserInfo(namespace, name, string(sa.UID)),
+	}, true, nil
+}
+
+// signingKey returns the signing key and its key ID. Issuing a token loads
+// or creates the key on first use; verifying a token only loads it, so that
+// verification has no side effects on the storage.
+func (m *TokenManager) signingKey(ctx context.Context, create bool) (*ecdsa.PrivateKey, string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.key != nil {
+		return m.key, m.keyID, nil
+	}
+
+	load := LoadSigningKey
+	if create {
+		load = LoadOrCreateSigningKey
+	}
+	key, err := load(ctx, m.client, m.options.Namespace, m.options.SigningKeySecretName)
+	if err != nil {
+		return nil, "", err
+	}
+	kid, err := keyID(&key.PublicKey)
+	if err != nil {
+		return nil, "", err
+	}
+	m.key, m.keyID = key, kid
+	return key, kid, nil
+}
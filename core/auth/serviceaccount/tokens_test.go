@@ -0,0 +1,241 @@
+package serviceaccount_test
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apiserver/pkg/authentication/authenticator"
+	k8sstorage "k8s.io/apiserver/pkg/storage"
+	clocktesting "k8s.io/utils/clock/testing"
+	"k8s.io/utils/ptr"
+
+	"github.com/dtomasi/k1s/core/auth/serviceaccount"
+	"github.com/dtomasi/k1s/core/client"
+	"github.com/dtomasi/k1s/core/registry"
+	"github.com/dtomasi/k1s/core/storage"
+	typesv1 "github.com/dtomasi/k1s/core/types/v1"
+)
+
+// mapStorage is a minimal in-memory storage keyed by the client's storage keys
+type mapStorage struct {
+	storage.Interface
+	objects map[string]runtime.Object
+}
+
+func (s *mapStorage) Versioner() k8sstorage.Versioner {
+	return storage.SimpleVersioner{}
+}
+
+func (s *mapStorage) Create(_ context.Context, key string, obj, out runtime.Object, _ uint64) error {
+	if _, exists := s.objects[key]; exists {
+		return apierrors.NewAlreadyExists(schema.GroupResource{}, key)
+	}
+	s.objects[key] = obj.DeepCopyObject()
+	copyInto(obj, out)
+	return nil
+}
+
+func (s *mapStorage) Get(_ context.Context, key string, _ k8sstorage.GetOptions, out runtime.Object) error {
+	obj, exists := s.objects[key]
+	if !exists {
+		return apierrors.NewNotFound(schema.GroupResource{}, key)
+	}
+	copyInto(obj, out)
+	return nil
+}
+
+func (s *mapStorage) Delete(_ context.Context, key string, out runtime.Object, _ *k8sstorage.Preconditions,
+	_ k8sstorage.ValidateObjectFunc, _ runtime.Object) error {
+	obj, exists := s.objects[key]
+	if !exists {
+		return apierrors.NewNotFound(schema.GroupResource{}, key)
+	}
+	delete(s.objects, key)
+	copyInto(obj, out)
+	return nil
+}
+
+func copyInto(obj, out runtime.Object) {
+	if out != nil {
+		reflect.ValueOf(out).Elem().Set(reflect.ValueOf(obj.DeepCopyObject()).Elem())
+	}
+}
+
+var _ = Describe("TokenManager", func() {
+	var (
+		ctx    context.Context
+		c      client.Client
+		store  *mapStorage
+		clock  *clocktesting.FakeClock
+		tokens *serviceaccount.TokenManager
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+
+		scheme := runtime.NewScheme()
+		Expect(typesv1.AddToScheme(scheme)).To(Succeed())
+		reg := registry.NewRegistry()
+		Expect(registry.RegisterCoreResources(reg)).To(Succeed())
+
+		store = &mapStorage{objects: map[string]runtime.Object{}}
+		var err error
+		c, err = client.NewClient(client.ClientOptions{Scheme: scheme, Storage: store, Registry: reg})
+		Expect(err).NotTo(HaveOccurred())
+
+		clock = clocktesting.NewFakeClock(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC))
+		tokens = serviceaccount.NewTokenManager(c, serviceaccount.TokenManagerOptions{Clock: clock})
+		Expect(c.Create(ctx, typesv1.NewServiceAccount("builder", "default"))).To(Succeed())
+	})
+
+	createToken := func(request *authenticationv1.TokenRequest) string {
+		result, err := tokens.CreateToken(ctx, "default", "builder", request)
+		Expect(err).NotTo(HaveOccurred())
+		return result.Status.Token
+	}
+
+	It("should resolve tokens to the user of their service account", func() {
+		result, err := tokens.CreateToken(ctx, "default", "builder", &authenticationv1.TokenRequest{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.Spec.Audiences).To(ConsistOf(serviceaccount.DefaultIssuer))
+		Expect(*result.Spec.ExpirationSeconds).To(Equal(int64(3600)))
+		Expect(result.Status.ExpirationTimestamp.Time).To(BeTemporally("==", clock.Now().Add(time.Hour)))
+
+		resp, ok, err := tokens.AuthenticateToken(ctx, result.Status.Token)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(ok).To(BeTrue())
+		Expect(resp.User.GetName()).To(Equal("system:serviceaccount:default:builder"))
+		Expect(resp.User.GetGroups()).To(ConsistOf("system:serviceaccounts", "system:serviceaccounts:default"))
+		Expect(resp.Audiences).To(ConsistOf(serviceaccount.DefaultIssuer))
+	})
+
+	It("should store the signing key as a secret shared by all managers", func() {
+		token := createToken(nil)
+
+		secret := &corev1.Secret{}
+		Expect(c.Get(ctx, client.ObjectKey{
+			Namespace: "kube-system",
+			Name:      serviceaccount.DefaultSigningKeySecretName,
+		}, secret)).To(Succeed())
+		Expect(secret.Data).To(HaveKey(serviceaccount.SigningKeySecretKey))
+		Expect(*secret.Immutable).To(BeTrue())
+
+		other := serviceaccount.NewTokenManager(c, serviceaccount.TokenManagerOptions{Clock: clock})
+		_, ok, err := other.AuthenticateToken(ctx, token)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(ok).To(BeTrue())
+	})
+
+	It("should bound tokens to their expiration", func() {
+		token := createToken(&authenticationv1.TokenRequest{
+			Spec: authenticationv1.TokenRequestSpec{ExpirationSeconds: ptr.To(int64(600))},
+		})
+
+		clock.Step(10 * time.Minute)
+		_, ok, err := tokens.AuthenticateToken(ctx, token)
+		Expect(err).To(MatchError("token has expired"))
+		Expect(ok).To(BeFalse())
+	})
+
+	It("should limit the requested expiration", func() {
+		_, err := tokens.CreateToken(ctx, "default", "builder", &authenticationv1.TokenRequest{
+			Spec: authenticationv1.TokenRequestSpec{ExpirationSeconds: ptr.To(int64(60))},
+		})
+		Expect(apierrors.IsBadRequest(err)).To(BeTrue(), "unexpected error: %v", err)
+
+		result, err := tokens.CreateToken(ctx, "default", "builder", &authenticationv1.TokenRequest{
+			Spec: authenticationv1.TokenRequestSpec{ExpirationSeconds: ptr.To(int64(7 * 24 * 3600))},
+		})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(*result.Spec.ExpirationSeconds).To(Equal(int64(24 * 3600)))
+	})
+
+	It("should bound tokens to their audiences", func() {
+		token := createToken(&authenticationv1.TokenRequest{
+			Spec: authenticationv1.TokenRequestSpec{Audiences: []string{"deploy-script"}},
+		})
+
+		_, ok, err := tokens.AuthenticateToken(ctx, token)
+		Expect(err).To(MatchError("token audiences are invalid"))
+		Expect(ok).To(BeFalse())
+
+		resp, ok, err := tokens.AuthenticateToken(
+			authenticator.WithAudiences(ctx, authenticator.Audiences{"deploy-script"}), token)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(ok).To(BeTrue())
+		Expect(resp.Audiences).To(ConsistOf("deploy-script"))
+	})
+
+	It("should reject tokens of deleted service accounts", func() {
+		token := createToken(nil)
+		Expect(c.Delete(ctx, typesv1.NewServiceAccount("builder", "default"))).To(Succeed())
+
+		_, ok, err := tokens.AuthenticateToken(ctx, token)
+		Expect(err).To(MatchError(ContainSubstring("has been deleted")))
+		Expect(ok).To(BeFalse())
+
+		_, err = tokens.CreateToken(ctx, "default", "builder", nil)
+		Expect(apierrors.IsNotFound(err)).To(BeTrue(), "unexpected error: %v", err)
+	})
+
+	It("should reject tokens of recreated service accounts", func() {
+		token := createToken(nil)
+		Expect(c.Delete(ctx, typesv1.NewServiceAccount("builder", "default"))).To(Succeed())
+		recreated := typesv1.NewServiceAccount("builder", "default")
+		recreated.UID = "builder-recreated"
+		Expect(c.Create(ctx, recreated)).To(Succeed())
+
+		_, ok, err := tokens.AuthenticateToken(ctx, token)
+		Expect(err).To(MatchError(ContainSubstring("has been recreated")))
+		Expect(ok).To(BeFalse())
+
+		_, ok, err = tokens.AuthenticateToken(ctx, createToken(nil))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(ok).To(BeTrue())
+	})
+
+	It("should not create the signing key when verifying tokens", func() {
+		issuer := serviceaccount.NewTokenManager(c, serviceaccount.TokenManagerOptions{
+			Clock:                clock,
+			SigningKeySecretName: "other-signing-key",
+		})
+		result, err := issuer.CreateToken(ctx, "default", "builder", nil)
+		Expect(err).NotTo(HaveOccurred())
+
+		_, ok, err := tokens.AuthenticateToken(ctx, result.Status.Token)
+		Expect(err).To(MatchError("token is not signed with the signing key"))
+		Expect(ok).To(BeFalse())
+		Expect(store.objects).NotTo(HaveKey("//v1/secrets/kube-system/" + serviceaccount.DefaultSigningKeySecretName))
+	})
+
+	It("should reject tampered tokens and ignore other tokens", func() {
+		token := createToken(nil)
+		parts := strings.Split(token, ".")
+		other := createToken(&authenticationv1.TokenRequest{
+			Spec: authenticationv1.TokenRequestSpec{ExpirationSeconds: ptr.To(int64(7200))},
+		})
+
+		// The signature of another token does not match the claims
+		_, ok, err := tokens.AuthenticateToken(ctx, parts[0]+"."+parts[1]+"."+strings.Split(other, ".")[2])
+		Expect(err).To(MatchError("invalid token signature"))
+		Expect(ok).To(BeFalse())
+
+		_, ok, err = tokens.AuthenticateToken(ctx, "static-token")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(ok).To(BeFalse())
+
+		foreign := serviceaccount.NewTokenManager(c, serviceaccount.TokenManagerOptions{Issuer: "https://other", Clock: clock})
+		_, ok, err = foreign.AuthenticateToken(ctx, token)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(ok).To(BeFalse())
+	})
+})
@@ -0,0 +1,105 @@
+package client
+
+import (
+	"context"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/dtomasi/k1s/core/admission"
+)
+
+// admissionAttributes returns the admission attributes of a request on obj,
+// or nil if the client has no admission chain. The old object is only set
+// for updates and deletions.
+func (c *client) admissionAttributes(ctx context.Context, operation admission.Operation, obj, old Object,
+	gvk schema.GroupVersionKind, gvr schema.GroupVersionResource, subresource string,
+	options runtime.Object, dryRun []string) admission.Attributes {
+	if c.admission == nil {
+		return nil
+	}
+
+	// Avoid typed nil objects in the attributes
+	var object, oldObject runtime.Object
+	key := ObjectKey{}
+	if obj != nil {
+		object = obj
+		key = ObjectKeyFromObject(obj)
+	}
+	if old != nil {
+		oldObject = old
+		key = ObjectKeyFromObject(old)
+	}
+
+	return admission.NewAttributesRecord(object, oldObject, gvk, key.Namespace, key.Name, gvr, subresource,
+		operation, options, isDryRun(dryRun), admission.UserFrom(ctx))
+}
+
+// admitMutating runs the mutating admission plugins for the request
+func (c *client) admitMutating(ctx context.Context, attrs admission.Attributes) error {
+	if attrs == nil {
+		return nil
+	}
+	return c.admission.Admit(ctx, attrs)
+}
+
+// admitValidating runs the validating admission plugins for the request
+func (c *client) admitValidating(ctx context.Context, attrs admission.Attributes) error {
+	if attrs == nil {
+		return nil
+	}
+	return c.admission.Validate(ctx, attrs)
+}
+
+// metaCreateOptions returns the API create options of a request
+func metaCreateOptions(options *CreateOptions) *metav1.CreateOptions {
+	if options.Raw != nil {
+		return options.Raw
+	}
+	return &metav1.CreateOptions{DryRun: options.DryRun, FieldManager: options.FieldManager}
+}
+
+// metaUpdateOptions returns the API update options of a request
+func metaUpdateOptions(options *UpdateOptions) *metav1.UpdateOptions {
+	if options.Raw != nil {
+		return options.Raw
+	}
+	return &metav1.UpdateOptions{DryRun: options.DryRun, FieldManager: options.FieldManager}
+}
+
+// metaDeleteOptions returns the API delete options of a request
+func metaDeleteOptions(options *DeleteOptions) *metav1.DeleteOptions {
+	if options.Raw != nil {
+		return options.Raw
+	}
+	return &metav1.DeleteOptions{
+		DryRun:             options.DryRun,
+		GracePeriodSeconds: options.GracePeriodSeconds,
+		Preconditions:      options.Preconditions,
+		PropagationPolicy:  options.PropagationPolicy,
+	}
+}
+
+// isDryRun reports whether a request only runs defaulting, admission and
+// validation without persisting its changes
+func isDryRun(dryRun []string) bool {
+	return len(dryRun) > 0
+}
+
+// patchUpdateOption passes the dry-run and field manager of a patch to the
+// update that stores the patched object
+type patchUpdateOption struct {
+	options *PatchOptions
+}
+
+// ApplyToUpdate implements UpdateOption.
+func (o patchUpdateOption) ApplyToUpdate(opts *UpdateOptions) {
+	if o.options.Raw != nil {
+		opts.DryRun = o.options.Raw.DryRun
+		opts.FieldManager = o.options.Raw.FieldManager
+		return
+	}
+	opts.DryRun = o.options.DryRun
+	opts.FieldManager = o.options.FieldManager
+}
@@ -0,0 +1,198 @@
+package client_test
+
+import (
+	"context"
+	"errors"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apiserver/pkg/authentication/user"
+
+	"github.com/dtomasi/k1s/core/admission"
+	"github.com/dtomasi/k1s/core/client"
+)
+
+var _ = Describe("Client admission", func() {
+	var (
+		ctx        context.Context
+		chain      *admission.Chain
+		testClient client.Client
+		testItem   *TestItem
+		itemsGVR   = schema.GroupVersionResource{Group: "test.k1s.io", Version: "v1", Resource: "testitems"}
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		chain = admission.NewChain()
+		testScheme := createTestScheme()
+
+		var err error
+		testClient, err = client.NewClient(client.ClientOptions{
+			Scheme:    testScheme,
+			Storage:   newMockStorage(),
+			Defaulter: &mockDefaulter{},
+			Registry:  &mockRegistry{},
+			Admission: chain,
+		})
+		Expect(err).NotTo(HaveOccurred())
+
+		testItem = &TestItem{
+			ObjectMeta: metav1.ObjectMeta{Name: "test-item", Namespace: "default"},
+			Spec:       TestItemSpec{Name: "Test Item"},
+		}
+	})
+
+	It("should run mutating plugins after defaulting and before validating plugins", func() {
+		var order []string
+		chain.RegisterMutating(itemsGVR, admission.MutatingFunc(func(_ context.Context, a admission.Attributes) error {
+			item := a.GetObject().(*TestItem)
+			Expect(item.Spec.Quantity).To(Equal(int32(1)))
+			item.Labels = map[string]string{"approved-by": "admission"}
+			order = append(order, "mutating")
+			return nil
+		}))
+		chain.RegisterValidating(itemsGVR, admission.ValidatingFunc(func(_ context.Context, a admission.Attributes) error {
+			Expect(a.GetObject().(*TestItem).Labels).To(HaveKeyWithValue("approved-by", "admission"))
+			order = append(order, "validating")
+			return nil
+		}))
+
+		Expect(testClient.Create(ctx, testItem)).To(Succeed())
+		Expect(order).To(Equal([]string{"mutating", "validating"}))
+
+		stored := &TestItem{}
+		Expect(testClient.Get(ctx, client.ObjectKeyFromObject(testItem), stored)).To(Succeed())
+		Expect(stored.Labels).To(HaveKeyWithValue("approved-by", "admission"))
+	})
+
+	It("should reject requests with a forbidden error", func() {
+		chain.RegisterValidating(itemsGVR, admission.ValidatingFunc(func(context.Context, admission.Attributes) error {
+			return errors.New("items need an approved-by label")
+		}))
+
+		err := testClient.Create(ctx, testItem)
+		Expect(apierrors.IsForbidden(err)).To(BeTrue())
+		Expect(err.Error()).To(ContainSubstring("items need an approved-by label"))
+	})
+
+	It("should pass the operation, old object, user and dry-run flag", func() {
+		Expect(testClient.Create(ctx, testItem)).To(Succeed())
+
+		var (
+			attrs        []admission.Attributes
+			descriptions []string
+		)
+		chain.RegisterValidating(itemsGVR, admission.ValidatingFunc(func(_ context.Context, a admission.Attributes) error {
+			attrs = append(attrs, a)
+			if a.GetObject() != nil {
+				descriptions = append(descriptions, a.GetObject().(*TestItem).Spec.Description)
+			}
+			return nil
+		}))
+
+		userCtx := admission.WithUser(ctx, &user.DefaultInfo{Name: "alice"})
+		testItem.Spec.Description = "updated"
+		Expect(testClient.Update(userCtx, testItem, dryRunAll{})).To(Succeed())
+
+		stored := &TestItem{}
+		Expect(testClient.Get(ctx, client.ObjectKeyFromObject(testItem), stored)).To(Succeed())
+		Expect(stored.Spec.Description).To(BeEmpty())
+
+		Expect(testClient.Delete(userCtx, testItem)).To(Succeed())
+
+		Expect(attrs).To(HaveLen(2))
+		Expect(attrs[0].GetOperation()).To(Equal(admission.Update))
+		Expect(attrs[0].GetOldObject().(*TestItem).Spec.Description).To(BeEmpty())
+		Expect(descriptions).To(Equal([]string{"updated"}))
+		Expect(attrs[0].GetUserInfo().GetName()).To(Equal("alice"))
+		Expect(attrs[0].IsDryRun()).To(BeTrue())
+
+		Expect(attrs[1].GetOperation()).To(Equal(admission.Delete))
+		Expect(attrs[1].GetObject()).To(BeNil())
+		Expect(attrs[1].GetOldObject()).NotTo(BeNil())
+		Expect(attrs[1].GetName()).To(Equal("test-item"))
+		Expect(attrs[1].IsDryRun()).To(BeFalse())
+	})
+
+	It("should not persist dry-run requests", func() {
+		Expect(testClient.Create(ctx, testItem)).To(Succeed())
+
+		var operations []admission.Operation
+		chain.RegisterValidating(itemsGVR, admission.ValidatingFunc(func(_ context.Context, a admission.Attributes) error {
+			Expect(a.IsDryRun()).To(BeTrue())
+			operations = append(operations, a.GetOperation())
+			return nil
+		}))
+
+		newItem := &TestItem{ObjectMeta: metav1.ObjectMeta{Name: "new-item", Namespace: "default"}}
+		Expect(testClient.Create(ctx, newItem, dryRunAll{})).To(Succeed())
+		Expect(testClient.Get(ctx, client.ObjectKeyFromObject(newItem), &TestItem{})).NotTo(Succeed())
+
+		testItem.Status.Status = "Sold"
+		Expect(testClient.Status().Update(ctx, testItem, dryRunAll{})).To(Succeed())
+
+		patch := client.RawPatch{
+			PatchType: types.StrategicMergePatchType,
+			PatchData: []byte(`{"spec":{"description":"patched"}}`),
+		}
+		Expect(testClient.Patch(ctx, testItem, patch, dryRunAll{})).To(Succeed())
+
+		deleted := &TestItem{ObjectMeta: metav1.ObjectMeta{Name: "test-item", Namespace: "default"}}
+		Expect(testClient.Delete(ctx, deleted, dryRunAll{})).To(Succeed())
+		Expect(deleted.Spec.Name).To(Equal("Test Item"))
+
+		stored := &TestItem{}
+		Expect(testClient.Get(ctx, client.ObjectKeyFromObject(testItem), stored)).To(Succeed())
+		Expect(stored.Status.Status).NotTo(Equal("Sold"))
+		Expect(stored.Spec.Description).To(BeEmpty())
+		Expect(operations).To(Equal([]admission.Operation{
+			admission.Create, admission.Update, admission.Update, admission.Delete,
+		}))
+	})
+
+	It("should pass the status subresource for status updates", func() {
+		Expect(testClient.Create(ctx, testItem)).To(Succeed())
+
+		var subresource string
+		chain.RegisterValidating(itemsGVR, admission.ValidatingFunc(func(_ context.Context, a admission.Attributes) error {
+			subresource = a.GetSubresource()
+			return nil
+		}))
+
+		testItem.Status.Status = "Sold"
+		Expect(testClient.Status().Update(ctx, testItem)).To(Succeed())
+		Expect(subresource).To(Equal("status"))
+	})
+
+	It("should skip plugins registered for other resources", func() {
+		otherGVR := schema.GroupVersionResource{Group: "test.k1s.io", Version: "v1", Resource: "others"}
+		chain.RegisterValidating(otherGVR, admission.ValidatingFunc(func(context.Context, admission.Attributes) error {
+			return errors.New("should not be called")
+		}))
+
+		Expect(testClient.Create(ctx, testItem)).To(Succeed())
+	})
+})
+
+// dryRunAll marks a request as dry run
+type dryRunAll struct{}
+
+func (dryRunAll) ApplyToCreate(opts *client.CreateOptions) {
+	opts.DryRun = []string{metav1.DryRunAll}
+}
+
+func (dryRunAll) ApplyToUpdate(opts *client.UpdateOptions) {
+	opts.DryRun = []string{metav1.DryRunAll}
+}
+
+func (dryRunAll) ApplyToPatch(opts *client.PatchOptions) {
+	opts.DryRun = []string{metav1.DryRunAll}
+}
+
+func (dryRunAll) ApplyToDelete(opts *client.DeleteOptions) {
+	opts.DryRun = []string{metav1.DryRunAll}
+}
@@ -2,15 +2,24 @@ package client
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"reflect"
 
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/strategicpatch"
+	"k8s.io/apimachinery/pkg/util/uuid"
 	"k8s.io/apiserver/pkg/storage"
 
+	"github.com/dtomasi/k1s/core/admission"
 	"github.com/dtomasi/k1s/core/codec"
 	"github.com/dtomasi/k1s/core/defaulting"
 	"github.com/dtomasi/k1s/core/registry"
@@ -27,6 +36,7 @@ type client struct {
 	defaulter    defaulting.Defaulter
 	registry     registry.Registry
 	codecFactory *codec.CodecFactory
+	admission    *admission.Chain
 	statusWriter StatusWriter
 }
 
@@ -39,6 +49,9 @@ type ClientOptions struct {
 	Defaulter    defaulting.Defaulter
 	Registry     registry.Registry
 	CodecFactory *codec.CodecFactory
+	// Admission is the admission chain run by Create, Update, Patch and
+	// Delete after defaulting. Optional.
+	Admission *admission.Chain
 }
 
 // NewClient creates a new k1s client with the provided options.
@@ -61,6 +74,7 @@ func NewClient(opts ClientOptions) (Client, error) {
 		defaulter:    opts.Defaulter,
 		registry:     opts.Registry,
 		codecFactory: opts.CodecFactory,
+		admission:    opts.Admission,
 	}
 
 	if c.codecFactory == nil {
@@ -111,6 +125,13 @@ func (c *client) Get(ctx context.Context, key ObjectKey, obj Object, opts ...Get
 		getOpts.ResourceVersion = options.Raw.ResourceVersion
 	}
 
+	if u, ok := obj.(*unstructured.Unstructured); ok {
+		if err := c.getUnstructured(ctx, storageKey, getOpts, gvk, u); err != nil {
+			return fmt.Errorf("failed to get object: %w", err)
+		}
+		return nil
+	}
+
 	if err := c.storage.Get(ctx, storageKey, getOpts, obj); err != nil {
 		return fmt.Errorf("failed to get object: %w", err)
 	}
@@ -139,6 +160,7 @@ func (c *client) List(ctx context.Context, list ObjectList, opts ...ListOption)
 
 	listOpts := storage.ListOptions{
 		ResourceVersion: "0",
+		Recursive:       true,
 	}
 	if options.Raw != nil {
 		if options.Raw.ResourceVersion != "" {
@@ -148,6 +170,13 @@ func (c *client) List(ctx context.Context, list ObjectList, opts ...ListOption)
 		// These would be handled by the storage implementation if needed
 	}
 
+	if u, ok := list.(*unstructured.UnstructuredList); ok {
+		if err := c.listUnstructured(ctx, storageKey, listOpts, gvk, u); err != nil {
+			return fmt.Errorf("failed to list objects: %w", err)
+		}
+		return nil
+	}
+
 	if err := c.storage.List(ctx, storageKey, listOpts, list); err != nil {
 		return fmt.Errorf("failed to list objects: %w", err)
 	}
@@ -162,6 +191,16 @@ func (c *client) Create(ctx context.Context, obj Object, opts ...CreateOption) e
 		opt.ApplyToCreate(options)
 	}
 
+	gvk, err := c.getGVKForObject(obj)
+	if err != nil {
+		return fmt.Errorf("failed to get GVK for object: %w", err)
+	}
+
+	gvr, err := c.registry.GetGVRForGVK(gvk)
+	if err != nil {
+		return fmt.Errorf("failed to get GVR for GVK %s: %w", gvk, err)
+	}
+
 	// Apply defaults if defaulter is available
 	if c.defaulter != nil {
 		if err := c.defaulter.Default(ctx, obj); err != nil {
@@ -169,6 +208,13 @@ func (c *client) Create(ctx context.Context, obj Object, opts ...CreateOption) e
 		}
 	}
 
+	createOptions := metaCreateOptions(options)
+	attrs := c.admissionAttributes(ctx, admission.Create, obj, nil, gvk, gvr, "",
+		createOptions, createOptions.DryRun)
+	if err := c.admitMutating(ctx, attrs); err != nil {
+		return err
+	}
+
 	// Validate the object if validator is available
 	if c.validator != nil {
 		if err := c.validator.Validate(ctx, obj); err != nil {
@@ -176,19 +222,18 @@ func (c *client) Create(ctx context.Context, obj Object, opts ...CreateOption) e
 		}
 	}
 
-	gvk, err := c.getGVKForObject(obj)
-	if err != nil {
-		return fmt.Errorf("failed to get GVK for object: %w", err)
-	}
-
-	gvr, err := c.registry.GetGVRForGVK(gvk)
-	if err != nil {
-		return fmt.Errorf("failed to get GVR for GVK %s: %w", gvk, err)
+	if err := c.admitValidating(ctx, attrs); err != nil {
+		return err
 	}
 
 	key := ObjectKeyFromObject(obj)
 	storageKey := c.buildStorageKey(gvr, key)
 
+	// A dry run returns the admitted object without persisting it
+	if isDryRun(createOptions.DryRun) {
+		return nil
+	}
+
 	// Ensure the object has proper metadata
 	c.ensureObjectMetadata(obj)
 
@@ -214,17 +259,29 @@ func (c *client) Update(ctx context.Context, obj Object, opts ...UpdateOption) e
 	}
 
 	// Create a new object of the same type to get the existing version
-	existing, err := c.scheme.New(gvk)
+	existingObj, err := c.newObjectFor(obj, gvk)
 	if err != nil {
 		return fmt.Errorf("failed to create object for existing version: %w", err)
 	}
-	existingObj := existing.(Object)
 
 	// Get the existing object
 	if err := c.Get(ctx, key, existingObj); err != nil {
 		return fmt.Errorf("failed to get existing object for update: %w", err)
 	}
 
+	gvr, err := c.registry.GetGVRForGVK(gvk)
+	if err != nil {
+		return fmt.Errorf("failed to get GVR for GVK %s: %w", gvk, err)
+	}
+
+	// Like the API server, an update that carries a resourceVersion only
+	// succeeds against that version; an empty one updates unconditionally
+	existingRV := existingObj.GetResourceVersion()
+	if rv := obj.GetResourceVersion(); rv != "" && rv != existingRV {
+		return apierrors.NewConflict(gvr.GroupResource(), key.Name,
+			errors.New("the object has been modified; please apply your changes to the latest version and try again"))
+	}
+
 	// Apply defaults if defaulter is available
 	if c.defaulter != nil {
 		if err := c.defaulter.Default(ctx, obj); err != nil {
@@ -232,6 +289,13 @@ func (c *client) Update(ctx context.Context, obj Object, opts ...UpdateOption) e
 		}
 	}
 
+	updateOptions := metaUpdateOptions(options)
+	attrs := c.admissionAttributes(ctx, admission.Update, obj, existingObj, gvk, gvr, "",
+		updateOptions, updateOptions.DryRun)
+	if err := c.admitMutating(ctx, attrs); err != nil {
+		return err
+	}
+
 	// Validate the update if validator is available
 	if c.validator != nil {
 		if err := c.validator.ValidateUpdate(ctx, obj, existingObj); err != nil {
@@ -239,29 +303,34 @@ func (c *client) Update(ctx context.Context, obj Object, opts ...UpdateOption) e
 		}
 	}
 
-	gvr, err := c.registry.GetGVRForGVK(gvk)
-	if err != nil {
-		return fmt.Errorf("failed to get GVR for GVK %s: %w", gvk, err)
+	if err := c.admitValidating(ctx, attrs); err != nil {
+		return err
 	}
 
 	storageKey := c.buildStorageKey(gvr, key)
 
 	// Update resource version
-	obj.SetResourceVersion(existingObj.GetResourceVersion())
-	obj.SetGeneration(existingObj.GetGeneration() + 1)
-
-	// For update operations, we delete the old and create the new
-	existingRV := existingObj.GetResourceVersion()
-	preconditions := &storage.Preconditions{
-		ResourceVersion: &existingRV,
+	obj.SetResourceVersion(existingRV)
+	obj.SetGeneration(existingObj.GetGeneration())
+	if specChanged(existingObj, obj) {
+		obj.SetGeneration(existingObj.GetGeneration() + 1)
 	}
 
-	if err := c.storage.Delete(ctx, storageKey, obj, preconditions, nil, existingObj); err != nil {
-		return fmt.Errorf("failed to delete existing object during update: %w", err)
+	// A dry run returns the admitted object without persisting it
+	if isDryRun(updateOptions.DryRun) {
+		return nil
 	}
 
-	if err := c.storage.Create(ctx, storageKey, obj, obj, 0); err != nil {
-		return fmt.Errorf("failed to create updated object: %w", err)
+	// The stored object is replaced atomically. The precondition fails if
+	// another writer updated the object since it was read.
+	preconditions := &storage.Preconditions{
+		ResourceVersion: &existingRV,
+	}
+	if err := c.storage.GuaranteedUpdate(ctx, storageKey, &metav1.PartialObjectMetadata{}, false, preconditions,
+		func(runtime.Object, storage.ResponseMeta) (runtime.Object, *uint64, error) {
+			return obj, nil, nil
+		}, nil); err != nil {
+		return fmt.Errorf("failed to update object: %w", err)
 	}
 
 	return nil
@@ -293,6 +362,8 @@ func (c *client) Delete(ctx context.Context, obj Object, opts ...DeleteOption) e
 
 	key := ObjectKeyFromObject(obj)
 	storageKey := c.buildStorageKey(gvr, key)
+	deleteOptions := metaDeleteOptions(options)
+	dryRun := isDryRun(deleteOptions.DryRun)
 
 	var preconditions *storage.Preconditions
 	if options.Preconditions != nil {
@@ -302,10 +373,52 @@ func (c *client) Delete(ctx context.Context, obj Object, opts ...DeleteOption) e
 		}
 	}
 
-	if err := c.storage.Delete(ctx, storageKey, obj, preconditions, nil, nil); err != nil {
+	if c.admission != nil || dryRun {
+		// Admission plugins see the stored object as the old object
+		existingObj, err := c.newObjectFor(obj, gvk)
+		if err != nil {
+			return fmt.Errorf("failed to create object for existing version: %w", err)
+		}
+		if err := c.Get(ctx, key, existingObj); err != nil {
+			return fmt.Errorf("failed to get existing object for delete: %w", err)
+		}
+
+		attrs := c.admissionAttributes(ctx, admission.Delete, nil, existingObj, gvk, gvr, "",
+			deleteOptions, deleteOptions.DryRun)
+		if err := c.admitMutating(ctx, attrs); err != nil {
+			return err
+		}
+		if err := c.admitValidating(ctx, attrs); err != nil {
+			return err
+		}
+
+		// A dry run returns the object that would be deleted without
+		// removing it
+		if dryRun {
+			if err := k1sstorage.CheckPreconditions(storageKey, existingObj, preconditions); err != nil {
+				return fmt.Errorf("failed to delete object: %w", err)
+			}
+			return c.Get(ctx, key, obj)
+		}
+	}
+
+	// Unstructured objects are decoded from the raw stored object
+	var out runtime.Object = obj
+	raw := &rawObject{}
+	if isUnstructured(obj) {
+		out = raw
+	}
+
+	if err := c.storage.Delete(ctx, storageKey, out, preconditions, nil, nil); err != nil {
 		return fmt.Errorf("failed to delete object: %w", err)
 	}
 
+	if u, ok := obj.(*unstructured.Unstructured); ok && len(raw.data) > 0 {
+		if err := decodeUnstructured(raw.data, gvk, u); err != nil {
+			return fmt.Errorf("failed to decode deleted object: %w", err)
+		}
+	}
+
 	return nil
 }
 
@@ -323,11 +436,10 @@ func (c *client) Patch(ctx context.Context, obj Object, patch Patch, opts ...Pat
 	}
 
 	// Get the existing object
-	existing, err := c.scheme.New(gvk)
+	existingObj, err := c.newObjectFor(obj, gvk)
 	if err != nil {
 		return fmt.Errorf("failed to create object for existing version: %w", err)
 	}
-	existingObj := existing.(Object)
 
 	if err := c.Get(ctx, key, existingObj); err != nil {
 		return fmt.Errorf("failed to get existing object for patch: %w", err)
@@ -359,13 +471,22 @@ func (c *client) Patch(ctx context.Context, obj Object, patch Patch, opts ...Pat
 	}
 
 	// Update the object with patched values
-	return c.Update(ctx, patchedObj)
+	return c.Update(ctx, patchedObj, patchUpdateOption{options: options})
 }
 
 // Helper methods
 
-// getGVKForObject returns the GroupVersionKind for an object.
+// getGVKForObject returns the GroupVersionKind for an object. Unstructured
+// objects carry their own kind, typed objects are looked up in the scheme.
 func (c *client) getGVKForObject(obj Object) (schema.GroupVersionKind, error) {
+	if isUnstructured(obj) {
+		gvk := obj.GetObjectKind().GroupVersionKind()
+		if gvk.Kind == "" {
+			return schema.GroupVersionKind{}, fmt.Errorf("unstructured object has no kind")
+		}
+		return gvk, nil
+	}
+
 	gvks, _, err := c.scheme.ObjectKinds(obj)
 	if err != nil {
 		return schema.GroupVersionKind{}, err
@@ -376,28 +497,27 @@ func (c *client) getGVKForObject(obj Object) (schema.GroupVersionKind, error) {
 	return gvks[0], nil
 }
 
-// getGVKForObjectList returns the GroupVersionKind for an object list.
+// getGVKForObjectList returns the GroupVersionKind of the items of an object list.
 func (c *client) getGVKForObjectList(list ObjectList) (schema.GroupVersionKind, error) {
-	gvks, _, err := c.scheme.ObjectKinds(list)
-	if err != nil {
-		return schema.GroupVersionKind{}, err
-	}
-	if len(gvks) == 0 {
-		return schema.GroupVersionKind{}, fmt.Errorf("no GroupVersionKind found for object list")
-	}
-
-	// For lists, convert from "ItemList" to "Item"
-	gvk := gvks[0]
-	switch gvk.Kind {
-	case "ItemList":
-		gvk.Kind = "Item"
-	case "CategoryList":
-		gvk.Kind = "Category"
-	default:
-		// Add more list type mappings as needed
+	var gvk schema.GroupVersionKind
+	if isUnstructured(list) {
+		gvk = list.GetObjectKind().GroupVersionKind()
+		if gvk.Kind == "" {
+			return schema.GroupVersionKind{}, fmt.Errorf("unstructured list has no kind")
+		}
+	} else {
+		gvks, _, err := c.scheme.ObjectKinds(list)
+		if err != nil {
+			return schema.GroupVersionKind{}, err
+		}
+		if len(gvks) == 0 {
+			return schema.GroupVersionKind{}, fmt.Errorf("no GroupVersionKind found for object list")
+		}
+		gvk = gvks[0]
 	}
 
-	return gvk, nil
+	// For lists, map the list kind to the item kind, e.g. "ItemList" to "Item"
+	return c.itemKindFor(gvk), nil
 }
 
 // buildStorageKey creates a storage key for an object.
@@ -416,25 +536,84 @@ func (c *client) buildListStorageKey(gvr schema.GroupVersionResource, namespace
 	return fmt.Sprintf("/%s/%s/%s/", gvr.Group, gvr.Version, gvr.Resource)
 }
 
-// ensureObjectMetadata ensures that the object has proper metadata set.
+// ensureObjectMetadata ensures that the object has proper metadata set. Like
+// the API server, it assigns every created object a new random UID, so a
+// recreated object can be told apart from the deleted one.
 func (c *client) ensureObjectMetadata(obj Object) {
-	if obj.GetUID() == "" {
-		obj.SetUID(types.UID(fmt.Sprintf("%s-%d", obj.GetName(), obj.GetGeneration())))
-	}
+	obj.SetUID(uuid.NewUUID())
 	if obj.GetResourceVersion() == "" {
 		obj.SetResourceVersion("1")
 	}
+	if obj.GetGeneration() == 0 {
+		obj.SetGeneration(1)
+	}
 	if obj.GetCreationTimestamp().Time.IsZero() {
 		now := metav1.Now()
 		obj.SetCreationTimestamp(now)
 	}
 }
 
+// specChanged reports whether an update changes the object outside of its
+// metadata and status. Like the API server, only such changes increment the
+// generation, so status and metadata updates do not retrigger reconcilers
+// that filter on generation changes.
+func specChanged(oldObj, newObj Object) bool {
+	oldContent, err := specContent(oldObj)
+	if err != nil {
+		return true
+	}
+	newContent, err := specContent(newObj)
+	if err != nil {
+		return true
+	}
+	return !equality.Semantic.DeepEqual(oldContent, newContent)
+}
+
+// specContent returns the content of obj without type, metadata and status
+func specContent(obj Object) (map[string]interface{}, error) {
+	var content map[string]interface{}
+	if u, ok := obj.(*unstructured.Unstructured); ok {
+		content = runtime.DeepCopyJSON(u.UnstructuredContent())
+	} else {
+		var err error
+		if content, err = runtime.DefaultUnstructuredConverter.ToUnstructured(obj); err != nil {
+			return nil, err
+		}
+	}
+	for _, field := range []string{"apiVersion", "kind", "metadata", "status"} {
+		delete(content, field)
+	}
+	return content, nil
+}
+
 // Patch application methods - simplified implementations
 
+// applyStrategicMergePatch applies a strategic merge patch using the patch
+// strategies of the Go type. Like the API server, it rejects unstructured
+// objects, which carry no patch strategies.
 func (c *client) applyStrategicMergePatch(existing Object, patchData []byte) (Object, error) {
-	// For now, use JSON merge patch as a fallback
-	return c.applyMergePatch(existing, patchData)
+	if isUnstructured(existing) {
+		return nil, fmt.Errorf("strategic merge patches are not supported for unstructured objects")
+	}
+
+	original, err := json.Marshal(existing)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode object: %w", err)
+	}
+	patched, err := strategicpatch.StrategicMergePatch(original, patchData, existing)
+	if err != nil {
+		return nil, err
+	}
+
+	// Decode into a new object, so that fields removed by the patch are cleared
+	patchedObj, ok := reflect.New(reflect.TypeOf(existing).Elem()).Interface().(Object)
+	if !ok {
+		return nil, fmt.Errorf("failed to create object of type %T", existing)
+	}
+	if err := json.Unmarshal(patched, patchedObj); err != nil {
+		return nil, fmt.Errorf("failed to decode patched object: %w", err)
+	}
+	return patchedObj, nil
 }
 
 func (c *client) applyMergePatch(existing Object, patchData []byte) (Object, error) {
@@ -7,6 +7,7 @@ import (
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
@@ -438,6 +439,21 @@ var _ = Describe("Client", func() {
 			Expect(err).To(HaveOccurred())
 			Expect(err.Error()).To(ContainSubstring("already exists"))
 		})
+
+		It("should assign a new UID to recreated objects", func() {
+			testItem.UID = "caller-uid"
+			Expect(testClient.Create(ctx, testItem)).To(Succeed())
+			uid := testItem.UID
+			Expect(uid).NotTo(BeEmpty())
+			Expect(uid).NotTo(Equal(types.UID("caller-uid")))
+			Expect(testClient.Delete(ctx, testItem)).To(Succeed())
+
+			recreated := testItem.DeepCopyObject().(*TestItem)
+			recreated.ResourceVersion = ""
+			Expect(testClient.Create(ctx, recreated)).To(Succeed())
+			Expect(recreated.UID).NotTo(BeEmpty())
+			Expect(recreated.UID).NotTo(Equal(uid))
+		})
 	})
 
 	Describe("Get", func() {
@@ -491,6 +507,28 @@ var _ = Describe("Client", func() {
 			Expect(err).To(HaveOccurred())
 			Expect(err.Error()).To(ContainSubstring("update validation failed"))
 		})
+		It("should only increment the generation when the spec changes", func() {
+			Expect(testItem.GetGeneration()).To(Equal(int64(1)))
+
+			testItem.Labels = map[string]string{"tier": "gold"}
+			Expect(testClient.Update(ctx, testItem)).To(Succeed())
+			Expect(testItem.GetGeneration()).To(Equal(int64(1)))
+
+			testItem.Spec.Description = "Updated description"
+			Expect(testClient.Update(ctx, testItem)).To(Succeed())
+			Expect(testItem.GetGeneration()).To(Equal(int64(2)))
+
+			testItem.Status.Status = "Reserved"
+			Expect(testClient.Status().Update(ctx, testItem)).To(Succeed())
+			Expect(testItem.GetGeneration()).To(Equal(int64(2)))
+		})
+
+		It("should reject updates of a stale resourceVersion", func() {
+			testItem.ResourceVersion = "stale"
+			testItem.Spec.Description = "Updated description"
+			err := testClient.Update(ctx, testItem)
+			Expect(apierrors.IsConflict(err)).To(BeTrue())
+		})
 	})
 
 	Describe("Delete", func() {
@@ -582,6 +620,18 @@ var _ = Describe("Client", func() {
 			err := testClient.Patch(ctx, testItem, patch)
 			Expect(err).NotTo(HaveOccurred())
 		})
+
+		It("should apply strategic merge patches to the stored object", func() {
+			patch := client.RawPatch{
+				PatchType: types.StrategicMergePatchType,
+				PatchData: []byte(`{"spec":{"description":"Patched description"}}`),
+			}
+			Expect(testClient.Patch(ctx, testItem, patch)).To(Succeed())
+
+			patched := &TestItem{}
+			Expect(testClient.Get(ctx, client.ObjectKeyFromObject(testItem), patched)).To(Succeed())
+			Expect(patched.Spec.Description).To(Equal("Patched description"))
+		})
 	})
 
 	Describe("Status", func() {
@@ -596,6 +646,17 @@ var _ = Describe("Client", func() {
 			Expect(err).NotTo(HaveOccurred())
 		})
 
+		It("should reject status updates of a stale resourceVersion", func() {
+			testItem.ResourceVersion = "stale"
+			testItem.Status.Status = "Sold"
+			err := testClient.Status().Update(ctx, testItem)
+			Expect(apierrors.IsConflict(err)).To(BeTrue())
+
+			stored := &TestItem{}
+			Expect(testClient.Get(ctx, client.ObjectKeyFromObject(testItem), stored)).To(Succeed())
+			Expect(stored.Status.Status).NotTo(Equal("Sold"))
+		})
+
 		It("should patch status subresource", func() {
 			patch := client.RawPatch{
 				PatchType: types.MergePatchType,
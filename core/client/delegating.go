@@ -0,0 +1,337 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/wait"
+)
+
+// defaultReadYourWritesTimeout bounds how long a read waits for the cache to
+// observe the client's own writes
+const defaultReadYourWritesTimeout = 5 * time.Second
+
+// readYourWritesInterval is the interval at which a read polls the cache
+// for the client's own writes
+const readYourWritesInterval = 10 * time.Millisecond
+
+// ErrResourceNotCached is returned by cache readers for types they do not
+// cache. The delegating client reads those types from storage.
+type ErrResourceNotCached struct {
+	GVK schema.GroupVersionKind
+}
+
+// Error implements error.
+func (e ErrResourceNotCached) Error() string {
+	return fmt.Sprintf("%s is not cached", e.GVK)
+}
+
+// DelegatingClientOptions configures a client that reads from a cache.
+type DelegatingClientOptions struct {
+	// Client performs all writes and the reads of uncached types.
+	Client Client
+
+	// CacheReader serves reads, typically informers.NewCacheReader. It
+	// returns ErrResourceNotCached for types it does not cache.
+	CacheReader Reader
+
+	// UncachedObjects are types that are always read from storage.
+	// Unstructured objects are never read from the cache.
+	UncachedObjects []Object
+
+	// ReadYourWritesTimeout bounds how long a read waits for the cache to
+	// observe the client's own writes before it reads from storage instead.
+	// Defaults to 5 seconds.
+	ReadYourWritesTimeout time.Duration
+}
+
+// NewDelegatingClient returns a client that reads from the cache and writes
+// to storage, like controller-runtime's client.New with a cache. A read of
+// an object the client wrote waits until the cache has observed the write's
+// resourceVersion, so callers read their own writes.
+func NewDelegatingClient(opts DelegatingClientOptions) (Client, error) {
+	if opts.Client == nil {
+		return nil, fmt.Errorf("client is required")
+	}
+	if opts.CacheReader == nil {
+		return nil, fmt.Errorf("cache reader is required")
+	}
+	if opts.ReadYourWritesTimeout == 0 {
+		opts.ReadYourWritesTimeout = defaultReadYourWritesTimeout
+	}
+
+	uncached := make(map[schema.GroupVersionKind]bool, len(opts.UncachedObjects))
+	for _, obj := range opts.UncachedObjects {
+		gvks, _, err := opts.Client.Scheme().ObjectKinds(obj)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get GVK for uncached object %T: %w", obj, err)
+		}
+		for _, gvk := range gvks {
+			uncached[gvk] = true
+		}
+	}
+
+	return &delegatingClient{
+		Client:   opts.Client,
+		cache:    opts.CacheReader,
+		uncached: uncached,
+		timeout:  opts.ReadYourWritesTimeout,
+		pending:  make(map[pendingKey]pendingWrite),
+	}, nil
+}
+
+// pendingKey identifies an object the client wrote
+type pendingKey struct {
+	gvk schema.GroupVersionKind
+	key ObjectKey
+}
+
+// pendingWrite is a write the cache has not been seen to observe yet
+type pendingWrite struct {
+	resourceVersion uint64
+	deleted         bool
+}
+
+// delegatingClient reads from a cache and writes through the wrapped client
+type delegatingClient struct {
+	Client
+
+	cache    Reader
+	uncached map[schema.GroupVersionKind]bool
+	timeout  time.Duration
+
+	mu      sync.Mutex
+	pending map[pendingKey]pendingWrite
+}
+
+// Get reads the object from the cache, after the cache observed the client's writes of it
+func (d *delegatingClient) Get(ctx context.Context, key ObjectKey, obj Object, opts ...GetOption) error {
+	gvk, cached := d.cachedKind(obj)
+	if !cached {
+		return d.Client.Get(ctx, key, obj, opts...)
+	}
+
+	if !d.waitForWrites(ctx, gvk, obj, func(k pendingKey) bool { return k.key == key }) {
+		return d.Client.Get(ctx, key, obj, opts...)
+	}
+
+	err := d.cache.Get(ctx, key, obj, opts...)
+	if isNotCached(err) {
+		return d.Client.Get(ctx, key, obj, opts...)
+	}
+	return err
+}
+
+// List reads the objects from the cache, after the cache observed the client's writes of their type
+func (d *delegatingClient) List(ctx context.Context, list ObjectList, opts ...ListOption) error {
+	gvk, cached := d.cachedKind(list)
+	if !cached {
+		return d.Client.List(ctx, list, opts...)
+	}
+	gvk.Kind = strings.TrimSuffix(gvk.Kind, "List")
+
+	item, err := d.Scheme().New(gvk)
+	if err != nil {
+		return d.Client.List(ctx, list, opts...)
+	}
+	obj, ok := item.(Object)
+	if !ok || !d.waitForWrites(ctx, gvk, obj, func(pendingKey) bool { return true }) {
+		return d.Client.List(ctx, list, opts...)
+	}
+
+	err = d.cache.List(ctx, list, opts...)
+	if isNotCached(err) {
+		return d.Client.List(ctx, list, opts...)
+	}
+	return err
+}
+
+// Create creates the object in storage
+func (d *delegatingClient) Create(ctx context.Context, obj Object, opts ...CreateOption) error {
+	if err := d.Client.Create(ctx, obj, opts...); err != nil {
+		return err
+	}
+	d.recordWrite(obj, false)
+	return nil
+}
+
+// Update updates the object in storage
+func (d *delegatingClient) Update(ctx context.Context, obj Object, opts ...UpdateOption) error {
+	if err := d.Client.Update(ctx, obj, opts...); err != nil {
+		return err
+	}
+	d.recordWrite(obj, false)
+	return nil
+}
+
+// Patch patches the object in storage
+func (d *delegatingClient) Patch(ctx context.Context, obj Object, patch Patch, opts ...PatchOption) error {
+	if err := d.Client.Patch(ctx, obj, patch, opts...); err != nil {
+		return err
+	}
+	d.recordWrite(obj, false)
+	return nil
+}
+
+// Delete deletes the object from storage
+func (d *delegatingClient) Delete(ctx context.Context, obj Object, opts ...DeleteOption) error {
+	if err := d.Client.Delete(ctx, obj, opts...); err != nil {
+		return err
+	}
+
+	// A dry run keeps the object, so the cache never observes a deletion
+	options := &DeleteOptions{}
+	for _, opt := range opts {
+		opt.ApplyToDelete(options)
+	}
+	if !isDryRun(metaDeleteOptions(options).DryRun) {
+		d.recordWrite(obj, true)
+	}
+	return nil
+}
+
+// Status returns a status writer that records its writes for reads from the cache
+func (d *delegatingClient) Status() StatusWriter {
+	return &delegatingStatusWriter{StatusWriter: d.Client.Status(), client: d}
+}
+
+// delegatingStatusWriter records the status writes of a delegating client
+type delegatingStatusWriter struct {
+	StatusWriter
+	client *delegatingClient
+}
+
+// Update updates the status of the object in storage
+func (w *delegatingStatusWriter) Update(ctx context.Context, obj Object, opts ...UpdateOption) error {
+	if err := w.StatusWriter.Update(ctx, obj, opts...); err != nil {
+		return err
+	}
+	w.client.recordWrite(obj, false)
+	return nil
+}
+
+// Patch patches the status of the object in storage
+func (w *delegatingStatusWriter) Patch(ctx context.Context, obj Object, patch Patch, opts ...PatchOption) error {
+	if err := w.StatusWriter.Patch(ctx, obj, patch, opts...); err != nil {
+		return err
+	}
+	w.client.recordWrite(obj, false)
+	return nil
+}
+
+// cachedKind returns the kind of obj and whether it is read from the cache
+func (d *delegatingClient) cachedKind(obj runtime.Object) (schema.GroupVersionKind, bool) {
+	switch obj.(type) {
+	case *unstructured.Unstructured, *unstructured.UnstructuredList:
+		return schema.GroupVersionKind{}, false
+	}
+	gvks, _, err := d.Scheme().ObjectKinds(obj)
+	if err != nil || len(gvks) == 0 {
+		return schema.GroupVersionKind{}, false
+	}
+	gvk := gvks[0]
+
+	itemGVK := gvk
+	if meta.IsListType(obj) {
+		itemGVK.Kind = strings.TrimSuffix(gvk.Kind, "List")
+	}
+	return gvk, !d.uncached[itemGVK]
+}
+
+// recordWrite remembers the resourceVersion of a write, so reads wait for the cache to observe it
+func (d *delegatingClient) recordWrite(obj Object, deleted bool) {
+	gvk, cached := d.cachedKind(obj)
+	if !cached {
+		return
+	}
+	rv, err := strconv.ParseUint(obj.GetResourceVersion(), 10, 64)
+	if err != nil {
+		return
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.pending[pendingKey{gvk: gvk, key: ObjectKeyFromObject(obj)}] = pendingWrite{resourceVersion: rv, deleted: deleted}
+}
+
+// waitForWrites waits until the cache observed the pending writes of kind
+// gvk selected by match. All writes share one timeout. It returns false if
+// the cache did not observe them in time, so the caller reads from storage
+// instead. obj is a scratch object of the kind.
+func (d *delegatingClient) waitForWrites(ctx context.Context, gvk schema.GroupVersionKind, obj Object,
+	match func(pendingKey) bool) bool {
+	d.mu.Lock()
+	writes := make(map[pendingKey]pendingWrite)
+	for k, w := range d.pending {
+		if k.gvk == gvk && match(k) {
+			writes[k] = w
+		}
+	}
+	d.mu.Unlock()
+	if len(writes) == 0 {
+		return true
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, d.timeout)
+	defer cancel()
+
+	for k, w := range writes {
+		scratch, ok := obj.DeepCopyObject().(Object)
+		if !ok {
+			return false
+		}
+		err := wait.PollUntilContextCancel(ctx, readYourWritesInterval, true,
+			func(ctx context.Context) (bool, error) {
+				return d.observed(ctx, k.key, scratch, w)
+			})
+
+		// The write is either observed now or read from storage, so it is no longer waited for
+		d.mu.Lock()
+		if d.pending[k] == w {
+			delete(d.pending, k)
+		}
+		d.mu.Unlock()
+
+		if err != nil {
+			return false
+		}
+	}
+	return true
+}
+
+// observed reports whether the cache has observed a write
+func (d *delegatingClient) observed(ctx context.Context, key ObjectKey, scratch Object, w pendingWrite) (bool, error) {
+	err := d.cache.Get(ctx, key, scratch)
+	switch {
+	case apierrors.IsNotFound(err):
+		return w.deleted, nil
+	case err != nil:
+		return false, err
+	}
+
+	rv, err := strconv.ParseUint(scratch.GetResourceVersion(), 10, 64)
+	if err != nil {
+		return false, err
+	}
+	if w.deleted {
+		// The object was created again after the deletion
+		return rv > w.resourceVersion, nil
+	}
+	return rv >= w.resourceVersion, nil
+}
+
+// isNotCached reports whether err is an ErrResourceNotCached
+func isNotCached(err error) bool {
+	var notCached ErrResourceNotCached
+	return errors.As(err, &notCached)
+}
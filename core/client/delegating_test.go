@@ -0,0 +1,212 @@
+package client_test
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/dtomasi/k1s/core/client"
+)
+
+// fakeCache is a cache reader whose contents the test controls
+type fakeCache struct {
+	mu        sync.Mutex
+	items     map[client.ObjectKey]TestItem
+	delayed   map[client.ObjectKey]delayedItem
+	notCached bool
+}
+
+// delayedItem is an item the cache observes some time after it is first read
+type delayedItem struct {
+	item     TestItem
+	after    time.Duration
+	observed time.Time
+}
+
+func (f *fakeCache) set(item TestItem) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.items[client.ObjectKeyFromObject(&item)] = item
+}
+
+func (f *fakeCache) delay(item TestItem, after time.Duration) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.delayed == nil {
+		f.delayed = map[client.ObjectKey]delayedItem{}
+	}
+	f.delayed[client.ObjectKeyFromObject(&item)] = delayedItem{item: item, after: after}
+}
+
+func (f *fakeCache) Get(_ context.Context, key client.ObjectKey, obj client.Object, _ ...client.GetOption) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.notCached {
+		return client.ErrResourceNotCached{}
+	}
+	if d, ok := f.delayed[key]; ok {
+		if d.observed.IsZero() {
+			d.observed = time.Now().Add(d.after)
+			f.delayed[key] = d
+		}
+		if time.Now().After(d.observed) {
+			f.items[key] = d.item
+			delete(f.delayed, key)
+		}
+	}
+	item, ok := f.items[key]
+	if !ok {
+		return apierrors.NewNotFound(schema.GroupResource{Resource: "testitems"}, key.Name)
+	}
+	*obj.(*TestItem) = item
+	return nil
+}
+
+func (f *fakeCache) List(_ context.Context, list client.ObjectList, _ ...client.ListOption) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.notCached {
+		return client.ErrResourceNotCached{}
+	}
+	items := &TestItemList{}
+	for _, item := range f.items {
+		items.Items = append(items.Items, item)
+	}
+	*list.(*TestItemList) = *items
+	return nil
+}
+
+var _ = Describe("DelegatingClient", func() {
+	var (
+		ctx     context.Context
+		backend client.Client
+		cache   *fakeCache
+		key     = client.ObjectKey{Namespace: "default", Name: "item"}
+	)
+
+	newItem := func(name, rv string) *TestItem {
+		return &TestItem{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "item", ResourceVersion: rv},
+			Spec:       TestItemSpec{Name: name},
+		}
+	}
+
+	newDelegatingClient := func(uncached ...client.Object) client.Client {
+		c, err := client.NewDelegatingClient(client.DelegatingClientOptions{
+			Client:                backend,
+			CacheReader:           cache,
+			UncachedObjects:       uncached,
+			ReadYourWritesTimeout: 200 * time.Millisecond,
+		})
+		Expect(err).NotTo(HaveOccurred())
+		return c
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		cache = &fakeCache{items: map[client.ObjectKey]TestItem{}}
+
+		var err error
+		backend, err = client.NewClient(client.ClientOptions{
+			Scheme:   createTestScheme(),
+			Storage:  newMockStorage(),
+			Registry: &mockRegistry{},
+		})
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("should require a client and a cache reader", func() {
+		_, err := client.NewDelegatingClient(client.DelegatingClientOptions{CacheReader: cache})
+		Expect(err).To(HaveOccurred())
+		_, err = client.NewDelegatingClient(client.DelegatingClientOptions{Client: backend})
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should read from the cache", func() {
+		c := newDelegatingClient()
+		Expect(backend.Create(ctx, newItem("stored", ""))).To(Succeed())
+		cache.set(*newItem("cached", "1"))
+
+		item := &TestItem{}
+		Expect(c.Get(ctx, key, item)).To(Succeed())
+		Expect(item.Spec.Name).To(Equal("cached"))
+
+		list := &TestItemList{}
+		Expect(c.List(ctx, list)).To(Succeed())
+		Expect(list.Items).To(HaveLen(1))
+		Expect(list.Items[0].Spec.Name).To(Equal("cached"))
+	})
+
+	It("should wait for the cache to observe its own writes", func() {
+		c := newDelegatingClient()
+		Expect(c.Create(ctx, newItem("created", ""))).To(Succeed())
+
+		go func() {
+			defer GinkgoRecover()
+			time.Sleep(50 * time.Millisecond)
+			cache.set(*newItem("observed", "1"))
+		}()
+
+		item := &TestItem{}
+		Expect(c.Get(ctx, key, item)).To(Succeed())
+		Expect(item.Spec.Name).To(Equal("observed"))
+	})
+
+	It("should read from storage if the cache does not observe a write in time", func() {
+		c := newDelegatingClient()
+		Expect(c.Create(ctx, newItem("created", ""))).To(Succeed())
+
+		item := &TestItem{}
+		Expect(c.Get(ctx, key, item)).To(Succeed())
+		Expect(item.Spec.Name).To(Equal("created"))
+	})
+
+	It("should wait for all unobserved writes within one timeout", func() {
+		c := newDelegatingClient()
+		for _, name := range []string{"a", "b", "c", "d"} {
+			item := newItem(name, "")
+			item.Name = name
+			Expect(c.Create(ctx, item)).To(Succeed())
+			cache.delay(*item, 150*time.Millisecond)
+		}
+
+		// Each write alone is observed in time, all of them together are not
+		start := time.Now()
+		list := &TestItemList{}
+		Expect(c.List(ctx, list)).To(Succeed())
+		Expect(time.Since(start)).To(BeNumerically("<", 400*time.Millisecond))
+		Expect(list.Items).To(HaveLen(4))
+	})
+
+	It("should not wait for dry-run deletions", func() {
+		c := newDelegatingClient()
+		Expect(backend.Create(ctx, newItem("stored", ""))).To(Succeed())
+		cache.set(*newItem("cached", "1"))
+
+		Expect(c.Delete(ctx, newItem("", ""), dryRunAll{})).To(Succeed())
+
+		item := &TestItem{}
+		Expect(c.Get(ctx, key, item)).To(Succeed())
+		Expect(item.Spec.Name).To(Equal("cached"))
+	})
+
+	It("should read uncached types from storage", func() {
+		Expect(backend.Create(ctx, newItem("stored", ""))).To(Succeed())
+		cache.set(*newItem("cached", "1"))
+
+		item := &TestItem{}
+		Expect(newDelegatingClient(&TestItem{}).Get(ctx, key, item)).To(Succeed())
+		Expect(item.Spec.Name).To(Equal("stored"))
+
+		cache.notCached = true
+		item = &TestItem{}
+		Expect(newDelegatingClient().Get(ctx, key, item)).To(Succeed())
+		Expect(item.Spec.Name).To(Equal("stored"))
+	})
+})
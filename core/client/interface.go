@@ -223,6 +223,10 @@ type UpdateOptions struct {
 
 // DeleteOptions contains options for delete requests.
 type DeleteOptions struct {
+	// DryRun, when present, indicates that modifications should not be
+	// persisted. An invalid or unrecognized dryRun directive will
+	// result in an error response and no further processing of the request.
+	DryRun []string
 	// GracePeriodSeconds is the duration in seconds before the object should be deleted.
 	GracePeriodSeconds *int64
 	// Preconditions must be fulfilled before a deletion is carried out.
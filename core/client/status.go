@@ -2,10 +2,17 @@ package client
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"reflect"
 
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apiserver/pkg/storage"
+
+	"github.com/dtomasi/k1s/core/admission"
 )
 
 // statusWriter implements the StatusWriter interface.
@@ -27,22 +34,47 @@ func (sw *statusWriter) Update(ctx context.Context, obj Object, opts ...UpdateOp
 		return fmt.Errorf("failed to get GVK for object: %w", err)
 	}
 
-	existing, err := sw.client.scheme.New(gvk)
+	existingObj, err := sw.client.newObjectFor(obj, gvk)
 	if err != nil {
 		return fmt.Errorf("failed to create object for existing version: %w", err)
 	}
-	existingObj := existing.(Object)
 
 	// Get the current object
 	if err := sw.client.Get(ctx, key, existingObj); err != nil {
 		return fmt.Errorf("failed to get existing object for status update: %w", err)
 	}
 
+	gvr, err := sw.client.registry.GetGVRForGVK(gvk)
+	if err != nil {
+		return fmt.Errorf("failed to get GVR for GVK %s: %w", gvk, err)
+	}
+
+	// Like an update of the object, a status update that carries a
+	// resourceVersion only succeeds against that version
+	existingRV := existingObj.GetResourceVersion()
+	if rv := obj.GetResourceVersion(); rv != "" && rv != existingRV {
+		return apierrors.NewConflict(gvr.GroupResource(), key.Name,
+			errors.New("the object has been modified; please apply your changes to the latest version and try again"))
+	}
+
+	// Keep the stored object for the admission plugins
+	var oldObj Object
+	if sw.client.admission != nil {
+		oldObj = existingObj.DeepCopyObject().(Object)
+	}
+
 	// Update only the status field while preserving other fields
 	if err := sw.updateObjectStatus(existingObj, obj); err != nil {
 		return fmt.Errorf("failed to update status field: %w", err)
 	}
 
+	updateOptions := metaUpdateOptions(options)
+	attrs := sw.client.admissionAttributes(ctx, admission.Update, existingObj, oldObj, gvk, gvr, "status",
+		updateOptions, updateOptions.DryRun)
+	if err := sw.client.admitMutating(ctx, attrs); err != nil {
+		return err
+	}
+
 	// Validate the status update if validator is available
 	if sw.client.validator != nil {
 		if err := sw.client.validator.ValidateUpdate(ctx, existingObj, existingObj); err != nil {
@@ -50,28 +82,28 @@ func (sw *statusWriter) Update(ctx context.Context, obj Object, opts ...UpdateOp
 		}
 	}
 
-	gvr, err := sw.client.registry.GetGVRForGVK(gvk)
-	if err != nil {
-		return fmt.Errorf("failed to get GVR for GVK %s: %w", gvk, err)
+	if err := sw.client.admitValidating(ctx, attrs); err != nil {
+		return err
 	}
 
-	storageKey := sw.client.buildStorageKey(gvr, key)
+	// A dry run returns the admitted object without persisting it
+	if isDryRun(updateOptions.DryRun) {
+		sw.copyObject(existingObj, obj)
+		return nil
+	}
 
-	// Increment the generation for status updates
-	existingObj.SetGeneration(existingObj.GetGeneration() + 1)
+	storageKey := sw.client.buildStorageKey(gvr, key)
 
-	// For status updates, we need to update the existing object instead of creating a new one
-	// First delete the existing one, then create the updated version
-	existingRV := existingObj.GetResourceVersion()
+	// The stored object is replaced atomically, unless another writer
+	// updated it since it was read. Status updates keep the generation,
+	// which only tracks changes of the spec.
 	preconditions := &storage.Preconditions{
 		ResourceVersion: &existingRV,
 	}
-
-	if err := sw.client.storage.Delete(ctx, storageKey, existingObj, preconditions, nil, existingObj); err != nil {
-		return fmt.Errorf("failed to delete existing object during status update: %w", err)
-	}
-
-	if err := sw.client.storage.Create(ctx, storageKey, existingObj, existingObj, 0); err != nil {
+	if err := sw.client.storage.GuaranteedUpdate(ctx, storageKey, &metav1.PartialObjectMetadata{}, false, preconditions,
+		func(runtime.Object, storage.ResponseMeta) (runtime.Object, *uint64, error) {
+			return existingObj, nil, nil
+		}, nil); err != nil {
 		return fmt.Errorf("failed to update object status: %w", err)
 	}
 
@@ -95,11 +127,10 @@ func (sw *statusWriter) Patch(ctx context.Context, obj Object, patch Patch, opts
 		return fmt.Errorf("failed to get GVK for object: %w", err)
 	}
 
-	existing, err := sw.client.scheme.New(gvk)
+	existingObj, err := sw.client.newObjectFor(obj, gvk)
 	if err != nil {
 		return fmt.Errorf("failed to create object for existing version: %w", err)
 	}
-	existingObj := existing.(Object)
 
 	if err := sw.client.Get(ctx, key, existingObj); err != nil {
 		return fmt.Errorf("failed to get existing object for status patch: %w", err)
@@ -112,11 +143,27 @@ func (sw *statusWriter) Patch(ctx context.Context, obj Object, patch Patch, opts
 	}
 
 	// Update the object with the patched status
-	return sw.Update(ctx, patchedObj)
+	return sw.Update(ctx, patchedObj, patchUpdateOption{options: options})
 }
 
 // updateObjectStatus updates the status field of the target object with the status from the source object.
 func (sw *statusWriter) updateObjectStatus(target, source Object) error {
+	if targetU, ok := target.(*unstructured.Unstructured); ok {
+		sourceU, ok := source.(*unstructured.Unstructured)
+		if !ok {
+			return fmt.Errorf("status field types do not match")
+		}
+		status, found, err := unstructured.NestedFieldCopy(sourceU.Object, "status")
+		if err != nil {
+			return err
+		}
+		if !found {
+			unstructured.RemoveNestedField(targetU.Object, "status")
+			return nil
+		}
+		return unstructured.SetNestedField(targetU.Object, status, "status")
+	}
+
 	targetValue := reflect.ValueOf(target)
 	sourceValue := reflect.ValueOf(source)
 
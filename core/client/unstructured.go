@@ -0,0 +1,211 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/apiserver/pkg/storage"
+)
+
+// rawObject receives the stored JSON of an object from a storage backend.
+// Backends decode objects with encoding/json, and stored objects may lack
+// the apiVersion and kind an *unstructured.Unstructured needs to decode.
+type rawObject struct {
+	data []byte
+}
+
+// UnmarshalJSON keeps a copy of the stored JSON
+func (r *rawObject) UnmarshalJSON(data []byte) error {
+	r.data = append(r.data[:0], data...)
+	return nil
+}
+
+// MarshalJSON returns the stored JSON
+func (r *rawObject) MarshalJSON() ([]byte, error) {
+	return r.data, nil
+}
+
+// GetObjectKind implements runtime.Object
+func (r *rawObject) GetObjectKind() schema.ObjectKind {
+	return schema.EmptyObjectKind
+}
+
+// DeepCopyObject implements runtime.Object
+func (r *rawObject) DeepCopyObject() runtime.Object {
+	return &rawObject{data: append([]byte(nil), r.data...)}
+}
+
+// isUnstructured reports whether obj is an unstructured object or list
+func isUnstructured(obj runtime.Object) bool {
+	switch obj.(type) {
+	case *unstructured.Unstructured, *unstructured.UnstructuredList:
+		return true
+	}
+	return false
+}
+
+// newObjectFor returns an empty object of kind gvk, of the same flavour as obj
+func (c *client) newObjectFor(obj Object, gvk schema.GroupVersionKind) (Object, error) {
+	if isUnstructured(obj) {
+		u := &unstructured.Unstructured{}
+		u.SetGroupVersionKind(gvk)
+		return u, nil
+	}
+
+	newObj, err := c.scheme.New(gvk)
+	if err != nil {
+		return nil, err
+	}
+	clientObj, ok := newObj.(Object)
+	if !ok {
+		return nil, fmt.Errorf("object of kind %s does not implement client.Object", gvk)
+	}
+	return clientObj, nil
+}
+
+// itemKindFor returns the kind of the items of a list kind, using the
+// ListKind of the registered resources and the "List" suffix otherwise
+func (c *client) itemKindFor(listGVK schema.GroupVersionKind) schema.GroupVersionKind {
+	for _, gvr := range c.registry.ListResources() {
+		if gvr.GroupVersion() != listGVK.GroupVersion() {
+			continue
+		}
+		config, err := c.registry.GetResourceConfig(gvr)
+		if err == nil && config.ListKind == listGVK.Kind && config.Kind != "" {
+			return listGVK.GroupVersion().WithKind(config.Kind)
+		}
+	}
+	return listGVK.GroupVersion().WithKind(strings.TrimSuffix(listGVK.Kind, "List"))
+}
+
+// decodeUnstructured decodes stored JSON into out, setting the kind gvk if
+// the stored object has none
+func decodeUnstructured(data []byte, gvk schema.GroupVersionKind, out *unstructured.Unstructured) error {
+	content := map[string]interface{}{}
+	if err := json.Unmarshal(data, &content); err != nil {
+		return fmt.Errorf("failed to decode object: %w", err)
+	}
+	out.Object = content
+	if out.GetKind() == "" || out.GetAPIVersion() == "" {
+		out.SetGroupVersionKind(gvk)
+	}
+	return nil
+}
+
+// getUnstructured reads the object at storageKey into an unstructured object
+func (c *client) getUnstructured(ctx context.Context, storageKey string, opts storage.GetOptions,
+	gvk schema.GroupVersionKind, out *unstructured.Unstructured) error {
+	raw := &rawObject{}
+	if err := c.storage.Get(ctx, storageKey, opts, raw); err != nil {
+		return err
+	}
+	return decodeUnstructured(raw.data, gvk, out)
+}
+
+// listUnstructured lists the objects at storageKey into an unstructured list
+func (c *client) listUnstructured(ctx context.Context, storageKey string, opts storage.ListOptions,
+	itemGVK schema.GroupVersionKind, out *unstructured.UnstructuredList) error {
+	list := &metav1.List{}
+	if err := c.storage.List(ctx, storageKey, opts, list); err != nil {
+		return err
+	}
+
+	items := make([]unstructured.Unstructured, len(list.Items))
+	for i, item := range list.Items {
+		if err := decodeUnstructured(item.Raw, itemGVK, &items[i]); err != nil {
+			return err
+		}
+	}
+	out.Items = items
+	out.SetResourceVersion(list.ResourceVersion)
+	out.SetContinue(list.Continue)
+	if out.GetKind() == "" {
+		out.SetGroupVersionKind(itemGVK.GroupVersion().WithKind(itemGVK.Kind + "List"))
+	}
+	return nil
+}
+
+// unstructuredWatcher converts the objects of watch events into unstructured objects
+type unstructuredWatcher struct {
+	watcher watch.Interface
+	gvk     schema.GroupVersionKind
+	result  chan watch.Event
+	stopCh  chan struct{}
+	once    sync.Once
+}
+
+// newUnstructuredWatcher wraps w so that events carry unstructured objects of kind gvk
+func newUnstructuredWatcher(w watch.Interface, gvk schema.GroupVersionKind) watch.Interface {
+	uw := &unstructuredWatcher{
+		watcher: w,
+		gvk:     gvk,
+		result:  make(chan watch.Event),
+		stopCh:  make(chan struct{}),
+	}
+	go uw.run()
+	return uw
+}
+
+// Stop stops the underlying watcher
+func (w *unstructuredWatcher) Stop() {
+	w.once.Do(func() {
+		close(w.stopCh)
+		w.watcher.Stop()
+	})
+}
+
+// ResultChan returns the converted events
+func (w *unstructuredWatcher) ResultChan() <-chan watch.Event {
+	return w.result
+}
+
+// run forwards converted events until the underlying stream ends or the watcher is stopped
+func (w *unstructuredWatcher) run() {
+	defer close(w.result)
+
+	input := w.watcher.ResultChan()
+	for {
+		select {
+		case <-w.stopCh:
+			return
+		case event, ok := <-input:
+			if !ok {
+				return
+			}
+			if event.Type != watch.Error && event.Type != watch.Bookmark && event.Object != nil {
+				if u, err := toUnstructured(event.Object, w.gvk); err == nil {
+					event.Object = u
+				}
+			}
+			select {
+			case w.result <- event:
+			case <-w.stopCh:
+				return
+			}
+		}
+	}
+}
+
+// toUnstructured converts obj into an unstructured object of kind gvk
+func toUnstructured(obj runtime.Object, gvk schema.GroupVersionKind) (*unstructured.Unstructured, error) {
+	if u, ok := obj.(*unstructured.Unstructured); ok {
+		return u.DeepCopy(), nil
+	}
+	data, err := json.Marshal(obj)
+	if err != nil {
+		return nil, err
+	}
+	u := &unstructured.Unstructured{}
+	if err := decodeUnstructured(data, gvk, u); err != nil {
+		return nil, err
+	}
+	return u, nil
+}
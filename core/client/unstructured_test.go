@@ -0,0 +1,257 @@
+package client_test
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/apiserver/pkg/storage"
+
+	"github.com/dtomasi/k1s/core/client"
+	k1sruntime "github.com/dtomasi/k1s/core/runtime"
+)
+
+// jsonStorage stores objects as JSON and decodes them with encoding/json,
+// the way the storage backends do
+type jsonStorage struct {
+	*mockStorage
+	mu      sync.Mutex
+	rv      uint64
+	data    map[string][]byte
+	watcher chan watch.Event
+}
+
+func newJSONStorage() *jsonStorage {
+	return &jsonStorage{
+		mockStorage: newMockStorage(),
+		data:        map[string][]byte{},
+		watcher:     make(chan watch.Event, 10),
+	}
+}
+
+func (s *jsonStorage) Create(_ context.Context, key string, obj, out runtime.Object, _ uint64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, exists := s.data[key]; exists {
+		return apierrors.NewAlreadyExists(schema.GroupResource{}, key)
+	}
+	s.rv++
+	accessor, err := meta.Accessor(obj)
+	if err != nil {
+		return err
+	}
+	accessor.SetResourceVersion(strconv.FormatUint(s.rv, 10))
+	data, err := json.Marshal(obj)
+	if err != nil {
+		return err
+	}
+	s.data[key] = data
+
+	event := &TestItem{}
+	if err := json.Unmarshal(data, event); err == nil {
+		select {
+		case s.watcher <- watch.Event{Type: watch.Added, Object: event}:
+		default:
+		}
+	}
+	if out != nil {
+		return json.Unmarshal(data, out)
+	}
+	return nil
+}
+
+func (s *jsonStorage) Delete(_ context.Context, key string, out runtime.Object, _ *storage.Preconditions,
+	_ storage.ValidateObjectFunc, _ runtime.Object) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	data, exists := s.data[key]
+	if !exists {
+		return apierrors.NewNotFound(schema.GroupResource{}, key)
+	}
+	delete(s.data, key)
+	if out != nil {
+		return json.Unmarshal(data, out)
+	}
+	return nil
+}
+
+func (s *jsonStorage) GuaranteedUpdate(_ context.Context, key string, destination runtime.Object, _ bool,
+	preconditions *storage.Preconditions, tryUpdate storage.UpdateFunc, _ runtime.Object) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	data, exists := s.data[key]
+	if !exists {
+		return apierrors.NewNotFound(schema.GroupResource{}, key)
+	}
+	current := destination.DeepCopyObject()
+	if err := json.Unmarshal(data, current); err != nil {
+		return err
+	}
+	accessor, err := meta.Accessor(current)
+	if err != nil {
+		return err
+	}
+	if preconditions != nil && preconditions.ResourceVersion != nil &&
+		*preconditions.ResourceVersion != accessor.GetResourceVersion() {
+		return apierrors.NewConflict(schema.GroupResource{}, key, nil)
+	}
+
+	updated, _, err := tryUpdate(current, storage.ResponseMeta{})
+	if err != nil {
+		return err
+	}
+	s.rv++
+	if accessor, err = meta.Accessor(updated); err != nil {
+		return err
+	}
+	accessor.SetResourceVersion(strconv.FormatUint(s.rv, 10))
+	if data, err = json.Marshal(updated); err != nil {
+		return err
+	}
+	s.data[key] = data
+	return json.Unmarshal(data, destination)
+}
+
+func (s *jsonStorage) Get(_ context.Context, key string, _ storage.GetOptions, objPtr runtime.Object) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	data, exists := s.data[key]
+	if !exists {
+		return apierrors.NewNotFound(schema.GroupResource{}, key)
+	}
+	return json.Unmarshal(data, objPtr)
+}
+
+func (s *jsonStorage) List(_ context.Context, key string, _ storage.ListOptions, listObj runtime.Object) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	list, ok := listObj.(*metav1.List)
+	if !ok {
+		return s.mockStorage.List(context.Background(), key, storage.ListOptions{}, listObj)
+	}
+	for k, data := range s.data {
+		if strings.HasPrefix(k, key) {
+			list.Items = append(list.Items, runtime.RawExtension{Raw: data})
+		}
+	}
+	list.ResourceVersion = strconv.FormatUint(s.rv, 10)
+	return nil
+}
+
+func (s *jsonStorage) Watch(_ context.Context, _ string, _ storage.ListOptions) (watch.Interface, error) {
+	return watch.NewProxyWatcher(s.watcher), nil
+}
+
+var _ = Describe("Unstructured objects", func() {
+	var (
+		ctx   context.Context
+		c     client.Client
+		store *jsonStorage
+		gvk   = schema.GroupVersionKind{Group: "test.k1s.io", Version: "v1", Kind: "TestItem"}
+	)
+
+	newUnstructuredItem := func(name, description string) *unstructured.Unstructured {
+		u := &unstructured.Unstructured{}
+		u.SetGroupVersionKind(gvk)
+		u.SetNamespace("default")
+		u.SetName(name)
+		Expect(unstructured.SetNestedField(u.Object, description, "spec", "description")).To(Succeed())
+		return u
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		store = newJSONStorage()
+
+		// The scheme does not know the TestItem type
+		var err error
+		c, err = client.NewClient(client.ClientOptions{
+			Scheme:   k1sruntime.NewScheme(),
+			Storage:  store,
+			Registry: &mockRegistry{},
+		})
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("should create and get objects whose types are not in the scheme", func() {
+		Expect(c.Create(ctx, newUnstructuredItem("item", "first"))).To(Succeed())
+
+		got := &unstructured.Unstructured{}
+		got.SetGroupVersionKind(gvk)
+		Expect(c.Get(ctx, client.ObjectKey{Namespace: "default", Name: "item"}, got)).To(Succeed())
+		Expect(got.GroupVersionKind()).To(Equal(gvk))
+		Expect(got.GetResourceVersion()).NotTo(BeEmpty())
+		description, _, _ := unstructured.NestedString(got.Object, "spec", "description")
+		Expect(description).To(Equal("first"))
+	})
+
+	It("should return NotFound for missing objects", func() {
+		got := &unstructured.Unstructured{}
+		got.SetGroupVersionKind(gvk)
+		err := c.Get(ctx, client.ObjectKey{Namespace: "default", Name: "missing"}, got)
+		Expect(apierrors.IsNotFound(err)).To(BeTrue())
+	})
+
+	It("should list into an UnstructuredList with the item kind from the registry", func() {
+		Expect(c.Create(ctx, newUnstructuredItem("a", "first"))).To(Succeed())
+		Expect(c.Create(ctx, newUnstructuredItem("b", "second"))).To(Succeed())
+
+		list := &unstructured.UnstructuredList{}
+		list.SetGroupVersionKind(gvk.GroupVersion().WithKind("TestItemList"))
+		Expect(c.List(ctx, list, client.InNamespace("default"))).To(Succeed())
+		Expect(list.Items).To(HaveLen(2))
+		for _, item := range list.Items {
+			Expect(item.GroupVersionKind()).To(Equal(gvk))
+		}
+	})
+
+	It("should update and delete unstructured objects", func() {
+		item := newUnstructuredItem("item", "first")
+		Expect(c.Create(ctx, item)).To(Succeed())
+
+		Expect(unstructured.SetNestedField(item.Object, "second", "spec", "description")).To(Succeed())
+		Expect(c.Update(ctx, item)).To(Succeed())
+
+		got := &unstructured.Unstructured{}
+		got.SetGroupVersionKind(gvk)
+		key := client.ObjectKey{Namespace: "default", Name: "item"}
+		Expect(c.Get(ctx, key, got)).To(Succeed())
+		description, _, _ := unstructured.NestedString(got.Object, "spec", "description")
+		Expect(description).To(Equal("second"))
+
+		Expect(c.Delete(ctx, got)).To(Succeed())
+		Expect(apierrors.IsNotFound(c.Get(ctx, key, got))).To(BeTrue())
+	})
+
+	It("should deliver unstructured objects to watchers of unstructured lists", func() {
+		list := &unstructured.UnstructuredList{}
+		list.SetGroupVersionKind(gvk.GroupVersion().WithKind("TestItemList"))
+		wc, err := client.NewWatchClient(c)
+		Expect(err).NotTo(HaveOccurred())
+		w, err := wc.Watch(ctx, list, client.InNamespace("default"))
+		Expect(err).NotTo(HaveOccurred())
+		defer w.Stop()
+
+		Expect(c.Create(ctx, newUnstructuredItem("item", "first"))).To(Succeed())
+
+		var event watch.Event
+		Eventually(w.ResultChan(), time.Second).Should(Receive(&event))
+		Expect(event.Type).To(Equal(watch.Added))
+		u, ok := event.Object.(*unstructured.Unstructured)
+		Expect(ok).To(BeTrue())
+		Expect(u.GetName()).To(Equal("item"))
+		Expect(u.GroupVersionKind()).To(Equal(gvk))
+	})
+})
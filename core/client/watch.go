@@ -74,6 +74,11 @@ func (w *watchClient) Watch(ctx context.Context, obj ObjectList, opts ...WatchOp
 		return nil, fmt.Errorf("failed to start watch: %w", err)
 	}
 
+	// Unstructured watches receive unstructured objects of the watched kind
+	if isUnstructured(obj) {
+		watcher = newUnstructuredWatcher(watcher, gvk)
+	}
+
 	// If we have selectors, wrap the watcher with filtering
 	if w.needsFiltering(options) {
 		return w.newFilteringWatcher(watcher, options), nil
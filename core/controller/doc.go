@@ -1,2 +1,7 @@
 // Package controller provides k1s controller functionality.
+//
+// The controller runtime itself lives in the separate
+// github.com/dtomasi/k1s/controller-runtime module, which builds a
+// controller-runtime compatible Manager, Builder and Reconciler on top of
+// core/runtime.Runtime and core/informers.SharedInformerFactory.
 package controller
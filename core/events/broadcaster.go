@@ -2,15 +2,27 @@ package events
 
 import (
 	"context"
+	"io"
 	"sync"
 	"sync/atomic"
+	"time"
 
 	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/watch"
 )
 
-// eventBroadcaster implements the EventBroadcaster interface
+// maxEventRetryBackoff caps the backoff between retries of a failed write
+const maxEventRetryBackoff = 5 * time.Second
+
+// eventBroadcaster implements the EventBroadcaster interface.
+//
+// Recorded events are queued and distributed by a single loop. Every sink has
+// its own ordered queue that is processed by a worker, which retries failed
+// writes with an exponential backoff. Like client-go, events are dropped
+// instead of blocking the recorder when a queue is full. Shutdown stops
+// accepting events and waits until the queues are drained.
 type eventBroadcaster struct {
 	mu        sync.RWMutex
 	sinks     map[int]sinkRegistration
@@ -21,16 +33,29 @@ type eventBroadcaster struct {
 	metrics   *EventMetrics
 	options   EventBroadcasterOptions
 	started   bool
+	stopping  bool
 	ctx       context.Context
 	cancel    context.CancelFunc
+
+	// inflight tracks the events that are being queued
+	inflight sync.WaitGroup
+
+	// drained is closed when the distribution loop has finished
+	drained chan struct{}
+
+	// retention tracks the goroutines that prune the events of sinks
+	retention sync.WaitGroup
 }
 
 // sinkRegistration represents a registered event sink
 type sinkRegistration struct {
-	id      int
-	sink    EventSink
-	stopCh  chan struct{}
-	watcher *sinkWatcher
+	id         int
+	sink       EventSink
+	correlator *EventCorrelator
+	queue      chan *corev1.Event
+	stopCh     chan struct{}
+	done       chan struct{}
+	watcher    *sinkWatcher
 }
 
 // watcherRegistration represents a registered event watcher
@@ -65,6 +90,16 @@ func NewEventBroadcaster(options EventBroadcasterOptions) EventBroadcaster {
 	if options.Clock == nil {
 		options.Clock = RealClock{}
 	}
+	if options.MaxRetries == 0 {
+		options.MaxRetries = DefaultEventMaxRetries
+	}
+	// A failing sink must not retry an event forever
+	if options.MaxRetries < 0 || options.MaxRetries > MaxEventRetries {
+		options.MaxRetries = MaxEventRetries
+	}
+	if options.RetryBackoff <= 0 {
+		options.RetryBackoff = DefaultEventRetryBackoff
+	}
 
 	ctx, cancel := context.WithCancel(context.Background())
 
@@ -77,6 +112,11 @@ func NewEventBroadcaster(options EventBroadcasterOptions) EventBroadcaster {
 		options:   options,
 		ctx:       ctx,
 		cancel:    cancel,
+		drained:   make(chan struct{}),
+	}
+
+	for _, sink := range options.Sinks {
+		broadcaster.StartRecordingToSink(sink)
 	}
 
 	return broadcaster
@@ -98,11 +138,25 @@ func (b *eventBroadcaster) StartRecordingToSink(sink EventSink) watch.Interface
 		broadcaster: b,
 	}
 
+	correlatorOptions := b.options.Correlator
+	if correlatorOptions.Clock == nil {
+		correlatorOptions.Clock = b.options.Clock
+	}
+
 	registration := sinkRegistration{
-		id:      id,
-		sink:    sink,
-		stopCh:  stopCh,
-		watcher: watcher,
+		id:         id,
+		sink:       sink,
+		correlator: NewEventCorrelator(correlatorOptions),
+		queue:      make(chan *corev1.Event, b.options.QueueSize),
+		stopCh:     stopCh,
+		done:       make(chan struct{}),
+		watcher:    watcher,
+	}
+
+	// A stopped broadcaster does not deliver events anymore
+	if b.stopping {
+		close(registration.done)
+		return watcher
 	}
 
 	b.sinks[id] = registration
@@ -113,6 +167,13 @@ func (b *eventBroadcaster) StartRecordingToSink(sink EventSink) watch.Interface
 		b.start()
 	}
 
+	go b.runSink(registration)
+
+	if store, ok := sink.(EventStore); ok && b.options.Retention.Enabled {
+		b.retention.Add(1)
+		go b.runRetention(registration, store)
+	}
+
 	return watcher
 }
 
@@ -139,6 +200,12 @@ func (b *eventBroadcaster) StartEventWatcher(eventHandler func(*corev1.Event)) w
 		watcher: watcher,
 	}
 
+	// A stopped broadcaster does not deliver events anymore
+	if b.stopping {
+		close(stopCh)
+		return watcher
+	}
+
 	b.watchers[id] = registration
 	atomic.AddInt32(&b.metrics.WatchersActive, 1)
 
@@ -163,14 +230,85 @@ func (b *eventBroadcaster) NewRecorder(scheme *runtime.Scheme, source corev1.Eve
 	return NewEventRecorder(b, options)
 }
 
-// Shutdown gracefully shuts down the broadcaster and all its watchers
-func (b *eventBroadcaster) Shutdown() {
+// NewEventsRecorder returns an EventsRecorder that records to this broadcaster
+func (b *eventBroadcaster) NewEventsRecorder(scheme *runtime.Scheme, source corev1.EventSource) EventsRecorder {
+	options := EventRecorderOptions{
+		Scheme: scheme,
+		Source: source,
+		Clock:  b.options.Clock,
+	}
+	return NewEventsRecorder(b, options)
+}
+
+// Shutdown stops accepting events and blocks until the queued events have
+// been written to the sinks or the context is done. Events that could not be
+// written before the context is done are counted as dropped.
+func (b *eventBroadcaster) Shutdown(ctx context.Context) error {
 	b.mu.Lock()
-	defer b.mu.Unlock()
+	if b.stopping {
+		b.mu.Unlock()
+		return nil
+	}
+	b.stopping = true
+	started := b.started
+	b.mu.Unlock()
+
+	if started {
+		// No new events are queued once the in-flight events are queued, so
+		// the distribution loop can drain the queue and stop
+		b.inflight.Wait()
+		close(b.eventChan)
+
+		if err := b.waitForSinks(ctx); err != nil {
+			b.stop()
+			b.retention.Wait()
+
+			// The events left in the queues are not written anymore
+			b.mu.RLock()
+			for _, sink := range b.sinks {
+				atomic.AddInt64(&b.metrics.EventsDropped, int64(len(sink.queue)))
+			}
+			b.mu.RUnlock()
+			return err
+		}
+	}
 
-	if !b.started {
-		return
+	b.stop()
+	b.retention.Wait()
+	return nil
+}
+
+// waitForSinks waits until the distribution loop and all sink workers are done
+func (b *eventBroadcaster) waitForSinks(ctx context.Context) error {
+	select {
+	case <-b.drained:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+
+	b.mu.RLock()
+	sinks := make([]sinkRegistration, 0, len(b.sinks))
+	for _, sink := range b.sinks {
+		sinks = append(sinks, sink)
+	}
+	b.mu.RUnlock()
+
+	for _, sink := range sinks {
+		select {
+		case <-sink.done:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
 	}
+	return nil
+}
+
+// stop aborts pending deliveries and stops all sinks and watchers
+func (b *eventBroadcaster) stop() {
+	b.cancel()
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
 
 	// Stop all sinks
 	for _, sink := range b.sinks {
@@ -192,8 +330,6 @@ func (b *eventBroadcaster) Shutdown() {
 		}
 	}
 
-	// Stop the broadcaster
-	b.cancel()
 	select {
 	case <-b.stopCh:
 		// Already closed
@@ -216,21 +352,24 @@ func (b *eventBroadcaster) start() {
 	go b.run()
 }
 
-// run is the main event distribution loop
+// run is the main event distribution loop. It returns when the event queue
+// is closed by Shutdown and closes the queues of the sinks.
 func (b *eventBroadcaster) run() {
-	for {
-		select {
-		case event := <-b.eventChan:
-			b.distributeEvent(event)
-		case <-b.stopCh:
-			return
-		case <-b.ctx.Done():
-			return
-		}
+	defer close(b.drained)
+
+	for event := range b.eventChan {
+		b.distributeEvent(event)
+	}
+
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	for _, sink := range b.sinks {
+		close(sink.queue)
 	}
 }
 
-// distributeEvent sends an event to all registered sinks and watchers
+// distributeEvent queues an event for all registered sinks and sends it to
+// all watchers
 func (b *eventBroadcaster) distributeEvent(event *corev1.Event) {
 	b.mu.RLock()
 	sinks := make([]sinkRegistration, 0, len(b.sinks))
@@ -244,9 +383,20 @@ func (b *eventBroadcaster) distributeEvent(event *corev1.Event) {
 	}
 	b.mu.RUnlock()
 
-	// Send to sinks
+	// Queue for sinks. The event is dropped for sinks whose queue is full,
+	// e.g. because their writes are retried, so a failing sink does not
+	// delay the others.
+	if b.ctx.Err() != nil {
+		atomic.AddInt64(&b.metrics.EventsDropped, int64(len(sinks)))
+		sinks = nil
+	}
 	for _, sink := range sinks {
-		go b.sendToSink(sink, event)
+		select {
+		case sink.queue <- event:
+		case <-sink.stopCh:
+		default:
+			atomic.AddInt64(&b.metrics.EventsDropped, 1)
+		}
 	}
 
 	// Send to watchers
@@ -255,25 +405,82 @@ func (b *eventBroadcaster) distributeEvent(event *corev1.Event) {
 	}
 }
 
-// sendToSink sends an event to a specific sink
+// runSink writes the queued events of a sink in order and closes the sink
+// when it is stopped
+func (b *eventBroadcaster) runSink(registration sinkRegistration) {
+	defer close(registration.done)
+	if closer, ok := registration.sink.(io.Closer); ok {
+		defer func() { _ = closer.Close() }()
+	}
+
+	for {
+		select {
+		case event, ok := <-registration.queue:
+			if !ok {
+				return
+			}
+			b.sendToSink(registration, event)
+		case <-registration.stopCh:
+			return
+		}
+	}
+}
+
+// sendToSink correlates an event and writes it to a specific sink, retrying
+// failed writes with an exponential backoff
 func (b *eventBroadcaster) sendToSink(registration sinkRegistration, event *corev1.Event) {
-	select {
-	case <-registration.stopCh:
+	result, err := registration.correlator.EventCorrelate(event)
+	if err != nil || result.Skip {
+		atomic.AddInt64(&b.metrics.EventsDropped, 1)
 		return
-	default:
 	}
 
-	// Try to create or update the event in the sink
-	_, err := registration.sink.Create(event)
-	if err != nil {
-		// If create failed, try update (for event aggregation)
-		_, err = registration.sink.Update(event)
-		if err != nil {
+	backoff := b.options.RetryBackoff
+	for attempt := 0; ; attempt++ {
+		written, err := writeToSink(registration.sink, result)
+		if err == nil {
+			registration.correlator.UpdateState(written)
+			atomic.AddInt64(&b.metrics.EventsRecorded, 1)
+			return
+		}
+
+		if attempt >= b.options.MaxRetries {
 			atomic.AddInt64(&b.metrics.EventsDropped, 1)
+			return
 		}
+
+		atomic.AddInt64(&b.metrics.EventsRetried, 1)
+		select {
+		case <-time.After(backoff):
+		case <-registration.stopCh:
+			atomic.AddInt64(&b.metrics.EventsDropped, 1)
+			return
+		case <-b.ctx.Done():
+			atomic.AddInt64(&b.metrics.EventsDropped, 1)
+			return
+		}
+		backoff = min(2*backoff, maxEventRetryBackoff)
 	}
 }
 
+// writeToSink creates a new event or updates the existing event of a
+// correlated event
+func writeToSink(sink EventSink, result *EventCorrelateResult) (*corev1.Event, error) {
+	event := result.Event
+	if event.Count > 1 && result.Patch != nil {
+		written, err := sink.Patch(event, result.Patch)
+		if !apierrors.IsNotFound(err) {
+			return written, err
+		}
+		// The existing event is gone, e.g. it was never written or has been
+		// deleted, so create it again with the aggregated count
+		event = event.DeepCopy()
+		event.ResourceVersion = ""
+	}
+
+	return sink.Create(event)
+}
+
 // sendToWatcher sends an event to a specific watcher
 func (b *eventBroadcaster) sendToWatcher(registration watcherRegistration, event *corev1.Event) {
 	select {
@@ -298,13 +505,24 @@ func (b *eventBroadcaster) runWatcher(registration watcherRegistration) {
 	<-registration.stopCh
 }
 
-// recordEvent is called by the EventRecorder to send events to the broadcaster
+// recordEvent is called by the EventRecorder to send events to the
+// broadcaster. It never blocks: events are dropped when the queue is full
+// and once the broadcaster is stopping.
 func (b *eventBroadcaster) recordEvent(event *corev1.Event) {
+	b.mu.RLock()
+	if b.stopping {
+		b.mu.RUnlock()
+		atomic.AddInt64(&b.metrics.EventsDropped, 1)
+		return
+	}
+	b.inflight.Add(1)
+	b.mu.RUnlock()
+	defer b.inflight.Done()
+
 	select {
 	case b.eventChan <- event:
 		// Event queued successfully
 	default:
-		// Queue is full, drop the event
 		atomic.AddInt64(&b.metrics.EventsDropped, 1)
 	}
 }
@@ -314,6 +532,10 @@ func (b *eventBroadcaster) GetMetrics() EventMetrics {
 	return EventMetrics{
 		EventsRecorded: atomic.LoadInt64(&b.metrics.EventsRecorded),
 		EventsDropped:  atomic.LoadInt64(&b.metrics.EventsDropped),
+		EventsRetried:  atomic.LoadInt64(&b.metrics.EventsRetried),
+		EventsPruned:   atomic.LoadInt64(&b.metrics.EventsPruned),
+		PruneRuns:      atomic.LoadInt64(&b.metrics.PruneRuns),
+		PruneErrors:    atomic.LoadInt64(&b.metrics.PruneErrors),
 		SinksActive:    atomic.LoadInt32(&b.metrics.SinksActive),
 		WatchersActive: atomic.LoadInt32(&b.metrics.WatchersActive),
 	}
@@ -321,13 +543,19 @@ func (b *eventBroadcaster) GetMetrics() EventMetrics {
 
 // sinkWatcher implementation of watch.Interface
 
-// Stop stops the sink watcher
+// Stop stops the sink watcher. Events that are still queued for the sink are
+// not written.
 func (w *sinkWatcher) Stop() {
 	w.broadcaster.mu.Lock()
 	defer w.broadcaster.mu.Unlock()
 
 	if registration, exists := w.broadcaster.sinks[w.id]; exists {
-		close(registration.stopCh)
+		select {
+		case <-registration.stopCh:
+			// Already closed by Shutdown
+		default:
+			close(registration.stopCh)
+		}
 		delete(w.broadcaster.sinks, w.id)
 		atomic.AddInt32(&w.broadcaster.metrics.SinksActive, -1)
 	}
@@ -346,7 +574,12 @@ func (w *eventWatcher) Stop() {
 	defer w.broadcaster.mu.Unlock()
 
 	if registration, exists := w.broadcaster.watchers[w.id]; exists {
-		close(registration.stopCh)
+		select {
+		case <-registration.stopCh:
+			// Already closed
+		default:
+			close(registration.stopCh)
+		}
 		// The actual cleanup happens in runWatcher goroutine
 	}
 }
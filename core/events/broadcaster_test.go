@@ -0,0 +1,216 @@
+package events_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/dtomasi/k1s/core/events"
+)
+
+// metricsProvider is implemented by the event broadcaster
+type metricsProvider interface {
+	GetMetrics() events.EventMetrics
+}
+
+var _ = Describe("Event delivery", func() {
+	var (
+		object *corev1.ConfigMap
+		sink   *threadSafeMockEventSink
+	)
+
+	// newBroadcaster returns a broadcaster recording to the sink, with a spam
+	// filter that does not limit the events of the tests
+	newBroadcaster := func(options events.EventBroadcasterOptions) (events.EventBroadcaster, events.EventRecorder) {
+		options.Correlator.BurstSize = 10000
+		broadcaster := events.NewEventBroadcaster(options)
+		broadcaster.StartRecordingToSink(sink)
+		return broadcaster, broadcaster.NewRecorder(newTestScheme(), events.NewEventSource("test-component"))
+	}
+
+	metrics := func(broadcaster events.EventBroadcaster) func() events.EventMetrics {
+		return func() events.EventMetrics {
+			return broadcaster.(metricsProvider).GetMetrics()
+		}
+	}
+
+	messages := func() []string {
+		var result []string
+		for _, event := range sink.GetEvents() {
+			result = append(result, event.Message)
+		}
+		return result
+	}
+
+	BeforeEach(func() {
+		object = &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "config", Namespace: "default"}}
+		sink = newMockEventSink()
+	})
+
+	It("should write the events to a sink in order", func() {
+		broadcaster, recorder := newBroadcaster(events.EventBroadcasterOptions{})
+		defer func() { Expect(broadcaster.Shutdown(context.Background())).To(Succeed()) }()
+
+		var expected []string
+		for i := 0; i < 100; i++ {
+			message := fmt.Sprintf("event %d", i)
+			expected = append(expected, message)
+			recorder.Event(object, corev1.EventTypeNormal, fmt.Sprintf("Reason%d", i), message)
+		}
+
+		Eventually(messages).Should(Equal(expected))
+		Expect(metrics(broadcaster)().EventsDropped).To(BeZero())
+	})
+
+	It("should retry failed writes", func() {
+		var attempts int32
+		sink.createFunc = func(event *corev1.Event) (*corev1.Event, error) {
+			if atomic.AddInt32(&attempts, 1) <= 2 {
+				return nil, errors.New("storage is locked")
+			}
+			return event, nil
+		}
+		broadcaster, recorder := newBroadcaster(events.EventBroadcasterOptions{RetryBackoff: time.Millisecond})
+		defer func() { Expect(broadcaster.Shutdown(context.Background())).To(Succeed()) }()
+
+		recorder.Event(object, corev1.EventTypeWarning, events.ReasonFailed, "reconcile failed")
+
+		Eventually(metrics(broadcaster)).Should(And(
+			HaveField("EventsRecorded", int64(1)),
+			HaveField("EventsRetried", int64(2)),
+			HaveField("EventsDropped", int64(0)),
+		))
+	})
+
+	It("should drop events after the maximum number of retries", func() {
+		sink.createFunc = func(*corev1.Event) (*corev1.Event, error) {
+			return nil, errors.New("storage is read-only")
+		}
+		broadcaster, recorder := newBroadcaster(events.EventBroadcasterOptions{
+			MaxRetries:   3,
+			RetryBackoff: time.Millisecond,
+		})
+		defer func() { Expect(broadcaster.Shutdown(context.Background())).To(Succeed()) }()
+
+		recorder.Event(object, corev1.EventTypeWarning, events.ReasonFailed, "reconcile failed")
+
+		Eventually(metrics(broadcaster)).Should(And(
+			HaveField("EventsRetried", int64(3)),
+			HaveField("EventsDropped", int64(1)),
+		))
+		Expect(sink.GetEventCount()).To(Equal(4))
+	})
+
+	It("should cap the retries of failed writes", func() {
+		sink.createFunc = func(*corev1.Event) (*corev1.Event, error) {
+			return nil, errors.New("storage is read-only")
+		}
+		broadcaster, recorder := newBroadcaster(events.EventBroadcasterOptions{
+			MaxRetries:   -1,
+			RetryBackoff: time.Millisecond,
+		})
+		defer func() { Expect(broadcaster.Shutdown(context.Background())).To(Succeed()) }()
+
+		recorder.Event(object, corev1.EventTypeWarning, events.ReasonFailed, "reconcile failed")
+
+		Eventually(metrics(broadcaster), 10*time.Second).Should(And(
+			HaveField("EventsRetried", int64(events.MaxEventRetries)),
+			HaveField("EventsDropped", int64(1)),
+		))
+	})
+
+	It("should drop events instead of blocking when the queue is full", func() {
+		release := make(chan struct{})
+		sink.createFunc = func(event *corev1.Event) (*corev1.Event, error) {
+			<-release
+			return event, nil
+		}
+		broadcaster, recorder := newBroadcaster(events.EventBroadcasterOptions{QueueSize: 1})
+		defer func() { Expect(broadcaster.Shutdown(context.Background())).To(Succeed()) }()
+
+		recorded := make(chan struct{})
+		go func() {
+			defer close(recorded)
+			for i := 0; i < 100; i++ {
+				recorder.Event(object, corev1.EventTypeNormal, fmt.Sprintf("Reason%d", i), "message")
+			}
+		}()
+		Eventually(recorded).Should(BeClosed())
+		close(release)
+
+		Eventually(metrics(broadcaster)).Should(HaveField("EventsDropped", BeNumerically(">", 0)))
+	})
+
+	It("should write the queued events on shutdown", func() {
+		sink.createFunc = func(event *corev1.Event) (*corev1.Event, error) {
+			time.Sleep(time.Millisecond)
+			return event, nil
+		}
+		broadcaster, recorder := newBroadcaster(events.EventBroadcasterOptions{})
+
+		for i := 0; i < 50; i++ {
+			recorder.Event(object, corev1.EventTypeNormal, fmt.Sprintf("Reason%d", i), "message")
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		Expect(broadcaster.Shutdown(ctx)).To(Succeed())
+		Expect(sink.GetEventCount()).To(Equal(50))
+		Expect(metrics(broadcaster)().EventsRecorded).To(Equal(int64(50)))
+	})
+
+	It("should give up on shutdown when the deadline passes", func() {
+		release := make(chan struct{})
+		defer close(release)
+		sink.createFunc = func(event *corev1.Event) (*corev1.Event, error) {
+			<-release
+			return event, nil
+		}
+		broadcaster, recorder := newBroadcaster(events.EventBroadcasterOptions{})
+
+		for i := 0; i < 3; i++ {
+			recorder.Event(object, corev1.EventTypeNormal, fmt.Sprintf("Reason%d", i), "message")
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+		Expect(broadcaster.Shutdown(ctx)).To(MatchError(context.DeadlineExceeded))
+		Eventually(metrics(broadcaster)).Should(HaveField("EventsDropped", BeNumerically(">=", 2)))
+
+		// Events recorded after shutdown are dropped
+		recorder.Event(object, corev1.EventTypeNormal, "Late", "message")
+		Expect(broadcaster.Shutdown(context.Background())).To(Succeed())
+	})
+
+	It("should record to the sinks of the options and close them on shutdown", func() {
+		closing := &closingSink{threadSafeMockEventSink: newMockEventSink()}
+		broadcaster := events.NewEventBroadcaster(events.EventBroadcasterOptions{
+			Sinks: []events.EventSink{closing},
+		})
+		recorder := broadcaster.NewRecorder(newTestScheme(), events.NewEventSource("test-component"))
+
+		recorder.Event(object, corev1.EventTypeNormal, events.ReasonCreated, "created")
+
+		Expect(broadcaster.Shutdown(context.Background())).To(Succeed())
+		Expect(closing.GetEventCount()).To(Equal(1))
+		Expect(closing.closed.Load()).To(BeTrue())
+	})
+})
+
+// closingSink is a sink that records whether it has been closed
+type closingSink struct {
+	*threadSafeMockEventSink
+	closed atomic.Bool
+}
+
+func (s *closingSink) Close() error {
+	s.closed.Store(true)
+	return nil
+}
@@ -0,0 +1,351 @@
+package events
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/strategicpatch"
+	"k8s.io/utils/lru"
+)
+
+// EventCorrelator correlates recorded events before they are written to a
+// sink, following the semantics of the client-go EventCorrelator:
+//
+//   - events that only differ in their message are aggregated into a single
+//     "(combined from similar events)" event once MaxEvents different
+//     messages were seen for the same source, object, type and reason
+//   - identical events are deduplicated into the first event by incrementing
+//     its count
+//   - a token bucket per source and object limits the number of new events
+//
+// Unlike client-go, the spam filter only drops events that would create a new
+// Event object. Count updates of existing events are always passed on, so an
+// event that occurred 500 times is stored with a count of 500.
+type EventCorrelator struct {
+	aggregator *eventAggregator
+	logger     *eventLogger
+	filter     *spamFilter
+}
+
+// EventCorrelateResult is the result of correlating an event
+type EventCorrelateResult struct {
+	// Event is the event to write to the sink. An event with a count greater
+	// than one updates the existing event of the same name.
+	Event *corev1.Event
+
+	// Patch is a strategic merge patch of the count, lastTimestamp and message
+	// of the existing event
+	Patch []byte
+
+	// Skip is true if the event was dropped by the spam filter
+	Skip bool
+}
+
+// NewEventCorrelator creates a new EventCorrelator
+func NewEventCorrelator(options EventCorrelatorOptions) *EventCorrelator {
+	if options.MaxEvents <= 0 {
+		options.MaxEvents = DefaultAggregateMaxEvents
+	}
+	if options.MaxInterval <= 0 {
+		options.MaxInterval = DefaultAggregateMaxInterval
+	}
+	if options.BurstSize <= 0 {
+		options.BurstSize = DefaultSpamBurst
+	}
+	if options.QPS <= 0 {
+		options.QPS = DefaultSpamQPS
+	}
+	if options.CacheSize <= 0 {
+		options.CacheSize = DefaultCorrelatorCacheSize
+	}
+	if options.Clock == nil {
+		options.Clock = RealClock{}
+	}
+
+	return &EventCorrelator{
+		aggregator: &eventAggregator{
+			cache:       lru.New(options.CacheSize),
+			maxEvents:   options.MaxEvents,
+			maxInterval: options.MaxInterval,
+			clock:       options.Clock,
+		},
+		logger: &eventLogger{
+			cache: lru.New(options.CacheSize),
+		},
+		filter: &spamFilter{
+			cache: lru.New(options.CacheSize),
+			burst: options.BurstSize,
+			qps:   options.QPS,
+			clock: options.Clock,
+		},
+	}
+}
+
+// EventCorrelate correlates the event with the events seen before
+func (c *EventCorrelator) EventCorrelate(newEvent *corev1.Event) (*EventCorrelateResult, error) {
+	if newEvent == nil {
+		return nil, fmt.Errorf("event is nil")
+	}
+
+	aggregated, key := c.aggregator.aggregate(newEvent)
+	observed, patch, err := c.logger.observe(aggregated, key)
+	if observed.Count <= 1 && !c.filter.allow(observed) {
+		return &EventCorrelateResult{Skip: true}, nil
+	}
+	return &EventCorrelateResult{Event: observed, Patch: patch}, err
+}
+
+// UpdateState records the event as written by a sink, so that later updates
+// refer to its name and resourceVersion
+func (c *EventCorrelator) UpdateState(event *corev1.Event) {
+	c.logger.update(getEventKey(event), event)
+}
+
+// getEventKey returns the key of identical events
+func getEventKey(event *corev1.Event) string {
+	return strings.Join([]string{
+		event.Source.Component,
+		event.Source.Host,
+		event.InvolvedObject.Kind,
+		event.InvolvedObject.Namespace,
+		event.InvolvedObject.Name,
+		event.InvolvedObject.FieldPath,
+		string(event.InvolvedObject.UID),
+		event.InvolvedObject.APIVersion,
+		event.Type,
+		event.Reason,
+		event.Message,
+	}, "")
+}
+
+// getSpamKey returns the key of the events of a source about an object
+func getSpamKey(event *corev1.Event) string {
+	return strings.Join([]string{
+		event.Source.Component,
+		event.Source.Host,
+		event.InvolvedObject.Kind,
+		event.InvolvedObject.Namespace,
+		event.InvolvedObject.Name,
+		string(event.InvolvedObject.UID),
+		event.InvolvedObject.APIVersion,
+		event.Type,
+	}, "")
+}
+
+// getAggregateKey returns the key of similar events and the message that
+// distinguishes them
+func getAggregateKey(event *corev1.Event) (string, string) {
+	return strings.Join([]string{
+		event.Source.Component,
+		event.Source.Host,
+		event.InvolvedObject.Kind,
+		event.InvolvedObject.Namespace,
+		event.InvolvedObject.Name,
+		string(event.InvolvedObject.UID),
+		event.InvolvedObject.APIVersion,
+		event.Type,
+		event.Reason,
+		event.ReportingController,
+		event.ReportingInstance,
+	}, ""), event.Message
+}
+
+// eventAggregator aggregates similar events with different messages
+type eventAggregator struct {
+	mu          sync.Mutex
+	cache       *lru.Cache
+	maxEvents   int
+	maxInterval time.Duration
+	clock       Clock
+}
+
+// aggregateRecord holds the messages of similar events
+type aggregateRecord struct {
+	localKeys     sets.Set[string]
+	lastTimestamp metav1.Time
+}
+
+// aggregate returns the event to observe and its key. Once maxEvents similar
+// events were seen within maxInterval, it returns an aggregated event.
+func (a *eventAggregator) aggregate(newEvent *corev1.Event) (*corev1.Event, string) {
+	now := a.clock.Now()
+	aggregateKey, localKey := getAggregateKey(newEvent)
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	var record aggregateRecord
+	if value, found := a.cache.Get(aggregateKey); found {
+		record = value.(aggregateRecord)
+	}
+
+	// Restart the aggregation if the similar events are too old
+	if record.localKeys == nil || now.Sub(record.lastTimestamp.Time) > a.maxInterval {
+		record = aggregateRecord{localKeys: sets.New[string]()}
+	}
+
+	record.localKeys.Insert(localKey)
+	record.lastTimestamp = now
+	a.cache.Add(aggregateKey, record)
+
+	if record.localKeys.Len() < a.maxEvents {
+		return newEvent, getEventKey(newEvent)
+	}
+
+	// Keep the number of messages bounded
+	record.localKeys.Delete(localKey)
+
+	var eventTime metav1.MicroTime
+	if !newEvent.EventTime.IsZero() {
+		eventTime = metav1.NewMicroTime(now.Time)
+	}
+
+	return &corev1.Event{
+		TypeMeta: newEvent.TypeMeta,
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      fmt.Sprintf("%s.%x", newEvent.InvolvedObject.Name, now.UnixNano()),
+			Namespace: newEvent.Namespace,
+		},
+		InvolvedObject:      newEvent.InvolvedObject,
+		Reason:              newEvent.Reason,
+		Message:             "(combined from similar events): " + newEvent.Message,
+		Type:                newEvent.Type,
+		FirstTimestamp:      now,
+		LastTimestamp:       now,
+		Count:               1,
+		Source:              newEvent.Source,
+		ReportingController: newEvent.ReportingController,
+		ReportingInstance:   newEvent.ReportingInstance,
+		EventTime:           eventTime,
+		Action:              newEvent.Action,
+		Related:             newEvent.Related,
+	}, aggregateKey
+}
+
+// eventLog is the last observation of an event
+type eventLog struct {
+	count           int32
+	firstTimestamp  metav1.Time
+	eventTime       metav1.MicroTime
+	name            string
+	resourceVersion string
+}
+
+// eventLogger deduplicates identical events
+type eventLogger struct {
+	mu    sync.Mutex
+	cache *lru.Cache
+}
+
+// observe returns the event to write. An event that was observed before
+// refers to the existing event and increments its count.
+func (l *eventLogger) observe(newEvent *corev1.Event, key string) (*corev1.Event, []byte, error) {
+	event := newEvent.DeepCopy()
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	var (
+		patch []byte
+		err   error
+	)
+	if value, found := l.cache.Get(key); found {
+		last := value.(eventLog)
+		event.Name = last.name
+		event.ResourceVersion = last.resourceVersion
+		event.FirstTimestamp = last.firstTimestamp
+		event.Count = last.count + 1
+
+		// Events of the events.k8s.io API record repetitions in a series
+		if !event.EventTime.IsZero() {
+			event.EventTime = last.eventTime
+			event.Series = &corev1.EventSeries{
+				Count:            event.Count,
+				LastObservedTime: metav1.NewMicroTime(event.LastTimestamp.Time),
+			}
+		}
+
+		// The patch only updates the fields that change between observations
+		original := event.DeepCopy()
+		original.Count = 0
+		original.LastTimestamp = metav1.NewTime(time.Unix(0, 0))
+		original.Message = ""
+		original.Series = nil
+
+		var newData, oldData []byte
+		if newData, err = json.Marshal(event); err == nil {
+			if oldData, err = json.Marshal(original); err == nil {
+				patch, err = strategicpatch.CreateTwoWayMergePatch(oldData, newData, event)
+			}
+		}
+	}
+
+	l.cache.Add(key, eventLog{
+		count:           event.Count,
+		firstTimestamp:  event.FirstTimestamp,
+		eventTime:       event.EventTime,
+		name:            event.Name,
+		resourceVersion: event.ResourceVersion,
+	})
+	return event, patch, err
+}
+
+// update records the event as written by a sink
+func (l *eventLogger) update(key string, event *corev1.Event) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.cache.Add(key, eventLog{
+		count:           event.Count,
+		firstTimestamp:  event.FirstTimestamp,
+		eventTime:       event.EventTime,
+		name:            event.Name,
+		resourceVersion: event.ResourceVersion,
+	})
+}
+
+// spamFilter limits the events of a source about an object with a token bucket
+type spamFilter struct {
+	mu    sync.Mutex
+	cache *lru.Cache
+	burst int
+	qps   float64
+	clock Clock
+}
+
+// tokenBucket is a passive token bucket that refills on access
+type tokenBucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// allow returns true if the event may be written
+func (f *spamFilter) allow(event *corev1.Event) bool {
+	now := f.clock.Now().Time
+	key := getSpamKey(event)
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	bucket := &tokenBucket{tokens: float64(f.burst), last: now}
+	if value, found := f.cache.Get(key); found {
+		bucket = value.(*tokenBucket)
+		if elapsed := now.Sub(bucket.last); elapsed > 0 {
+			bucket.tokens = min(float64(f.burst), bucket.tokens+elapsed.Seconds()*f.qps)
+			bucket.last = now
+		}
+	}
+	f.cache.Add(key, bucket)
+
+	if bucket.tokens < 1 {
+		return false
+	}
+	bucket.tokens--
+	return true
+}
@@ -0,0 +1,198 @@
+package events_test
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/strategicpatch"
+
+	"github.com/dtomasi/k1s/core/events"
+	v1 "github.com/dtomasi/k1s/core/types/v1"
+)
+
+var _ = Describe("EventCorrelator", func() {
+	var (
+		clock      *mockClock
+		correlator *events.EventCorrelator
+	)
+
+	// newEvent returns an event about a ConfigMap as the recorder creates it
+	newEvent := func(name, reason, message string) *corev1.Event {
+		return &corev1.Event{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+			InvolvedObject: corev1.ObjectReference{
+				Kind: "ConfigMap", APIVersion: "v1", Namespace: "default", Name: "config", UID: "uid",
+			},
+			Reason:         reason,
+			Message:        message,
+			Type:           corev1.EventTypeWarning,
+			Count:          1,
+			FirstTimestamp: clock.Now(),
+			LastTimestamp:  clock.Now(),
+			Source:         events.NewEventSource("test-component"),
+		}
+	}
+
+	correlate := func(event *corev1.Event) *events.EventCorrelateResult {
+		result, err := correlator.EventCorrelate(event)
+		Expect(err).NotTo(HaveOccurred())
+		return result
+	}
+
+	BeforeEach(func() {
+		clock = &mockClock{now: metav1.NewTime(time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC))}
+		correlator = events.NewEventCorrelator(events.EventCorrelatorOptions{Clock: clock})
+	})
+
+	It("should reject nil events", func() {
+		_, err := correlator.EventCorrelate(nil)
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should deduplicate identical events into the first event", func() {
+		first := correlate(newEvent("config.1", events.ReasonFailed, "reconcile failed"))
+		Expect(first.Skip).To(BeFalse())
+		Expect(first.Event.Name).To(Equal("config.1"))
+		Expect(first.Event.Count).To(Equal(int32(1)))
+		Expect(first.Patch).To(BeNil())
+
+		firstTimestamp := first.Event.FirstTimestamp
+		var last *events.EventCorrelateResult
+		for i := 2; i <= 500; i++ {
+			clock.now = metav1.NewTime(clock.now.Add(time.Second))
+			last = correlate(newEvent(fmt.Sprintf("config.%d", i), events.ReasonFailed, "reconcile failed"))
+			Expect(last.Skip).To(BeFalse())
+		}
+
+		Expect(last.Event.Name).To(Equal("config.1"))
+		Expect(last.Event.Count).To(Equal(int32(500)))
+		Expect(last.Event.FirstTimestamp).To(Equal(firstTimestamp))
+		Expect(last.Event.LastTimestamp).To(Equal(clock.now))
+
+		// The patch updates the existing event
+		patched, err := strategicpatch.StrategicMergePatch([]byte(`{"count":1,"message":"reconcile failed"}`), last.Patch, &corev1.Event{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(patched)).To(ContainSubstring(`"count":500`))
+	})
+
+	It("should aggregate similar events with different messages", func() {
+		for i := 0; i < events.DefaultAggregateMaxEvents-1; i++ {
+			result := correlate(newEvent(fmt.Sprintf("config.%d", i), events.ReasonFailed, fmt.Sprintf("attempt %d failed", i)))
+			Expect(result.Event.Message).To(Equal(fmt.Sprintf("attempt %d failed", i)))
+			Expect(result.Event.Count).To(Equal(int32(1)))
+		}
+
+		aggregated := correlate(newEvent("config.a", events.ReasonFailed, "attempt 9 failed"))
+		Expect(aggregated.Event.Message).To(Equal("(combined from similar events): attempt 9 failed"))
+		Expect(aggregated.Event.Count).To(Equal(int32(1)))
+
+		next := correlate(newEvent("config.b", events.ReasonFailed, "attempt 10 failed"))
+		Expect(next.Event.Name).To(Equal(aggregated.Event.Name))
+		Expect(next.Event.Message).To(Equal("(combined from similar events): attempt 10 failed"))
+		Expect(next.Event.Count).To(Equal(int32(2)))
+
+		// Other reasons are aggregated separately
+		other := correlate(newEvent("config.c", events.ReasonTimeout, "attempt 11 timed out"))
+		Expect(other.Event.Message).To(Equal("attempt 11 timed out"))
+	})
+
+	It("should restart the aggregation after the interval", func() {
+		for i := 0; i < events.DefaultAggregateMaxEvents; i++ {
+			correlate(newEvent(fmt.Sprintf("config.%d", i), events.ReasonFailed, fmt.Sprintf("attempt %d failed", i)))
+		}
+
+		clock.now = metav1.NewTime(clock.now.Add(events.DefaultAggregateMaxInterval + time.Second))
+		result := correlate(newEvent("config.new", events.ReasonFailed, "attempt 20 failed"))
+		Expect(result.Event.Message).To(Equal("attempt 20 failed"))
+	})
+
+	It("should limit new events about an object", func() {
+		correlator = events.NewEventCorrelator(events.EventCorrelatorOptions{
+			Clock:     clock,
+			BurstSize: 3,
+			QPS:       1,
+		})
+
+		reasons := []string{"One", "Two", "Three", "Four"}
+		for _, reason := range reasons[:3] {
+			Expect(correlate(newEvent("config."+reason, reason, "message")).Skip).To(BeFalse())
+		}
+		Expect(correlate(newEvent("config.Four", "Four", "message")).Skip).To(BeTrue())
+
+		// Count updates of existing events are not limited
+		result := correlate(newEvent("config.x", "One", "message"))
+		Expect(result.Skip).To(BeFalse())
+		Expect(result.Event.Count).To(Equal(int32(2)))
+
+		// The bucket refills over time
+		clock.now = metav1.NewTime(clock.now.Add(time.Second))
+		Expect(correlate(newEvent("config.Five", "Five", "message")).Skip).To(BeFalse())
+	})
+
+	It("should deduplicate the events written to a sink", func() {
+		sink := newMockEventSink()
+		broadcaster := events.NewEventBroadcaster(events.EventBroadcasterOptions{Clock: clock})
+		defer func() { Expect(broadcaster.Shutdown(context.Background())).To(Succeed()) }()
+		broadcaster.StartRecordingToSink(sink)
+
+		recorder := events.NewEventRecorder(broadcaster, events.EventRecorderOptions{
+			Scheme: newTestScheme(),
+			Source: events.NewEventSource("test-component"),
+			Clock:  clock,
+		})
+		object := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "config", Namespace: "default"}}
+		for i := 0; i < 50; i++ {
+			recorder.Event(object, corev1.EventTypeWarning, events.ReasonFailed, "reconcile failed")
+		}
+
+		Eventually(sink.GetEventCount).Should(Equal(50))
+		recorded := sink.GetEvents()
+		for i, event := range recorded {
+			Expect(event.Name).To(Equal(recorded[0].Name))
+			Expect(event.Count).To(Equal(int32(i + 1)))
+		}
+	})
+
+	It("should record repeated events of the events recorder as a series", func() {
+		sink := newMockEventSink()
+		broadcaster := events.NewEventBroadcaster(events.EventBroadcasterOptions{Clock: clock})
+		defer func() { Expect(broadcaster.Shutdown(context.Background())).To(Succeed()) }()
+		broadcaster.StartRecordingToSink(sink)
+
+		recorder := events.NewEventsRecorder(broadcaster, events.EventRecorderOptions{
+			Scheme: newTestScheme(),
+			Source: events.NewEventSource("test-component"),
+			Clock:  clock,
+		})
+		object := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "config", Namespace: "default"}}
+		related := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "source", Namespace: "default"}}
+		for i := 0; i < 3; i++ {
+			recorder.Eventf(object, related, corev1.EventTypeWarning, events.ReasonFailed, "Sync", "sync %d failed", 1)
+		}
+
+		Eventually(sink.GetEventCount).Should(Equal(3))
+		recorded := sink.GetEvents()
+		Expect(recorded[0].Action).To(Equal("Sync"))
+		Expect(recorded[0].Related).NotTo(BeNil())
+		Expect(recorded[0].Related.Name).To(Equal("source"))
+		Expect(recorded[0].EventTime.IsZero()).To(BeFalse())
+		Expect(recorded[0].Series).To(BeNil())
+		Expect(recorded[2].Series).NotTo(BeNil())
+		Expect(recorded[2].Series.Count).To(Equal(int32(3)))
+	})
+})
+
+// newTestScheme returns a scheme with Events and ConfigMaps
+func newTestScheme() *runtime.Scheme {
+	scheme := runtime.NewScheme()
+	Expect(v1.AddEventToScheme(scheme)).To(Succeed())
+	scheme.AddKnownTypes(schema.GroupVersion{Group: "", Version: "v1"}, &corev1.ConfigMap{}, &corev1.ConfigMapList{})
+	return scheme
+}
@@ -0,0 +1,49 @@
+package events
+
+import (
+	"fmt"
+	"sync/atomic"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// eventsRecorder implements the EventsRecorder interface. Its events carry
+// the fields of the events.k8s.io/v1 API, so that sinks can write them in
+// either API, and repeated events are recorded as a series.
+type eventsRecorder struct {
+	*eventRecorder
+}
+
+// NewEventsRecorder creates a new EventsRecorder instance
+func NewEventsRecorder(broadcaster EventBroadcaster, options EventRecorderOptions) EventsRecorder {
+	return &eventsRecorder{
+		eventRecorder: NewEventRecorder(broadcaster, options).(*eventRecorder),
+	}
+}
+
+// Eventf constructs an event about regarding and records it
+func (r *eventsRecorder) Eventf(regarding runtime.Object, related runtime.Object, eventtype, reason, action, note string, args ...interface{}) {
+	regardingRef, err := CreateObjectReference(r.scheme, regarding)
+	if err != nil {
+		atomic.AddInt64(&r.metrics.EventsDropped, 1)
+		return
+	}
+
+	var relatedRef *corev1.ObjectReference
+	if related != nil {
+		ref, err := CreateObjectReference(r.scheme, related)
+		if err != nil {
+			atomic.AddInt64(&r.metrics.EventsDropped, 1)
+			return
+		}
+		relatedRef = &ref
+	}
+
+	event := r.createEvent(r.namespaceFor(regardingRef), regardingRef, eventtype, reason, fmt.Sprintf(note, args...), nil)
+	event.EventTime = metav1.NewMicroTime(event.FirstTimestamp.Time)
+	event.Action = action
+	event.Related = relatedRef
+	r.send(event)
+}
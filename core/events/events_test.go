@@ -1,6 +1,7 @@
 package events_test
 
 import (
+	"context"
 	"sync"
 	"testing"
 	"time"
@@ -71,7 +72,7 @@ var _ = Describe("Event System", func() {
 
 	AfterEach(func() {
 		if broadcaster != nil {
-			broadcaster.Shutdown()
+			Expect(broadcaster.Shutdown(context.Background())).To(Succeed())
 		}
 	})
 
@@ -212,7 +213,7 @@ var _ = Describe("Event System", func() {
 			}, "100ms", "10ms").Should(Equal(1))
 
 			// Shutdown broadcaster
-			broadcaster.Shutdown()
+			Expect(broadcaster.Shutdown(context.Background())).To(Succeed())
 
 			// Try to record another event (should not be processed)
 			recorder.Event(testObject, corev1.EventTypeNormal, events.ReasonUpdated, "After shutdown")
@@ -64,7 +64,13 @@ func (r *eventRecorder) recordEvent(object runtime.Object, eventtype, reason, me
 		return
 	}
 
-	// Determine event namespace
+	// Create the event
+	event := r.createEvent(r.namespaceFor(objRef), objRef, eventtype, reason, message, annotations)
+	r.send(event)
+}
+
+// namespaceFor returns the namespace of the events about the referenced object
+func (r *eventRecorder) namespaceFor(objRef corev1.ObjectReference) string {
 	eventNamespace := r.eventNamespace
 	if eventNamespace == "" {
 		eventNamespace = objRef.Namespace
@@ -72,11 +78,11 @@ func (r *eventRecorder) recordEvent(object runtime.Object, eventtype, reason, me
 	if eventNamespace == "" {
 		eventNamespace = metav1.NamespaceDefault
 	}
+	return eventNamespace
+}
 
-	// Create the event
-	event := r.createEvent(eventNamespace, objRef, eventtype, reason, message, annotations)
-
-	// Send to broadcaster
+// send sends a created event to the broadcaster
+func (r *eventRecorder) send(event *corev1.Event) {
 	if r.broadcaster != nil {
 		r.sendEventToBroadcaster(event)
 	}
@@ -0,0 +1,132 @@
+package events
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync/atomic"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+)
+
+// PruneEvents deletes the stored events that violate the retention policy:
+// events older than the maximum age, and the oldest events of objects and
+// namespaces with more events than allowed. It returns the number of deleted
+// events.
+func PruneEvents(ctx context.Context, store EventStore, options EventRetentionOptions, now time.Time) (int, error) {
+	if options.MaxAge <= 0 {
+		options.MaxAge = DefaultEventRetentionMaxAge
+	}
+
+	stored, err := store.ListEvents(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to list events: %w", err)
+	}
+
+	pruned := 0
+	var errs []error
+	for _, event := range selectEventsToPrune(stored, options, now) {
+		if err := store.DeleteEvent(ctx, event); err != nil && !apierrors.IsNotFound(err) {
+			errs = append(errs, fmt.Errorf("failed to delete event %s/%s: %w", event.Namespace, event.Name, err))
+			continue
+		}
+		pruned++
+	}
+	return pruned, errors.Join(errs...)
+}
+
+// selectEventsToPrune returns the events that violate the retention policy
+func selectEventsToPrune(stored []corev1.Event, options EventRetentionOptions, now time.Time) []*corev1.Event {
+	// Newest events first, so that the events beyond a cap are the oldest
+	events := make([]*corev1.Event, len(stored))
+	for i := range stored {
+		events[i] = &stored[i]
+	}
+	sort.SliceStable(events, func(i, j int) bool {
+		return lastObserved(events[i]).After(lastObserved(events[j]))
+	})
+
+	var pruned []*corev1.Event
+	perObject := make(map[string]int)
+	perNamespace := make(map[string]int)
+	for _, event := range events {
+		if now.Sub(lastObserved(event)) > options.MaxAge {
+			pruned = append(pruned, event)
+			continue
+		}
+
+		object := involvedObjectKey(event.InvolvedObject)
+		if options.MaxEventsPerObject > 0 && perObject[object] >= options.MaxEventsPerObject {
+			pruned = append(pruned, event)
+			continue
+		}
+		if options.MaxEventsPerNamespace > 0 && perNamespace[event.Namespace] >= options.MaxEventsPerNamespace {
+			pruned = append(pruned, event)
+			continue
+		}
+		perObject[object]++
+		perNamespace[event.Namespace]++
+	}
+	return pruned
+}
+
+// lastObserved returns the time an event was last observed
+func lastObserved(event *corev1.Event) time.Time {
+	switch {
+	case event.Series != nil && !event.Series.LastObservedTime.IsZero():
+		return event.Series.LastObservedTime.Time
+	case !event.LastTimestamp.IsZero():
+		return event.LastTimestamp.Time
+	case !event.EventTime.IsZero():
+		return event.EventTime.Time
+	case !event.FirstTimestamp.IsZero():
+		return event.FirstTimestamp.Time
+	default:
+		return event.CreationTimestamp.Time
+	}
+}
+
+// involvedObjectKey identifies the object of an event by its type, namespace
+// and name rather than its UID, so objects of different kinds never share
+// the events allowed per object.
+func involvedObjectKey(ref corev1.ObjectReference) string {
+	return ref.APIVersion + "/" + ref.Kind + "/" + ref.Namespace + "/" + ref.Name
+}
+
+// runRetention prunes the events of a sink when it is started and then at
+// the configured interval until the sink or the broadcaster is stopped
+func (b *eventBroadcaster) runRetention(registration sinkRegistration, store EventStore) {
+	defer b.retention.Done()
+
+	b.prune(store)
+	if b.options.Retention.Interval <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(b.options.Retention.Interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			b.prune(store)
+		case <-registration.stopCh:
+			return
+		case <-b.ctx.Done():
+			return
+		}
+	}
+}
+
+// prune runs the retention policy once and records its statistics
+func (b *eventBroadcaster) prune(store EventStore) {
+	pruned, err := PruneEvents(b.ctx, store, b.options.Retention, b.options.Clock.Now().Time)
+	atomic.AddInt64(&b.metrics.EventsPruned, int64(pruned))
+	if err != nil {
+		atomic.AddInt64(&b.metrics.PruneErrors, 1)
+		return
+	}
+	atomic.AddInt64(&b.metrics.PruneRuns, 1)
+}
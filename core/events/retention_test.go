@@ -0,0 +1,181 @@
+package events_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/dtomasi/k1s/core/events"
+)
+
+var _ = Describe("Event retention", func() {
+	var (
+		now   time.Time
+		store *mockEventStore
+	)
+
+	// storeEvent stores an event about an object that was last observed age ago
+	storeEvent := func(namespace, name string, object types.UID, age time.Duration) {
+		store.add(corev1.Event{
+			ObjectMeta:     metav1.ObjectMeta{Name: name, Namespace: namespace},
+			InvolvedObject: corev1.ObjectReference{Kind: "ConfigMap", Namespace: namespace, Name: string(object), UID: object},
+			LastTimestamp:  metav1.NewTime(now.Add(-age)),
+		})
+	}
+
+	BeforeEach(func() {
+		now = time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+		store = newMockEventStore()
+	})
+
+	It("should prune events older than an hour by default", func() {
+		storeEvent("default", "fresh", "config", 59*time.Minute)
+		storeEvent("default", "stale", "config", 61*time.Minute)
+
+		pruned, err := events.PruneEvents(context.Background(), store, events.EventRetentionOptions{}, now)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(pruned).To(Equal(1))
+		Expect(store.names()).To(ConsistOf("fresh"))
+	})
+
+	It("should keep the newest events of an object", func() {
+		for i := 0; i < 5; i++ {
+			storeEvent("default", fmt.Sprintf("config.%d", i), "config", time.Duration(i)*time.Minute)
+		}
+		storeEvent("default", "other", "other", 10*time.Minute)
+
+		pruned, err := events.PruneEvents(context.Background(), store, events.EventRetentionOptions{
+			MaxEventsPerObject: 2,
+		}, now)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(pruned).To(Equal(3))
+		Expect(store.names()).To(ConsistOf("config.0", "config.1", "other"))
+	})
+
+	It("should keep the newest events of each object of the same name", func() {
+		for i, kind := range []string{"ConfigMap", "Secret"} {
+			store.add(corev1.Event{
+				ObjectMeta: metav1.ObjectMeta{Name: kind, Namespace: "default"},
+				InvolvedObject: corev1.ObjectReference{
+					APIVersion: "v1", Kind: kind, Namespace: "default", Name: "config", UID: "config",
+				},
+				LastTimestamp: metav1.NewTime(now.Add(-time.Duration(i) * time.Minute)),
+			})
+		}
+
+		pruned, err := events.PruneEvents(context.Background(), store, events.EventRetentionOptions{
+			MaxEventsPerObject: 1,
+		}, now)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(pruned).To(BeZero())
+		Expect(store.names()).To(ConsistOf("ConfigMap", "Secret"))
+	})
+
+	It("should keep the newest events of a namespace", func() {
+		storeEvent("default", "a", "a", time.Minute)
+		storeEvent("default", "b", "b", 2*time.Minute)
+		storeEvent("default", "c", "c", 3*time.Minute)
+		storeEvent("other", "d", "d", 4*time.Minute)
+
+		pruned, err := events.PruneEvents(context.Background(), store, events.EventRetentionOptions{
+			MaxEventsPerNamespace: 2,
+		}, now)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(pruned).To(Equal(1))
+		Expect(store.names()).To(ConsistOf("a", "b", "d"))
+	})
+
+	It("should prune the events of a sink on start and at the interval", func() {
+		storeEvent("default", "stale", "config", 2*time.Hour)
+		clock := &mockClock{now: metav1.NewTime(now)}
+		broadcaster := events.NewEventBroadcaster(events.EventBroadcasterOptions{
+			Clock: clock,
+			Retention: events.EventRetentionOptions{
+				Enabled:  true,
+				Interval: 10 * time.Millisecond,
+			},
+		})
+		defer func() { Expect(broadcaster.Shutdown(context.Background())).To(Succeed()) }()
+		broadcaster.StartRecordingToSink(store)
+
+		metrics := func() events.EventMetrics {
+			return broadcaster.(metricsProvider).GetMetrics()
+		}
+		Eventually(store.names).Should(BeEmpty())
+		Eventually(metrics).Should(And(
+			HaveField("EventsPruned", int64(1)),
+			HaveField("PruneRuns", BeNumerically(">=", 2)),
+		))
+
+		store.setListError(errors.New("storage is locked"))
+		Eventually(metrics).Should(HaveField("PruneErrors", BeNumerically(">=", 1)))
+	})
+})
+
+// mockEventStore is an event sink whose events can be listed and deleted
+type mockEventStore struct {
+	*threadSafeMockEventSink
+
+	mu      sync.Mutex
+	stored  map[string]corev1.Event
+	listErr error
+}
+
+func newMockEventStore() *mockEventStore {
+	return &mockEventStore{
+		threadSafeMockEventSink: newMockEventSink(),
+		stored:                  make(map[string]corev1.Event),
+	}
+}
+
+func (m *mockEventStore) add(event corev1.Event) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.stored[event.Name] = event
+}
+
+func (m *mockEventStore) setListError(err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.listErr = err
+}
+
+func (m *mockEventStore) names() []string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	names := make([]string, 0, len(m.stored))
+	for name := range m.stored {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func (m *mockEventStore) ListEvents(context.Context) ([]corev1.Event, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.listErr != nil {
+		return nil, m.listErr
+	}
+	result := make([]corev1.Event, 0, len(m.stored))
+	for _, event := range m.stored {
+		result = append(result, event)
+	}
+	return result, nil
+}
+
+func (m *mockEventStore) DeleteEvent(_ context.Context, event *corev1.Event) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.stored, event.Name)
+	return nil
+}
@@ -0,0 +1,232 @@
+package sinks
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/dtomasi/k1s/core/events"
+	typesv1 "github.com/dtomasi/k1s/core/types/v1"
+)
+
+// Default values for file sinks
+const (
+	DefaultFileSinkMaxSize  = 10 * 1024 * 1024
+	DefaultFileSinkMaxFiles = 5
+)
+
+// FileSink implements EventSink by appending events as JSON lines to a file.
+// Every write of an event, including count updates, appends the complete
+// event, so the last line of an event holds its final state.
+//
+// When a write would grow the file beyond its maximum size, the file is
+// rotated: path becomes path.1, path.1 becomes path.2 and so on, and the
+// oldest file is removed. If the rotation fails, the event is still appended
+// to the file and the rotation is retried with the next write.
+type FileSink struct {
+	mu       sync.Mutex
+	path     string
+	maxSize  int64
+	maxFiles int
+	api      events.EventAPI
+	file     *os.File
+	size     int64
+}
+
+// FileSinkOptions provides configuration options for creating a FileSink
+type FileSinkOptions struct {
+	// Path is the path of the event log. Missing directories are created.
+	Path string
+
+	// MaxSize is the size in bytes after which the file is rotated.
+	// Defaults to DefaultFileSinkMaxSize.
+	MaxSize int64
+
+	// MaxFiles is the number of rotated files that are kept.
+	// Defaults to DefaultFileSinkMaxFiles.
+	MaxFiles int
+
+	// API selects the API of the written events. EventAPIBoth writes both
+	// forms of every event. Defaults to core v1 Events.
+	API events.EventAPI
+}
+
+// NewFileSink creates a new FileSink, appending to an existing file
+func NewFileSink(options FileSinkOptions) (*FileSink, error) {
+	if options.Path == "" {
+		return nil, fmt.Errorf("file sink path is required")
+	}
+	if options.MaxSize <= 0 {
+		options.MaxSize = DefaultFileSinkMaxSize
+	}
+	if options.MaxFiles <= 0 {
+		options.MaxFiles = DefaultFileSinkMaxFiles
+	}
+	if options.API == "" {
+		options.API = events.EventAPICoreV1
+	}
+
+	sink := &FileSink{
+		path:     options.Path,
+		maxSize:  options.MaxSize,
+		maxFiles: options.MaxFiles,
+		api:      options.API,
+	}
+	if err := os.MkdirAll(filepath.Dir(options.Path), 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create event log directory: %w", err)
+	}
+	if err := sink.open(); err != nil {
+		return nil, err
+	}
+	return sink, nil
+}
+
+// Create appends a new event to the file
+func (s *FileSink) Create(event *corev1.Event) (*corev1.Event, error) {
+	return s.write(event)
+}
+
+// Update appends the updated event to the file
+func (s *FileSink) Update(event *corev1.Event) (*corev1.Event, error) {
+	return s.write(event)
+}
+
+// Patch appends the patched event to the file. The broadcaster passes the
+// complete event, so the patch itself is not needed.
+func (s *FileSink) Patch(event *corev1.Event, _ []byte) (*corev1.Event, error) {
+	return s.write(event)
+}
+
+// Close closes the file. Later writes fail.
+func (s *FileSink) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.file == nil {
+		return nil
+	}
+	err := s.file.Close()
+	s.file = nil
+	return err
+}
+
+// write appends the JSON lines of an event, rotating the file if needed
+func (s *FileSink) write(event *corev1.Event) (*corev1.Event, error) {
+	if event == nil {
+		return nil, fmt.Errorf("event cannot be nil")
+	}
+
+	var data []byte
+	if s.api != events.EventAPIEventsV1 {
+		line, err := marshalLine(withEventTypeMeta(event))
+		if err != nil {
+			return nil, err
+		}
+		data = append(data, line...)
+	}
+	if s.api != events.EventAPICoreV1 {
+		line, err := marshalLine(typesv1.EventToEventsV1(event))
+		if err != nil {
+			return nil, err
+		}
+		data = append(data, line...)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.file == nil {
+		return nil, fmt.Errorf("file sink %s is closed", s.path)
+	}
+	if s.size > 0 && s.size+int64(len(data)) > s.maxSize {
+		// A failed rotation reopens the file, so the event is still written
+		if err := s.rotate(); err != nil && s.file == nil {
+			return nil, err
+		}
+	}
+
+	n, err := s.file.Write(data)
+	s.size += int64(n)
+	if err != nil {
+		return nil, fmt.Errorf("failed to write event: %w", err)
+	}
+	return event, nil
+}
+
+// open opens the file for appending
+func (s *FileSink) open() error {
+	file, err := os.OpenFile(s.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return fmt.Errorf("failed to open event log: %w", err)
+	}
+	info, err := file.Stat()
+	if err != nil {
+		_ = file.Close()
+		return fmt.Errorf("failed to open event log: %w", err)
+	}
+
+	s.file = file
+	s.size = info.Size()
+	return nil
+}
+
+// rotate shifts the rotated files, removing the oldest, and reopens the file.
+// The file is reopened for appending even if the rotation fails.
+func (s *FileSink) rotate() error {
+	rotateErr := s.file.Close()
+	s.file = nil
+	if rotateErr == nil {
+		rotateErr = s.shift()
+	}
+
+	if err := s.open(); err != nil {
+		return err
+	}
+	if rotateErr != nil {
+		return fmt.Errorf("failed to rotate event log: %w", rotateErr)
+	}
+	return nil
+}
+
+// shift renames the file to path.1 and every rotated file to the next
+// number, removing the oldest
+func (s *FileSink) shift() error {
+	if err := os.Remove(s.rotatedPath(s.maxFiles)); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	for i := s.maxFiles - 1; i >= 1; i-- {
+		if err := os.Rename(s.rotatedPath(i), s.rotatedPath(i+1)); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+	return os.Rename(s.path, s.rotatedPath(1))
+}
+
+// rotatedPath returns the path of the i-th rotated file
+func (s *FileSink) rotatedPath(i int) string {
+	return fmt.Sprintf("%s.%d", s.path, i)
+}
+
+// withEventTypeMeta returns the event with its apiVersion and kind set
+func withEventTypeMeta(event *corev1.Event) *corev1.Event {
+	if event.APIVersion != "" && event.Kind != "" {
+		return event
+	}
+	event = event.DeepCopy()
+	event.APIVersion = "v1"
+	event.Kind = "Event"
+	return event
+}
+
+// marshalLine encodes an object as a JSON line
+func marshalLine(obj interface{}) ([]byte, error) {
+	data, err := json.Marshal(obj)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode event: %w", err)
+	}
+	return append(data, '\n'), nil
+}
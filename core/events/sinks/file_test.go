@@ -0,0 +1,156 @@
+package sinks_test
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	eventsv1 "k8s.io/api/events/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/dtomasi/k1s/core/events"
+	"github.com/dtomasi/k1s/core/events/sinks"
+)
+
+// newSinkTestEvent returns an event about a ConfigMap
+func newSinkTestEvent(name, message string) *corev1.Event {
+	return &corev1.Event{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+		InvolvedObject: corev1.ObjectReference{
+			Kind: "ConfigMap", Name: "test-config", Namespace: "default",
+		},
+		Reason:        "Failed",
+		Message:       message,
+		Type:          corev1.EventTypeWarning,
+		Count:         1,
+		Source:        corev1.EventSource{Component: "test-controller"},
+		LastTimestamp: metav1.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC),
+	}
+}
+
+// readLines returns the lines of a file
+func readLines(path string) []string {
+	file, err := os.Open(path)
+	Expect(err).NotTo(HaveOccurred())
+	defer func() { _ = file.Close() }()
+
+	var lines []string
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		lines = append(lines, scanner.Text())
+	}
+	Expect(scanner.Err()).NotTo(HaveOccurred())
+	return lines
+}
+
+var _ = Describe("FileSink", func() {
+	var path string
+
+	BeforeEach(func() {
+		path = filepath.Join(GinkgoT().TempDir(), "logs", "events.jsonl")
+	})
+
+	It("should append events as JSON lines", func() {
+		sink, err := sinks.NewFileSink(sinks.FileSinkOptions{Path: path})
+		Expect(err).NotTo(HaveOccurred())
+
+		event := newSinkTestEvent("config.1", "sync failed")
+		_, err = sink.Create(event)
+		Expect(err).NotTo(HaveOccurred())
+		updated := event.DeepCopy()
+		updated.Count = 2
+		_, err = sink.Patch(updated, []byte(`{"count":2}`))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(sink.Close()).To(Succeed())
+
+		lines := readLines(path)
+		Expect(lines).To(HaveLen(2))
+		decoded := &corev1.Event{}
+		Expect(json.Unmarshal([]byte(lines[1]), decoded)).To(Succeed())
+		Expect(decoded.Kind).To(Equal("Event"))
+		Expect(decoded.APIVersion).To(Equal("v1"))
+		Expect(decoded.Message).To(Equal("sync failed"))
+		Expect(decoded.Count).To(Equal(int32(2)))
+
+		_, err = sink.Create(event)
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should append to an existing file", func() {
+		sink, err := sinks.NewFileSink(sinks.FileSinkOptions{Path: path})
+		Expect(err).NotTo(HaveOccurred())
+		_, err = sink.Create(newSinkTestEvent("config.1", "first"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(sink.Close()).To(Succeed())
+
+		sink, err = sinks.NewFileSink(sinks.FileSinkOptions{Path: path})
+		Expect(err).NotTo(HaveOccurred())
+		_, err = sink.Create(newSinkTestEvent("config.2", "second"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(sink.Close()).To(Succeed())
+
+		Expect(readLines(path)).To(HaveLen(2))
+	})
+
+	It("should rotate the file by size and keep a limited number of files", func() {
+		sink, err := sinks.NewFileSink(sinks.FileSinkOptions{Path: path, MaxSize: 1, MaxFiles: 2})
+		Expect(err).NotTo(HaveOccurred())
+		for _, message := range []string{"first", "second", "third", "fourth"} {
+			_, err = sink.Create(newSinkTestEvent("config."+message, message))
+			Expect(err).NotTo(HaveOccurred())
+		}
+		Expect(sink.Close()).To(Succeed())
+
+		Expect(readLines(path)).To(ConsistOf(ContainSubstring("fourth")))
+		Expect(readLines(path + ".1")).To(ConsistOf(ContainSubstring("third")))
+		Expect(readLines(path + ".2")).To(ConsistOf(ContainSubstring("second")))
+		Expect(path + ".3").NotTo(BeAnExistingFile())
+	})
+
+	It("should keep writing to the file when the rotation fails", func() {
+		sink, err := sinks.NewFileSink(sinks.FileSinkOptions{Path: path, MaxSize: 1, MaxFiles: 1})
+		Expect(err).NotTo(HaveOccurred())
+
+		// A non-empty directory in place of the oldest file cannot be removed
+		Expect(os.MkdirAll(filepath.Join(path+".1", "blocked"), 0o755)).To(Succeed())
+		for _, message := range []string{"first", "second"} {
+			_, err = sink.Create(newSinkTestEvent("config."+message, message))
+			Expect(err).NotTo(HaveOccurred())
+		}
+		Expect(readLines(path)).To(HaveLen(2))
+
+		Expect(os.RemoveAll(path + ".1")).To(Succeed())
+		_, err = sink.Create(newSinkTestEvent("config.third", "third"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(sink.Close()).To(Succeed())
+
+		Expect(readLines(path)).To(ConsistOf(ContainSubstring("third")))
+		Expect(readLines(path + ".1")).To(HaveLen(2))
+	})
+
+	It("should write both APIs", func() {
+		sink, err := sinks.NewFileSink(sinks.FileSinkOptions{Path: path, API: events.EventAPIBoth})
+		Expect(err).NotTo(HaveOccurred())
+		_, err = sink.Create(newSinkTestEvent("config.1", "sync failed"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(sink.Close()).To(Succeed())
+
+		lines := readLines(path)
+		Expect(lines).To(HaveLen(2))
+		Expect(lines[0]).To(ContainSubstring(`"apiVersion":"v1"`))
+		converted := &eventsv1.Event{}
+		Expect(json.Unmarshal([]byte(lines[1]), converted)).To(Succeed())
+		Expect(converted.APIVersion).To(Equal("events.k8s.io/v1"))
+		Expect(converted.Note).To(Equal("sync failed"))
+	})
+
+	It("should require a path", func() {
+		_, err := sinks.NewFileSink(sinks.FileSinkOptions{})
+		Expect(err).To(HaveOccurred())
+	})
+})
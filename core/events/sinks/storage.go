@@ -2,23 +2,30 @@ package sinks
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/strategicpatch"
 
 	"github.com/dtomasi/k1s/core/client"
 	"github.com/dtomasi/k1s/core/events"
+	typesv1 "github.com/dtomasi/k1s/core/types/v1"
 )
 
 // StorageSink implements EventSink by storing events as Kubernetes Event resources
 // using the k1s client. This provides persistent storage of events that can be
 // queried using standard CLI operations.
+//
+// Depending on its API, the sink writes core v1 Events, events.k8s.io/v1
+// Events, or both.
 type StorageSink struct {
 	client client.Client
 	ctx    context.Context
+	api    events.EventAPI
 }
 
 // StorageSinkOptions provides configuration options for creating a StorageSink
@@ -28,17 +35,27 @@ type StorageSinkOptions struct {
 
 	// Context is the context used for storage operations
 	Context context.Context
+
+	// API selects the API of the stored events. Defaults to core v1 Events.
+	API events.EventAPI
 }
 
+var _ events.EventStore = &StorageSink{}
+
 // NewStorageSink creates a new StorageSink instance
 func NewStorageSink(options StorageSinkOptions) events.EventSink {
 	if options.Context == nil {
 		options.Context = context.Background()
 	}
 
+	if options.API == "" {
+		options.API = events.EventAPICoreV1
+	}
+
 	return &StorageSink{
 		client: options.Client,
 		ctx:    options.Context,
+		api:    options.API,
 	}
 }
 
@@ -68,6 +85,15 @@ func (s *StorageSink) Create(event *corev1.Event) (*corev1.Event, error) {
 		}
 	}
 
+	switch s.api {
+	case events.EventAPIEventsV1:
+		return s.createEventsV1(event)
+	case events.EventAPIBoth:
+		if _, err := s.createEventsV1(event.DeepCopy()); err != nil {
+			return nil, err
+		}
+	}
+
 	// Try to create the event
 	err := s.client.Create(s.ctx, event)
 	if err != nil {
@@ -87,6 +113,15 @@ func (s *StorageSink) Update(event *corev1.Event) (*corev1.Event, error) {
 		return nil, fmt.Errorf("event cannot be nil")
 	}
 
+	switch s.api {
+	case events.EventAPIEventsV1:
+		return s.updateEventsV1(event)
+	case events.EventAPIBoth:
+		if _, err := s.updateEventsV1(event.DeepCopy()); err != nil {
+			return nil, err
+		}
+	}
+
 	// Try to get the existing event first
 	objectKey := client.ObjectKey{
 		Namespace: event.Namespace,
@@ -124,6 +159,15 @@ func (s *StorageSink) Patch(event *corev1.Event, data []byte) (*corev1.Event, er
 		return nil, fmt.Errorf("patch data cannot be nil")
 	}
 
+	switch s.api {
+	case events.EventAPIEventsV1:
+		return s.patchEventsV1(event, data)
+	case events.EventAPIBoth:
+		if _, err := s.patchEventsV1(event, data); err != nil {
+			return nil, err
+		}
+	}
+
 	// Create a strategic merge patch
 	patch := client.RawPatch{
 		PatchType: types.StrategicMergePatchType,
@@ -138,6 +182,114 @@ func (s *StorageSink) Patch(event *corev1.Event, data []byte) (*corev1.Event, er
 	return event, nil
 }
 
+// ListEvents returns the stored events, converting events.k8s.io/v1 Events
+// to core Events. It implements events.EventStore.
+func (s *StorageSink) ListEvents(ctx context.Context) ([]corev1.Event, error) {
+	if s.api == events.EventAPIEventsV1 {
+		list := &typesv1.EventsV1EventList{}
+		if err := s.client.List(ctx, list); err != nil {
+			return nil, fmt.Errorf("failed to list events: %w", err)
+		}
+		result := make([]corev1.Event, 0, len(list.Items))
+		for i := range list.Items {
+			result = append(result, *typesv1.EventFromEventsV1(&list.Items[i]))
+		}
+		return result, nil
+	}
+
+	// With both APIs, every event is stored in both forms
+	list := &corev1.EventList{}
+	if err := s.client.List(ctx, list); err != nil {
+		return nil, fmt.Errorf("failed to list events: %w", err)
+	}
+	return list.Items, nil
+}
+
+// DeleteEvent deletes a stored event in the APIs of the sink. It implements
+// events.EventStore.
+func (s *StorageSink) DeleteEvent(ctx context.Context, event *corev1.Event) error {
+	meta := metav1.ObjectMeta{Name: event.Name, Namespace: event.Namespace}
+	if s.api != events.EventAPIEventsV1 {
+		if err := s.client.Delete(ctx, &corev1.Event{ObjectMeta: meta}); err != nil {
+			return err
+		}
+	}
+	if s.api != events.EventAPICoreV1 {
+		err := s.client.Delete(ctx, &typesv1.EventsV1Event{ObjectMeta: meta})
+		if err != nil && !(s.api == events.EventAPIBoth && apierrors.IsNotFound(err)) {
+			return err
+		}
+	}
+	return nil
+}
+
+// createEventsV1 stores the event as an events.k8s.io/v1 Event
+func (s *StorageSink) createEventsV1(event *corev1.Event) (*corev1.Event, error) {
+	err := s.client.Create(s.ctx, typesv1.EventToEventsV1(event))
+	if err != nil {
+		// If the event already exists, try to update it (for event aggregation)
+		if apierrors.IsAlreadyExists(err) {
+			return s.updateEventsV1(event)
+		}
+		return nil, fmt.Errorf("failed to create event: %w", err)
+	}
+	return event, nil
+}
+
+// updateEventsV1 aggregates the event into an existing events.k8s.io/v1 Event
+func (s *StorageSink) updateEventsV1(event *corev1.Event) (*corev1.Event, error) {
+	existing, err := s.getEventsV1(event)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return s.createEventsV1(event)
+		}
+		return nil, fmt.Errorf("failed to get existing event: %w", err)
+	}
+
+	updated := s.aggregateEvents(typesv1.EventFromEventsV1(existing), event)
+	if err := s.client.Update(s.ctx, typesv1.EventToEventsV1(updated)); err != nil {
+		return nil, fmt.Errorf("failed to update event: %w", err)
+	}
+	return updated, nil
+}
+
+// patchEventsV1 applies a strategic merge patch of a core v1 Event to an
+// existing events.k8s.io/v1 Event
+func (s *StorageSink) patchEventsV1(event *corev1.Event, data []byte) (*corev1.Event, error) {
+	existing, err := s.getEventsV1(event)
+	if err != nil {
+		return nil, fmt.Errorf("failed to patch event: %w", err)
+	}
+
+	original, err := json.Marshal(typesv1.EventFromEventsV1(existing))
+	if err != nil {
+		return nil, fmt.Errorf("failed to patch event: %w", err)
+	}
+	patched, err := strategicpatch.StrategicMergePatch(original, data, &corev1.Event{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to patch event: %w", err)
+	}
+	updated := &corev1.Event{}
+	if err := json.Unmarshal(patched, updated); err != nil {
+		return nil, fmt.Errorf("failed to patch event: %w", err)
+	}
+
+	if err := s.client.Update(s.ctx, typesv1.EventToEventsV1(updated)); err != nil {
+		return nil, fmt.Errorf("failed to patch event: %w", err)
+	}
+	return updated, nil
+}
+
+// getEventsV1 returns the stored events.k8s.io/v1 Event of the same name
+func (s *StorageSink) getEventsV1(event *corev1.Event) (*typesv1.EventsV1Event, error) {
+	existing := &typesv1.EventsV1Event{}
+	key := client.ObjectKey{Namespace: event.Namespace, Name: event.Name}
+	if err := s.client.Get(s.ctx, key, existing); err != nil {
+		return nil, err
+	}
+	return existing, nil
+}
+
 // aggregateEvents combines information from two events for event aggregation
 func (s *StorageSink) aggregateEvents(existing, new *corev1.Event) *corev1.Event {
 	// Create a copy of the existing event to update
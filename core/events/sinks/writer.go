@@ -0,0 +1,173 @@
+package sinks
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/dtomasi/k1s/core/events"
+)
+
+// WriterFormat is the format in which a WriterSink renders events
+type WriterFormat string
+
+const (
+	// WriterFormatHuman renders one human-readable line per event
+	WriterFormatHuman WriterFormat = "human"
+	// WriterFormatLogfmt renders one logfmt line per event
+	WriterFormatLogfmt WriterFormat = "logfmt"
+)
+
+// WriterSink implements EventSink by rendering events to an io.Writer, e.g.
+// the output of a CLI or the log of a build job. Count updates are rendered
+// as new lines with the current count.
+type WriterSink struct {
+	mu     sync.Mutex
+	writer io.Writer
+	format WriterFormat
+}
+
+// WriterSinkOptions provides configuration options for creating a WriterSink
+type WriterSinkOptions struct {
+	// Writer receives the rendered events. Defaults to os.Stdout.
+	Writer io.Writer
+
+	// Format selects how events are rendered. Defaults to WriterFormatHuman.
+	Format WriterFormat
+}
+
+// NewWriterSink creates a new WriterSink instance
+func NewWriterSink(options WriterSinkOptions) events.EventSink {
+	if options.Writer == nil {
+		options.Writer = os.Stdout
+	}
+	if options.Format == "" {
+		options.Format = WriterFormatHuman
+	}
+
+	return &WriterSink{
+		writer: options.Writer,
+		format: options.Format,
+	}
+}
+
+// Create renders a new event
+func (s *WriterSink) Create(event *corev1.Event) (*corev1.Event, error) {
+	return s.write(event)
+}
+
+// Update renders the updated event
+func (s *WriterSink) Update(event *corev1.Event) (*corev1.Event, error) {
+	return s.write(event)
+}
+
+// Patch renders the patched event. The broadcaster passes the complete
+// event, so the patch itself is not needed.
+func (s *WriterSink) Patch(event *corev1.Event, _ []byte) (*corev1.Event, error) {
+	return s.write(event)
+}
+
+// write renders an event in the format of the sink
+func (s *WriterSink) write(event *corev1.Event) (*corev1.Event, error) {
+	if event == nil {
+		return nil, fmt.Errorf("event cannot be nil")
+	}
+
+	var line string
+	switch s.format {
+	case WriterFormatHuman:
+		line = formatHuman(event)
+	case WriterFormatLogfmt:
+		line = formatLogfmt(event)
+	default:
+		return nil, fmt.Errorf("unsupported event format %q", s.format)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, err := io.WriteString(s.writer, line); err != nil {
+		return nil, fmt.Errorf("failed to write event: %w", err)
+	}
+	return event, nil
+}
+
+// formatHuman renders an event like
+// "2024-01-01T12:00:00Z Warning Failed ConfigMap default/config: sync failed (x3)"
+func formatHuman(event *corev1.Event) string {
+	var b strings.Builder
+	b.WriteString(eventTimestamp(event).Format(time.RFC3339))
+	b.WriteString(" ")
+	b.WriteString(event.Type)
+	b.WriteString(" ")
+	b.WriteString(event.Reason)
+	b.WriteString(" ")
+	b.WriteString(objectName(event.InvolvedObject))
+	b.WriteString(": ")
+	b.WriteString(strings.TrimSpace(event.Message))
+	if event.Count > 1 {
+		fmt.Fprintf(&b, " (x%d)", event.Count)
+	}
+	b.WriteString("\n")
+	return b.String()
+}
+
+// formatLogfmt renders an event as logfmt key-value pairs
+func formatLogfmt(event *corev1.Event) string {
+	pairs := [][2]string{
+		{"time", eventTimestamp(event).Format(time.RFC3339)},
+		{"type", event.Type},
+		{"reason", event.Reason},
+		{"kind", event.InvolvedObject.Kind},
+		{"namespace", event.InvolvedObject.Namespace},
+		{"name", event.InvolvedObject.Name},
+		{"message", strings.TrimSpace(event.Message)},
+		{"count", strconv.Itoa(int(event.Count))},
+		{"source", event.Source.Component},
+	}
+
+	var b strings.Builder
+	for i, pair := range pairs {
+		if i > 0 {
+			b.WriteString(" ")
+		}
+		b.WriteString(pair[0])
+		b.WriteString("=")
+		b.WriteString(logfmtValue(pair[1]))
+	}
+	b.WriteString("\n")
+	return b.String()
+}
+
+// logfmtValue quotes a value if it is empty or contains spaces, quotes or '='
+func logfmtValue(value string) string {
+	if value == "" || strings.ContainsAny(value, " \t\n\"=\\") {
+		return strconv.Quote(value)
+	}
+	return value
+}
+
+// eventTimestamp returns the time an event was last observed
+func eventTimestamp(event *corev1.Event) time.Time {
+	switch {
+	case !event.LastTimestamp.IsZero():
+		return event.LastTimestamp.UTC()
+	case !event.EventTime.IsZero():
+		return event.EventTime.UTC()
+	default:
+		return event.FirstTimestamp.UTC()
+	}
+}
+
+// objectName renders an object reference as "Kind namespace/name"
+func objectName(ref corev1.ObjectReference) string {
+	if ref.Namespace == "" {
+		return ref.Kind + " " + ref.Name
+	}
+	return ref.Kind + " " + ref.Namespace + "/" + ref.Name
+}
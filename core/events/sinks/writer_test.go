@@ -0,0 +1,41 @@
+package sinks_test
+
+import (
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/dtomasi/k1s/core/events/sinks"
+)
+
+var _ = Describe("WriterSink", func() {
+	It("should render human-readable events", func() {
+		var out strings.Builder
+		sink := sinks.NewWriterSink(sinks.WriterSinkOptions{Writer: &out})
+
+		event := newSinkTestEvent("config.1", "sync failed")
+		event.Count = 3
+		_, err := sink.Create(event)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(out.String()).To(Equal("2024-01-01T12:00:00Z Warning Failed ConfigMap default/test-config: sync failed (x3)\n"))
+	})
+
+	It("should render logfmt events", func() {
+		var out strings.Builder
+		sink := sinks.NewWriterSink(sinks.WriterSinkOptions{Writer: &out, Format: sinks.WriterFormatLogfmt})
+
+		_, err := sink.Create(newSinkTestEvent("config.1", `sync "failed"`))
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(out.String()).To(Equal(`time=2024-01-01T12:00:00Z type=Warning reason=Failed kind=ConfigMap namespace=default ` +
+			`name=test-config message="sync \"failed\"" count=1 source=test-controller` + "\n"))
+	})
+
+	It("should reject unknown formats", func() {
+		sink := sinks.NewWriterSink(sinks.WriterSinkOptions{Writer: &strings.Builder{}, Format: "xml"})
+		_, err := sink.Create(newSinkTestEvent("config.1", "sync failed"))
+		Expect(err).To(HaveOccurred())
+	})
+})
@@ -1,7 +1,9 @@
 package events
 
 import (
+	"context"
 	"fmt"
+	"time"
 
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -30,6 +32,17 @@ type EventRecorder interface {
 	AnnotatedEventf(object runtime.Object, annotations map[string]string, eventtype, reason, messageFmt string, args ...interface{})
 }
 
+// EventsRecorder records events of the events.k8s.io/v1 API.
+// This interface matches the client-go tools/events EventRecorder interface.
+type EventsRecorder interface {
+	// Eventf constructs an event from the given information and puts it in the queue for sending.
+	// 'regarding' is the object this event is about and 'related' an optional secondary object
+	// for more complex actions. 'eventtype' is Normal or Warning. 'reason' is why the action
+	// was taken, 'action' what action was taken or failed with regard to 'regarding'.
+	// 'note' is intended to be consumed by humans and is formatted with 'args'.
+	Eventf(regarding runtime.Object, related runtime.Object, eventtype, reason, action, note string, args ...interface{})
+}
+
 // EventBroadcaster provides event broadcasting functionality similar to client-go.
 // It manages multiple event sinks and distributes events to all registered sinks.
 type EventBroadcaster interface {
@@ -44,12 +57,35 @@ type EventBroadcaster interface {
 	// NewRecorder returns an EventRecorder that records to this broadcaster.
 	NewRecorder(scheme *runtime.Scheme, source corev1.EventSource) EventRecorder
 
-	// Shutdown gracefully shuts down the broadcaster and all its watchers.
-	Shutdown()
+	// NewEventsRecorder returns an EventsRecorder that records events of the
+	// events.k8s.io/v1 API to this broadcaster.
+	NewEventsRecorder(scheme *runtime.Scheme, source corev1.EventSource) EventsRecorder
+
+	// Shutdown stops accepting events and blocks until the queued events have
+	// been written to all sinks or the context is done, in which case the
+	// remaining events are dropped and the context error is returned.
+	// A broadcaster cannot be restarted after Shutdown.
+	Shutdown(ctx context.Context) error
 }
 
+// EventAPI selects the API of the Event objects a sink writes
+type EventAPI string
+
+const (
+	// EventAPICoreV1 writes core v1 Events
+	EventAPICoreV1 EventAPI = "v1"
+
+	// EventAPIEventsV1 writes events.k8s.io/v1 Events
+	EventAPIEventsV1 EventAPI = "events.k8s.io/v1"
+
+	// EventAPIBoth writes every event in both APIs, for consumers of either
+	EventAPIBoth EventAPI = "both"
+)
+
 // EventSink represents a destination for events. This interface allows
 // events to be sent to different backends (storage, logging, etc.).
+// Sinks that implement io.Closer, e.g. to close a file, are closed by
+// EventBroadcaster.Shutdown once their queued events have been written.
 type EventSink interface {
 	// Create creates a new event in the sink.
 	Create(event *corev1.Event) (*corev1.Event, error)
@@ -142,6 +178,18 @@ type EventMetrics struct {
 	// EventsDropped counts the number of events that were dropped due to errors
 	EventsDropped int64
 
+	// EventsRetried counts the number of failed sink writes that were retried
+	EventsRetried int64
+
+	// EventsPruned counts the number of stored events deleted by the retention policy
+	EventsPruned int64
+
+	// PruneRuns counts the number of completed prune runs
+	PruneRuns int64
+
+	// PruneErrors counts the number of prune runs that failed
+	PruneErrors int64
+
 	// SinksActive counts the number of active event sinks
 	SinksActive int32
 
@@ -188,6 +236,86 @@ type EventBroadcasterOptions struct {
 
 	// Clock allows injection of a custom clock for testing
 	Clock Clock
+
+	// MaxRetries is the number of times a failed sink write is retried before
+	// the event is dropped. It is capped at MaxEventRetries; a negative value
+	// means MaxEventRetries.
+	MaxRetries int
+
+	// RetryBackoff is the initial backoff between retries of a failed sink
+	// write. It doubles with every retry.
+	RetryBackoff time.Duration
+
+	// Correlator configures the correlation of the events written to sinks.
+	// Each sink correlates its events separately.
+	Correlator EventCorrelatorOptions
+
+	// Sinks are recorded to from the start, in addition to the sinks started
+	// with StartRecordingToSink, e.g. a sinks.FileSink or sinks.WriterSink.
+	Sinks []EventSink
+
+	// Retention configures the pruning of the events stored by sinks that
+	// implement EventStore
+	Retention EventRetentionOptions
+}
+
+// EventRetentionOptions provides configuration options for the pruning of
+// stored events. Events are pruned when a sink is started and, with an
+// interval, periodically while the broadcaster runs.
+type EventRetentionOptions struct {
+	// Enabled enables the pruning of stored events
+	Enabled bool
+
+	// MaxAge is the age after which events are pruned, measured from the time
+	// they were last observed. Defaults to DefaultEventRetentionMaxAge.
+	MaxAge time.Duration
+
+	// MaxEventsPerObject is the number of events kept per involved object.
+	// The oldest events are pruned first. Zero keeps all events.
+	MaxEventsPerObject int
+
+	// MaxEventsPerNamespace is the number of events kept per namespace.
+	// The oldest events are pruned first. Zero keeps all events.
+	MaxEventsPerNamespace int
+
+	// Interval is the interval at which events are pruned while the
+	// broadcaster runs. Zero prunes only when a sink is started.
+	Interval time.Duration
+}
+
+// EventStore is implemented by sinks whose events can be listed and deleted,
+// so that the broadcaster can prune them.
+type EventStore interface {
+	// ListEvents returns all stored events
+	ListEvents(ctx context.Context) ([]corev1.Event, error)
+
+	// DeleteEvent deletes a stored event
+	DeleteEvent(ctx context.Context, event *corev1.Event) error
+}
+
+// EventCorrelatorOptions provides configuration options for creating an EventCorrelator.
+// Zero values use the defaults of client-go.
+type EventCorrelatorOptions struct {
+	// MaxEvents is the number of similar events with different messages after
+	// which they are aggregated into a single event
+	MaxEvents int
+
+	// MaxInterval is the time after which the aggregation of similar events restarts
+	MaxInterval time.Duration
+
+	// BurstSize is the number of new events a source may record about an object
+	// before the spam filter drops them
+	BurstSize int
+
+	// QPS is the rate at which the spam filter allows new events after a burst
+	QPS float64
+
+	// CacheSize is the number of entries kept in each correlation cache
+	CacheSize int
+
+	// Clock allows injection of a custom clock for testing.
+	// Defaults to the clock of the broadcaster.
+	Clock Clock
 }
 
 // Default values for event system configuration
@@ -195,4 +323,23 @@ const (
 	DefaultEventQueueSize = 1000
 	DefaultEventNamespace = ""
 	DefaultComponent      = "k1s-runtime"
+
+	DefaultEventMaxRetries   = 5
+	DefaultEventRetryBackoff = 100 * time.Millisecond
+
+	// MaxEventRetries caps the retries of a failed sink write, like the
+	// maximum tries per event of client-go
+	MaxEventRetries = 12
+
+	// DefaultEventRetentionMaxAge matches the event TTL of kube-apiserver
+	DefaultEventRetentionMaxAge = time.Hour
+)
+
+// Default values for event correlation, matching client-go
+const (
+	DefaultAggregateMaxEvents   = 10
+	DefaultAggregateMaxInterval = 10 * time.Minute
+	DefaultSpamBurst            = 25
+	DefaultSpamQPS              = 1. / 300.
+	DefaultCorrelatorCacheSize  = 4096
 )
@@ -7,6 +7,7 @@ import (
 	"sync"
 	"time"
 
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -35,6 +36,11 @@ type SharedInformerFactory interface {
 
 	// InformerFor returns a SharedIndexInformer for the given resource.
 	InformerFor(gvr schema.GroupVersionResource) cache.SharedIndexInformer
+
+	// IndexField adds a field index to the informer of the given resource. The
+	// index is named after the field and can be queried with ByFields. Indexes
+	// must be added before the informer is started.
+	IndexField(gvr schema.GroupVersionResource, field string, extractValue IndexerFunc) error
 }
 
 // GenericInformer provides access to a SharedIndexInformer and GenericLister.
@@ -74,6 +80,9 @@ type sharedInformerFactory struct {
 	// namespace restricts informers to a specific namespace (empty = all namespaces)
 	namespace string
 
+	// metadataOnly lists the resources whose informers only cache object metadata
+	metadataOnly map[schema.GroupVersionResource]bool
+
 	// ctx is the factory context
 	ctx    context.Context
 	cancel context.CancelFunc
@@ -98,6 +107,7 @@ func NewSharedInformerFactory(clientImpl client.Client, defaultResync time.Durat
 		informers:        make(map[schema.GroupVersionResource]cache.SharedIndexInformer),
 		genericInformers: make(map[schema.GroupVersionResource]GenericInformer),
 		started:          make(map[schema.GroupVersionResource]bool),
+		metadataOnly:     make(map[schema.GroupVersionResource]bool),
 		ctx:              ctx,
 		cancel:           cancel,
 	}
@@ -147,6 +157,23 @@ func (o *resyncOption) Apply(factory *sharedInformerFactory) {
 	}
 }
 
+// WithMetadataOnly creates an option to cache only the metadata of the given
+// resources. Their informers hold *metav1.PartialObjectMetadata objects, which
+// keeps the cache small for resources that are large in storage.
+func WithMetadataOnly(gvrs ...schema.GroupVersionResource) SharedInformerFactoryOption {
+	return &metadataOnlyOption{gvrs: gvrs}
+}
+
+type metadataOnlyOption struct {
+	gvrs []schema.GroupVersionResource
+}
+
+func (o *metadataOnlyOption) Apply(factory *sharedInformerFactory) {
+	for _, gvr := range o.gvrs {
+		factory.metadataOnly[gvr] = true
+	}
+}
+
 // ForResource returns a GenericInformer for the given resource.
 func (f *sharedInformerFactory) ForResource(gvr schema.GroupVersionResource) GenericInformer {
 	f.lock.Lock()
@@ -185,6 +212,15 @@ func (f *sharedInformerFactory) InformerFor(gvr schema.GroupVersionResource) cac
 	return informer
 }
 
+// IndexField adds a field index to the informer of the given resource.
+func (f *sharedInformerFactory) IndexField(gvr schema.GroupVersionResource, field string, extractValue IndexerFunc) error {
+	informer := f.InformerFor(gvr)
+	if err := informer.AddIndexers(cache.Indexers{FieldIndexName(field): indexFunc(extractValue)}); err != nil {
+		return fmt.Errorf("failed to index field %s of %s: %w", field, gvr, err)
+	}
+	return nil
+}
+
 // createInformer creates a SharedIndexInformer for the given resource.
 func (f *sharedInformerFactory) createInformer(gvr schema.GroupVersionResource) cache.SharedIndexInformer {
 	resyncPeriod := f.defaultResync
@@ -223,6 +259,9 @@ func (f *sharedInformerFactory) createInformer(gvr schema.GroupVersionResource)
 				return nil, fmt.Errorf("failed to list %s: %w", gvr, err)
 			}
 
+			if f.metadataOnly[gvr] {
+				return toPartialObjectMetadataList(listObj, f.kindForGVR(gvr))
+			}
 			return listObj, nil
 		},
 		WatchFunc: func(options metav1.ListOptions) (watch.Interface, error) {
@@ -248,14 +287,18 @@ func (f *sharedInformerFactory) createInformer(gvr schema.GroupVersionResource)
 				return nil, fmt.Errorf("failed to get list object for %s: %w", gvr, err)
 			}
 
-			return f.client.Watch(f.ctx, listObj, watchOpts...)
+			w, err := f.client.Watch(f.ctx, listObj, watchOpts...)
+			if err != nil {
+				return nil, err
+			}
+			return newTypedWatcher(w, f.informerObjectForGVR(gvr)), nil
 		},
 	}
 
 	// Create the SharedIndexInformer
 	informer := cache.NewSharedIndexInformer(
 		listWatch,
-		f.getObjectForGVR(gvr),
+		f.informerObjectForGVR(gvr),
 		resyncPeriod,
 		cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc},
 	)
@@ -310,26 +353,44 @@ func (f *sharedInformerFactory) WaitForCacheSync(stopCh <-chan struct{}) map[sch
 // Helper methods for resource type handling
 
 func (f *sharedInformerFactory) getObjectForGVR(gvr schema.GroupVersionResource) runtime.Object {
-	// Convert GVR to GVK
-	var gvk schema.GroupVersionKind
+	obj, err := f.client.Scheme().New(f.kindForGVR(gvr))
+	if err != nil {
+		// Return a generic object if we can't create the specific type
+		return &unstructured.Unstructured{}
+	}
 
+	return obj
+}
+
+// informerObjectForGVR returns the type of object the informer of gvr caches
+func (f *sharedInformerFactory) informerObjectForGVR(gvr schema.GroupVersionResource) runtime.Object {
+	if f.metadataOnly[gvr] {
+		partial := &metav1.PartialObjectMetadata{}
+		partial.SetGroupVersionKind(f.kindForGVR(gvr))
+		return partial
+	}
+	return f.getObjectForGVR(gvr)
+}
+
+// kindForGVR converts a GVR to a GVK using the REST mapper if available, then
+// the kinds registered with the scheme, and finally simple naming conventions
+func (f *sharedInformerFactory) kindForGVR(gvr schema.GroupVersionResource) schema.GroupVersionKind {
 	if f.client.RESTMapper() != nil {
-		if k, err := f.client.RESTMapper().KindFor(gvr); err == nil {
-			gvk = k
-		} else {
-			gvk = f.constructGVKFromGVR(gvr)
+		if gvk, err := f.client.RESTMapper().KindFor(gvr); err == nil {
+			return gvk
 		}
-	} else {
-		gvk = f.constructGVKFromGVR(gvr)
 	}
 
-	obj, err := f.client.Scheme().New(gvk)
-	if err != nil {
-		// Return a generic object if we can't create the specific type
-		return &unstructured.Unstructured{}
+	for gvk := range f.client.Scheme().AllKnownTypes() {
+		if gvk.GroupVersion() != gvr.GroupVersion() || strings.HasSuffix(gvk.Kind, "List") {
+			continue
+		}
+		if plural, _ := meta.UnsafeGuessKindToResource(gvk); plural.Resource == gvr.Resource {
+			return gvk
+		}
 	}
 
-	return obj
+	return f.constructGVKFromGVR(gvr)
 }
 
 // constructGVKFromGVR constructs a GVK from a GVR using simple conventions
@@ -354,17 +415,7 @@ func (f *sharedInformerFactory) constructGVKFromGVR(gvr schema.GroupVersionResou
 
 func (f *sharedInformerFactory) getListObjectForGVR(gvr schema.GroupVersionResource) (client.ObjectList, error) {
 	// Convert GVR to GVK first
-	var gvk schema.GroupVersionKind
-
-	if f.client.RESTMapper() != nil {
-		if k, err := f.client.RESTMapper().KindFor(gvr); err == nil {
-			gvk = k
-		} else {
-			gvk = f.constructGVKFromGVR(gvr)
-		}
-	} else {
-		gvk = f.constructGVKFromGVR(gvr)
-	}
+	gvk := f.kindForGVR(gvr)
 
 	// Construct list GVK
 	listGVK := schema.GroupVersionKind{
@@ -0,0 +1,108 @@
+package informers
+
+import (
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/selection"
+	"k8s.io/client-go/tools/cache"
+
+	"github.com/dtomasi/k1s/core/client"
+)
+
+// allNamespaces is the namespace of index keys that match across namespaces
+const allNamespaces = "__all_namespaces"
+
+// IndexerFunc extracts the values of an indexed field from an object, like
+// controller-runtime's client.IndexerFunc.
+type IndexerFunc func(client.Object) []string
+
+// FieldIndexName returns the name of the informer index of a field.
+func FieldIndexName(field string) string {
+	return "field:" + field
+}
+
+// KeyToNamespacedKey returns the index key of a field value in a namespace.
+// An empty namespace returns the key that matches across all namespaces.
+func KeyToNamespacedKey(namespace, value string) string {
+	if namespace == "" {
+		return allNamespaces + "/" + value
+	}
+	return namespace + "/" + value
+}
+
+// indexFunc adapts extractValue to an informer index function. Each value is
+// indexed for the object's namespace and for all namespaces.
+func indexFunc(extractValue IndexerFunc) cache.IndexFunc {
+	return func(obj interface{}) ([]string, error) {
+		o, ok := obj.(client.Object)
+		if !ok {
+			return nil, fmt.Errorf("object of type %T is not a client.Object", obj)
+		}
+
+		values := extractValue(o)
+		keys := make([]string, 0, 2*len(values))
+		for _, value := range values {
+			keys = append(keys, KeyToNamespacedKey("", value))
+			if o.GetNamespace() != "" {
+				keys = append(keys, KeyToNamespacedKey(o.GetNamespace(), value))
+			}
+		}
+		return keys, nil
+	}
+}
+
+// ByFields returns the objects of an informer's indexer that match a field
+// selector in namespace, or in all namespaces if namespace is empty. Every
+// field of the selector must be indexed with IndexField and matched exactly,
+// as in client.MatchingFields.
+func ByFields(indexer cache.Indexer, namespace string, selector fields.Selector) ([]interface{}, error) {
+	requirements := selector.Requirements()
+	if len(requirements) == 0 {
+		if namespace == "" {
+			return indexer.List(), nil
+		}
+		return indexer.ByIndex(cache.NamespaceIndex, namespace)
+	}
+
+	var result []interface{}
+	for i, requirement := range requirements {
+		if requirement.Operator != selection.Equals && requirement.Operator != selection.DoubleEquals {
+			return nil, fmt.Errorf("field selector %q must be an exact match", requirement.Field)
+		}
+
+		indexName := FieldIndexName(requirement.Field)
+		if _, ok := indexer.GetIndexers()[indexName]; !ok {
+			return nil, fmt.Errorf("field %q is not indexed", requirement.Field)
+		}
+		objs, err := indexer.ByIndex(indexName, KeyToNamespacedKey(namespace, requirement.Value))
+		if err != nil {
+			return nil, err
+		}
+
+		if i == 0 {
+			result = objs
+			continue
+		}
+		result = intersect(result, objs)
+	}
+	return result, nil
+}
+
+// intersect returns the objects of a that are also in b
+func intersect(a, b []interface{}) []interface{} {
+	keys := make(map[string]bool, len(b))
+	for _, obj := range b {
+		if key, err := cache.MetaNamespaceKeyFunc(obj); err == nil {
+			keys[key] = true
+		}
+	}
+
+	result := make([]interface{}, 0, len(a))
+	for _, obj := range a {
+		if key, err := cache.MetaNamespaceKeyFunc(obj); err == nil && keys[key] {
+			result = append(result, obj)
+		}
+	}
+	return result
+}
@@ -0,0 +1,126 @@
+package informers_test
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	k8sstorage "k8s.io/apiserver/pkg/storage"
+
+	"github.com/dtomasi/k1s/core/client"
+	"github.com/dtomasi/k1s/core/informers"
+)
+
+// listStorage serves a fixed set of objects on List
+type listStorage struct {
+	mockStorage
+	items []runtime.Object
+}
+
+func (s *listStorage) List(ctx context.Context, key string, opts k8sstorage.ListOptions, listObj runtime.Object) error {
+	return meta.SetList(listObj, s.items)
+}
+
+func newTestObject(namespace, name, specName string) *TestObject {
+	return &TestObject{
+		ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name, Labels: map[string]string{"app": name}},
+		Spec:       TestSpec{Name: specName},
+	}
+}
+
+var _ = Describe("Indexes and metadata-only informers", func() {
+	var (
+		testClient client.Client
+		testGVR    = schema.GroupVersionResource{Group: "test.k1s.io", Version: "v1", Resource: "testobjects"}
+		stopCh     chan struct{}
+	)
+
+	BeforeEach(func() {
+		scheme := runtime.NewScheme()
+		gv := schema.GroupVersion{Group: "test.k1s.io", Version: "v1"}
+		scheme.AddKnownTypes(gv, &TestObject{})
+		scheme.AddKnownTypeWithName(gv.WithKind("TestObjectList"), &TestList{})
+
+		var err error
+		testClient, err = client.NewClient(client.ClientOptions{
+			Scheme: scheme,
+			Storage: &listStorage{items: []runtime.Object{
+				newTestObject("a", "one", "shared"),
+				newTestObject("a", "two", "unique"),
+				newTestObject("b", "three", "shared"),
+			}},
+			Registry: &mockRegistry{},
+		})
+		Expect(err).NotTo(HaveOccurred())
+		stopCh = make(chan struct{})
+	})
+
+	AfterEach(func() {
+		close(stopCh)
+	})
+
+	specName := func(obj client.Object) []string {
+		return []string{obj.(*TestObject).Spec.Name}
+	}
+
+	startAndSync := func(factory informers.SharedInformerFactory) {
+		factory.Start(stopCh)
+		timeout := make(chan struct{})
+		timer := time.AfterFunc(5*time.Second, func() { close(timeout) })
+		defer timer.Stop()
+		Expect(factory.WaitForCacheSync(timeout)).To(HaveKeyWithValue(testGVR, true))
+	}
+
+	It("should list objects by indexed fields", func() {
+		factory := informers.NewSharedInformerFactory(testClient, 0)
+		defer factory.Shutdown()
+		Expect(factory.IndexField(testGVR, "spec.name", specName)).To(Succeed())
+		startAndSync(factory)
+
+		indexer := factory.InformerFor(testGVR).GetIndexer()
+		objs, err := informers.ByFields(indexer, "", fields.OneTermEqualSelector("spec.name", "shared"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(objs).To(HaveLen(2))
+
+		objs, err = informers.ByFields(indexer, "a", fields.OneTermEqualSelector("spec.name", "shared"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(objs).To(ConsistOf(HaveField("ObjectMeta.Name", "one")))
+	})
+
+	It("should reject selectors on fields without an index", func() {
+		factory := informers.NewSharedInformerFactory(testClient, 0)
+		defer factory.Shutdown()
+		factory.InformerFor(testGVR)
+		startAndSync(factory)
+
+		_, err := informers.ByFields(factory.InformerFor(testGVR).GetIndexer(), "",
+			fields.OneTermEqualSelector("spec.name", "shared"))
+		Expect(err).To(MatchError(ContainSubstring("not indexed")))
+	})
+
+	It("should only cache metadata for metadata-only resources", func() {
+		factory := informers.NewSharedInformerFactoryWithOptions(testClient, 0, informers.WithMetadataOnly(testGVR))
+		defer factory.Shutdown()
+		Expect(factory.IndexField(testGVR, "metadata.labels.app", func(obj client.Object) []string {
+			return []string{obj.GetLabels()["app"]}
+		})).To(Succeed())
+		startAndSync(factory)
+
+		objs, err := informers.ByFields(factory.InformerFor(testGVR).GetIndexer(), "b",
+			fields.OneTermEqualSelector("metadata.labels.app", "three"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(objs).To(HaveLen(1))
+
+		partial, ok := objs[0].(*metav1.PartialObjectMetadata)
+		Expect(ok).To(BeTrue(), "unexpected type %T", objs[0])
+		Expect(partial.Name).To(Equal("three"))
+		Expect(partial.GroupVersionKind()).To(Equal(schema.GroupVersionKind{Group: "test.k1s.io", Version: "v1", Kind: "TestObject"}))
+	})
+})
@@ -0,0 +1,65 @@
+package informers
+
+import (
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// toPartialObjectMetadataList converts a typed list into a list of the
+// metadata of its items, which are of kind gvk
+func toPartialObjectMetadataList(list runtime.Object, gvk schema.GroupVersionKind) (*metav1.PartialObjectMetadataList, error) {
+	listAccessor, err := meta.ListAccessor(list)
+	if err != nil {
+		return nil, err
+	}
+	items, err := meta.ExtractList(list)
+	if err != nil {
+		return nil, fmt.Errorf("failed to extract list items: %w", err)
+	}
+
+	result := &metav1.PartialObjectMetadataList{
+		ListMeta: metav1.ListMeta{
+			ResourceVersion: listAccessor.GetResourceVersion(),
+			Continue:        listAccessor.GetContinue(),
+		},
+		Items: make([]metav1.PartialObjectMetadata, 0, len(items)),
+	}
+	result.SetGroupVersionKind(metav1.SchemeGroupVersion.WithKind("PartialObjectMetadataList"))
+
+	for _, item := range items {
+		accessor, err := meta.Accessor(item)
+		if err != nil {
+			return nil, err
+		}
+		result.Items = append(result.Items, toPartialObjectMetadata(accessor, gvk))
+	}
+	return result, nil
+}
+
+// toPartialObjectMetadata copies the metadata of an object of kind gvk
+func toPartialObjectMetadata(obj metav1.Object, gvk schema.GroupVersionKind) metav1.PartialObjectMetadata {
+	partial := metav1.PartialObjectMetadata{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:                       obj.GetName(),
+			GenerateName:               obj.GetGenerateName(),
+			Namespace:                  obj.GetNamespace(),
+			UID:                        obj.GetUID(),
+			ResourceVersion:            obj.GetResourceVersion(),
+			Generation:                 obj.GetGeneration(),
+			CreationTimestamp:          obj.GetCreationTimestamp(),
+			DeletionTimestamp:          obj.GetDeletionTimestamp(),
+			DeletionGracePeriodSeconds: obj.GetDeletionGracePeriodSeconds(),
+			Labels:                     obj.GetLabels(),
+			Annotations:                obj.GetAnnotations(),
+			OwnerReferences:            obj.GetOwnerReferences(),
+			Finalizers:                 obj.GetFinalizers(),
+			ManagedFields:              obj.GetManagedFields(),
+		},
+	}
+	partial.SetGroupVersionKind(gvk)
+	return *partial.DeepCopy()
+}
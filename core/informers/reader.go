@@ -0,0 +1,191 @@
+package informers
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/tools/cache"
+
+	"github.com/dtomasi/k1s/core/client"
+)
+
+// cacheReader serves client reads from the listers of a SharedInformerFactory
+type cacheReader struct {
+	factory SharedInformerFactory
+	scheme  *runtime.Scheme
+	mapper  meta.RESTMapper
+	stopCh  <-chan struct{}
+
+	mu     sync.Mutex
+	synced map[schema.GroupVersionResource]bool
+}
+
+// NewCacheReader returns a reader that serves Get and List from the shared
+// informers of factory. The informer of a kind is started on its first read,
+// which waits until its cache has synced; informers stop when stopCh is
+// closed. Kinds that are not registered with the scheme of c, and reads of a
+// different type than the informer caches, return client.ErrResourceNotCached.
+// Use it as the cache reader of client.NewDelegatingClient.
+func NewCacheReader(factory SharedInformerFactory, c client.Client, stopCh <-chan struct{}) client.Reader {
+	return &cacheReader{
+		factory: factory,
+		scheme:  c.Scheme(),
+		mapper:  c.RESTMapper(),
+		stopCh:  stopCh,
+		synced:  make(map[schema.GroupVersionResource]bool),
+	}
+}
+
+// Get reads the object from the informer of its kind
+func (r *cacheReader) Get(ctx context.Context, key client.ObjectKey, obj client.Object, _ ...client.GetOption) error {
+	gvk, err := r.kindFor(obj)
+	if err != nil {
+		return err
+	}
+	gvr, informer, err := r.informerFor(ctx, gvk)
+	if err != nil {
+		return err
+	}
+
+	storeKey := key.Name
+	if key.Namespace != "" {
+		storeKey = key.Namespace + "/" + key.Name
+	}
+	item, exists, err := informer.GetIndexer().GetByKey(storeKey)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return apierrors.NewNotFound(gvr.GroupResource(), key.Name)
+	}
+
+	cached, ok := item.(runtime.Object)
+	if !ok || reflect.TypeOf(cached) != reflect.TypeOf(obj) {
+		return client.ErrResourceNotCached{GVK: gvk}
+	}
+	reflect.ValueOf(obj).Elem().Set(reflect.ValueOf(cached.DeepCopyObject()).Elem())
+	return nil
+}
+
+// List reads the objects from the informer of the list's item kind
+func (r *cacheReader) List(ctx context.Context, list client.ObjectList, opts ...client.ListOption) error {
+	options := &client.ListOptions{}
+	for _, opt := range opts {
+		opt.ApplyToList(options)
+	}
+
+	gvk, err := r.kindFor(list)
+	if err != nil {
+		return err
+	}
+	gvk.Kind = strings.TrimSuffix(gvk.Kind, "List")
+	_, informer, err := r.informerFor(ctx, gvk)
+	if err != nil {
+		return err
+	}
+
+	labelSelector, err := metav1.LabelSelectorAsSelector(&options.LabelSelector)
+	if err != nil {
+		return fmt.Errorf("invalid label selector: %w", err)
+	}
+	fieldSelector := fields.Everything()
+	if options.FieldSelector != "" {
+		fieldSelector, err = fields.ParseSelector(options.FieldSelector)
+		if err != nil {
+			return fmt.Errorf("invalid field selector: %w", err)
+		}
+	}
+
+	items, err := ByFields(informer.GetIndexer(), options.Namespace, fieldSelector)
+	if err != nil {
+		return err
+	}
+
+	_, partialList := list.(*metav1.PartialObjectMetadataList)
+	objs := make([]runtime.Object, 0, len(items))
+	for _, item := range items {
+		obj, ok := item.(client.Object)
+		if !ok {
+			return client.ErrResourceNotCached{GVK: gvk}
+		}
+		if _, partial := obj.(*metav1.PartialObjectMetadata); partial != partialList {
+			return client.ErrResourceNotCached{GVK: gvk}
+		}
+		if !labelSelector.Matches(labels.Set(obj.GetLabels())) {
+			continue
+		}
+		objs = append(objs, obj.DeepCopyObject())
+	}
+	return meta.SetList(list, objs)
+}
+
+// kindFor returns the kind of obj, or ErrResourceNotCached for unregistered and unstructured objects
+func (r *cacheReader) kindFor(obj runtime.Object) (schema.GroupVersionKind, error) {
+	switch o := obj.(type) {
+	case *unstructured.Unstructured, *unstructured.UnstructuredList:
+		return schema.GroupVersionKind{}, client.ErrResourceNotCached{GVK: obj.GetObjectKind().GroupVersionKind()}
+	case *metav1.PartialObjectMetadata:
+		return o.GroupVersionKind(), nil
+	case *metav1.PartialObjectMetadataList:
+		return o.GroupVersionKind(), nil
+	}
+
+	gvks, _, err := r.scheme.ObjectKinds(obj)
+	if err != nil || len(gvks) == 0 {
+		return schema.GroupVersionKind{}, client.ErrResourceNotCached{GVK: obj.GetObjectKind().GroupVersionKind()}
+	}
+	return gvks[0], nil
+}
+
+// informerFor returns the informer of a kind, starting it and waiting for its cache to sync on first use
+func (r *cacheReader) informerFor(ctx context.Context, gvk schema.GroupVersionKind) (
+	schema.GroupVersionResource, cache.SharedIndexInformer, error) {
+	gvr, _ := meta.UnsafeGuessKindToResource(gvk)
+	if r.mapper != nil {
+		if mapping, err := r.mapper.RESTMapping(gvk.GroupKind(), gvk.Version); err == nil {
+			gvr = mapping.Resource
+		}
+	}
+	informer := r.factory.InformerFor(gvr)
+
+	r.mu.Lock()
+	synced := r.synced[gvr]
+	if !synced {
+		r.factory.Start(r.stopCh)
+	}
+	r.mu.Unlock()
+	if synced {
+		return gvr, informer, nil
+	}
+
+	// Stop waiting when the read is cancelled or the informers stop
+	waitCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	go func() {
+		select {
+		case <-r.stopCh:
+			cancel()
+		case <-waitCtx.Done():
+		}
+	}()
+
+	if !cache.WaitForCacheSync(waitCtx.Done(), informer.HasSynced) {
+		return gvr, nil, fmt.Errorf("failed to sync the cache of %s", gvr)
+	}
+
+	r.mu.Lock()
+	r.synced[gvr] = true
+	r.mu.Unlock()
+	return gvr, informer, nil
+}
@@ -0,0 +1,100 @@
+package informers_test
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/dtomasi/k1s/core/client"
+	"github.com/dtomasi/k1s/core/informers"
+)
+
+var _ = Describe("CacheReader", func() {
+	var (
+		ctx     context.Context
+		reader  client.Reader
+		factory informers.SharedInformerFactory
+		stopCh  chan struct{}
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		scheme := runtime.NewScheme()
+		gv := schema.GroupVersion{Group: "test.k1s.io", Version: "v1"}
+		scheme.AddKnownTypes(gv, &TestObject{})
+		scheme.AddKnownTypeWithName(gv.WithKind("TestObjectList"), &TestList{})
+
+		c, err := client.NewClient(client.ClientOptions{
+			Scheme: scheme,
+			Storage: &listStorage{items: []runtime.Object{
+				newTestObject("a", "one", "shared"),
+				newTestObject("a", "two", "unique"),
+				newTestObject("b", "three", "shared"),
+			}},
+			Registry: &mockRegistry{},
+		})
+		Expect(err).NotTo(HaveOccurred())
+
+		stopCh = make(chan struct{})
+		factory = informers.NewSharedInformerFactory(c, 0)
+		reader = informers.NewCacheReader(factory, c, stopCh)
+	})
+
+	AfterEach(func() {
+		close(stopCh)
+		factory.Shutdown()
+	})
+
+	It("should start the informer on the first read and get objects from its cache", func() {
+		obj := &TestObject{}
+		Expect(reader.Get(ctx, client.ObjectKey{Namespace: "a", Name: "two"}, obj)).To(Succeed())
+		Expect(obj.Spec.Name).To(Equal("unique"))
+
+		err := reader.Get(ctx, client.ObjectKey{Namespace: "a", Name: "missing"}, &TestObject{})
+		Expect(apierrors.IsNotFound(err)).To(BeTrue(), "unexpected error: %v", err)
+	})
+
+	It("should list objects by namespace, labels and indexed fields", func() {
+		Expect(factory.IndexField(schema.GroupVersionResource{Group: "test.k1s.io", Version: "v1", Resource: "testobjects"},
+			"spec.name", func(obj client.Object) []string {
+				return []string{obj.(*TestObject).Spec.Name}
+			})).To(Succeed())
+
+		list := &TestList{}
+		Expect(reader.List(ctx, list)).To(Succeed())
+		Expect(list.Items).To(HaveLen(3))
+
+		Expect(reader.List(ctx, list, client.InNamespace("a"))).To(Succeed())
+		Expect(list.Items).To(HaveLen(2))
+
+		Expect(reader.List(ctx, list, client.MatchingLabels(map[string]string{"app": "three"}))).To(Succeed())
+		Expect(list.Items).To(ConsistOf(HaveField("ObjectMeta.Name", "three")))
+
+		Expect(reader.List(ctx, list, client.MatchingFields(map[string]string{"spec.name": "shared"}))).To(Succeed())
+		Expect(list.Items).To(HaveLen(2))
+	})
+
+	It("should not serve unstructured objects", func() {
+		obj := &unstructured.Unstructured{}
+		obj.SetGroupVersionKind(schema.GroupVersionKind{Group: "test.k1s.io", Version: "v1", Kind: "TestObject"})
+		err := reader.Get(ctx, client.ObjectKey{Namespace: "a", Name: "one"}, obj)
+		Expect(err).To(BeAssignableToTypeOf(client.ErrResourceNotCached{}))
+	})
+
+	It("should fail reads once the informers are stopped", func() {
+		cancelled, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+		defer cancel()
+		close(stopCh)
+		stopCh = make(chan struct{})
+
+		err := reader.Get(cancelled, client.ObjectKey{Namespace: "a", Name: "one"}, &TestObject{})
+		Expect(err).To(HaveOccurred())
+	})
+})
@@ -0,0 +1,110 @@
+package informers
+
+import (
+	"encoding/json"
+	"reflect"
+	"sync"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/watch"
+)
+
+// typedWatcher converts the objects of a storage watch stream into the type
+// an informer expects. Storage backends may deliver unstructured objects or
+// partial metadata (for example for deletions), which the informer's reflector
+// would otherwise drop. Backends send the same object to all watchers of a
+// write, so every informer caches its own copy.
+type typedWatcher struct {
+	watcher  watch.Interface
+	expected runtime.Object
+	result   chan watch.Event
+	stopCh   chan struct{}
+	stopOnce sync.Once
+}
+
+// newTypedWatcher wraps w so that every event carries a private copy of type expected
+func newTypedWatcher(w watch.Interface, expected runtime.Object) watch.Interface {
+	tw := &typedWatcher{
+		watcher:  w,
+		expected: expected,
+		result:   make(chan watch.Event),
+		stopCh:   make(chan struct{}),
+	}
+	go tw.run(w.ResultChan())
+	return tw
+}
+
+// Stop stops the underlying watcher
+func (w *typedWatcher) Stop() {
+	w.stopOnce.Do(func() {
+		close(w.stopCh)
+		w.watcher.Stop()
+	})
+}
+
+// ResultChan returns the converted events
+func (w *typedWatcher) ResultChan() <-chan watch.Event {
+	return w.result
+}
+
+// run forwards converted events until the underlying stream ends or the watcher is stopped
+func (w *typedWatcher) run(input <-chan watch.Event) {
+	defer close(w.result)
+
+	for {
+		select {
+		case <-w.stopCh:
+			return
+		case event, ok := <-input:
+			if !ok {
+				return
+			}
+			if event.Type != watch.Error && event.Type != watch.Bookmark {
+				event.Object = w.convert(event.Object)
+			}
+			select {
+			case w.result <- event:
+			case <-w.stopCh:
+				return
+			}
+		}
+	}
+}
+
+// convert returns a copy of obj with the expected type, or obj itself if it cannot be converted
+func (w *typedWatcher) convert(obj runtime.Object) runtime.Object {
+	if obj == nil {
+		return nil
+	}
+	// Metadata-only informers cache the metadata of any object
+	if expected, ok := w.expected.(*metav1.PartialObjectMetadata); ok {
+		accessor, err := meta.Accessor(obj)
+		if err != nil {
+			return obj
+		}
+		partial := toPartialObjectMetadata(accessor, expected.GroupVersionKind())
+		return &partial
+	}
+
+	if reflect.TypeOf(obj) == reflect.TypeOf(w.expected) {
+		return obj.DeepCopyObject()
+	}
+
+	// Round-trip through JSON into a fresh object of the expected type
+	data, err := json.Marshal(obj)
+	if err != nil {
+		return obj
+	}
+	out := w.expected.DeepCopyObject()
+	if err := json.Unmarshal(data, out); err != nil {
+		return obj
+	}
+
+	// Keep the type information of the expected kind, not of partial metadata
+	if _, isPartial := obj.(*metav1.PartialObjectMetadata); isPartial {
+		out.GetObjectKind().SetGroupVersionKind(w.expected.GetObjectKind().GroupVersionKind())
+	}
+	return out
+}
@@ -7,8 +7,9 @@ import (
 )
 
 // RegisterCoreResources registers all core Kubernetes resources with the given registry.
-// This function adds Namespace, ConfigMap, Secret, ServiceAccount, and Event resources
-// with their metadata, print columns, and short names.
+// This function adds Namespace, ConfigMap, Secret, ServiceAccount, Event, Lease,
+// RBAC, ValidatingAdmissionPolicy and CustomResourceDefinition resources with
+// their metadata, print columns, and short names.
 func RegisterCoreResources(registry Registry) error {
 	// Get all core resource information
 	coreResourceInfos := corev1.GetCoreResourceInfos()
@@ -43,6 +44,17 @@ func getResourceDescription(kind string) string {
 		"Secret":         "Holds sensitive data such as passwords, OAuth tokens, and ssh keys",
 		"ServiceAccount": "Provides identity for processes that run in pods",
 		"Event":          "Records events in the system for observability and debugging",
+		"Lease":          "Records the holder of a lock, such as the leader of a controller",
+
+		"Role":               "Grants permissions on resources within a namespace",
+		"ClusterRole":        "Grants permissions on resources in all namespaces and on cluster-scoped resources",
+		"RoleBinding":        "Grants the permissions of a role to subjects within a namespace",
+		"ClusterRoleBinding": "Grants the permissions of a cluster role to subjects in all namespaces",
+
+		"ValidatingAdmissionPolicy":        "Describes CEL validations evaluated on writes",
+		"ValidatingAdmissionPolicyBinding": "Binds a validating admission policy to resources and parameters",
+
+		"CustomResourceDefinition": "Describes a custom resource type and its schema",
 	}
 
 	if desc, exists := descriptions[kind]; exists {
@@ -59,6 +71,15 @@ func GetCoreResourceGVRs() []string {
 		"v1/secrets",
 		"v1/serviceaccounts",
 		"v1/events",
+		"events.k8s.io/v1/events",
+		"coordination.k8s.io/v1/leases",
+		"rbac.authorization.k8s.io/v1/roles",
+		"rbac.authorization.k8s.io/v1/clusterroles",
+		"rbac.authorization.k8s.io/v1/rolebindings",
+		"rbac.authorization.k8s.io/v1/clusterrolebindings",
+		"admissionregistration.k8s.io/v1/validatingadmissionpolicies",
+		"admissionregistration.k8s.io/v1/validatingadmissionpolicybindings",
+		"apiextensions.k8s.io/v1/customresourcedefinitions",
 	}
 }
 
@@ -85,6 +85,27 @@ var _ = Describe("Core Resources Registry", func() {
 			Expect(eventConfig.Kind).To(Equal("Event"))
 			Expect(eventConfig.Namespaced).To(BeTrue())
 			Expect(eventConfig.ShortNames).To(ContainElement("ev"))
+
+			// Test RBAC configuration
+			roleConfig, err := testRegistry.GetResourceConfig(typesv1.GetRoleGVR())
+			Expect(err).ToNot(HaveOccurred())
+			Expect(roleConfig.Kind).To(Equal("Role"))
+			Expect(roleConfig.ListKind).To(Equal("RoleList"))
+			Expect(roleConfig.Namespaced).To(BeTrue())
+
+			clusterRoleBindingConfig, err := testRegistry.GetResourceConfig(typesv1.GetClusterRoleBindingGVR())
+			Expect(err).ToNot(HaveOccurred())
+			Expect(clusterRoleBindingConfig.Plural).To(Equal("clusterrolebindings"))
+			Expect(clusterRoleBindingConfig.Namespaced).To(BeFalse())
+			Expect(clusterRoleBindingConfig.Description).To(ContainSubstring("cluster role"))
+
+			// Test CustomResourceDefinition configuration
+			crdConfig, err := testRegistry.GetResourceConfig(typesv1.GetCustomResourceDefinitionGVR())
+			Expect(err).ToNot(HaveOccurred())
+			Expect(crdConfig.Kind).To(Equal("CustomResourceDefinition"))
+			Expect(crdConfig.ListKind).To(Equal("CustomResourceDefinitionList"))
+			Expect(crdConfig.Namespaced).To(BeFalse())
+			Expect(crdConfig.PrintColumns).To(ContainElement(HaveField("Name", "Created At")))
 		})
 
 		It("should register short names correctly", func() {
@@ -107,6 +128,12 @@ var _ = Describe("Core Resources Registry", func() {
 			evGVR, err := testRegistry.GetGVRForShortName("ev")
 			Expect(err).ToNot(HaveOccurred())
 			Expect(evGVR).To(Equal(typesv1.GetEventGVR()))
+
+			for _, shortName := range []string{"crd", "crds"} {
+				crdGVR, err := testRegistry.GetGVRForShortName(shortName)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(crdGVR).To(Equal(typesv1.GetCustomResourceDefinitionGVR()))
+			}
 		})
 
 		It("should register categories correctly", func() {
@@ -162,6 +189,7 @@ var _ = Describe("Core Resources Registry", func() {
 			Expect(registry.IsCoreResource("v1/secrets")).To(BeTrue())
 			Expect(registry.IsCoreResource("v1/serviceaccounts")).To(BeTrue())
 			Expect(registry.IsCoreResource("v1/events")).To(BeTrue())
+			Expect(registry.IsCoreResource("rbac.authorization.k8s.io/v1/clusterroles")).To(BeTrue())
 
 			Expect(registry.IsCoreResource("apps/v1/deployments")).To(BeFalse())
 			Expect(registry.IsCoreResource("custom/v1/mycrd")).To(BeFalse())
@@ -8,7 +8,8 @@ import (
 
 // RegisterCoreResources registers all core Kubernetes resources with the given scheme.
 // This function is called during scheme initialization to automatically register
-// core resources like Namespace, ConfigMap, Secret, ServiceAccount, and Event.
+// core resources like Namespace, ConfigMap, Secret, ServiceAccount, Event, Lease
+// and the RBAC types.
 func RegisterCoreResources(scheme *runtime.Scheme) error {
 	// Register all core resource types using the resources/v1 package
 	return corev1.AddToScheme(scheme)
@@ -4,10 +4,14 @@ package runtime
 import (
 	"fmt"
 	"path/filepath"
+	"time"
 
 	"github.com/dtomasi/k1s/core/storage"
+	cachestorage "github.com/dtomasi/k1s/storage/cache"
+	filesystemstorage "github.com/dtomasi/k1s/storage/filesystem"
 	memorystorage "github.com/dtomasi/k1s/storage/memory"
 	pebblestorage "github.com/dtomasi/k1s/storage/pebble"
+	sqlitestorage "github.com/dtomasi/k1s/storage/sqlite"
 )
 
 // NewDefaultRuntime creates a k1s runtime with sensible defaults for CLI applications.
@@ -44,6 +48,52 @@ func NewRuntimeWithPebbleStorage(dbPath string) (Runtime, error) {
 	return NewRuntime(pebbleStorage)
 }
 
+// NewRuntimeWithSQLiteStorage creates a k1s runtime with SQLite storage.
+// Good for CLI applications that need a single-file database which can be
+// shared safely between several concurrently running processes.
+func NewRuntimeWithSQLiteStorage(dbPath string) (Runtime, error) {
+	return newRuntimeWithSQLiteStorage(dbPath, storage.Config{})
+}
+
+// newRuntimeWithSQLiteStorage creates a SQLite-backed runtime with the given storage config
+func newRuntimeWithSQLiteStorage(dbPath string, config storage.Config, opts ...Option) (Runtime, error) {
+	if dbPath == "" {
+		dbPath = sqlitestorage.DefaultDBPath
+	}
+
+	absPath, err := filepath.Abs(dbPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve database path %s: %w", dbPath, err)
+	}
+
+	sqliteStorage := sqlitestorage.NewSQLiteStorageWithPath(absPath, config)
+
+	return NewRuntime(sqliteStorage, opts...)
+}
+
+// NewRuntimeWithFilesystemStorage creates a k1s runtime that stores resources
+// as YAML files below rootDir. Good for CLI applications whose state should be
+// human-reviewable and committed to git.
+func NewRuntimeWithFilesystemStorage(rootDir string) (Runtime, error) {
+	return newRuntimeWithFilesystemStorage(rootDir, storage.Config{})
+}
+
+// newRuntimeWithFilesystemStorage creates a filesystem-backed runtime with the given storage config
+func newRuntimeWithFilesystemStorage(rootDir string, config storage.Config, opts ...Option) (Runtime, error) {
+	if rootDir == "" {
+		rootDir = filesystemstorage.DefaultRootDir
+	}
+
+	absPath, err := filepath.Abs(rootDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve storage directory %s: %w", rootDir, err)
+	}
+
+	filesystemStorage := filesystemstorage.NewFilesystemStorageWithPath(absPath, config)
+
+	return NewRuntime(filesystemStorage, opts...)
+}
+
 // NewRuntimeWithTenant creates a k1s runtime with the specified tenant ID.
 // Useful for multi-tenant CLI applications or namespace isolation.
 func NewRuntimeWithTenant(tenantID string, dbPath string) (Runtime, error) {
@@ -74,6 +124,10 @@ const (
 	RuntimeTypeMemory RuntimeType = "memory"
 	// RuntimeTypePebble uses PebbleDB storage (persistent)
 	RuntimeTypePebble RuntimeType = "pebble"
+	// RuntimeTypeSQLite uses SQLite storage (persistent, multi-process safe)
+	RuntimeTypeSQLite RuntimeType = "sqlite"
+	// RuntimeTypeFilesystem uses YAML files on disk (persistent, git-friendly)
+	RuntimeTypeFilesystem RuntimeType = "filesystem"
 )
 
 // SimpleRuntimeConfig contains basic configuration for creating a runtime
@@ -81,11 +135,23 @@ type SimpleRuntimeConfig struct {
 	Type     RuntimeType
 	DBPath   string
 	TenantID string
+
+	// CacheSize enables a read-through object cache in front of the storage
+	// backend when greater than zero
+	CacheSize int
+
+	// CacheTTL is how long cached objects are served before being read again.
+	// Zero uses the cache default, a negative value disables expiry.
+	CacheTTL time.Duration
 }
 
 // NewRuntimeFromConfig creates a k1s runtime from simple configuration.
 // This is the most flexible factory function for CLI applications.
 func NewRuntimeFromConfig(config SimpleRuntimeConfig) (Runtime, error) {
+	if config.CacheSize > 0 {
+		return newCachedRuntimeFromConfig(config)
+	}
+
 	switch config.Type {
 	case RuntimeTypeMemory:
 		if config.TenantID != "" {
@@ -99,7 +165,94 @@ func NewRuntimeFromConfig(config SimpleRuntimeConfig) (Runtime, error) {
 		}
 		return NewRuntimeWithPebbleStorage(config.DBPath)
 
+	case RuntimeTypeSQLite:
+		if config.TenantID != "" {
+			return newRuntimeWithSQLiteStorage(config.DBPath, storage.Config{TenantID: config.TenantID},
+				WithTenant(config.TenantID))
+		}
+		return NewRuntimeWithSQLiteStorage(config.DBPath)
+
+	case RuntimeTypeFilesystem:
+		if config.TenantID != "" {
+			return newRuntimeWithFilesystemStorage(config.DBPath, storage.Config{TenantID: config.TenantID},
+				WithTenant(config.TenantID))
+		}
+		return NewRuntimeWithFilesystemStorage(config.DBPath)
+
 	default:
 		return nil, fmt.Errorf("unsupported runtime type: %s", config.Type)
 	}
 }
+
+// newCachedRuntimeFromConfig creates a runtime whose storage backend is
+// wrapped in a read-through cache
+func newCachedRuntimeFromConfig(config SimpleRuntimeConfig) (Runtime, error) {
+	backend, err := NewStorageFromConfig(config)
+	if err != nil {
+		return nil, err
+	}
+
+	var opts []Option
+	if config.TenantID != "" {
+		opts = append(opts, WithTenant(config.TenantID))
+	}
+	return NewRuntime(backend, opts...)
+}
+
+// NewStorageFromConfig creates the storage backend described by a simple
+// configuration, wrapped in a read-through cache when CacheSize is set.
+// Tenant management tooling uses it to operate on a store without creating
+// a full runtime.
+func NewStorageFromConfig(config SimpleRuntimeConfig) (storage.Backend, error) {
+	storageConfig := storage.Config{TenantID: config.TenantID}
+
+	var backend storage.Backend
+	switch config.Type {
+	case RuntimeTypeMemory:
+		backend = memorystorage.NewMemoryStorage(storageConfig)
+
+	case RuntimeTypePebble:
+		dbPath := config.DBPath
+		if dbPath == "" {
+			dbPath = "./data/k1s.db"
+		}
+		absPath, err := filepath.Abs(dbPath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve database path %s: %w", dbPath, err)
+		}
+		backend = pebblestorage.NewPebbleStorageWithPath(absPath, storageConfig)
+
+	case RuntimeTypeSQLite:
+		dbPath := config.DBPath
+		if dbPath == "" {
+			dbPath = sqlitestorage.DefaultDBPath
+		}
+		absPath, err := filepath.Abs(dbPath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve database path %s: %w", dbPath, err)
+		}
+		backend = sqlitestorage.NewSQLiteStorageWithPath(absPath, storageConfig)
+
+	case RuntimeTypeFilesystem:
+		rootDir := config.DBPath
+		if rootDir == "" {
+			rootDir = filesystemstorage.DefaultRootDir
+		}
+		absPath, err := filepath.Abs(rootDir)
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve storage directory %s: %w", rootDir, err)
+		}
+		backend = filesystemstorage.NewFilesystemStorageWithPath(absPath, storageConfig)
+
+	default:
+		return nil, fmt.Errorf("unsupported runtime type: %s", config.Type)
+	}
+
+	if config.CacheSize > 0 {
+		backend = cachestorage.NewCachedStorage(backend, cachestorage.Config{
+			Size: config.CacheSize,
+			TTL:  config.CacheTTL,
+		})
+	}
+	return backend, nil
+}
@@ -5,14 +5,19 @@ import (
 	"fmt"
 	"sync"
 
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 
+	"github.com/dtomasi/k1s/core/admission"
+	"github.com/dtomasi/k1s/core/admission/lifecycle"
+	"github.com/dtomasi/k1s/core/admission/policy"
 	"github.com/dtomasi/k1s/core/client"
 	"github.com/dtomasi/k1s/core/defaulting"
 	"github.com/dtomasi/k1s/core/events"
 	"github.com/dtomasi/k1s/core/events/sinks"
 	"github.com/dtomasi/k1s/core/registry"
 	"github.com/dtomasi/k1s/core/storage"
+	corev1 "github.com/dtomasi/k1s/core/types/v1"
 	"github.com/dtomasi/k1s/core/validation"
 )
 
@@ -71,6 +76,9 @@ type RuntimeOptions struct {
 
 	// DefaultComponent is the default component name for event recording
 	DefaultComponent string
+
+	// EventAPI selects the API of the recorded events. Defaults to core v1.
+	EventAPI events.EventAPI
 }
 
 // Option is a functional option for configuring the runtime
@@ -90,6 +98,9 @@ type Config struct {
 	// DefaultComponent is the default component name for event recording
 	DefaultComponent string
 
+	// EventAPI selects the API of the recorded events
+	EventAPI events.EventAPI
+
 	// EventBroadcasterOptions provides configuration for the event broadcaster
 	EventBroadcasterOptions events.EventBroadcasterOptions
 
@@ -98,6 +109,9 @@ type Config struct {
 
 	// DefaultingConfig provides defaulting configuration (placeholder for future use)
 	DefaultingConfig interface{}
+
+	// Admission is the admission chain of the runtime's client
+	Admission *admission.Chain
 }
 
 // DefaultConfig returns a configuration with sensible defaults
@@ -107,9 +121,14 @@ func DefaultConfig() *Config {
 		EnableRBAC:       false,
 		EnableEvents:     true,
 		DefaultComponent: events.DefaultComponent,
+		EventAPI:         events.EventAPICoreV1,
 		EventBroadcasterOptions: events.EventBroadcasterOptions{
 			QueueSize:      events.DefaultEventQueueSize,
 			MetricsEnabled: true,
+			Retention: events.EventRetentionOptions{
+				Enabled: true,
+				MaxAge:  events.DefaultEventRetentionMaxAge,
+			},
 		},
 	}
 }
@@ -142,6 +161,30 @@ func WithDefaultComponent(component string) Option {
 	}
 }
 
+// WithEventAPI selects the API of the recorded events. EventAPIBoth writes
+// every event as a core v1 and an events.k8s.io/v1 Event.
+func WithEventAPI(api events.EventAPI) Option {
+	return func(c *Config) {
+		c.EventAPI = api
+	}
+}
+
+// WithEventSinks records events to the given sinks in addition to storage,
+// e.g. a sinks.FileSink that is archived by a build job
+func WithEventSinks(eventSinks ...events.EventSink) Option {
+	return func(c *Config) {
+		c.EventBroadcasterOptions.Sinks = append(c.EventBroadcasterOptions.Sinks, eventSinks...)
+	}
+}
+
+// WithEventRetention sets the retention policy of stored events. Events are
+// pruned when the runtime starts and, with an interval, periodically.
+func WithEventRetention(retention events.EventRetentionOptions) Option {
+	return func(c *Config) {
+		c.EventBroadcasterOptions.Retention = retention
+	}
+}
+
 // WithValidation sets validation configuration (placeholder for future use)
 func WithValidation(config interface{}) Option {
 	return func(c *Config) {
@@ -156,6 +199,13 @@ func WithDefaulting(config interface{}) Option {
 	}
 }
 
+// WithAdmission sets the admission chain run by the runtime's client
+func WithAdmission(chain *admission.Chain) Option {
+	return func(c *Config) {
+		c.Admission = chain
+	}
+}
+
 // NewRuntime creates a new k1s runtime instance with dependency injection
 // This is the new primary constructor that takes a storage backend and options
 func NewRuntime(storageBackend storage.Interface, opts ...Option) (Runtime, error) {
@@ -183,12 +233,22 @@ func NewRuntime(storageBackend storage.Interface, opts ...Option) (Runtime, erro
 	// Create resource registry
 	resourceRegistry := registry.NewRegistry()
 
-	// Initialize validation engine (use nil for now)
-	var validator validation.Validator
+	// Validate the built-in core types like the API server
+	validator := validation.NewManager()
+	if err := corev1.RegisterValidationStrategies(validator); err != nil {
+		return nil, fmt.Errorf("failed to register validation strategies: %w", err)
+	}
 
 	// Initialize defaulting engine (use nil for now)
 	var defaulter defaulting.Defaulter
 
+	// Enforce the namespace lifecycle and evaluate stored
+	// ValidatingAdmissionPolicies on every write
+	chain := config.Admission
+	if chain == nil {
+		chain = admission.NewChain()
+	}
+
 	// Create client with all components
 	clientOptions := client.ClientOptions{
 		Scheme:    scheme,
@@ -196,12 +256,15 @@ func NewRuntime(storageBackend storage.Interface, opts ...Option) (Runtime, erro
 		Registry:  resourceRegistry,
 		Validator: validator,
 		Defaulter: defaulter,
+		Admission: chain,
 	}
 
 	client, err := client.NewClient(clientOptions)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create client: %w", err)
 	}
+	lifecycle.Register(chain, client, resourceRegistry)
+	policy.Register(chain, client)
 
 	ctx, cancel := context.WithCancel(context.Background())
 
@@ -213,6 +276,7 @@ func NewRuntime(storageBackend storage.Interface, opts ...Option) (Runtime, erro
 			Scheme:                  scheme,
 			EnableEvents:            config.EnableEvents,
 			DefaultComponent:        config.DefaultComponent,
+			EventAPI:                config.EventAPI,
 			EventBroadcasterOptions: config.EventBroadcasterOptions,
 		},
 		ctx:    ctx,
@@ -270,6 +334,14 @@ func (r *k1sRuntime) Start(ctx context.Context) error {
 		return fmt.Errorf("runtime is already started")
 	}
 
+	// Like a cluster, the runtime always has the default namespace. The
+	// write is not cancelled with ctx, which may end right after starting.
+	if r.scheme.Recognizes(corev1.GetNamespaceGVK()) {
+		if err := lifecycle.EnsureNamespace(context.WithoutCancel(ctx), r.client, metav1.NamespaceDefault); err != nil {
+			return err
+		}
+	}
+
 	// Initialize event system if enabled
 	if r.options.EnableEvents {
 		r.initializeEventSystem()
@@ -288,16 +360,19 @@ func (r *k1sRuntime) Stop(ctx context.Context) error {
 		return nil
 	}
 
-	// Shutdown event system
+	// Shutdown event system, writing the queued events to storage
+	var err error
 	if r.eventBroadcaster != nil {
-		r.eventBroadcaster.Shutdown()
+		if shutdownErr := r.eventBroadcaster.Shutdown(ctx); shutdownErr != nil {
+			err = fmt.Errorf("failed to flush events: %w", shutdownErr)
+		}
 	}
 
 	// Cancel runtime context
 	r.cancel()
 
 	r.started = false
-	return nil
+	return err
 }
 
 // GetClient returns the primary client for runtime operations
@@ -350,13 +425,15 @@ func (r *k1sRuntime) initializeEventSystem() {
 	r.eventSink = sinks.NewStorageSink(sinks.StorageSinkOptions{
 		Client:  r.client,
 		Context: r.ctx,
+		API:     r.options.EventAPI,
 	})
 
 	// Start recording to storage sink
 	r.eventBroadcaster.StartRecordingToSink(r.eventSink)
 
-	// Create event-aware client wrapper
-	eventRecorder := r.GetEventRecorder(r.options.DefaultComponent)
+	// Create event-aware client wrapper. The recorder is created directly since
+	// Start holds the runtime lock.
+	eventRecorder := r.eventBroadcaster.NewRecorder(r.scheme, events.NewEventSource(r.options.DefaultComponent))
 	r.eventAwareClient = client.WithEventRecording(r.client, eventRecorder)
 }
 
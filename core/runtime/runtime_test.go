@@ -2,20 +2,26 @@ package runtime_test
 
 import (
 	"context"
+	"strings"
 	"time"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/watch"
 	k8sstorage "k8s.io/apiserver/pkg/storage"
 
 	"github.com/dtomasi/k1s/core/client"
+	"github.com/dtomasi/k1s/core/events"
+	"github.com/dtomasi/k1s/core/events/sinks"
 	"github.com/dtomasi/k1s/core/registry"
 	k1sruntime "github.com/dtomasi/k1s/core/runtime"
 	"github.com/dtomasi/k1s/core/storage"
+	corev1types "github.com/dtomasi/k1s/core/types/v1"
 )
 
 // Mock storage for testing
@@ -162,6 +168,54 @@ var _ = Describe("Runtime Tests", func() {
 			Expect(runtime).NotTo(BeNil())
 		})
 
+		It("should record events to the sinks of the broadcaster options", func() {
+			scheme := runtime.NewScheme()
+			Expect(corev1types.AddToScheme(scheme)).To(Succeed())
+			reg := registry.NewRegistry()
+			Expect(registry.RegisterCoreResources(reg)).To(Succeed())
+			c, err := client.NewClient(client.ClientOptions{Scheme: scheme, Storage: mockStore, Registry: reg})
+			Expect(err).NotTo(HaveOccurred())
+
+			var out strings.Builder
+			rt, err := k1sruntime.NewRuntimeWithOptions(k1sruntime.RuntimeOptions{
+				Client:       c,
+				Scheme:       scheme,
+				EnableEvents: true,
+				EventBroadcasterOptions: events.EventBroadcasterOptions{
+					Sinks: []events.EventSink{sinks.NewWriterSink(sinks.WriterSinkOptions{Writer: &out})},
+				},
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			ctx := context.Background()
+			Expect(rt.Start(ctx)).To(Succeed())
+			object := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "config", Namespace: "default"}}
+			rt.GetEventRecorder("test-component").Event(object, corev1.EventTypeNormal, "Synced", "config synced")
+			Expect(rt.Stop(ctx)).To(Succeed())
+
+			Expect(out.String()).To(ContainSubstring("Normal Synced ConfigMap default/config: config synced"))
+		})
+
+		It("should create the default namespace when started", func() {
+			scheme := runtime.NewScheme()
+			Expect(corev1types.AddToScheme(scheme)).To(Succeed())
+			reg := registry.NewRegistry()
+			Expect(registry.RegisterCoreResources(reg)).To(Succeed())
+			c, err := client.NewClient(client.ClientOptions{Scheme: scheme, Storage: mockStore, Registry: reg})
+			Expect(err).NotTo(HaveOccurred())
+
+			rt, err := k1sruntime.NewRuntimeWithOptions(k1sruntime.RuntimeOptions{Client: c, Scheme: scheme})
+			Expect(err).NotTo(HaveOccurred())
+
+			ctx := context.Background()
+			Expect(rt.Start(ctx)).To(Succeed())
+			Expect(rt.Stop(ctx)).To(Succeed())
+			Expect(rt.Start(ctx)).To(Succeed())
+			defer func() { Expect(rt.Stop(ctx)).To(Succeed()) }()
+
+			Expect(c.Get(ctx, client.ObjectKey{Name: "default"}, &corev1.Namespace{})).To(Succeed())
+		})
+
 		It("should reject nil storage backend", func() {
 			_, err := k1sruntime.NewRuntime(nil)
 			Expect(err).To(HaveOccurred())
@@ -181,6 +235,61 @@ var _ = Describe("Runtime Tests", func() {
 		})
 	})
 
+	Context("Runtime creation from SimpleRuntimeConfig", func() {
+		It("should wrap the storage backend in a cache when configured", func() {
+			config := k1sruntime.SimpleRuntimeConfig{
+				Type:      k1sruntime.RuntimeTypeMemory,
+				TenantID:  "test-tenant",
+				CacheSize: 16,
+				CacheTTL:  time.Minute,
+			}
+			backend, err := k1sruntime.NewStorageFromConfig(config)
+			Expect(err).NotTo(HaveOccurred())
+			defer func() { _ = backend.Close() }()
+
+			cached, ok := backend.(interface {
+				GetCacheMetrics() (hits, misses, evictions uint64)
+			})
+			Expect(ok).To(BeTrue(), "backend %T is not cached", backend)
+
+			ctx := context.Background()
+			key := "/v1/configmaps/default/config"
+			Expect(backend.Create(ctx, key, corev1types.NewConfigMap("config", "default"), &corev1.ConfigMap{}, 0)).
+				To(Succeed())
+			for i := 0; i < 2; i++ {
+				Expect(backend.Get(ctx, key, k8sstorage.GetOptions{}, &corev1.ConfigMap{})).To(Succeed())
+			}
+			hits, _, _ := cached.GetCacheMetrics()
+			Expect(hits).To(Equal(uint64(1)))
+
+			runtime, err := k1sruntime.NewRuntimeFromConfig(config)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(runtime).NotTo(BeNil())
+		})
+
+		It("should not cache the storage backend by default", func() {
+			backend, err := k1sruntime.NewStorageFromConfig(k1sruntime.SimpleRuntimeConfig{
+				Type: k1sruntime.RuntimeTypeMemory,
+			})
+			Expect(err).NotTo(HaveOccurred())
+			defer func() { _ = backend.Close() }()
+
+			_, ok := backend.(interface {
+				GetCacheMetrics() (hits, misses, evictions uint64)
+			})
+			Expect(ok).To(BeFalse())
+		})
+
+		It("should reject an unsupported runtime type with caching enabled", func() {
+			_, err := k1sruntime.NewRuntimeFromConfig(k1sruntime.SimpleRuntimeConfig{
+				Type:      "unknown",
+				CacheSize: 16,
+			})
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("unsupported runtime type"))
+		})
+	})
+
 	Context("Legacy Runtime creation functions", func() {
 		It("should test CreateRuntimeWithClient", func() {
 			runtime, err := k1sruntime.CreateRuntimeWithClient(testClient, testScheme)
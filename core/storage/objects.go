@@ -0,0 +1,116 @@
+package storage
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/conversion"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apiserver/pkg/storage"
+)
+
+// WatchObject returns the object for the watch event of a write: a new
+// object of the type of like, decoded from the stored data. Watch events
+// must not carry the object of the caller, because watchers such as
+// informers read it asynchronously while the caller may modify it as soon
+// as the write returns.
+func WatchObject(data []byte, like runtime.Object) runtime.Object {
+	t := reflect.TypeOf(like)
+	if t == nil || t.Kind() != reflect.Ptr {
+		return like.DeepCopyObject()
+	}
+	obj, ok := reflect.New(t.Elem()).Interface().(runtime.Object)
+	if !ok || json.Unmarshal(data, obj) != nil {
+		return like.DeepCopyObject()
+	}
+	return obj
+}
+
+// CheckPreconditions validates the UID and resource version preconditions
+// against the current object stored at key. A mismatch is reported as a
+// Conflict error.
+func CheckPreconditions(key string, obj runtime.Object, preconditions *storage.Preconditions) error {
+	if preconditions == nil || (preconditions.UID == nil && preconditions.ResourceVersion == nil) {
+		return nil
+	}
+
+	accessor, err := meta.Accessor(obj)
+	if err != nil {
+		return fmt.Errorf("failed to get object accessor: %w", err)
+	}
+
+	gr := schema.GroupResource{Resource: "objects"} // Generic resource for storage
+
+	// Check UID precondition
+	if preconditions.UID != nil && accessor.GetUID() != *preconditions.UID {
+		return apierrors.NewConflict(gr, key, fmt.Errorf("UID mismatch: expected %s, got %s",
+			*preconditions.UID, accessor.GetUID()))
+	}
+
+	// Check ResourceVersion precondition
+	if preconditions.ResourceVersion != nil && accessor.GetResourceVersion() != *preconditions.ResourceVersion {
+		return apierrors.NewConflict(gr, key, fmt.Errorf("resource version mismatch: expected %s, got %s",
+			*preconditions.ResourceVersion, accessor.GetResourceVersion()))
+	}
+
+	return nil
+}
+
+// SetListItems decodes stored JSON items into the Items field of a list
+// object. A *metav1.List receives the items as raw extensions.
+func SetListItems(listObj runtime.Object, items [][]byte) error {
+	// For generic lists, directly set items as RawExtension
+	if list, ok := listObj.(*metav1.List); ok {
+		list.Items = make([]runtime.RawExtension, len(items))
+		for i, raw := range items {
+			list.Items[i] = runtime.RawExtension{Raw: raw}
+		}
+		return nil
+	}
+
+	itemsPtr, err := meta.GetItemsPtr(listObj)
+	if err != nil {
+		return err
+	}
+	itemsValue, err := conversion.EnforcePtr(itemsPtr)
+	if err != nil {
+		return err
+	}
+
+	elemType := itemsValue.Type().Elem()
+	slice := reflect.MakeSlice(itemsValue.Type(), len(items), len(items))
+	for i, raw := range items {
+		elem := slice.Index(i)
+		var target interface{}
+		if elemType.Kind() == reflect.Ptr {
+			elem.Set(reflect.New(elemType.Elem()))
+			target = elem.Interface()
+		} else {
+			target = elem.Addr().Interface()
+		}
+		if err := json.Unmarshal(raw, target); err != nil {
+			return fmt.Errorf("failed to unmarshal item %d: %w", i, err)
+		}
+	}
+	itemsValue.Set(slice)
+	return nil
+}
+
+// DecodeUnstructured decodes stored JSON into an unstructured object
+func DecodeUnstructured(data []byte) (*unstructured.Unstructured, error) {
+	if data == nil {
+		return nil, errors.New("no stored object")
+	}
+	content := map[string]interface{}{}
+	if err := json.Unmarshal(data, &content); err != nil {
+		return nil, err
+	}
+	return &unstructured.Unstructured{Object: content}, nil
+}
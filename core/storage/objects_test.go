@@ -0,0 +1,91 @@
+package storage_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/dtomasi/k1s/core/storage"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	k8sstorage "k8s.io/apiserver/pkg/storage"
+)
+
+var _ = Describe("Objects", func() {
+	Describe("CheckPreconditions", func() {
+		var pod *corev1.Pod
+
+		BeforeEach(func() {
+			pod = &corev1.Pod{ObjectMeta: metav1.ObjectMeta{
+				Name:            "my-pod",
+				UID:             types.UID("uid-1"),
+				ResourceVersion: "5",
+			}}
+		})
+
+		It("should accept nil preconditions", func() {
+			Expect(storage.CheckPreconditions("pods/my-pod", pod, nil)).To(Succeed())
+		})
+
+		It("should accept matching preconditions", func() {
+			uid := types.UID("uid-1")
+			rv := "5"
+			preconditions := &k8sstorage.Preconditions{UID: &uid, ResourceVersion: &rv}
+			Expect(storage.CheckPreconditions("pods/my-pod", pod, preconditions)).To(Succeed())
+		})
+
+		It("should return a conflict on UID mismatch", func() {
+			uid := types.UID("uid-2")
+			err := storage.CheckPreconditions("pods/my-pod", pod, &k8sstorage.Preconditions{UID: &uid})
+			Expect(apierrors.IsConflict(err)).To(BeTrue())
+		})
+
+		It("should return a conflict on resource version mismatch", func() {
+			rv := "4"
+			err := storage.CheckPreconditions("pods/my-pod", pod, &k8sstorage.Preconditions{ResourceVersion: &rv})
+			Expect(apierrors.IsConflict(err)).To(BeTrue())
+		})
+	})
+
+	Describe("SetListItems", func() {
+		items := [][]byte{
+			[]byte(`{"metadata":{"name":"a"}}`),
+			[]byte(`{"metadata":{"name":"b"}}`),
+		}
+
+		It("should decode items into a typed list", func() {
+			list := &corev1.PodList{}
+			Expect(storage.SetListItems(list, items)).To(Succeed())
+			Expect(list.Items).To(HaveLen(2))
+			Expect(list.Items[0].Name).To(Equal("a"))
+			Expect(list.Items[1].Name).To(Equal("b"))
+		})
+
+		It("should set raw items on a generic list", func() {
+			list := &metav1.List{}
+			Expect(storage.SetListItems(list, items)).To(Succeed())
+			Expect(list.Items).To(HaveLen(2))
+			Expect(list.Items[1].Raw).To(Equal(items[1]))
+		})
+
+		It("should return an error for invalid items", func() {
+			list := &corev1.PodList{}
+			Expect(storage.SetListItems(list, [][]byte{[]byte("{")})).NotTo(Succeed())
+		})
+	})
+
+	Describe("DecodeUnstructured", func() {
+		It("should decode stored JSON", func() {
+			obj, err := storage.DecodeUnstructured([]byte(`{"kind":"Pod","metadata":{"name":"a"}}`))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(obj.GetKind()).To(Equal("Pod"))
+			Expect(obj.GetName()).To(Equal("a"))
+		})
+
+		It("should return an error for missing data", func() {
+			_, err := storage.DecodeUnstructured(nil)
+			Expect(err).To(HaveOccurred())
+		})
+	})
+})
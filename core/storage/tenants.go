@@ -0,0 +1,165 @@
+package storage
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+const (
+	// TenantsKeyPrefix is the key segment under which all tenant data is stored
+	TenantsKeyPrefix = "tenants"
+
+	// MaxTenantIDLength is the maximum length of a tenant ID
+	MaxTenantIDLength = 253
+)
+
+// TenantsResource is the group resource used for tenant errors
+var TenantsResource = schema.GroupResource{Resource: "tenants"}
+
+// Tenant describes a tenant registered with a storage backend
+type Tenant struct {
+	// ID is the unique identifier of the tenant
+	ID string `json:"id"`
+
+	// MaxObjects limits the number of objects the tenant may store. Zero means unlimited.
+	MaxObjects int64 `json:"maxObjects,omitempty"`
+
+	// CreationTimestamp is when the tenant was registered. It is zero for
+	// tenants that hold data but were never registered explicitly.
+	CreationTimestamp metav1.Time `json:"creationTimestamp,omitempty"`
+}
+
+// TenantManager is implemented by storage backends that support tenant
+// lifecycle management. Its methods operate on the whole store, independent
+// of the TenantID the backend instance was configured with.
+type TenantManager interface {
+	// CreateTenant registers a tenant. It returns an AlreadyExists error if
+	// the tenant is already registered.
+	CreateTenant(ctx context.Context, tenant Tenant) error
+
+	// GetTenant returns a tenant. Tenants holding data without being
+	// registered are returned with only their ID set.
+	GetTenant(ctx context.Context, tenantID string) (*Tenant, error)
+
+	// ListTenants returns all registered tenants and all tenants holding data, sorted by ID
+	ListTenants(ctx context.Context) ([]Tenant, error)
+
+	// DeleteTenant removes a tenant together with all of its data. It returns
+	// a NotFound error if the tenant is neither registered nor holds data.
+	DeleteTenant(ctx context.Context, tenantID string) error
+
+	// CountTenant returns the number of objects stored by a tenant
+	CountTenant(ctx context.Context, tenantID string) (int64, error)
+}
+
+// ValidateTenantID checks that a tenant ID can be used as a tenant identifier
+func ValidateTenantID(tenantID string) error {
+	if tenantID == "" {
+		return fmt.Errorf("tenant ID cannot be empty")
+	}
+	if len(tenantID) > MaxTenantIDLength {
+		return fmt.Errorf("tenant ID must be no more than %d characters", MaxTenantIDLength)
+	}
+	for _, r := range tenantID {
+		if r < ' ' || r == 0x7f {
+			return fmt.Errorf("tenant ID %q must not contain control characters", tenantID)
+		}
+	}
+	return nil
+}
+
+// EscapeTenantID encodes a tenant ID as a single key segment or directory
+// name. Path separators, the escape character '%', control characters and a
+// leading '.' are percent-encoded, so that IDs such as "a/b" or ".." can
+// never collide with the keys of another tenant. All other IDs are kept as
+// is, which is how tenant data was stored before IDs were escaped.
+func EscapeTenantID(tenantID string) string {
+	var b strings.Builder
+	for i := 0; i < len(tenantID); i++ {
+		c := tenantID[i]
+		if !needsTenantEscape(c, i) {
+			b.WriteByte(c)
+			continue
+		}
+		fmt.Fprintf(&b, "%%%02X", c)
+	}
+	return b.String()
+}
+
+// UnescapeTenantID decodes a key segment produced by EscapeTenantID
+func UnescapeTenantID(segment string) (string, error) {
+	var b strings.Builder
+	for i := 0; i < len(segment); i++ {
+		c := segment[i]
+		if c != '%' {
+			b.WriteByte(c)
+			continue
+		}
+		if i+2 >= len(segment) {
+			return "", fmt.Errorf("invalid escaped tenant ID %q", segment)
+		}
+		v, err := strconv.ParseUint(segment[i+1:i+3], 16, 8)
+		if err != nil {
+			return "", fmt.Errorf("invalid escaped tenant ID %q: %w", segment, err)
+		}
+		b.WriteByte(byte(v))
+		i += 2
+	}
+	return b.String(), nil
+}
+
+// needsTenantEscape reports whether EscapeTenantID encodes the byte c at index i
+func needsTenantEscape(c byte, i int) bool {
+	return c == '/' || c == '\\' || c == '%' || c < ' ' || c == 0x7f || (c == '.' && i == 0)
+}
+
+// TenantKey returns the key of a tenant's registration record. The tenant's
+// data is stored below TenantKey(tenantID) + "/".
+func TenantKey(tenantID string) string {
+	return TenantsKeyPrefix + "/" + EscapeTenantID(tenantID)
+}
+
+// TenantIDFromKey extracts the tenant ID from a key stored below TenantsKeyPrefix
+func TenantIDFromKey(key string) (string, bool) {
+	rest, ok := strings.CutPrefix(key, TenantsKeyPrefix+"/")
+	if !ok || rest == "" {
+		return "", false
+	}
+	segment, _, _ := strings.Cut(rest, "/")
+	tenantID, err := UnescapeTenantID(segment)
+	if err != nil {
+		return "", false
+	}
+	return tenantID, true
+}
+
+// EncodeTenant serializes a tenant registration record
+func EncodeTenant(tenant Tenant) ([]byte, error) {
+	return json.Marshal(tenant)
+}
+
+// DecodeTenant deserializes a tenant registration record
+func DecodeTenant(data []byte) (*Tenant, error) {
+	tenant := &Tenant{}
+	if err := json.Unmarshal(data, tenant); err != nil {
+		return nil, fmt.Errorf("failed to decode tenant: %w", err)
+	}
+	return tenant, nil
+}
+
+// CheckTenantQuota returns a Forbidden error when storing one more object
+// would exceed the tenant's MaxObjects quota
+func CheckTenantQuota(tenant *Tenant, count int64, key string) error {
+	if tenant == nil || tenant.MaxObjects <= 0 || count < tenant.MaxObjects {
+		return nil
+	}
+	return apierrors.NewForbidden(schema.GroupResource{Resource: "objects"}, key,
+		fmt.Errorf("tenant %q exceeded quota of %d objects", tenant.ID, tenant.MaxObjects))
+}
@@ -0,0 +1,88 @@
+package storage_test
+
+import (
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+
+	"github.com/dtomasi/k1s/core/storage"
+)
+
+var _ = Describe("Tenants", func() {
+	Describe("ValidateTenantID", func() {
+		It("should accept regular IDs", func() {
+			Expect(storage.ValidateTenantID("team-a")).To(Succeed())
+			Expect(storage.ValidateTenantID("team a/b:c")).To(Succeed())
+		})
+
+		It("should reject empty, overlong and control character IDs", func() {
+			Expect(storage.ValidateTenantID("")).NotTo(Succeed())
+			Expect(storage.ValidateTenantID(strings.Repeat("a", storage.MaxTenantIDLength+1))).NotTo(Succeed())
+			Expect(storage.ValidateTenantID("team\n")).NotTo(Succeed())
+		})
+	})
+
+	Describe("EscapeTenantID", func() {
+		It("should keep IDs that are valid key segments", func() {
+			for _, id := range []string{"Team_a-1", "acme.corp", "a:b", "team a", "ä"} {
+				Expect(storage.EscapeTenantID(id)).To(Equal(id))
+			}
+		})
+
+		It("should escape separators and the escape character", func() {
+			Expect(storage.EscapeTenantID("a/b")).To(Equal("a%2Fb"))
+			Expect(storage.EscapeTenantID(`a\b`)).To(Equal("a%5Cb"))
+			Expect(storage.EscapeTenantID("a%2Fb")).To(Equal("a%252Fb"))
+			Expect(storage.EscapeTenantID("..")).To(Equal("%2E."))
+			Expect(storage.EscapeTenantID(".hidden")).To(Equal("%2Ehidden"))
+		})
+
+		It("should round-trip through UnescapeTenantID", func() {
+			for _, id := range []string{"team-a", "a/b", "a:b", "ä ö", "%41"} {
+				unescaped, err := storage.UnescapeTenantID(storage.EscapeTenantID(id))
+				Expect(err).NotTo(HaveOccurred())
+				Expect(unescaped).To(Equal(id))
+			}
+		})
+
+		It("should reject malformed escapes", func() {
+			_, err := storage.UnescapeTenantID("a%2")
+			Expect(err).To(HaveOccurred())
+			_, err = storage.UnescapeTenantID("a%zz")
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Describe("TenantIDFromKey", func() {
+		It("should extract the tenant of registration and data keys", func() {
+			id, ok := storage.TenantIDFromKey(storage.TenantKey("a/b"))
+			Expect(ok).To(BeTrue())
+			Expect(id).To(Equal("a/b"))
+
+			id, ok = storage.TenantIDFromKey(storage.TenantKey("a") + "/v1/items/default/x")
+			Expect(ok).To(BeTrue())
+			Expect(id).To(Equal("a"))
+		})
+
+		It("should ignore keys outside the tenants prefix", func() {
+			_, ok := storage.TenantIDFromKey("/v1/items/default/x")
+			Expect(ok).To(BeFalse())
+		})
+	})
+
+	Describe("CheckTenantQuota", func() {
+		It("should return Forbidden once the quota is reached", func() {
+			tenant := &storage.Tenant{ID: "a", MaxObjects: 2}
+			Expect(storage.CheckTenantQuota(tenant, 1, "key")).To(Succeed())
+			Expect(apierrors.IsForbidden(storage.CheckTenantQuota(tenant, 2, "key"))).To(BeTrue())
+		})
+
+		It("should not limit tenants without a quota", func() {
+			Expect(storage.CheckTenantQuota(&storage.Tenant{ID: "a"}, 1000, "key")).To(Succeed())
+			Expect(storage.CheckTenantQuota(nil, 1000, "key")).To(Succeed())
+		})
+	})
+})
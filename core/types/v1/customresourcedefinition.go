@@ -0,0 +1,130 @@
+package v1
+
+import (
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// CustomResourceDefinition describes a custom resource type so that
+// applications can store and share their type definitions. It directly uses
+// the standard Kubernetes apiextensionsv1.CustomResourceDefinition for full
+// compatibility.
+type CustomResourceDefinition = apiextensionsv1.CustomResourceDefinition
+
+// CustomResourceDefinitionList represents a list of CustomResourceDefinition objects.
+type CustomResourceDefinitionList = apiextensionsv1.CustomResourceDefinitionList
+
+var (
+	// CustomResourceDefinitionGVK is the GroupVersionKind for CustomResourceDefinition.
+	CustomResourceDefinitionGVK = schema.GroupVersionKind{
+		Group:   "apiextensions.k8s.io",
+		Version: "v1",
+		Kind:    "CustomResourceDefinition",
+	}
+
+	// CustomResourceDefinitionGVR is the GroupVersionResource for CustomResourceDefinition.
+	CustomResourceDefinitionGVR = schema.GroupVersionResource{
+		Group:    "apiextensions.k8s.io",
+		Version:  "v1",
+		Resource: "customresourcedefinitions",
+	}
+)
+
+// GetCustomResourceDefinitionGVK returns the GroupVersionKind for CustomResourceDefinition.
+func GetCustomResourceDefinitionGVK() schema.GroupVersionKind {
+	return CustomResourceDefinitionGVK
+}
+
+// GetCustomResourceDefinitionGVR returns the GroupVersionResource for CustomResourceDefinition.
+func GetCustomResourceDefinitionGVR() schema.GroupVersionResource {
+	return CustomResourceDefinitionGVR
+}
+
+// NewCustomResourceDefinition creates a new CustomResourceDefinition for the
+// given group and names. The object name is derived from the plural name and
+// the group, as Kubernetes requires.
+func NewCustomResourceDefinition(group string, names apiextensionsv1.CustomResourceDefinitionNames, scope apiextensionsv1.ResourceScope) *CustomResourceDefinition {
+	return &CustomResourceDefinition{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "apiextensions.k8s.io/v1",
+			Kind:       "CustomResourceDefinition",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name: names.Plural + "." + group,
+		},
+		Spec: apiextensionsv1.CustomResourceDefinitionSpec{
+			Group: group,
+			Names: names,
+			Scope: scope,
+		},
+	}
+}
+
+// IsCustomResourceDefinitionNamespaceScoped returns false as
+// CustomResourceDefinition is a cluster-scoped resource.
+func IsCustomResourceDefinitionNamespaceScoped() bool {
+	return false
+}
+
+// GetCustomResourceDefinitionShortNames returns short names for CustomResourceDefinition resource.
+func GetCustomResourceDefinitionShortNames() []string {
+	return []string{"crd", "crds"}
+}
+
+// GetCustomResourceDefinitionCategories returns categories for CustomResourceDefinition resource.
+func GetCustomResourceDefinitionCategories() []string {
+	return []string{"api-extensions"}
+}
+
+// GetCustomResourceDefinitionPrintColumns returns table columns for CustomResourceDefinition display.
+func GetCustomResourceDefinitionPrintColumns() []metav1.TableColumnDefinition {
+	return []metav1.TableColumnDefinition{
+		{
+			Name:        "Name",
+			Type:        "string",
+			Format:      "name",
+			Description: "Name of the custom resource definition",
+			Priority:    0,
+		},
+		{
+			Name:        "Group",
+			Type:        "string",
+			Format:      "",
+			Description: "API group of the defined resource",
+			Priority:    0,
+		},
+		{
+			Name:        "Kind",
+			Type:        "string",
+			Format:      "",
+			Description: "Kind of the defined resource",
+			Priority:    0,
+		},
+		{
+			Name:        "Scope",
+			Type:        "string",
+			Format:      "",
+			Description: "Whether the defined resource is namespaced or cluster-scoped",
+			Priority:    0,
+		},
+		{
+			Name:        "Created At",
+			Type:        "date",
+			Format:      "",
+			Description: "Creation timestamp of the custom resource definition",
+			Priority:    0,
+		},
+	}
+}
+
+// AddCustomResourceDefinitionToScheme adds CustomResourceDefinition types to the given scheme.
+func AddCustomResourceDefinitionToScheme(scheme *runtime.Scheme) error {
+	scheme.AddKnownTypes(apiextensionsv1.SchemeGroupVersion,
+		&CustomResourceDefinition{},
+		&CustomResourceDefinitionList{},
+	)
+	metav1.AddToGroupVersion(scheme, apiextensionsv1.SchemeGroupVersion)
+	return nil
+}
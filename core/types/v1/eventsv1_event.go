@@ -0,0 +1,189 @@
+package v1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	eventsv1 "k8s.io/api/events/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// EventsV1Event is an event of the events.k8s.io/v1 API, which modern kubectl
+// and client-go consume. Repeated occurrences are recorded in its series.
+// It directly uses the standard Kubernetes eventsv1.Event for full compatibility.
+type EventsV1Event = eventsv1.Event
+
+// EventsV1EventList represents a list of EventsV1Event objects.
+type EventsV1EventList = eventsv1.EventList
+
+var (
+	// EventsV1EventGVK is the GroupVersionKind for EventsV1Event.
+	EventsV1EventGVK = schema.GroupVersionKind{
+		Group:   "events.k8s.io",
+		Version: "v1",
+		Kind:    "Event",
+	}
+
+	// EventsV1EventGVR is the GroupVersionResource for EventsV1Event.
+	EventsV1EventGVR = schema.GroupVersionResource{
+		Group:    "events.k8s.io",
+		Version:  "v1",
+		Resource: "events",
+	}
+)
+
+// GetEventsV1EventGVK returns the GroupVersionKind for EventsV1Event.
+func GetEventsV1EventGVK() schema.GroupVersionKind {
+	return EventsV1EventGVK
+}
+
+// GetEventsV1EventGVR returns the GroupVersionResource for EventsV1Event.
+func GetEventsV1EventGVR() schema.GroupVersionResource {
+	return EventsV1EventGVR
+}
+
+// NewEventsV1Event creates a new EventsV1Event with the given parameters.
+func NewEventsV1Event(namespace, name, reason, action, note string, regarding corev1.ObjectReference, eventType string) *EventsV1Event {
+	return &EventsV1Event{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "events.k8s.io/v1",
+			Kind:       "Event",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+		},
+		EventTime:           metav1.NowMicro(),
+		ReportingController: "k1s",
+		ReportingInstance:   "k1s-runtime",
+		Action:              action,
+		Reason:              reason,
+		Regarding:           regarding,
+		Note:                note,
+		Type:                eventType,
+	}
+}
+
+// IsEventsV1EventNamespaceScoped returns true as EventsV1Event is a namespace-scoped resource.
+func IsEventsV1EventNamespaceScoped() bool {
+	return true
+}
+
+// GetEventsV1EventShortNames returns short names for EventsV1Event resource.
+// The "ev" short name refers to the core Event, like in kubectl.
+func GetEventsV1EventShortNames() []string {
+	return []string{}
+}
+
+// GetEventsV1EventCategories returns categories for EventsV1Event resource.
+func GetEventsV1EventCategories() []string {
+	return []string{}
+}
+
+// GetEventsV1EventPrintColumns returns table columns for EventsV1Event display.
+func GetEventsV1EventPrintColumns() []metav1.TableColumnDefinition {
+	return GetEventPrintColumns()
+}
+
+// GetEventsV1EventPrintColumnsWithNamespace returns table columns for EventsV1Event display including namespace.
+func GetEventsV1EventPrintColumnsWithNamespace() []metav1.TableColumnDefinition {
+	return GetEventPrintColumnsWithNamespace()
+}
+
+// AddEventsV1EventToScheme adds EventsV1Event types to the given scheme.
+func AddEventsV1EventToScheme(scheme *runtime.Scheme) error {
+	scheme.AddKnownTypes(eventsv1.SchemeGroupVersion,
+		&EventsV1Event{},
+		&EventsV1EventList{},
+	)
+	metav1.AddToGroupVersion(scheme, eventsv1.SchemeGroupVersion)
+	return nil
+}
+
+// EventToEventsV1 converts a core Event to the events.k8s.io/v1 API, the way
+// the API server serves core Events through both APIs. Repeated events are
+// converted to a series.
+func EventToEventsV1(event *Event) *EventsV1Event {
+	out := &EventsV1Event{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "events.k8s.io/v1",
+			Kind:       "Event",
+		},
+		ObjectMeta:               *event.ObjectMeta.DeepCopy(),
+		EventTime:                event.EventTime,
+		ReportingController:      event.ReportingController,
+		ReportingInstance:        event.ReportingInstance,
+		Action:                   event.Action,
+		Reason:                   event.Reason,
+		Regarding:                event.InvolvedObject,
+		Related:                  event.Related.DeepCopy(),
+		Note:                     event.Message,
+		Type:                     event.Type,
+		DeprecatedSource:         event.Source,
+		DeprecatedFirstTimestamp: event.FirstTimestamp,
+		DeprecatedLastTimestamp:  event.LastTimestamp,
+		DeprecatedCount:          event.Count,
+	}
+
+	// The events.k8s.io API requires the event time and reporting controller
+	if out.EventTime.IsZero() {
+		out.EventTime = metav1.NewMicroTime(event.FirstTimestamp.Time)
+	}
+	if out.ReportingController == "" {
+		out.ReportingController = event.Source.Component
+	}
+	if out.ReportingInstance == "" {
+		out.ReportingInstance = event.Source.Host
+	}
+
+	switch {
+	case event.Series != nil:
+		out.Series = &eventsv1.EventSeries{
+			Count:            event.Series.Count,
+			LastObservedTime: event.Series.LastObservedTime,
+		}
+	case event.Count > 1:
+		out.Series = &eventsv1.EventSeries{
+			Count:            event.Count,
+			LastObservedTime: metav1.NewMicroTime(event.LastTimestamp.Time),
+		}
+	}
+
+	return out
+}
+
+// EventFromEventsV1 converts an events.k8s.io/v1 Event to a core Event.
+func EventFromEventsV1(event *EventsV1Event) *Event {
+	out := &Event{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "v1",
+			Kind:       "Event",
+		},
+		ObjectMeta:          *event.ObjectMeta.DeepCopy(),
+		InvolvedObject:      event.Regarding,
+		Reason:              event.Reason,
+		Message:             event.Note,
+		Source:              event.DeprecatedSource,
+		FirstTimestamp:      event.DeprecatedFirstTimestamp,
+		LastTimestamp:       event.DeprecatedLastTimestamp,
+		Count:               event.DeprecatedCount,
+		Type:                event.Type,
+		EventTime:           event.EventTime,
+		Action:              event.Action,
+		Related:             event.Related.DeepCopy(),
+		ReportingController: event.ReportingController,
+		ReportingInstance:   event.ReportingInstance,
+	}
+
+	if event.Series != nil {
+		out.Series = &corev1.EventSeries{
+			Count:            event.Series.Count,
+			LastObservedTime: event.Series.LastObservedTime,
+		}
+		if out.Count < event.Series.Count {
+			out.Count = event.Series.Count
+		}
+	}
+
+	return out
+}
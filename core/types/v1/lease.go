@@ -0,0 +1,121 @@
+package v1
+
+import (
+	coordinationv1 "k8s.io/api/coordination/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// Lease records which process holds a lock, such as the leadership of a
+// controller, and until when. It directly uses the standard Kubernetes
+// coordinationv1.Lease for full compatibility.
+type Lease = coordinationv1.Lease
+
+// LeaseList represents a list of Lease objects.
+type LeaseList = coordinationv1.LeaseList
+
+var (
+	// LeaseGVK is the GroupVersionKind for Lease.
+	LeaseGVK = schema.GroupVersionKind{
+		Group:   "coordination.k8s.io",
+		Version: "v1",
+		Kind:    "Lease",
+	}
+
+	// LeaseGVR is the GroupVersionResource for Lease.
+	LeaseGVR = schema.GroupVersionResource{
+		Group:    "coordination.k8s.io",
+		Version:  "v1",
+		Resource: "leases",
+	}
+)
+
+// GetLeaseGVK returns the GroupVersionKind for Lease.
+func GetLeaseGVK() schema.GroupVersionKind {
+	return LeaseGVK
+}
+
+// GetLeaseGVR returns the GroupVersionResource for Lease.
+func GetLeaseGVR() schema.GroupVersionResource {
+	return LeaseGVR
+}
+
+// NewLease creates a new Lease with the given name and namespace.
+func NewLease(name, namespace string) *Lease {
+	return &Lease{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "coordination.k8s.io/v1",
+			Kind:       "Lease",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+		},
+	}
+}
+
+// IsLeaseNamespaceScoped returns true as Lease is a namespace-scoped resource.
+func IsLeaseNamespaceScoped() bool {
+	return true
+}
+
+// GetLeaseShortNames returns short names for Lease resource.
+func GetLeaseShortNames() []string {
+	return []string{}
+}
+
+// GetLeaseCategories returns categories for Lease resource.
+func GetLeaseCategories() []string {
+	return []string{}
+}
+
+// GetLeasePrintColumns returns table columns for Lease display.
+func GetLeasePrintColumns() []metav1.TableColumnDefinition {
+	return []metav1.TableColumnDefinition{
+		{
+			Name:        "Name",
+			Type:        "string",
+			Format:      "name",
+			Description: "Name of the lease",
+			Priority:    0,
+		},
+		{
+			Name:        "Holder",
+			Type:        "string",
+			Format:      "",
+			Description: "Identity of the current holder of the lease",
+			Priority:    0,
+		},
+		{
+			Name:        "Age",
+			Type:        "string",
+			Format:      "",
+			Description: "Age of the lease",
+			Priority:    0,
+		},
+	}
+}
+
+// GetLeasePrintColumnsWithNamespace returns table columns for Lease display including namespace.
+func GetLeasePrintColumnsWithNamespace() []metav1.TableColumnDefinition {
+	return append([]metav1.TableColumnDefinition{
+		{
+			Name:        "Namespace",
+			Type:        "string",
+			Format:      "",
+			Description: "Namespace of the lease",
+			Priority:    0,
+		},
+	}, GetLeasePrintColumns()...)
+}
+
+// AddLeaseToScheme adds Lease types to the given scheme.
+func AddLeaseToScheme(scheme *runtime.Scheme) error {
+	scheme.AddKnownTypes(coordinationv1.SchemeGroupVersion,
+		&Lease{},
+		&LeaseList{},
+	)
+	metav1.AddToGroupVersion(scheme, coordinationv1.SchemeGroupVersion)
+	return nil
+}
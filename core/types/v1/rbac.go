@@ -0,0 +1,338 @@
+package v1
+
+import (
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// Role grants permissions on resources within a namespace. It directly uses
+// the standard Kubernetes rbacv1.Role for full compatibility.
+type Role = rbacv1.Role
+
+// RoleList represents a list of Role objects.
+type RoleList = rbacv1.RoleList
+
+// ClusterRole grants permissions on resources in all namespaces and on
+// cluster-scoped resources. It directly uses the standard Kubernetes
+// rbacv1.ClusterRole for full compatibility.
+type ClusterRole = rbacv1.ClusterRole
+
+// ClusterRoleList represents a list of ClusterRole objects.
+type ClusterRoleList = rbacv1.ClusterRoleList
+
+// RoleBinding grants the permissions of a Role or ClusterRole to subjects
+// within a namespace. It directly uses the standard Kubernetes
+// rbacv1.RoleBinding for full compatibility.
+type RoleBinding = rbacv1.RoleBinding
+
+// RoleBindingList represents a list of RoleBinding objects.
+type RoleBindingList = rbacv1.RoleBindingList
+
+// ClusterRoleBinding grants the permissions of a ClusterRole to subjects in
+// all namespaces. It directly uses the standard Kubernetes
+// rbacv1.ClusterRoleBinding for full compatibility.
+type ClusterRoleBinding = rbacv1.ClusterRoleBinding
+
+// ClusterRoleBindingList represents a list of ClusterRoleBinding objects.
+type ClusterRoleBindingList = rbacv1.ClusterRoleBindingList
+
+var (
+	// RoleGVK is the GroupVersionKind for Role.
+	RoleGVK = schema.GroupVersionKind{
+		Group:   "rbac.authorization.k8s.io",
+		Version: "v1",
+		Kind:    "Role",
+	}
+
+	// RoleGVR is the GroupVersionResource for Role.
+	RoleGVR = schema.GroupVersionResource{
+		Group:    "rbac.authorization.k8s.io",
+		Version:  "v1",
+		Resource: "roles",
+	}
+
+	// ClusterRoleGVK is the GroupVersionKind for ClusterRole.
+	ClusterRoleGVK = schema.GroupVersionKind{
+		Group:   "rbac.authorization.k8s.io",
+		Version: "v1",
+		Kind:    "ClusterRole",
+	}
+
+	// ClusterRoleGVR is the GroupVersionResource for ClusterRole.
+	ClusterRoleGVR = schema.GroupVersionResource{
+		Group:    "rbac.authorization.k8s.io",
+		Version:  "v1",
+		Resource: "clusterroles",
+	}
+
+	// RoleBindingGVK is the GroupVersionKind for RoleBinding.
+	RoleBindingGVK = schema.GroupVersionKind{
+		Group:   "rbac.authorization.k8s.io",
+		Version: "v1",
+		Kind:    "RoleBinding",
+	}
+
+	// RoleBindingGVR is the GroupVersionResource for RoleBinding.
+	RoleBindingGVR = schema.GroupVersionResource{
+		Group:    "rbac.authorization.k8s.io",
+		Version:  "v1",
+		Resource: "rolebindings",
+	}
+
+	// ClusterRoleBindingGVK is the GroupVersionKind for ClusterRoleBinding.
+	ClusterRoleBindingGVK = schema.GroupVersionKind{
+		Group:   "rbac.authorization.k8s.io",
+		Version: "v1",
+		Kind:    "ClusterRoleBinding",
+	}
+
+	// ClusterRoleBindingGVR is the GroupVersionResource for ClusterRoleBinding.
+	ClusterRoleBindingGVR = schema.GroupVersionResource{
+		Group:    "rbac.authorization.k8s.io",
+		Version:  "v1",
+		Resource: "clusterrolebindings",
+	}
+)
+
+// GetRoleGVK returns the GroupVersionKind for Role.
+func GetRoleGVK() schema.GroupVersionKind {
+	return RoleGVK
+}
+
+// GetRoleGVR returns the GroupVersionResource for Role.
+func GetRoleGVR() schema.GroupVersionResource {
+	return RoleGVR
+}
+
+// GetClusterRoleGVK returns the GroupVersionKind for ClusterRole.
+func GetClusterRoleGVK() schema.GroupVersionKind {
+	return ClusterRoleGVK
+}
+
+// GetClusterRoleGVR returns the GroupVersionResource for ClusterRole.
+func GetClusterRoleGVR() schema.GroupVersionResource {
+	return ClusterRoleGVR
+}
+
+// GetRoleBindingGVK returns the GroupVersionKind for RoleBinding.
+func GetRoleBindingGVK() schema.GroupVersionKind {
+	return RoleBindingGVK
+}
+
+// GetRoleBindingGVR returns the GroupVersionResource for RoleBinding.
+func GetRoleBindingGVR() schema.GroupVersionResource {
+	return RoleBindingGVR
+}
+
+// GetClusterRoleBindingGVK returns the GroupVersionKind for ClusterRoleBinding.
+func GetClusterRoleBindingGVK() schema.GroupVersionKind {
+	return ClusterRoleBindingGVK
+}
+
+// GetClusterRoleBindingGVR returns the GroupVersionResource for ClusterRoleBinding.
+func GetClusterRoleBindingGVR() schema.GroupVersionResource {
+	return ClusterRoleBindingGVR
+}
+
+// NewRole creates a new Role with the given name, namespace and rules.
+func NewRole(name, namespace string, rules ...rbacv1.PolicyRule) *Role {
+	return &Role{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "rbac.authorization.k8s.io/v1",
+			Kind:       "Role",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+		},
+		Rules: rules,
+	}
+}
+
+// NewClusterRole creates a new ClusterRole with the given name and rules.
+func NewClusterRole(name string, rules ...rbacv1.PolicyRule) *ClusterRole {
+	return &ClusterRole{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "rbac.authorization.k8s.io/v1",
+			Kind:       "ClusterRole",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name: name,
+		},
+		Rules: rules,
+	}
+}
+
+// NewRoleBinding creates a new RoleBinding with the given name and namespace
+// that binds the role referenced by roleRef to the given subjects.
+func NewRoleBinding(name, namespace string, roleRef rbacv1.RoleRef, subjects ...rbacv1.Subject) *RoleBinding {
+	return &RoleBinding{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "rbac.authorization.k8s.io/v1",
+			Kind:       "RoleBinding",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+		},
+		RoleRef:  roleRef,
+		Subjects: subjects,
+	}
+}
+
+// NewClusterRoleBinding creates a new ClusterRoleBinding with the given name
+// that binds the named ClusterRole to the given subjects.
+func NewClusterRoleBinding(name, clusterRoleName string, subjects ...rbacv1.Subject) *ClusterRoleBinding {
+	return &ClusterRoleBinding{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "rbac.authorization.k8s.io/v1",
+			Kind:       "ClusterRoleBinding",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name: name,
+		},
+		RoleRef: rbacv1.RoleRef{
+			APIGroup: rbacv1.GroupName,
+			Kind:     "ClusterRole",
+			Name:     clusterRoleName,
+		},
+		Subjects: subjects,
+	}
+}
+
+// IsRoleNamespaceScoped returns true as Role and RoleBinding are
+// namespace-scoped resources.
+func IsRoleNamespaceScoped() bool {
+	return true
+}
+
+// IsClusterRoleNamespaceScoped returns false as ClusterRole and
+// ClusterRoleBinding are cluster-scoped resources.
+func IsClusterRoleNamespaceScoped() bool {
+	return false
+}
+
+// GetRBACShortNames returns short names for the RBAC resources, which have
+// none in Kubernetes.
+func GetRBACShortNames() []string {
+	return []string{}
+}
+
+// GetRBACCategories returns categories for the RBAC resources.
+func GetRBACCategories() []string {
+	return []string{}
+}
+
+// GetRolePrintColumns returns table columns for Role and ClusterRole display.
+func GetRolePrintColumns() []metav1.TableColumnDefinition {
+	return []metav1.TableColumnDefinition{
+		{
+			Name:        "Name",
+			Type:        "string",
+			Format:      "name",
+			Description: "Name of the role",
+			Priority:    0,
+		},
+		{
+			Name:        "Created At",
+			Type:        "date",
+			Format:      "",
+			Description: "Creation timestamp of the role",
+			Priority:    0,
+		},
+	}
+}
+
+// GetRolePrintColumnsWithNamespace returns table columns for Role display including namespace.
+func GetRolePrintColumnsWithNamespace() []metav1.TableColumnDefinition {
+	return append([]metav1.TableColumnDefinition{
+		{
+			Name:        "Namespace",
+			Type:        "string",
+			Format:      "",
+			Description: "Namespace of the role",
+			Priority:    0,
+		},
+	}, GetRolePrintColumns()...)
+}
+
+// GetRoleBindingPrintColumns returns table columns for RoleBinding and
+// ClusterRoleBinding display.
+func GetRoleBindingPrintColumns() []metav1.TableColumnDefinition {
+	return []metav1.TableColumnDefinition{
+		{
+			Name:        "Name",
+			Type:        "string",
+			Format:      "name",
+			Description: "Name of the binding",
+			Priority:    0,
+		},
+		{
+			Name:        "Role",
+			Type:        "string",
+			Format:      "",
+			Description: "Kind and name of the bound role",
+			Priority:    0,
+		},
+		{
+			Name:        "Age",
+			Type:        "string",
+			Format:      "",
+			Description: "Age of the binding",
+			Priority:    0,
+		},
+		{
+			Name:        "Users",
+			Type:        "string",
+			Format:      "",
+			Description: "Users bound to the role",
+			Priority:    1,
+		},
+		{
+			Name:        "Groups",
+			Type:        "string",
+			Format:      "",
+			Description: "Groups bound to the role",
+			Priority:    1,
+		},
+		{
+			Name:        "ServiceAccounts",
+			Type:        "string",
+			Format:      "",
+			Description: "Service accounts bound to the role",
+			Priority:    1,
+		},
+	}
+}
+
+// GetRoleBindingPrintColumnsWithNamespace returns table columns for RoleBinding display including namespace.
+func GetRoleBindingPrintColumnsWithNamespace() []metav1.TableColumnDefinition {
+	return append([]metav1.TableColumnDefinition{
+		{
+			Name:        "Namespace",
+			Type:        "string",
+			Format:      "",
+			Description: "Namespace of the binding",
+			Priority:    0,
+		},
+	}, GetRoleBindingPrintColumns()...)
+}
+
+// AddRBACToScheme adds Role, ClusterRole, RoleBinding and ClusterRoleBinding
+// types to the given scheme.
+func AddRBACToScheme(scheme *runtime.Scheme) error {
+	scheme.AddKnownTypes(rbacv1.SchemeGroupVersion,
+		&Role{},
+		&RoleList{},
+		&ClusterRole{},
+		&ClusterRoleList{},
+		&RoleBinding{},
+		&RoleBindingList{},
+		&ClusterRoleBinding{},
+		&ClusterRoleBindingList{},
+	)
+	metav1.AddToGroupVersion(scheme, rbacv1.SchemeGroupVersion)
+	return nil
+}
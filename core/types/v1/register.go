@@ -10,7 +10,9 @@ var SchemeGroupVersion = schema.GroupVersion{Group: "", Version: "v1"}
 
 // AddToScheme adds all core resource types to the given scheme.
 // This function registers all the core Kubernetes resource types:
-// Namespace, ConfigMap, Secret, ServiceAccount, and Event.
+// Namespace, ConfigMap, Secret, ServiceAccount, Event (core and
+// events.k8s.io), Lease, the RBAC types, the ValidatingAdmissionPolicy
+// types, and CustomResourceDefinition.
 func AddToScheme(s *runtime.Scheme) error {
 	// Add Namespace types
 	if err := AddNamespaceToScheme(s); err != nil {
@@ -37,6 +39,31 @@ func AddToScheme(s *runtime.Scheme) error {
 		return err
 	}
 
+	// Add events.k8s.io Event types
+	if err := AddEventsV1EventToScheme(s); err != nil {
+		return err
+	}
+
+	// Add Lease types
+	if err := AddLeaseToScheme(s); err != nil {
+		return err
+	}
+
+	// Add RBAC types
+	if err := AddRBACToScheme(s); err != nil {
+		return err
+	}
+
+	// Add ValidatingAdmissionPolicy types
+	if err := AddValidatingAdmissionPolicyToScheme(s); err != nil {
+		return err
+	}
+
+	// Add CustomResourceDefinition types
+	if err := AddCustomResourceDefinitionToScheme(s); err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -58,6 +85,15 @@ func GetAllGVKs() []schema.GroupVersionKind {
 		GetSecretGVK(),
 		GetServiceAccountGVK(),
 		GetEventGVK(),
+		GetEventsV1EventGVK(),
+		GetLeaseGVK(),
+		GetRoleGVK(),
+		GetClusterRoleGVK(),
+		GetRoleBindingGVK(),
+		GetClusterRoleBindingGVK(),
+		GetValidatingAdmissionPolicyGVK(),
+		GetValidatingAdmissionPolicyBindingGVK(),
+		GetCustomResourceDefinitionGVK(),
 	}
 }
 
@@ -69,6 +105,15 @@ func GetAllGVRs() []schema.GroupVersionResource {
 		GetSecretGVR(),
 		GetServiceAccountGVR(),
 		GetEventGVR(),
+		GetEventsV1EventGVR(),
+		GetLeaseGVR(),
+		GetRoleGVR(),
+		GetClusterRoleGVR(),
+		GetRoleBindingGVR(),
+		GetClusterRoleBindingGVR(),
+		GetValidatingAdmissionPolicyGVR(),
+		GetValidatingAdmissionPolicyBindingGVR(),
+		GetCustomResourceDefinitionGVR(),
 	}
 }
 
@@ -80,6 +125,18 @@ func GetGVKToGVRMappings() map[schema.GroupVersionKind]schema.GroupVersionResour
 		GetSecretGVK():         GetSecretGVR(),
 		GetServiceAccountGVK(): GetServiceAccountGVR(),
 		GetEventGVK():          GetEventGVR(),
+		GetEventsV1EventGVK():  GetEventsV1EventGVR(),
+		GetLeaseGVK():          GetLeaseGVR(),
+
+		GetRoleGVK():               GetRoleGVR(),
+		GetClusterRoleGVK():        GetClusterRoleGVR(),
+		GetRoleBindingGVK():        GetRoleBindingGVR(),
+		GetClusterRoleBindingGVK(): GetClusterRoleBindingGVR(),
+
+		GetValidatingAdmissionPolicyGVK():        GetValidatingAdmissionPolicyGVR(),
+		GetValidatingAdmissionPolicyBindingGVK(): GetValidatingAdmissionPolicyBindingGVR(),
+
+		GetCustomResourceDefinitionGVK(): GetCustomResourceDefinitionGVR(),
 	}
 }
 
@@ -91,5 +148,17 @@ func GetGVRToGVKMappings() map[schema.GroupVersionResource]schema.GroupVersionKi
 		GetSecretGVR():         GetSecretGVK(),
 		GetServiceAccountGVR(): GetServiceAccountGVK(),
 		GetEventGVR():          GetEventGVK(),
+		GetEventsV1EventGVR():  GetEventsV1EventGVK(),
+		GetLeaseGVR():          GetLeaseGVK(),
+
+		GetRoleGVR():               GetRoleGVK(),
+		GetClusterRoleGVR():        GetClusterRoleGVK(),
+		GetRoleBindingGVR():        GetRoleBindingGVK(),
+		GetClusterRoleBindingGVR(): GetClusterRoleBindingGVK(),
+
+		GetValidatingAdmissionPolicyGVR():        GetValidatingAdmissionPolicyGVK(),
+		GetValidatingAdmissionPolicyBindingGVR(): GetValidatingAdmissionPolicyBindingGVK(),
+
+		GetCustomResourceDefinitionGVR(): GetCustomResourceDefinitionGVK(),
 	}
 }
@@ -61,6 +61,46 @@ func NewOpaqueSecret(name, namespace string) *Secret {
 	return NewSecret(name, namespace, corev1.SecretTypeOpaque)
 }
 
+// NewTLSSecret creates a new Secret with kubernetes.io/tls type holding a
+// PEM encoded certificate and private key.
+func NewTLSSecret(name, namespace string, cert, key []byte) *Secret {
+	return NewSecretWithData(name, namespace, corev1.SecretTypeTLS, map[string][]byte{
+		corev1.TLSCertKey:       cert,
+		corev1.TLSPrivateKeyKey: key,
+	})
+}
+
+// NewBasicAuthSecret creates a new Secret with kubernetes.io/basic-auth type.
+func NewBasicAuthSecret(name, namespace, username, password string) *Secret {
+	return NewSecretWithData(name, namespace, corev1.SecretTypeBasicAuth, map[string][]byte{
+		corev1.BasicAuthUsernameKey: []byte(username),
+		corev1.BasicAuthPasswordKey: []byte(password),
+	})
+}
+
+// NewSSHAuthSecret creates a new Secret with kubernetes.io/ssh-auth type.
+func NewSSHAuthSecret(name, namespace string, privateKey []byte) *Secret {
+	return NewSecretWithData(name, namespace, corev1.SecretTypeSSHAuth, map[string][]byte{
+		corev1.SSHAuthPrivateKey: privateKey,
+	})
+}
+
+// NewDockerConfigJSONSecret creates a new Secret with kubernetes.io/dockerconfigjson
+// type holding the content of a ~/.docker/config.json file.
+func NewDockerConfigJSONSecret(name, namespace string, dockerConfigJSON []byte) *Secret {
+	return NewSecretWithData(name, namespace, corev1.SecretTypeDockerConfigJson, map[string][]byte{
+		corev1.DockerConfigJsonKey: dockerConfigJSON,
+	})
+}
+
+// NewServiceAccountTokenSecret creates a new Secret with
+// kubernetes.io/service-account-token type for the named service account.
+func NewServiceAccountTokenSecret(name, namespace, serviceAccountName string) *Secret {
+	secret := NewSecret(name, namespace, corev1.SecretTypeServiceAccountToken)
+	secret.Annotations = map[string]string{corev1.ServiceAccountNameKey: serviceAccountName}
+	return secret
+}
+
 // NewSecretWithData creates a new Secret with the given name, namespace, type and data.
 func NewSecretWithData(name, namespace string, secretType corev1.SecretType, data map[string][]byte) *Secret {
 	secret := NewSecret(name, namespace, secretType)
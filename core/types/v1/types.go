@@ -27,7 +27,8 @@ type ResourceInfo struct {
 	PrintColumnsWithNamespace []metav1.TableColumnDefinition
 }
 
-// GetCoreResourceInfos returns metadata for all core resource types.
+// GetCoreResourceInfos returns metadata for all core resource types, keyed by
+// kind. Kinds outside the core group are qualified with their group.
 func GetCoreResourceInfos() map[string]ResourceInfo {
 	return map[string]ResourceInfo{
 		"Namespace": {
@@ -85,6 +86,105 @@ func GetCoreResourceInfos() map[string]ResourceInfo {
 			PrintColumns:              GetEventPrintColumns(),
 			PrintColumnsWithNamespace: GetEventPrintColumnsWithNamespace(),
 		},
+		"Event.events.k8s.io": {
+			GVK:                       GetEventsV1EventGVK(),
+			GVR:                       GetEventsV1EventGVR(),
+			Singular:                  "event",
+			Plural:                    "events",
+			ShortNames:                GetEventsV1EventShortNames(),
+			Categories:                GetEventsV1EventCategories(),
+			NamespaceScoped:           IsEventsV1EventNamespaceScoped(),
+			PrintColumns:              GetEventsV1EventPrintColumns(),
+			PrintColumnsWithNamespace: GetEventsV1EventPrintColumnsWithNamespace(),
+		},
+		"Lease": {
+			GVK:                       GetLeaseGVK(),
+			GVR:                       GetLeaseGVR(),
+			Singular:                  "lease",
+			Plural:                    "leases",
+			ShortNames:                GetLeaseShortNames(),
+			Categories:                GetLeaseCategories(),
+			NamespaceScoped:           IsLeaseNamespaceScoped(),
+			PrintColumns:              GetLeasePrintColumns(),
+			PrintColumnsWithNamespace: GetLeasePrintColumnsWithNamespace(),
+		},
+		"Role": {
+			GVK:                       GetRoleGVK(),
+			GVR:                       GetRoleGVR(),
+			Singular:                  "role",
+			Plural:                    "roles",
+			ShortNames:                GetRBACShortNames(),
+			Categories:                GetRBACCategories(),
+			NamespaceScoped:           IsRoleNamespaceScoped(),
+			PrintColumns:              GetRolePrintColumns(),
+			PrintColumnsWithNamespace: GetRolePrintColumnsWithNamespace(),
+		},
+		"ClusterRole": {
+			GVK:                       GetClusterRoleGVK(),
+			GVR:                       GetClusterRoleGVR(),
+			Singular:                  "clusterrole",
+			Plural:                    "clusterroles",
+			ShortNames:                GetRBACShortNames(),
+			Categories:                GetRBACCategories(),
+			NamespaceScoped:           IsClusterRoleNamespaceScoped(),
+			PrintColumns:              GetRolePrintColumns(),
+			PrintColumnsWithNamespace: GetRolePrintColumns(), // Cluster-scoped
+		},
+		"RoleBinding": {
+			GVK:                       GetRoleBindingGVK(),
+			GVR:                       GetRoleBindingGVR(),
+			Singular:                  "rolebinding",
+			Plural:                    "rolebindings",
+			ShortNames:                GetRBACShortNames(),
+			Categories:                GetRBACCategories(),
+			NamespaceScoped:           IsRoleNamespaceScoped(),
+			PrintColumns:              GetRoleBindingPrintColumns(),
+			PrintColumnsWithNamespace: GetRoleBindingPrintColumnsWithNamespace(),
+		},
+		"ClusterRoleBinding": {
+			GVK:                       GetClusterRoleBindingGVK(),
+			GVR:                       GetClusterRoleBindingGVR(),
+			Singular:                  "clusterrolebinding",
+			Plural:                    "clusterrolebindings",
+			ShortNames:                GetRBACShortNames(),
+			Categories:                GetRBACCategories(),
+			NamespaceScoped:           IsClusterRoleNamespaceScoped(),
+			PrintColumns:              GetRoleBindingPrintColumns(),
+			PrintColumnsWithNamespace: GetRoleBindingPrintColumns(), // Cluster-scoped
+		},
+		"ValidatingAdmissionPolicy": {
+			GVK:                       GetValidatingAdmissionPolicyGVK(),
+			GVR:                       GetValidatingAdmissionPolicyGVR(),
+			Singular:                  "validatingadmissionpolicy",
+			Plural:                    "validatingadmissionpolicies",
+			ShortNames:                GetValidatingAdmissionPolicyShortNames(),
+			Categories:                GetValidatingAdmissionPolicyCategories(),
+			NamespaceScoped:           IsValidatingAdmissionPolicyNamespaceScoped(),
+			PrintColumns:              GetValidatingAdmissionPolicyPrintColumns(),
+			PrintColumnsWithNamespace: GetValidatingAdmissionPolicyPrintColumns(), // Cluster-scoped
+		},
+		"ValidatingAdmissionPolicyBinding": {
+			GVK:                       GetValidatingAdmissionPolicyBindingGVK(),
+			GVR:                       GetValidatingAdmissionPolicyBindingGVR(),
+			Singular:                  "validatingadmissionpolicybinding",
+			Plural:                    "validatingadmissionpolicybindings",
+			ShortNames:                GetValidatingAdmissionPolicyBindingShortNames(),
+			Categories:                GetValidatingAdmissionPolicyCategories(),
+			NamespaceScoped:           IsValidatingAdmissionPolicyNamespaceScoped(),
+			PrintColumns:              GetValidatingAdmissionPolicyBindingPrintColumns(),
+			PrintColumnsWithNamespace: GetValidatingAdmissionPolicyBindingPrintColumns(), // Cluster-scoped
+		},
+		"CustomResourceDefinition": {
+			GVK:                       GetCustomResourceDefinitionGVK(),
+			GVR:                       GetCustomResourceDefinitionGVR(),
+			Singular:                  "customresourcedefinition",
+			Plural:                    "customresourcedefinitions",
+			ShortNames:                GetCustomResourceDefinitionShortNames(),
+			Categories:                GetCustomResourceDefinitionCategories(),
+			NamespaceScoped:           IsCustomResourceDefinitionNamespaceScoped(),
+			PrintColumns:              GetCustomResourceDefinitionPrintColumns(),
+			PrintColumnsWithNamespace: GetCustomResourceDefinitionPrintColumns(), // Cluster-scoped
+		},
 	}
 }
 
@@ -5,6 +5,8 @@ import (
 	. "github.com/onsi/gomega"
 
 	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 
 	typesv1 "github.com/dtomasi/k1s/core/types/v1"
@@ -202,6 +204,126 @@ var _ = Describe("Core Types", func() {
 		})
 	})
 
+	Describe("EventsV1Event", func() {
+		var event *typesv1.Event
+
+		BeforeEach(func() {
+			involvedObject := corev1.ObjectReference{Kind: "ConfigMap", Namespace: "default", Name: "config"}
+			event = typesv1.NewWarningEvent("default", "config.1", "Failed", "sync failed", involvedObject)
+			event.Source = corev1.EventSource{Component: "controller", Host: "node"}
+			event.ReportingController = ""
+			event.ReportingInstance = ""
+			event.Count = 3
+		})
+
+		It("should return correct GVK and GVR", func() {
+			Expect(typesv1.GetEventsV1EventGVK()).To(Equal(schema.GroupVersionKind{Group: "events.k8s.io", Version: "v1", Kind: "Event"}))
+			Expect(typesv1.GetEventsV1EventGVR()).To(Equal(schema.GroupVersionResource{Group: "events.k8s.io", Version: "v1", Resource: "events"}))
+
+			info, found := typesv1.GetResourceInfoByGVR(typesv1.GetEventsV1EventGVR())
+			Expect(found).To(BeTrue())
+			Expect(info.NamespaceScoped).To(BeTrue())
+		})
+
+		It("should convert repeated core events to a series", func() {
+			converted := typesv1.EventToEventsV1(event)
+
+			Expect(converted.APIVersion).To(Equal("events.k8s.io/v1"))
+			Expect(converted.Regarding).To(Equal(event.InvolvedObject))
+			Expect(converted.Note).To(Equal("sync failed"))
+			Expect(converted.ReportingController).To(Equal("controller"))
+			Expect(converted.ReportingInstance).To(Equal("node"))
+			Expect(converted.EventTime.IsZero()).To(BeFalse())
+			Expect(converted.Series).NotTo(BeNil())
+			Expect(converted.Series.Count).To(Equal(int32(3)))
+			Expect(converted.DeprecatedCount).To(Equal(int32(3)))
+		})
+
+		It("should convert back to a core event", func() {
+			converted := typesv1.EventFromEventsV1(typesv1.EventToEventsV1(event))
+
+			Expect(converted.APIVersion).To(Equal("v1"))
+			Expect(converted.InvolvedObject).To(Equal(event.InvolvedObject))
+			Expect(converted.Message).To(Equal(event.Message))
+			Expect(converted.Source).To(Equal(event.Source))
+			Expect(converted.Count).To(Equal(int32(3)))
+			Expect(converted.Series.Count).To(Equal(int32(3)))
+		})
+	})
+
+	Describe("RBAC", func() {
+		It("should create roles with their rules", func() {
+			rule := rbacv1.PolicyRule{APIGroups: []string{""}, Resources: []string{"configmaps"}, Verbs: []string{"get"}}
+
+			role := typesv1.NewRole("reader", "test-namespace", rule)
+			Expect(role.Namespace).To(Equal("test-namespace"))
+			Expect(role.APIVersion).To(Equal("rbac.authorization.k8s.io/v1"))
+			Expect(role.Kind).To(Equal("Role"))
+			Expect(role.Rules).To(ConsistOf(rule))
+
+			clusterRole := typesv1.NewClusterRole("reader", rule)
+			Expect(clusterRole.Namespace).To(BeEmpty())
+			Expect(clusterRole.Kind).To(Equal("ClusterRole"))
+			Expect(clusterRole.Rules).To(ConsistOf(rule))
+		})
+
+		It("should create bindings to roles", func() {
+			subject := rbacv1.Subject{Kind: rbacv1.ServiceAccountKind, Name: "builder", Namespace: "test-namespace"}
+
+			roleRef := rbacv1.RoleRef{APIGroup: rbacv1.GroupName, Kind: "Role", Name: "reader"}
+			binding := typesv1.NewRoleBinding("reader", "test-namespace", roleRef, subject)
+			Expect(binding.Kind).To(Equal("RoleBinding"))
+			Expect(binding.RoleRef).To(Equal(roleRef))
+			Expect(binding.Subjects).To(ConsistOf(subject))
+
+			clusterBinding := typesv1.NewClusterRoleBinding("reader", "reader", subject)
+			Expect(clusterBinding.Kind).To(Equal("ClusterRoleBinding"))
+			Expect(clusterBinding.RoleRef).To(Equal(rbacv1.RoleRef{APIGroup: rbacv1.GroupName, Kind: "ClusterRole", Name: "reader"}))
+		})
+
+		It("should return the scope of the RBAC resources", func() {
+			for kind, namespaced := range map[string]bool{
+				"Role":               true,
+				"RoleBinding":        true,
+				"ClusterRole":        false,
+				"ClusterRoleBinding": false,
+			} {
+				info, found := typesv1.GetResourceInfoByKind(kind)
+				Expect(found).To(BeTrue())
+				Expect(info.GVK.Group).To(Equal("rbac.authorization.k8s.io"))
+				Expect(info.NamespaceScoped).To(Equal(namespaced), kind)
+				Expect(info.PrintColumns).ToNot(BeEmpty())
+			}
+
+			info, _ := typesv1.GetResourceInfoByKind("RoleBinding")
+			Expect(info.PrintColumnsWithNamespace[0].Name).To(Equal("Namespace"))
+			Expect(info.PrintColumns).To(ContainElement(HaveField("Name", "Role")))
+		})
+	})
+
+	Describe("CustomResourceDefinition", func() {
+		It("should create a custom resource definition named after its resource", func() {
+			names := apiextensionsv1.CustomResourceDefinitionNames{Plural: "items", Singular: "item", Kind: "Item"}
+
+			crd := typesv1.NewCustomResourceDefinition("example.com", names, apiextensionsv1.NamespaceScoped)
+			Expect(crd.Name).To(Equal("items.example.com"))
+			Expect(crd.APIVersion).To(Equal("apiextensions.k8s.io/v1"))
+			Expect(crd.Kind).To(Equal("CustomResourceDefinition"))
+			Expect(crd.Spec.Group).To(Equal("example.com"))
+			Expect(crd.Spec.Names).To(Equal(names))
+			Expect(crd.Spec.Scope).To(Equal(apiextensionsv1.NamespaceScoped))
+		})
+
+		It("should be a cluster-scoped resource with short names", func() {
+			info, found := typesv1.GetResourceInfoByKind("CustomResourceDefinition")
+			Expect(found).To(BeTrue())
+			Expect(info.GVR).To(Equal(typesv1.GetCustomResourceDefinitionGVR()))
+			Expect(info.NamespaceScoped).To(BeFalse())
+			Expect(info.ShortNames).To(ConsistOf("crd", "crds"))
+			Expect(info.PrintColumns).To(ContainElement(HaveField("Name", "Created At")))
+		})
+	})
+
 	Describe("Resource Info", func() {
 		It("should return all core resource infos", func() {
 			infos := typesv1.GetCoreResourceInfos()
@@ -0,0 +1,207 @@
+package v1
+
+import (
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// ValidatingAdmissionPolicy describes CEL validations that the client
+// evaluates on writes. It directly uses the standard Kubernetes
+// admissionregistrationv1.ValidatingAdmissionPolicy for full compatibility.
+type ValidatingAdmissionPolicy = admissionregistrationv1.ValidatingAdmissionPolicy
+
+// ValidatingAdmissionPolicyList represents a list of ValidatingAdmissionPolicy objects.
+type ValidatingAdmissionPolicyList = admissionregistrationv1.ValidatingAdmissionPolicyList
+
+// ValidatingAdmissionPolicyBinding binds a ValidatingAdmissionPolicy to
+// resources and parameters. It directly uses the standard Kubernetes
+// admissionregistrationv1.ValidatingAdmissionPolicyBinding for full compatibility.
+type ValidatingAdmissionPolicyBinding = admissionregistrationv1.ValidatingAdmissionPolicyBinding
+
+// ValidatingAdmissionPolicyBindingList represents a list of ValidatingAdmissionPolicyBinding objects.
+type ValidatingAdmissionPolicyBindingList = admissionregistrationv1.ValidatingAdmissionPolicyBindingList
+
+var (
+	// ValidatingAdmissionPolicyGVK is the GroupVersionKind for ValidatingAdmissionPolicy.
+	ValidatingAdmissionPolicyGVK = schema.GroupVersionKind{
+		Group:   "admissionregistration.k8s.io",
+		Version: "v1",
+		Kind:    "ValidatingAdmissionPolicy",
+	}
+
+	// ValidatingAdmissionPolicyGVR is the GroupVersionResource for ValidatingAdmissionPolicy.
+	ValidatingAdmissionPolicyGVR = schema.GroupVersionResource{
+		Group:    "admissionregistration.k8s.io",
+		Version:  "v1",
+		Resource: "validatingadmissionpolicies",
+	}
+
+	// ValidatingAdmissionPolicyBindingGVK is the GroupVersionKind for ValidatingAdmissionPolicyBinding.
+	ValidatingAdmissionPolicyBindingGVK = schema.GroupVersionKind{
+		Group:   "admissionregistration.k8s.io",
+		Version: "v1",
+		Kind:    "ValidatingAdmissionPolicyBinding",
+	}
+
+	// ValidatingAdmissionPolicyBindingGVR is the GroupVersionResource for ValidatingAdmissionPolicyBinding.
+	ValidatingAdmissionPolicyBindingGVR = schema.GroupVersionResource{
+		Group:    "admissionregistration.k8s.io",
+		Version:  "v1",
+		Resource: "validatingadmissionpolicybindings",
+	}
+)
+
+// GetValidatingAdmissionPolicyGVK returns the GroupVersionKind for ValidatingAdmissionPolicy.
+func GetValidatingAdmissionPolicyGVK() schema.GroupVersionKind {
+	return ValidatingAdmissionPolicyGVK
+}
+
+// GetValidatingAdmissionPolicyGVR returns the GroupVersionResource for ValidatingAdmissionPolicy.
+func GetValidatingAdmissionPolicyGVR() schema.GroupVersionResource {
+	return ValidatingAdmissionPolicyGVR
+}
+
+// GetValidatingAdmissionPolicyBindingGVK returns the GroupVersionKind for ValidatingAdmissionPolicyBinding.
+func GetValidatingAdmissionPolicyBindingGVK() schema.GroupVersionKind {
+	return ValidatingAdmissionPolicyBindingGVK
+}
+
+// GetValidatingAdmissionPolicyBindingGVR returns the GroupVersionResource for ValidatingAdmissionPolicyBinding.
+func GetValidatingAdmissionPolicyBindingGVR() schema.GroupVersionResource {
+	return ValidatingAdmissionPolicyBindingGVR
+}
+
+// NewValidatingAdmissionPolicy creates a new ValidatingAdmissionPolicy with the given name.
+func NewValidatingAdmissionPolicy(name string) *ValidatingAdmissionPolicy {
+	return &ValidatingAdmissionPolicy{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "admissionregistration.k8s.io/v1",
+			Kind:       "ValidatingAdmissionPolicy",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name: name,
+		},
+	}
+}
+
+// NewValidatingAdmissionPolicyBinding creates a new ValidatingAdmissionPolicyBinding
+// with the given name that binds the named policy with the Deny action.
+func NewValidatingAdmissionPolicyBinding(name, policyName string) *ValidatingAdmissionPolicyBinding {
+	return &ValidatingAdmissionPolicyBinding{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "admissionregistration.k8s.io/v1",
+			Kind:       "ValidatingAdmissionPolicyBinding",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name: name,
+		},
+		Spec: admissionregistrationv1.ValidatingAdmissionPolicyBindingSpec{
+			PolicyName:        policyName,
+			ValidationActions: []admissionregistrationv1.ValidationAction{admissionregistrationv1.Deny},
+		},
+	}
+}
+
+// IsValidatingAdmissionPolicyNamespaceScoped returns false as both
+// ValidatingAdmissionPolicy and its bindings are cluster-scoped resources.
+func IsValidatingAdmissionPolicyNamespaceScoped() bool {
+	return false
+}
+
+// GetValidatingAdmissionPolicyShortNames returns short names for ValidatingAdmissionPolicy resource.
+func GetValidatingAdmissionPolicyShortNames() []string {
+	return []string{"vap"}
+}
+
+// GetValidatingAdmissionPolicyBindingShortNames returns short names for ValidatingAdmissionPolicyBinding resource.
+func GetValidatingAdmissionPolicyBindingShortNames() []string {
+	return []string{"vapb"}
+}
+
+// GetValidatingAdmissionPolicyCategories returns categories for the admission policy resources.
+func GetValidatingAdmissionPolicyCategories() []string {
+	return []string{"api-extensions"}
+}
+
+// GetValidatingAdmissionPolicyPrintColumns returns table columns for ValidatingAdmissionPolicy display.
+func GetValidatingAdmissionPolicyPrintColumns() []metav1.TableColumnDefinition {
+	return []metav1.TableColumnDefinition{
+		{
+			Name:        "Name",
+			Type:        "string",
+			Format:      "name",
+			Description: "Name of the policy",
+			Priority:    0,
+		},
+		{
+			Name:        "Validations",
+			Type:        "integer",
+			Format:      "",
+			Description: "Number of validations",
+			Priority:    0,
+		},
+		{
+			Name:        "ParamKind",
+			Type:        "string",
+			Format:      "",
+			Description: "Kind of the policy parameters",
+			Priority:    0,
+		},
+		{
+			Name:        "Age",
+			Type:        "string",
+			Format:      "",
+			Description: "Age of the policy",
+			Priority:    0,
+		},
+	}
+}
+
+// GetValidatingAdmissionPolicyBindingPrintColumns returns table columns for ValidatingAdmissionPolicyBinding display.
+func GetValidatingAdmissionPolicyBindingPrintColumns() []metav1.TableColumnDefinition {
+	return []metav1.TableColumnDefinition{
+		{
+			Name:        "Name",
+			Type:        "string",
+			Format:      "name",
+			Description: "Name of the binding",
+			Priority:    0,
+		},
+		{
+			Name:        "PolicyName",
+			Type:        "string",
+			Format:      "",
+			Description: "Name of the bound policy",
+			Priority:    0,
+		},
+		{
+			Name:        "ParamRef",
+			Type:        "string",
+			Format:      "",
+			Description: "Reference to the policy parameters",
+			Priority:    0,
+		},
+		{
+			Name:        "Age",
+			Type:        "string",
+			Format:      "",
+			Description: "Age of the binding",
+			Priority:    0,
+		},
+	}
+}
+
+// AddValidatingAdmissionPolicyToScheme adds ValidatingAdmissionPolicy and
+// ValidatingAdmissionPolicyBinding types to the given scheme.
+func AddValidatingAdmissionPolicyToScheme(scheme *runtime.Scheme) error {
+	scheme.AddKnownTypes(admissionregistrationv1.SchemeGroupVersion,
+		&ValidatingAdmissionPolicy{},
+		&ValidatingAdmissionPolicyList{},
+		&ValidatingAdmissionPolicyBinding{},
+		&ValidatingAdmissionPolicyBindingList{},
+	)
+	metav1.AddToGroupVersion(scheme, admissionregistrationv1.SchemeGroupVersion)
+	return nil
+}
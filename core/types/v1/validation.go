@@ -0,0 +1,298 @@
+package v1
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/runtime"
+	utilvalidation "k8s.io/apimachinery/pkg/util/validation"
+
+	"github.com/dtomasi/k1s/core/validation"
+)
+
+// immutableFieldMessage is the message of errors for changes of immutable data
+const immutableFieldMessage = "field is immutable when `immutable` is set"
+
+// RegisterValidationStrategies registers the validation strategies of the
+// core types with the validation manager.
+func RegisterValidationStrategies(m validation.ValidationManager) error {
+	for _, strategy := range []validation.ValidationStrategy{
+		&ConfigMapValidationStrategy{},
+		&SecretValidationStrategy{},
+	} {
+		if err := m.RegisterStrategy(strategy); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ConfigMapValidationStrategy validates ConfigMaps like the API server: keys
+// must be valid and must not be used in both data and binaryData, and the
+// data of immutable ConfigMaps cannot be changed.
+type ConfigMapValidationStrategy struct{}
+
+var _ validation.UpdateValidationStrategy = &ConfigMapValidationStrategy{}
+
+// SupportsType returns true for ConfigMaps.
+func (s *ConfigMapValidationStrategy) SupportsType(obj runtime.Object) bool {
+	_, ok := obj.(*ConfigMap)
+	return ok
+}
+
+// Execute validates the keys of a ConfigMap.
+func (s *ConfigMapValidationStrategy) Execute(_ context.Context, obj runtime.Object) []validation.ValidationError {
+	cm, ok := obj.(*ConfigMap)
+	if !ok {
+		return nil
+	}
+
+	var errs []validation.ValidationError
+	for _, key := range sortedKeys(cm.Data) {
+		errs = append(errs, validateDataKey("data", key)...)
+	}
+	for _, key := range sortedKeys(cm.BinaryData) {
+		errs = append(errs, validateDataKey("binaryData", key)...)
+		if _, exists := cm.Data[key]; exists {
+			errs = append(errs, validation.ValidationError{
+				Field:   fmt.Sprintf("binaryData[%s]", key),
+				Value:   key,
+				Type:    validation.ValidationErrorTypeInvalid,
+				Message: "duplicate of key present in data",
+			})
+		}
+	}
+	return errs
+}
+
+// ExecuteUpdate rejects changes of the data of immutable ConfigMaps.
+func (s *ConfigMapValidationStrategy) ExecuteUpdate(_ context.Context, obj, old runtime.Object) []validation.ValidationError {
+	cm, ok := obj.(*ConfigMap)
+	oldCM, oldOK := old.(*ConfigMap)
+	if !ok || !oldOK || !isImmutable(oldCM.Immutable) {
+		return nil
+	}
+
+	errs := validateImmutableFlag(cm.Immutable)
+	if !equality.Semantic.DeepEqual(cm.Data, oldCM.Data) {
+		errs = append(errs, immutableFieldError("data"))
+	}
+	if !equality.Semantic.DeepEqual(cm.BinaryData, oldCM.BinaryData) {
+		errs = append(errs, immutableFieldError("binaryData"))
+	}
+	return errs
+}
+
+// SecretValidationStrategy validates Secrets like the API server: keys must
+// be valid, the data must not exceed the maximum size, the keys required by
+// the type of the Secret must be present, the type cannot be changed, and
+// the data of immutable Secrets cannot be changed.
+type SecretValidationStrategy struct{}
+
+var _ validation.UpdateValidationStrategy = &SecretValidationStrategy{}
+
+// SupportsType returns true for Secrets.
+func (s *SecretValidationStrategy) SupportsType(obj runtime.Object) bool {
+	_, ok := obj.(*Secret)
+	return ok
+}
+
+// Execute validates the keys, size and type specific data of a Secret.
+func (s *SecretValidationStrategy) Execute(_ context.Context, obj runtime.Object) []validation.ValidationError {
+	secret, ok := obj.(*Secret)
+	if !ok {
+		return nil
+	}
+
+	var errs []validation.ValidationError
+	size := 0
+	for _, key := range sortedKeys(secret.Data) {
+		errs = append(errs, validateDataKey("data", key)...)
+		size += len(secret.Data[key])
+	}
+	for _, key := range sortedKeys(secret.StringData) {
+		errs = append(errs, validateDataKey("stringData", key)...)
+		size += len(secret.StringData[key])
+	}
+	if size > corev1.MaxSecretSize {
+		errs = append(errs, validation.ValidationError{
+			Field:   "data",
+			Value:   size,
+			Type:    validation.ValidationErrorTypeTooLong,
+			Message: fmt.Sprintf("may not be more than %d bytes", corev1.MaxSecretSize),
+		})
+	}
+
+	return append(errs, validateSecretType(secret)...)
+}
+
+// ExecuteUpdate rejects changes of the type of Secrets and of the data of
+// immutable Secrets.
+func (s *SecretValidationStrategy) ExecuteUpdate(_ context.Context, obj, old runtime.Object) []validation.ValidationError {
+	secret, ok := obj.(*Secret)
+	oldSecret, oldOK := old.(*Secret)
+	if !ok || !oldOK {
+		return nil
+	}
+
+	var errs []validation.ValidationError
+	if secretType(secret) != secretType(oldSecret) {
+		errs = append(errs, validation.ValidationError{
+			Field:   "type",
+			Value:   secret.Type,
+			Type:    validation.ValidationErrorTypeInvalid,
+			Message: "field is immutable",
+		})
+	}
+
+	if !isImmutable(oldSecret.Immutable) {
+		return errs
+	}
+	errs = append(errs, validateImmutableFlag(secret.Immutable)...)
+	dataChanged := !equality.Semantic.DeepEqual(secret.Data, oldSecret.Data)
+	for key, value := range secret.StringData {
+		if oldValue, exists := oldSecret.Data[key]; !exists || string(oldValue) != value {
+			dataChanged = true
+		}
+	}
+	if dataChanged {
+		errs = append(errs, immutableFieldError("data"))
+	}
+	return errs
+}
+
+// validateSecretType validates the data required by the type of a Secret
+func validateSecretType(secret *Secret) []validation.ValidationError {
+	var errs []validation.ValidationError
+	switch secretType(secret) {
+	case corev1.SecretTypeServiceAccountToken:
+		if secret.Annotations[corev1.ServiceAccountNameKey] == "" {
+			errs = append(errs, requiredError(fmt.Sprintf("metadata.annotations[%s]", corev1.ServiceAccountNameKey)))
+		}
+	case corev1.SecretTypeDockercfg:
+		errs = append(errs, validateJSONKey(secret, corev1.DockerConfigKey)...)
+	case corev1.SecretTypeDockerConfigJson:
+		errs = append(errs, validateJSONKey(secret, corev1.DockerConfigJsonKey)...)
+	case corev1.SecretTypeBasicAuth:
+		// Either the username or the password suffices
+		if !hasSecretKey(secret, corev1.BasicAuthUsernameKey) && !hasSecretKey(secret, corev1.BasicAuthPasswordKey) {
+			errs = append(errs,
+				requiredError(dataField(corev1.BasicAuthUsernameKey)),
+				requiredError(dataField(corev1.BasicAuthPasswordKey)))
+		}
+	case corev1.SecretTypeSSHAuth:
+		if value, _ := secretValue(secret, corev1.SSHAuthPrivateKey); len(value) == 0 {
+			errs = append(errs, requiredError(dataField(corev1.SSHAuthPrivateKey)))
+		}
+	case corev1.SecretTypeTLS:
+		for _, key := range []string{corev1.TLSCertKey, corev1.TLSPrivateKeyKey} {
+			if !hasSecretKey(secret, key) {
+				errs = append(errs, requiredError(dataField(key)))
+			}
+		}
+	}
+	return errs
+}
+
+// validateJSONKey validates that a Secret holds valid JSON under key
+func validateJSONKey(secret *Secret, key string) []validation.ValidationError {
+	value, exists := secretValue(secret, key)
+	if !exists {
+		return []validation.ValidationError{requiredError(dataField(key))}
+	}
+	if !json.Valid(value) {
+		return []validation.ValidationError{{
+			Field:   dataField(key),
+			Type:    validation.ValidationErrorTypeFormat,
+			Message: "must be valid JSON",
+		}}
+	}
+	return nil
+}
+
+// secretType returns the type of a Secret, which defaults to Opaque
+func secretType(secret *Secret) corev1.SecretType {
+	if secret.Type == "" {
+		return corev1.SecretTypeOpaque
+	}
+	return secret.Type
+}
+
+// secretValue returns the value of a key in the data or string data of a Secret
+func secretValue(secret *Secret, key string) ([]byte, bool) {
+	if value, exists := secret.StringData[key]; exists {
+		return []byte(value), true
+	}
+	value, exists := secret.Data[key]
+	return value, exists
+}
+
+// hasSecretKey reports whether a Secret has a key in its data or string data
+func hasSecretKey(secret *Secret, key string) bool {
+	_, exists := secretValue(secret, key)
+	return exists
+}
+
+// validateDataKey validates a key of the data of a ConfigMap or Secret
+func validateDataKey(field, key string) []validation.ValidationError {
+	var errs []validation.ValidationError
+	for _, msg := range utilvalidation.IsConfigMapKey(key) {
+		errs = append(errs, validation.ValidationError{
+			Field:   fmt.Sprintf("%s[%s]", field, key),
+			Value:   key,
+			Type:    validation.ValidationErrorTypeInvalid,
+			Message: msg,
+		})
+	}
+	return errs
+}
+
+// validateImmutableFlag rejects unsetting the immutable flag
+func validateImmutableFlag(immutable *bool) []validation.ValidationError {
+	if isImmutable(immutable) {
+		return nil
+	}
+	return []validation.ValidationError{immutableFieldError("immutable")}
+}
+
+// isImmutable reports whether an immutable flag is set
+func isImmutable(immutable *bool) bool {
+	return immutable != nil && *immutable
+}
+
+// immutableFieldError returns the error for a changed immutable field
+func immutableFieldError(field string) validation.ValidationError {
+	return validation.ValidationError{
+		Field:   field,
+		Type:    validation.ValidationErrorTypeForbidden,
+		Message: immutableFieldMessage,
+	}
+}
+
+// requiredError returns the error for a missing field
+func requiredError(field string) validation.ValidationError {
+	return validation.ValidationError{
+		Field:   field,
+		Type:    validation.ValidationErrorTypeRequired,
+		Message: "required field is missing",
+	}
+}
+
+// dataField returns the field path of a data key
+func dataField(key string) string {
+	return fmt.Sprintf("data[%s]", key)
+}
+
+// sortedKeys returns the keys of m in sorted order
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
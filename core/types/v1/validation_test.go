@@ -0,0 +1,129 @@
+package v1_test
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+
+	typesv1 "github.com/dtomasi/k1s/core/types/v1"
+	"github.com/dtomasi/k1s/core/validation"
+)
+
+var _ = Describe("Validation Strategies", func() {
+	var (
+		ctx       context.Context
+		validator validation.ValidationManager
+		immutable = true
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		validator = validation.NewManager()
+		Expect(typesv1.RegisterValidationStrategies(validator)).To(Succeed())
+	})
+
+	fields := func(err error) []string {
+		var result []string
+		for _, e := range validation.GetValidationErrors(err) {
+			result = append(result, e.Field)
+		}
+		return result
+	}
+
+	Describe("ConfigMap", func() {
+		It("should reject invalid and duplicate keys", func() {
+			cm := typesv1.NewConfigMapWithData("config", "default", map[string]string{"bad key": "value", "shared": "value"})
+			cm.BinaryData = map[string][]byte{"shared": []byte("value")}
+
+			err := validator.Validate(ctx, cm)
+			Expect(fields(err)).To(ConsistOf("data[bad key]", "binaryData[shared]"))
+		})
+
+		It("should reject data changes of immutable configmaps", func() {
+			old := typesv1.NewConfigMapWithData("config", "default", map[string]string{"key": "value"})
+			old.Immutable = &immutable
+
+			updated := old.DeepCopy()
+			updated.Labels = map[string]string{"team": "a"}
+			Expect(validator.ValidateUpdate(ctx, updated, old)).To(Succeed())
+
+			updated.Data["key"] = "changed"
+			updated.Immutable = nil
+			err := validator.ValidateUpdate(ctx, updated, old)
+			Expect(fields(err)).To(ConsistOf("immutable", "data"))
+			Expect(err.Error()).To(ContainSubstring("field is immutable when `immutable` is set"))
+		})
+
+		It("should allow data changes of mutable configmaps", func() {
+			old := typesv1.NewConfigMapWithData("config", "default", map[string]string{"key": "value"})
+			updated := old.DeepCopy()
+			updated.Data["key"] = "changed"
+			updated.Immutable = &immutable
+
+			Expect(validator.ValidateUpdate(ctx, updated, old)).To(Succeed())
+		})
+	})
+
+	Describe("Secret", func() {
+		It("should accept the secrets of the typed constructors", func() {
+			for _, secret := range []*typesv1.Secret{
+				typesv1.NewOpaqueSecret("opaque", "default"),
+				typesv1.NewTLSSecret("tls", "default", []byte("cert"), []byte("key")),
+				typesv1.NewBasicAuthSecret("basic", "default", "admin", "secret"),
+				typesv1.NewSSHAuthSecret("ssh", "default", []byte("private-key")),
+				typesv1.NewDockerConfigJSONSecret("registry", "default", []byte(`{"auths":{}}`)),
+				typesv1.NewServiceAccountTokenSecret("token", "default", "builder"),
+			} {
+				Expect(validator.Validate(ctx, secret)).To(Succeed(), secret.Name)
+			}
+		})
+
+		It("should require the keys of the secret type", func() {
+			tls := typesv1.NewSecretWithData("tls", "default", corev1.SecretTypeTLS, map[string][]byte{"tls.crt": []byte("cert")})
+			Expect(fields(validator.Validate(ctx, tls))).To(ConsistOf("data[tls.key]"))
+
+			basic := typesv1.NewSecret("basic", "default", corev1.SecretTypeBasicAuth)
+			Expect(fields(validator.Validate(ctx, basic))).To(ConsistOf("data[username]", "data[password]"))
+
+			// Either the username or the password suffices, also as string data
+			basic.StringData = map[string]string{"password": "secret"}
+			Expect(validator.Validate(ctx, basic)).To(Succeed())
+
+			ssh := typesv1.NewSSHAuthSecret("ssh", "default", nil)
+			Expect(fields(validator.Validate(ctx, ssh))).To(ConsistOf("data[ssh-privatekey]"))
+
+			token := typesv1.NewSecret("token", "default", corev1.SecretTypeServiceAccountToken)
+			Expect(fields(validator.Validate(ctx, token))).To(ConsistOf("metadata.annotations[kubernetes.io/service-account.name]"))
+		})
+
+		It("should require valid docker config JSON", func() {
+			secret := typesv1.NewDockerConfigJSONSecret("registry", "default", []byte("not json"))
+
+			err := validator.Validate(ctx, secret)
+			Expect(fields(err)).To(ConsistOf("data[.dockerconfigjson]"))
+			Expect(err.Error()).To(ContainSubstring("must be valid JSON"))
+		})
+
+		It("should reject type changes", func() {
+			old := typesv1.NewOpaqueSecret("secret", "default")
+			updated := typesv1.NewBasicAuthSecret("secret", "default", "admin", "secret")
+
+			Expect(fields(validator.ValidateUpdate(ctx, updated, old))).To(ConsistOf("type"))
+		})
+
+		It("should reject data changes of immutable secrets", func() {
+			old := typesv1.NewBasicAuthSecret("basic", "default", "admin", "secret")
+			old.Immutable = &immutable
+
+			updated := old.DeepCopy()
+			updated.StringData = map[string]string{"password": "secret"}
+			Expect(validator.ValidateUpdate(ctx, updated, old)).To(Succeed())
+
+			updated.StringData = map[string]string{"password": "changed"}
+			Expect(fields(validator.ValidateUpdate(ctx, updated, old))).To(ConsistOf("data"))
+		})
+	})
+})
@@ -25,14 +25,26 @@ type compiledCELProgram struct {
 	expression string
 }
 
-// NewCELValidator creates a new CEL validator with a standard environment.
-func NewCELValidator() CELValidator {
-	env, err := cel.NewEnv(
-		cel.Variable("self", cel.DynType),
+// NewCELEnv returns the CEL environment used by k1s validation with the
+// given variables declared as dynamic values. Kubebuilder CEL rules use the
+// variable "self"; admission policies use "object", "oldObject", "request"
+// and "params".
+func NewCELEnv(variables ...string) (*cel.Env, error) {
+	opts := make([]cel.EnvOption, 0, len(variables)+3)
+	for _, name := range variables {
+		opts = append(opts, cel.Variable(name, cel.DynType))
+	}
+	opts = append(opts,
 		cel.HomogeneousAggregateLiterals(),
 		cel.EagerlyValidateDeclarations(true),
 		cel.DefaultUTCTimeZone(true),
 	)
+	return cel.NewEnv(opts...)
+}
+
+// NewCELValidator creates a new CEL validator with a standard environment.
+func NewCELValidator() CELValidator {
+	env, err := NewCELEnv("self")
 	if err != nil {
 		// This should never happen with our standard configuration
 		panic(fmt.Sprintf("failed to create CEL environment: %v", err))
@@ -30,6 +30,16 @@ type ValidationStrategy interface {
 	SupportsType(obj runtime.Object) bool
 }
 
+// UpdateValidationStrategy is implemented by strategies that also validate
+// updates against the stored object, e.g. to reject changes of immutable
+// fields. ExecuteUpdate runs in addition to Execute.
+type UpdateValidationStrategy interface {
+	ValidationStrategy
+
+	// ExecuteUpdate validates the update of old to obj and returns validation errors.
+	ExecuteUpdate(ctx context.Context, obj, old runtime.Object) []ValidationError
+}
+
 // ValidationManager coordinates multiple validation strategies and provides
 // a unified interface for validating objects.
 type ValidationManager interface {
@@ -112,7 +112,7 @@ func (m *manager) ValidateDelete(ctx context.Context, obj runtime.Object) error
 }
 
 // validateObject performs the actual validation logic.
-func (m *manager) validateObject(ctx context.Context, obj runtime.Object, _ runtime.Object) []ValidationError {
+func (m *manager) validateObject(ctx context.Context, obj runtime.Object, old runtime.Object) []ValidationError {
 	var allErrors []ValidationError
 
 	gvk := obj.GetObjectKind().GroupVersionKind()
@@ -129,7 +129,7 @@ func (m *manager) validateObject(ctx context.Context, obj runtime.Object, _ runt
 	// Apply GVK-specific strategy-based validation
 	for _, strategy := range strategies {
 		if strategy.SupportsType(obj) {
-			errors := strategy.Execute(ctx, obj)
+			errors := executeStrategy(ctx, strategy, obj, old)
 			allErrors = append(allErrors, errors...)
 
 			if m.options.FailFast && len(errors) > 0 {
@@ -141,7 +141,7 @@ func (m *manager) validateObject(ctx context.Context, obj runtime.Object, _ runt
 	// Apply global strategy-based validation
 	for _, strategy := range allStrategies {
 		if strategy.SupportsType(obj) {
-			errors := strategy.Execute(ctx, obj)
+			errors := executeStrategy(ctx, strategy, obj, old)
 			allErrors = append(allErrors, errors...)
 
 			if m.options.FailFast && len(errors) > 0 {
@@ -164,6 +164,15 @@ func (m *manager) validateObject(ctx context.Context, obj runtime.Object, _ runt
 	return allErrors
 }
 
+// executeStrategy runs a strategy, including its update validation if old is set
+func executeStrategy(ctx context.Context, strategy ValidationStrategy, obj, old runtime.Object) []ValidationError {
+	errors := strategy.Execute(ctx, obj)
+	if updateStrategy, ok := strategy.(UpdateValidationStrategy); ok && old != nil {
+		errors = append(errors, updateStrategy.ExecuteUpdate(ctx, obj, old)...)
+	}
+	return errors
+}
+
 // RegisterStrategy registers a validation strategy for objects.
 func (m *manager) RegisterStrategy(strategy ValidationStrategy) error {
 	if strategy == nil {
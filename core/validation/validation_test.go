@@ -56,6 +56,29 @@ func (t *TestObject) DeepCopyInto(out *TestObject) {
 
 const forbiddenValue = "forbidden"
 
+// updateStrategy rejects changes of the category of TestObjects
+type updateStrategy struct{}
+
+func (s *updateStrategy) SupportsType(obj runtime.Object) bool {
+	_, ok := obj.(*TestObject)
+	return ok
+}
+
+func (s *updateStrategy) Execute(_ context.Context, _ runtime.Object) []validation.ValidationError {
+	return nil
+}
+
+func (s *updateStrategy) ExecuteUpdate(_ context.Context, obj, old runtime.Object) []validation.ValidationError {
+	if obj.(*TestObject).Spec.Category != old.(*TestObject).Spec.Category {
+		return []validation.ValidationError{{
+			Field:   "spec.category",
+			Type:    validation.ValidationErrorTypeInvalid,
+			Message: "field is immutable",
+		}}
+	}
+	return nil
+}
+
 var _ = Describe("Validation Manager", func() {
 	var (
 		ctx     context.Context
@@ -492,6 +515,18 @@ var _ = Describe("Validation Manager", func() {
 				err := manager.ValidateUpdate(ctx, testObj, oldObj)
 				Expect(err).To(HaveOccurred())
 			})
+
+			It("should execute update strategies only for updates", func() {
+				Expect(manager.RegisterStrategy(&updateStrategy{})).To(Succeed())
+				oldObj := testObj.DeepCopyObject().(*TestObject)
+				testObj.Spec.Category = "other"
+
+				Expect(manager.Validate(ctx, testObj)).To(Succeed())
+
+				err := manager.ValidateUpdate(ctx, testObj, oldObj)
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(ContainSubstring("field is immutable"))
+			})
 		})
 
 		Context("validation deletion", func() {
@@ -51,6 +51,7 @@ This demo showcases:
 			fmt.Println("  create     Create resources from files")
 			fmt.Println("  apply      Apply configuration files")
 			fmt.Println("  delete     Delete resources")
+			fmt.Println("  tenant     Manage isolated tenant workspaces")
 			fmt.Println("")
 			fmt.Println("Use 'k1s-demo <command> --help' for more information about a command.")
 		},
@@ -59,10 +60,11 @@ This demo showcases:
 	// Global persistent flags
 	rootCmd.PersistentFlags().StringVar(&dbPath, "db-path", "", "Path to database file (empty = memory storage)")
 	rootCmd.PersistentFlags().StringVar(&tenantID, "tenant", "", "Tenant ID for multi-tenant usage")
-	rootCmd.PersistentFlags().StringVar(&storageType, "storage", "memory", "Storage type: memory or pebble")
+	rootCmd.PersistentFlags().StringVar(&storageType, "storage", "memory", "Storage type: memory, pebble, sqlite or filesystem")
 
 	// Add subcommands
 	rootCmd.AddCommand(NewGetCommand(&k1sRuntime))
+	rootCmd.AddCommand(NewTenantCommand())
 
 	return rootCmd
 }
@@ -0,0 +1,185 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/util/duration"
+
+	coreruntime "github.com/dtomasi/k1s/core/runtime"
+	"github.com/dtomasi/k1s/core/storage"
+)
+
+// NewTenantCommand creates the tenant command group
+func NewTenantCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "tenant",
+		Short: "Manage isolated tenant workspaces",
+		Long: `Manage isolated tenant workspaces sharing one storage backend.
+
+Each tenant is a separate workspace within the same database. Select a
+tenant for other commands with the global --tenant flag.`,
+		Example: `  # Create a tenant limited to 100 objects
+  k1s-demo tenant create team-a --max-objects 100 --storage sqlite
+
+  # List tenants with their object counts
+  k1s-demo tenant list --storage sqlite
+
+  # Delete a tenant and all of its data
+  k1s-demo tenant delete team-a --storage sqlite`,
+	}
+
+	cmd.AddCommand(newTenantCreateCommand())
+	cmd.AddCommand(newTenantListCommand())
+	cmd.AddCommand(newTenantDeleteCommand())
+	cmd.AddCommand(newTenantCountCommand())
+
+	return cmd
+}
+
+// newTenantCreateCommand creates the tenant create command
+func newTenantCreateCommand() *cobra.Command {
+	var maxObjects int64
+
+	cmd := &cobra.Command{
+		Use:   "create NAME",
+		Short: "Register a tenant",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return withTenantManager(cmd.Context(), func(ctx context.Context, tm storage.TenantManager) error {
+				if err := tm.CreateTenant(ctx, storage.Tenant{ID: args[0], MaxObjects: maxObjects}); err != nil {
+					return err
+				}
+				fmt.Printf("tenant/%s created\n", args[0])
+				return nil
+			})
+		},
+	}
+
+	cmd.Flags().Int64Var(&maxObjects, "max-objects", 0, "Maximum number of objects the tenant may store (0 = unlimited)")
+
+	return cmd
+}
+
+// newTenantListCommand creates the tenant list command
+func newTenantListCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:     "list",
+		Aliases: []string{"ls"},
+		Short:   "List tenants with their object counts",
+		Args:    cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return withTenantManager(cmd.Context(), func(ctx context.Context, tm storage.TenantManager) error {
+				tenants, err := tm.ListTenants(ctx)
+				if err != nil {
+					return err
+				}
+				if len(tenants) == 0 {
+					fmt.Println("No tenants found.")
+					return nil
+				}
+
+				w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
+				if _, err := fmt.Fprintln(w, "NAME\tOBJECTS\tMAX-OBJECTS\tAGE"); err != nil {
+					return err
+				}
+				for _, tenant := range tenants {
+					count, err := tm.CountTenant(ctx, tenant.ID)
+					if err != nil {
+						return err
+					}
+					if _, err := fmt.Fprintf(w, "%s\t%d\t%s\t%s\n",
+						tenant.ID, count, formatMaxObjects(tenant.MaxObjects), formatAge(tenant)); err != nil {
+						return err
+					}
+				}
+				return w.Flush()
+			})
+		},
+	}
+}
+
+// newTenantDeleteCommand creates the tenant delete command
+func newTenantDeleteCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "delete NAME",
+		Short: "Delete a tenant together with all of its data",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return withTenantManager(cmd.Context(), func(ctx context.Context, tm storage.TenantManager) error {
+				if err := tm.DeleteTenant(ctx, args[0]); err != nil {
+					return err
+				}
+				fmt.Printf("tenant/%s deleted\n", args[0])
+				return nil
+			})
+		},
+	}
+}
+
+// newTenantCountCommand creates the tenant count command
+func newTenantCountCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "count NAME",
+		Short: "Print the number of objects stored by a tenant",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return withTenantManager(cmd.Context(), func(ctx context.Context, tm storage.TenantManager) error {
+				count, err := tm.CountTenant(ctx, args[0])
+				if err != nil {
+					return err
+				}
+				fmt.Println(count)
+				return nil
+			})
+		},
+	}
+}
+
+// withTenantManager opens the storage backend selected by the global flags
+// and runs fn with its tenant manager
+func withTenantManager(ctx context.Context, fn func(context.Context, storage.TenantManager) error) error {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	// Tenant management operates on the whole store, so the --tenant flag is not applied
+	backend, err := coreruntime.NewStorageFromConfig(coreruntime.SimpleRuntimeConfig{
+		Type:   coreruntime.RuntimeType(storageType),
+		DBPath: dbPath,
+	})
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := backend.Close(); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: failed to close storage: %v\n", err)
+		}
+	}()
+
+	tm, ok := backend.(storage.TenantManager)
+	if !ok {
+		return fmt.Errorf("storage type %s does not support tenant management", storageType)
+	}
+	return fn(ctx, tm)
+}
+
+// formatMaxObjects renders a tenant quota
+func formatMaxObjects(maxObjects int64) string {
+	if maxObjects <= 0 {
+		return "<none>"
+	}
+	return fmt.Sprintf("%d", maxObjects)
+}
+
+// formatAge renders the time since a tenant was registered
+func formatAge(tenant storage.Tenant) string {
+	if tenant.CreationTimestamp.IsZero() {
+		return "<unknown>"
+	}
+	return duration.HumanDuration(time.Since(tenant.CreationTimestamp.Time))
+}
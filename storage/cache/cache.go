@@ -0,0 +1,433 @@
+package storage
+
+import (
+	"container/list"
+	"context"
+	"log"
+	"reflect"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/apiserver/pkg/storage"
+
+	k1sstorage "github.com/dtomasi/k1s/core/storage"
+)
+
+const (
+	// DefaultSize is the number of objects kept when no size is configured
+	DefaultSize = 1024
+
+	// DefaultTTL is how long an object is served from the cache when no TTL is configured
+	DefaultTTL = 5 * time.Minute
+)
+
+// Config holds the cache configuration
+type Config struct {
+	// Size is the maximum number of objects kept in the cache.
+	// Zero or a negative value uses DefaultSize.
+	Size int
+
+	// TTL is how long an object is served from the cache before it is read
+	// from the backend again. Zero uses DefaultTTL, a negative value disables
+	// expiry so that entries are only dropped on eviction or invalidation.
+	TTL time.Duration
+}
+
+// cachedStorage is a read-through cache decorating any storage backend.
+//
+// Get is served from an LRU of decoded objects keyed by storage key and
+// resourceVersion. Writes made through the cache invalidate their key
+// directly; writes made by anyone else (other processes sharing a SQLite
+// database, files edited on disk) are picked up from the backend's own watch
+// stream. All other operations are passed through unchanged.
+type cachedStorage struct {
+	// backend is the decorated storage backend
+	backend k1sstorage.Backend
+
+	// size is the maximum number of cached objects
+	size int
+
+	// ttl is how long an entry stays valid, zero disables expiry
+	ttl time.Duration
+
+	// mu protects the LRU, the indexes and the watch state
+	mu sync.Mutex
+
+	// lru orders entries from most to least recently used
+	lru *list.List
+
+	// entries maps storage keys to their LRU element
+	entries map[string]*list.Element
+
+	// byName maps namespace/name to the storage keys cached for it, used to
+	// resolve watch events, which carry objects but no keys
+	byName map[string]map[string]struct{}
+
+	// generation is bumped on every invalidation so that a backend read
+	// racing with an invalidation is not cached
+	generation uint64
+
+	// watching reports whether the invalidation watch is running
+	watching bool
+
+	// stopWatch cancels the invalidation watch
+	stopWatch context.CancelFunc
+
+	// metrics tracks cache statistics
+	metrics *cacheMetrics
+
+	// closed indicates if the storage is closed
+	closed atomic.Bool
+}
+
+// cacheEntry is a single cached object
+type cacheEntry struct {
+	key             string
+	name            string
+	resourceVersion string
+	obj             runtime.Object
+	expires         time.Time
+}
+
+// cacheMetrics tracks cache statistics
+type cacheMetrics struct {
+	hits      uint64
+	misses    uint64
+	evictions uint64
+}
+
+// NewCachedStorage wraps backend with a read-through cache
+func NewCachedStorage(backend k1sstorage.Backend, config Config) k1sstorage.Backend {
+	size := config.Size
+	if size <= 0 {
+		size = DefaultSize
+	}
+
+	ttl := config.TTL
+	switch {
+	case ttl == 0:
+		ttl = DefaultTTL
+	case ttl < 0:
+		ttl = 0
+	}
+
+	return &cachedStorage{
+		backend: backend,
+		size:    size,
+		ttl:     ttl,
+		lru:     list.New(),
+		entries: make(map[string]*list.Element),
+		byName:  make(map[string]map[string]struct{}),
+		metrics: &cacheMetrics{},
+	}
+}
+
+// Unwrap returns the decorated storage backend
+func (c *cachedStorage) Unwrap() k1sstorage.Backend {
+	return c.backend
+}
+
+// Name returns the name of the decorated backend
+func (c *cachedStorage) Name() string {
+	return c.backend.Name()
+}
+
+// Versioner returns the storage versioner of the decorated backend
+func (c *cachedStorage) Versioner() storage.Versioner {
+	return c.backend.Versioner()
+}
+
+// Create adds a new object at a key unless it already exists
+func (c *cachedStorage) Create(ctx context.Context, key string, obj, out runtime.Object, ttl uint64) error {
+	defer c.invalidateKey(key)
+	return c.backend.Create(ctx, key, obj, out, ttl)
+}
+
+// Delete removes the specified key and returns the value that existed at that key
+func (c *cachedStorage) Delete(ctx context.Context, key string, out runtime.Object, preconditions *storage.Preconditions,
+	validateDeletion storage.ValidateObjectFunc, cachedExistingObject runtime.Object) error {
+	defer c.invalidateKey(key)
+	return c.backend.Delete(ctx, key, out, preconditions, validateDeletion, cachedExistingObject)
+}
+
+// Watch begins watching a specific key or key prefix for changes
+func (c *cachedStorage) Watch(ctx context.Context, key string, opts storage.ListOptions) (watch.Interface, error) {
+	return c.backend.Watch(ctx, key, opts)
+}
+
+// Get unmarshals object found at key into objPtr, serving it from the cache when possible
+func (c *cachedStorage) Get(ctx context.Context, key string, opts storage.GetOptions, objPtr runtime.Object) error {
+	if ctx.Err() != nil {
+		return k1sstorage.NewContextCancelledError(ctx)
+	}
+
+	if c.lookup(key, opts.ResourceVersion, objPtr) {
+		atomic.AddUint64(&c.metrics.hits, 1)
+		return nil
+	}
+	atomic.AddUint64(&c.metrics.misses, 1)
+
+	// Only cache while the invalidation watch is running, otherwise
+	// changes made by others would go unnoticed until the TTL expires
+	generation, cacheable := c.beginRead(ctx)
+
+	if err := c.backend.Get(ctx, key, opts, objPtr); err != nil {
+		return err
+	}
+
+	if cacheable {
+		c.store(key, objPtr, generation)
+	}
+	return nil
+}
+
+// List unmarshalls objects found at key into a List api object
+func (c *cachedStorage) List(ctx context.Context, key string, opts storage.ListOptions, listObj runtime.Object) error {
+	return c.backend.List(ctx, key, opts, listObj)
+}
+
+// GuaranteedUpdate keeps calling tryUpdate to update key until it succeeds
+func (c *cachedStorage) GuaranteedUpdate(ctx context.Context, key string, destination runtime.Object, ignoreNotFound bool,
+	preconditions *storage.Preconditions, tryUpdate storage.UpdateFunc, cachedExistingObject runtime.Object) error {
+	defer c.invalidateKey(key)
+	return c.backend.GuaranteedUpdate(ctx, key, destination, ignoreNotFound, preconditions, tryUpdate, cachedExistingObject)
+}
+
+// RequestWatchProgress implements storage.Interface
+func (c *cachedStorage) RequestWatchProgress(ctx context.Context) error {
+	return c.backend.RequestWatchProgress(ctx)
+}
+
+// RequestProgress implements storage.Interface
+func (c *cachedStorage) RequestProgress(ctx context.Context) error {
+	return c.backend.RequestProgress(ctx)
+}
+
+// Close stops the invalidation watch and closes the decorated backend
+func (c *cachedStorage) Close() error {
+	c.closed.Store(true)
+
+	c.mu.Lock()
+	if c.stopWatch != nil {
+		c.stopWatch()
+		c.stopWatch = nil
+	}
+	c.lockedPurge()
+	c.mu.Unlock()
+
+	return c.backend.Close()
+}
+
+// Compact performs storage compaction on the decorated backend
+func (c *cachedStorage) Compact(ctx context.Context) error {
+	return c.backend.Compact(ctx)
+}
+
+// Count returns the number of objects stored under the given key prefix
+func (c *cachedStorage) Count(ctx context.Context, key string) (int64, error) {
+	return c.backend.Count(ctx, key)
+}
+
+// GetCacheMetrics returns cache hit, miss and eviction counts
+func (c *cachedStorage) GetCacheMetrics() (hits, misses, evictions uint64) {
+	return atomic.LoadUint64(&c.metrics.hits),
+		atomic.LoadUint64(&c.metrics.misses),
+		atomic.LoadUint64(&c.metrics.evictions)
+}
+
+// Len returns the number of cached objects
+func (c *cachedStorage) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.lru.Len()
+}
+
+// lookup copies a valid cached object for key into objPtr
+func (c *cachedStorage) lookup(key, resourceVersion string, objPtr runtime.Object) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	elem, ok := c.entries[key]
+	if !ok {
+		return false
+	}
+
+	entry := elem.Value.(*cacheEntry)
+	if c.ttl > 0 && time.Now().After(entry.expires) {
+		c.lockedRemove(elem)
+		return false
+	}
+	if resourceVersion != "" && resourceVersion != entry.resourceVersion {
+		return false
+	}
+
+	// Only serve objects of the requested type
+	dst := reflect.ValueOf(objPtr)
+	src := reflect.ValueOf(entry.obj.DeepCopyObject())
+	if dst.Kind() != reflect.Ptr || dst.IsNil() || dst.Type() != src.Type() {
+		return false
+	}
+	dst.Elem().Set(src.Elem())
+
+	c.lru.MoveToFront(elem)
+	return true
+}
+
+// beginRead makes sure the invalidation watch is running and returns the
+// current generation, or false when results must not be cached
+func (c *cachedStorage) beginRead(ctx context.Context) (uint64, bool) {
+	if c.closed.Load() {
+		return 0, false
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if !c.watching && !c.lockedStartWatch(ctx) {
+		return 0, false
+	}
+	return c.generation, true
+}
+
+// store caches obj for key unless an invalidation happened since generation.
+// Objects without a name or resourceVersion, such as the empty object of a
+// Get with IgnoreNotFound, are not cached: writes are matched to cached
+// objects by name, so their entries would never be invalidated.
+func (c *cachedStorage) store(key string, obj runtime.Object, generation uint64) {
+	accessor, err := meta.Accessor(obj)
+	if err != nil || accessor.GetName() == "" || accessor.GetResourceVersion() == "" {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if !c.watching || c.generation != generation {
+		return
+	}
+
+	if elem, ok := c.entries[key]; ok {
+		c.lockedRemove(elem)
+	}
+
+	entry := &cacheEntry{
+		key:             key,
+		name:            nameKey(accessor.GetNamespace(), accessor.GetName()),
+		resourceVersion: accessor.GetResourceVersion(),
+		obj:             obj.DeepCopyObject(),
+	}
+	if c.ttl > 0 {
+		entry.expires = time.Now().Add(c.ttl)
+	}
+
+	c.entries[key] = c.lru.PushFront(entry)
+	if c.byName[entry.name] == nil {
+		c.byName[entry.name] = make(map[string]struct{})
+	}
+	c.byName[entry.name][key] = struct{}{}
+
+	// Evict least recently used entries
+	for c.lru.Len() > c.size {
+		c.lockedRemove(c.lru.Back())
+		atomic.AddUint64(&c.metrics.evictions, 1)
+	}
+}
+
+// invalidateKey drops the cached object for key
+func (c *cachedStorage) invalidateKey(key string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.generation++
+	if elem, ok := c.entries[key]; ok {
+		c.lockedRemove(elem)
+	}
+}
+
+// invalidateObject drops all cached objects matching the namespace and name of obj
+func (c *cachedStorage) invalidateObject(obj runtime.Object) {
+	accessor, err := meta.Accessor(obj)
+	if err != nil {
+		c.mu.Lock()
+		c.lockedPurge()
+		c.mu.Unlock()
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.generation++
+	for key := range c.byName[nameKey(accessor.GetNamespace(), accessor.GetName())] {
+		c.lockedRemove(c.entries[key])
+	}
+}
+
+// lockedRemove removes a single entry. Callers must hold mu.
+func (c *cachedStorage) lockedRemove(elem *list.Element) {
+	entry := c.lru.Remove(elem).(*cacheEntry)
+	delete(c.entries, entry.key)
+	if keys := c.byName[entry.name]; keys != nil {
+		delete(keys, entry.key)
+		if len(keys) == 0 {
+			delete(c.byName, entry.name)
+		}
+	}
+}
+
+// lockedPurge drops all cached objects. Callers must hold mu.
+func (c *cachedStorage) lockedPurge() {
+	c.generation++
+	c.lru.Init()
+	c.entries = make(map[string]*list.Element)
+	c.byName = make(map[string]map[string]struct{})
+}
+
+// lockedStartWatch starts watching the whole backend for changes. Callers must hold mu.
+func (c *cachedStorage) lockedStartWatch(ctx context.Context) bool {
+	watchCtx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+	w, err := c.backend.Watch(watchCtx, "", storage.ListOptions{Recursive: true})
+	if err != nil {
+		cancel()
+		log.Printf("Warning: cache invalidation watch unavailable, caching disabled: %v", err)
+		return false
+	}
+
+	c.watching = true
+	c.stopWatch = cancel
+	go c.runWatch(w, cancel)
+	return true
+}
+
+// runWatch invalidates cached objects for every event on the backend's watch stream
+func (c *cachedStorage) runWatch(w watch.Interface, cancel context.CancelFunc) {
+	defer cancel()
+	defer w.Stop()
+
+	for event := range w.ResultChan() {
+		switch event.Type {
+		case watch.Added, watch.Modified, watch.Deleted:
+			c.invalidateObject(event.Object)
+		case watch.Error:
+			c.mu.Lock()
+			c.lockedPurge()
+			c.mu.Unlock()
+		}
+	}
+
+	// The stream ended, stop caching until it is restarted by the next read
+	c.mu.Lock()
+	c.watching = false
+	c.stopWatch = nil
+	c.lockedPurge()
+	c.mu.Unlock()
+}
+
+// nameKey returns the index key for a namespace and name
+func nameKey(namespace, name string) string {
+	return namespace + "/" + name
+}
@@ -0,0 +1,254 @@
+package storage
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/watch"
+	k8storage "k8s.io/apiserver/pkg/storage"
+
+	k1sstorage "github.com/dtomasi/k1s/core/storage"
+	memorystorage "github.com/dtomasi/k1s/storage/memory"
+	"github.com/dtomasi/k1s/storage/storagetest"
+)
+
+// countingBackend counts Get calls reaching the decorated backend
+type countingBackend struct {
+	k1sstorage.Backend
+
+	gets uint64
+
+	// watcher replaces the backend's watch stream when set
+	watcher watch.Interface
+}
+
+func (b *countingBackend) Get(ctx context.Context, key string, opts k8storage.GetOptions, objPtr runtime.Object) error {
+	atomic.AddUint64(&b.gets, 1)
+	return b.Backend.Get(ctx, key, opts, objPtr)
+}
+
+func (b *countingBackend) Watch(ctx context.Context, key string, opts k8storage.ListOptions) (watch.Interface, error) {
+	if b.watcher != nil {
+		return b.watcher, nil
+	}
+	return b.Backend.Watch(ctx, key, opts)
+}
+
+func (b *countingBackend) backendGets() uint64 {
+	return atomic.LoadUint64(&b.gets)
+}
+
+var _ = Describe("CachedStorage", func() {
+	var (
+		ctx     context.Context
+		backend *countingBackend
+		cache   k1sstorage.Backend
+	)
+
+	newCache := func(config Config) {
+		cache = NewCachedStorage(backend, config)
+		DeferCleanup(func() {
+			Expect(cache.Close()).To(Succeed())
+		})
+	}
+
+	create := func(name, value string) {
+		Expect(cache.Create(ctx, storagetest.Key(name), storagetest.NewTestObject(name, value), nil, 0)).To(Succeed())
+	}
+
+	get := func(name string) *storagetest.TestObject {
+		out := &storagetest.TestObject{}
+		Expect(cache.Get(ctx, storagetest.Key(name), k8storage.GetOptions{}, out)).To(Succeed())
+		return out
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		backend = &countingBackend{Backend: memorystorage.NewMemoryStorage(k1sstorage.Config{})}
+	})
+
+	Describe("NewCachedStorage", func() {
+		It("should apply defaults", func() {
+			newCache(Config{})
+			c := cache.(*cachedStorage)
+			Expect(c.size).To(Equal(DefaultSize))
+			Expect(c.ttl).To(Equal(DefaultTTL))
+		})
+
+		It("should disable expiry for a negative TTL", func() {
+			newCache(Config{TTL: -1})
+			Expect(cache.(*cachedStorage).ttl).To(BeZero())
+		})
+
+		It("should report the decorated backend", func() {
+			newCache(Config{})
+			Expect(cache.Name()).To(Equal(backend.Name()))
+			Expect(cache.(*cachedStorage).Unwrap()).To(BeIdenticalTo(backend))
+		})
+	})
+
+	Describe("Get", func() {
+		BeforeEach(func() {
+			newCache(Config{})
+			create("a", "one")
+		})
+
+		It("should serve repeated reads from the cache", func() {
+			Expect(get("a").Spec.Value).To(Equal("one"))
+			Expect(get("a").Spec.Value).To(Equal("one"))
+			Expect(backend.backendGets()).To(Equal(uint64(1)))
+
+			hits, misses, _ := cache.(*cachedStorage).GetCacheMetrics()
+			Expect(hits).To(Equal(uint64(1)))
+			Expect(misses).To(Equal(uint64(1)))
+		})
+
+		It("should hand out copies of cached objects", func() {
+			first := get("a")
+			first.Spec.Value = "changed"
+			Expect(get("a").Spec.Value).To(Equal("one"))
+		})
+
+		It("should read through for a different resourceVersion", func() {
+			current := get("a")
+			out := &storagetest.TestObject{}
+			err := cache.Get(ctx, storagetest.Key("a"), k8storage.GetOptions{ResourceVersion: current.ResourceVersion}, out)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(backend.backendGets()).To(Equal(uint64(1)))
+
+			_ = cache.Get(ctx, storagetest.Key("a"), k8storage.GetOptions{ResourceVersion: "999999"}, out)
+			Expect(backend.backendGets()).To(Equal(uint64(2)))
+		})
+
+		It("should not cache missing objects", func() {
+			out := &storagetest.TestObject{}
+			err := cache.Get(ctx, storagetest.Key("missing"), k8storage.GetOptions{}, out)
+			Expect(apierrors.IsNotFound(err)).To(BeTrue())
+			Expect(cache.(*cachedStorage).Len()).To(BeZero())
+		})
+
+		It("should not cache objects that are missing with IgnoreNotFound", func() {
+			out := &storagetest.TestObject{}
+			Expect(cache.Get(ctx, storagetest.Key("b"), k8storage.GetOptions{IgnoreNotFound: true}, out)).To(Succeed())
+			Expect(out.Name).To(BeEmpty())
+			Expect(cache.(*cachedStorage).Len()).To(BeZero())
+
+			// Create directly on the backend, so only the watch stream sees it
+			Expect(backend.Create(ctx, storagetest.Key("b"), storagetest.NewTestObject("b", "two"), nil, 0)).To(Succeed())
+			Expect(get("b").Spec.Value).To(Equal("two"))
+		})
+
+		It("should fail with a cancelled context", func() {
+			cancelled, cancel := context.WithCancel(ctx)
+			cancel()
+			err := cache.Get(cancelled, storagetest.Key("a"), k8storage.GetOptions{}, &storagetest.TestObject{})
+			Expect(k1sstorage.IsContextCancelled(err)).To(BeTrue())
+		})
+	})
+
+	Describe("Invalidation", func() {
+		BeforeEach(func() {
+			newCache(Config{})
+			create("a", "one")
+			get("a")
+		})
+
+		It("should invalidate on GuaranteedUpdate through the cache", func() {
+			out := &storagetest.TestObject{}
+			Expect(cache.GuaranteedUpdate(ctx, storagetest.Key("a"), out, false, nil,
+				func(input runtime.Object, _ k8storage.ResponseMeta) (runtime.Object, *uint64, error) {
+					obj := input.(*storagetest.TestObject)
+					obj.Spec.Value = "two"
+					return obj, nil, nil
+				}, nil)).To(Succeed())
+
+			Expect(get("a").Spec.Value).To(Equal("two"))
+		})
+
+		It("should invalidate on Delete through the cache", func() {
+			Expect(cache.Delete(ctx, storagetest.Key("a"), nil, nil, nil, nil)).To(Succeed())
+
+			err := cache.Get(ctx, storagetest.Key("a"), k8storage.GetOptions{}, &storagetest.TestObject{})
+			Expect(apierrors.IsNotFound(err)).To(BeTrue())
+		})
+
+		It("should invalidate on writes seen on the backend's watch stream", func() {
+			// Write directly to the backend, bypassing the cache
+			Expect(backend.GuaranteedUpdate(ctx, storagetest.Key("a"), &storagetest.TestObject{}, false, nil,
+				func(input runtime.Object, _ k8storage.ResponseMeta) (runtime.Object, *uint64, error) {
+					obj := input.(*storagetest.TestObject)
+					obj.Spec.Value = "external"
+					return obj, nil, nil
+				}, nil)).To(Succeed())
+
+			Eventually(func() string {
+				return get("a").Spec.Value
+			}).Should(Equal("external"))
+		})
+	})
+
+	Describe("LRU eviction", func() {
+		It("should evict the least recently used object", func() {
+			newCache(Config{Size: 2})
+			create("a", "a")
+			create("b", "b")
+			create("c", "c")
+
+			get("a")
+			get("b")
+			get("a") // a is now more recently used than b
+			get("c") // evicts b
+			Expect(backend.backendGets()).To(Equal(uint64(3)))
+
+			get("a")
+			Expect(backend.backendGets()).To(Equal(uint64(3)))
+			get("b")
+			Expect(backend.backendGets()).To(Equal(uint64(4)))
+
+			_, _, evictions := cache.(*cachedStorage).GetCacheMetrics()
+			Expect(evictions).To(Equal(uint64(2)))
+			Expect(cache.(*cachedStorage).Len()).To(Equal(2))
+		})
+	})
+
+	Describe("TTL", func() {
+		It("should read through once an entry has expired", func() {
+			// A silent watch stream, so only the TTL can invalidate
+			fake := watch.NewFake()
+			DeferCleanup(fake.Stop)
+			backend.watcher = fake
+
+			newCache(Config{TTL: 50 * time.Millisecond})
+			create("a", "one")
+
+			get("a")
+			get("a")
+			Expect(backend.backendGets()).To(Equal(uint64(1)))
+
+			time.Sleep(100 * time.Millisecond)
+			get("a")
+			Expect(backend.backendGets()).To(Equal(uint64(2)))
+		})
+	})
+
+	Describe("Watch stream", func() {
+		It("should stop caching when the stream ends", func() {
+			fake := watch.NewFake()
+			backend.watcher = fake
+
+			newCache(Config{})
+			create("a", "one")
+			get("a")
+			Expect(cache.(*cachedStorage).Len()).To(Equal(1))
+
+			fake.Stop()
+			Eventually(cache.(*cachedStorage).Len).Should(BeZero())
+		})
+	})
+})
@@ -0,0 +1,15 @@
+package storage_test
+
+import (
+	k1sstorage "github.com/dtomasi/k1s/core/storage"
+	memorystorage "github.com/dtomasi/k1s/storage/memory"
+	"github.com/dtomasi/k1s/storage/storagetest"
+
+	storage "github.com/dtomasi/k1s/storage/cache"
+)
+
+var _ = storagetest.DescribeBackend("cached memory", func() storagetest.Factory {
+	return func(config k1sstorage.Config) k1sstorage.Backend {
+		return storage.NewCachedStorage(memorystorage.NewMemoryStorage(config), storage.Config{})
+	}
+})
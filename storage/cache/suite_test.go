@@ -0,0 +1,13 @@
+package storage
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestCachedStorage(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Cached Storage Suite")
+}
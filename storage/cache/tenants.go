@@ -0,0 +1,73 @@
+package storage
+
+import (
+	"context"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+
+	k1sstorage "github.com/dtomasi/k1s/core/storage"
+)
+
+// Tenant management is passed through to the decorated backend. Backends
+// without tenant support fail every call with a MethodNotSupported error.
+var _ k1sstorage.TenantManager = &cachedStorage{}
+
+// CreateTenant registers a tenant
+func (c *cachedStorage) CreateTenant(ctx context.Context, tenant k1sstorage.Tenant) error {
+	tm, err := c.tenantManager("create")
+	if err != nil {
+		return err
+	}
+	return tm.CreateTenant(ctx, tenant)
+}
+
+// GetTenant returns a tenant
+func (c *cachedStorage) GetTenant(ctx context.Context, tenantID string) (*k1sstorage.Tenant, error) {
+	tm, err := c.tenantManager("get")
+	if err != nil {
+		return nil, err
+	}
+	return tm.GetTenant(ctx, tenantID)
+}
+
+// ListTenants returns all registered tenants and all tenants holding data
+func (c *cachedStorage) ListTenants(ctx context.Context) ([]k1sstorage.Tenant, error) {
+	tm, err := c.tenantManager("list")
+	if err != nil {
+		return nil, err
+	}
+	return tm.ListTenants(ctx)
+}
+
+// DeleteTenant removes a tenant together with all of its data and drops all cached objects
+func (c *cachedStorage) DeleteTenant(ctx context.Context, tenantID string) error {
+	tm, err := c.tenantManager("delete")
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		c.mu.Lock()
+		c.lockedPurge()
+		c.mu.Unlock()
+	}()
+	return tm.DeleteTenant(ctx, tenantID)
+}
+
+// CountTenant returns the number of objects stored by a tenant
+func (c *cachedStorage) CountTenant(ctx context.Context, tenantID string) (int64, error) {
+	tm, err := c.tenantManager("count")
+	if err != nil {
+		return 0, err
+	}
+	return tm.CountTenant(ctx, tenantID)
+}
+
+// tenantManager returns the decorated backend's tenant manager
+func (c *cachedStorage) tenantManager(action string) (k1sstorage.TenantManager, error) {
+	tm, ok := c.backend.(k1sstorage.TenantManager)
+	if !ok {
+		return nil, apierrors.NewMethodNotSupported(k1sstorage.TenantsResource, action)
+	}
+	return tm, nil
+}
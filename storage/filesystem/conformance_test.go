@@ -0,0 +1,17 @@
+package storage_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+
+	k1sstorage "github.com/dtomasi/k1s/core/storage"
+	"github.com/dtomasi/k1s/storage/storagetest"
+
+	storage "github.com/dtomasi/k1s/storage/filesystem"
+)
+
+var _ = storagetest.DescribeBackend("filesystem", func() storagetest.Factory {
+	root := GinkgoT().TempDir()
+	return func(config k1sstorage.Config) k1sstorage.Backend {
+		return storage.NewFilesystemStorageWithPath(root, config)
+	}
+})
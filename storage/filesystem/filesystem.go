@@ -0,0 +1,1465 @@
+package storage
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/apiserver/pkg/storage"
+	"sigs.k8s.io/yaml"
+
+	"github.com/fsnotify/fsnotify"
+
+	k1sstorage "github.com/dtomasi/k1s/core/storage"
+)
+
+// Error constants for goconst linter
+const (
+	errStorageIsClosed = "storage is closed"
+	errFailedToClose   = "failed to close"
+)
+
+const (
+	// DefaultRootDir is the directory used when no root directory is configured
+	DefaultRootDir = "./data/resources"
+
+	// metadataDir holds backend bookkeeping below the root directory. It is
+	// hidden so that it is skipped when scanning for resource files.
+	metadataDir = ".k1s"
+
+	// indexFile is the sidecar index inside metadataDir
+	indexFile = "index.json"
+
+	// indexLockFile is locked while the sidecar index is read and written
+	indexLockFile = "index.lock"
+
+	// fileExtension is the extension of resource files
+	fileExtension = ".yaml"
+
+	// coreGroupDir is the directory used for the empty (core) API group
+	coreGroupDir = "core"
+
+	// watchPollInterval is how often the tree is rescanned when inotify is unavailable
+	watchPollInterval = 500 * time.Millisecond
+
+	// watchDebounce coalesces bursts of inotify events into a single rescan
+	watchDebounce = 50 * time.Millisecond
+
+	// watchResyncInterval is how often the tree is rescanned even while inotify
+	// is active, as a safety net for filesystems that do not report all changes
+	watchResyncInterval = 2 * time.Second
+)
+
+// filesystemStorage implements a storage backend that keeps every object as a
+// human-readable YAML file, so that state can be reviewed and committed to git.
+//
+// A key such as /<group>/<version>/<resource>/<namespace>/<name> maps to
+// <root>/<group>/<version>/<resource>/<namespace>/<name>.yaml, where the empty
+// core group is stored in a "core" directory. Resource files do not contain a
+// resourceVersion; revisions live in a sidecar index so that files only change
+// when their content does. Files edited outside of k1s (by hand or by a git
+// checkout) are detected by content digest and surfaced as watch events.
+//
+// Processes sharing a root directory update the index under a file lock, so
+// they hand out distinct revisions and keep each other's entries. Each process
+// picks up the writes of the others through its directory watcher. Concurrent
+// writes of the same object by several processes are not coordinated; use the
+// SQLite backend when that is needed.
+type filesystemStorage struct {
+	// root is the directory holding resource files
+	root string
+
+	// baseDir is root joined with the tenant/namespace prefix
+	baseDir string
+
+	// mu protects the index, the object cache and all file operations
+	mu sync.Mutex
+
+	// index maps resource files to their keys and revisions
+	index *fileIndex
+
+	// objects caches the last known JSON encoding of every indexed file,
+	// used to report deleted objects to watchers
+	objects map[string][]byte
+
+	// watchers maintains active watches for keys/prefixes
+	watchers map[string][]*k1sstorage.SimpleWatch
+
+	// watchMu protects watcher operations
+	watchMu sync.RWMutex
+
+	// watcherStop stops the directory watcher
+	watcherStop chan struct{}
+
+	// watcherDone is closed when the directory watcher exits
+	watcherDone chan struct{}
+
+	// stale is set when the directory watcher reported changes made outside
+	// of this backend that the index does not reflect yet
+	stale atomic.Bool
+
+	// versioner handles resource version management
+	versioner k1sstorage.SimpleVersioner
+
+	// config contains storage configuration
+	config k1sstorage.Config
+
+	// metrics tracks operation statistics
+	metrics *filesystemMetrics
+
+	// closed indicates if the storage is closed
+	closed atomic.Bool
+}
+
+// filesystemMetrics tracks performance and operational metrics
+type filesystemMetrics struct {
+	operations uint64
+	errors     uint64
+	watchers   uint64
+}
+
+// fileIndex is the sidecar index persisted in <root>/.k1s/index.json
+type fileIndex struct {
+	// Revision is the last resourceVersion handed out
+	Revision uint64 `json:"revision"`
+
+	// Entries maps slash-separated file paths relative to the base directory to their metadata
+	Entries map[string]*indexEntry `json:"entries"`
+}
+
+// indexEntry records the state of a single resource file
+type indexEntry struct {
+	Key             string `json:"key"`
+	ResourceVersion uint64 `json:"resourceVersion"`
+	Digest          string `json:"digest"`
+	Size            int64  `json:"size"`
+	ModTime         int64  `json:"modTime"`
+	ExpiresAt       int64  `json:"expiresAt,omitempty"`
+}
+
+// expired reports whether the entry's TTL has elapsed
+func (e *indexEntry) expired(now time.Time) bool {
+	return e.ExpiresAt != 0 && e.ExpiresAt <= now.Unix()
+}
+
+// event is a watch notification collected while holding mu
+type event struct {
+	key       string
+	eventType watch.EventType
+	obj       runtime.Object
+}
+
+// NewFilesystemStorage creates a new filesystem storage backend using the default root directory
+func NewFilesystemStorage(config k1sstorage.Config) k1sstorage.Backend {
+	return NewFilesystemStorageWithPath(DefaultRootDir, config)
+}
+
+// NewFilesystemStorageWithPath creates a new filesystem storage backend rooted at a custom directory
+func NewFilesystemStorageWithPath(root string, config k1sstorage.Config) k1sstorage.Backend {
+	if root == "" {
+		root = DefaultRootDir
+	}
+
+	s := &filesystemStorage{
+		root:      root,
+		objects:   make(map[string][]byte),
+		watchers:  make(map[string][]*k1sstorage.SimpleWatch),
+		versioner: k1sstorage.SimpleVersioner{},
+		config:    config,
+		metrics:   &filesystemMetrics{},
+	}
+	s.baseDir = filepath.Join(append([]string{root}, s.prefixSegments()...)...)
+	return s
+}
+
+// Name returns the name of this storage backend
+func (s *filesystemStorage) Name() string {
+	return "filesystem"
+}
+
+// Versioner returns the storage versioner
+func (s *filesystemStorage) Versioner() storage.Versioner {
+	return s.versioner
+}
+
+// Root returns the directory holding resource files
+func (s *filesystemStorage) Root() string {
+	return s.root
+}
+
+// Create adds a new object at a key unless it already exists
+func (s *filesystemStorage) Create(ctx context.Context, key string, obj, out runtime.Object, ttl uint64) error {
+	if ctx.Err() != nil {
+		return k1sstorage.NewContextCancelledError(ctx)
+	}
+
+	// Apply key normalization
+	key = s.buildKey(key)
+	rel, err := keyToPath(key)
+	if err != nil {
+		atomic.AddUint64(&s.metrics.errors, 1)
+		return err
+	}
+
+	// Prepare object for storage
+	if err := s.versioner.PrepareObjectForStorage(obj); err != nil {
+		atomic.AddUint64(&s.metrics.errors, 1)
+		return fmt.Errorf("failed to prepare object for storage: %w", err)
+	}
+
+	s.mu.Lock()
+	events, err := s.lockedRefresh()
+	if err != nil {
+		s.mu.Unlock()
+		atomic.AddUint64(&s.metrics.errors, 1)
+		return err
+	}
+
+	// Check if key already exists
+	if entry, exists := s.index.Entries[rel]; exists && !entry.expired(time.Now()) {
+		s.mu.Unlock()
+		s.notifyAll(events)
+		atomic.AddUint64(&s.metrics.errors, 1)
+		// Use Kubernetes standard error type for already exists
+		gr := schema.GroupResource{Resource: "objects"} // Generic resource for storage
+		return apierrors.NewAlreadyExists(gr, key)
+	}
+
+	// Enforce the tenant's object quota
+	if err := s.lockedCheckQuota(key); err != nil {
+		s.mu.Unlock()
+		s.notifyAll(events)
+		atomic.AddUint64(&s.metrics.errors, 1)
+		return err
+	}
+
+	data, err := s.lockedWrite(key, rel, obj, expiresAt(ttl))
+	s.mu.Unlock()
+	s.notifyAll(events)
+	if err != nil {
+		atomic.AddUint64(&s.metrics.errors, 1)
+		return err
+	}
+
+	// Copy to output object if provided
+	if out != nil {
+		if err := json.Unmarshal(data, out); err != nil {
+			atomic.AddUint64(&s.metrics.errors, 1)
+			return fmt.Errorf("failed to unmarshal to output object: %w", err)
+		}
+	}
+
+	// Notify watchers
+	s.notifyWatchers(key, watch.Added, k1sstorage.WatchObject(data, obj))
+
+	atomic.AddUint64(&s.metrics.operations, 1)
+	return nil
+}
+
+// Delete removes the specified key and returns the value that existed at that key
+func (s *filesystemStorage) Delete(ctx context.Context, key string, out runtime.Object,
+	preconditions *storage.Preconditions, validateDeletion storage.ValidateObjectFunc,
+	cachedExistingObject runtime.Object) error {
+
+	if ctx.Err() != nil {
+		return k1sstorage.NewContextCancelledError(ctx)
+	}
+
+	// Apply key normalization
+	key = s.buildKey(key)
+	rel, err := keyToPath(key)
+	if err != nil {
+		atomic.AddUint64(&s.metrics.errors, 1)
+		return err
+	}
+
+	s.mu.Lock()
+	events, err := s.lockedRefresh()
+	if err != nil {
+		s.mu.Unlock()
+		atomic.AddUint64(&s.metrics.errors, 1)
+		return err
+	}
+
+	existingObj, data, err := s.lockedDelete(ctx, key, rel, out, preconditions, validateDeletion)
+	s.mu.Unlock()
+	s.notifyAll(events)
+	if err != nil {
+		atomic.AddUint64(&s.metrics.errors, 1)
+		return err
+	}
+
+	// Notify watchers, preferring the type of the cached object when available
+	like := existingObj
+	if cachedExistingObject != nil {
+		like = cachedExistingObject
+	}
+	s.notifyWatchers(key, watch.Deleted, k1sstorage.WatchObject(data, like))
+
+	atomic.AddUint64(&s.metrics.operations, 1)
+	return nil
+}
+
+// lockedDelete removes a resource file after checking preconditions and returns the
+// deleted object and its stored data. Callers must hold mu.
+func (s *filesystemStorage) lockedDelete(ctx context.Context, key, rel string, out runtime.Object,
+	preconditions *storage.Preconditions, validateDeletion storage.ValidateObjectFunc) (runtime.Object, []byte, error) {
+	entry, exists := s.index.Entries[rel]
+	if !exists || entry.expired(time.Now()) {
+		// Use Kubernetes standard error type for not found
+		gr := schema.GroupResource{Resource: "objects"} // Generic resource for storage
+		return nil, nil, apierrors.NewNotFound(gr, key)
+	}
+
+	data, err := s.readObject(rel, entry.ResourceVersion)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Decode the stored object so preconditions are checked against
+	// the persisted state rather than a possibly stale cached copy
+	var existingObj runtime.Object = &metav1.PartialObjectMetadata{}
+	if out != nil {
+		existingObj = out
+	}
+	if err := json.Unmarshal(data, existingObj); err != nil {
+		return nil, nil, fmt.Errorf("failed to unmarshal existing object: %w", err)
+	}
+
+	// Validate preconditions if provided
+	if err := k1sstorage.CheckPreconditions(key, existingObj, preconditions); err != nil {
+		return nil, nil, err
+	}
+
+	// Validate deletion if provided
+	if validateDeletion != nil {
+		if err := validateDeletion(ctx, existingObj); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	if err := s.lockedRemove(rel); err != nil {
+		return nil, nil, err
+	}
+
+	return existingObj, data, nil
+}
+
+// Get unmarshals object found at key into objPtr
+func (s *filesystemStorage) Get(ctx context.Context, key string, opts storage.GetOptions, objPtr runtime.Object) error {
+	if ctx.Err() != nil {
+		return k1sstorage.NewContextCancelledError(ctx)
+	}
+
+	// Apply key normalization
+	key = s.buildKey(key)
+	rel, err := keyToPath(key)
+	if err != nil {
+		atomic.AddUint64(&s.metrics.errors, 1)
+		return err
+	}
+
+	s.mu.Lock()
+	events, err := s.lockedRefresh()
+	if err != nil {
+		s.mu.Unlock()
+		atomic.AddUint64(&s.metrics.errors, 1)
+		return err
+	}
+
+	var data []byte
+	var storedVersion uint64
+	entry, exists := s.index.Entries[rel]
+	if exists && !entry.expired(time.Now()) {
+		storedVersion = entry.ResourceVersion
+		data, err = s.readObject(rel, storedVersion)
+	}
+	s.mu.Unlock()
+	s.notifyAll(events)
+
+	if err != nil {
+		atomic.AddUint64(&s.metrics.errors, 1)
+		return err
+	}
+	if data == nil {
+		atomic.AddUint64(&s.metrics.errors, 1)
+		if opts.IgnoreNotFound {
+			return nil
+		}
+		// Use Kubernetes standard error type for not found
+		gr := schema.GroupResource{Resource: "objects"} // Generic resource for storage
+		return apierrors.NewNotFound(gr, key)
+	}
+
+	// Check resource version if specified
+	if opts.ResourceVersion != "" {
+		requestedVersion, err := s.versioner.ParseWatchResourceVersion(opts.ResourceVersion)
+		if err != nil {
+			atomic.AddUint64(&s.metrics.errors, 1)
+			return fmt.Errorf("failed to parse resource version %s: %w", opts.ResourceVersion, err)
+		}
+
+		if requestedVersion != 0 && requestedVersion != storedVersion {
+			atomic.AddUint64(&s.metrics.errors, 1)
+			return fmt.Errorf("resource version mismatch: requested %s, stored %d", opts.ResourceVersion, storedVersion)
+		}
+	}
+
+	// Unmarshal data into objPtr
+	if err := json.Unmarshal(data, objPtr); err != nil {
+		atomic.AddUint64(&s.metrics.errors, 1)
+		return fmt.Errorf("failed to unmarshal object: %w", err)
+	}
+
+	atomic.AddUint64(&s.metrics.operations, 1)
+	return nil
+}
+
+// List unmarshalls objects found at key into a List api object
+func (s *filesystemStorage) List(ctx context.Context, key string, opts storage.ListOptions, listObj runtime.Object) error {
+	if ctx.Err() != nil {
+		return k1sstorage.NewContextCancelledError(ctx)
+	}
+
+	// Apply key normalization
+	key = s.buildKey(key)
+
+	s.mu.Lock()
+	events, err := s.lockedRefresh()
+	if err != nil {
+		s.mu.Unlock()
+		atomic.AddUint64(&s.metrics.errors, 1)
+		return err
+	}
+
+	var items [][]byte
+	var maxResourceVersion uint64
+	for _, rel := range s.lockedMatch(key, opts.Recursive) {
+		entry := s.index.Entries[rel]
+		data, readErr := s.readObject(rel, entry.ResourceVersion)
+		if readErr != nil {
+			err = readErr
+			break
+		}
+		items = append(items, data)
+
+		// Track max resource version
+		if entry.ResourceVersion > maxResourceVersion {
+			maxResourceVersion = entry.ResourceVersion
+		}
+	}
+	s.mu.Unlock()
+	s.notifyAll(events)
+
+	if err != nil {
+		atomic.AddUint64(&s.metrics.errors, 1)
+		return err
+	}
+
+	// Set list metadata
+	if err := s.versioner.UpdateList(listObj, maxResourceVersion, "", nil); err != nil {
+		atomic.AddUint64(&s.metrics.errors, 1)
+		return fmt.Errorf("failed to update list metadata: %w", err)
+	}
+
+	// Set the items in the list
+	if err := k1sstorage.SetListItems(listObj, items); err != nil {
+		atomic.AddUint64(&s.metrics.errors, 1)
+		return fmt.Errorf("failed to set list items: %w", err)
+	}
+
+	atomic.AddUint64(&s.metrics.operations, 1)
+	return nil
+}
+
+// Watch begins watching a specific key or key prefix for changes. Writes made
+// through this backend are delivered immediately; changes made to the files
+// by other means are detected via inotify, or by polling where inotify is not
+// available, and delivered as unstructured objects.
+func (s *filesystemStorage) Watch(ctx context.Context, key string, opts storage.ListOptions) (watch.Interface, error) {
+	if ctx.Err() != nil {
+		return nil, k1sstorage.NewContextCancelledError(ctx)
+	}
+
+	if s.closed.Load() {
+		return nil, errors.New(errStorageIsClosed)
+	}
+
+	// Apply key normalization
+	key = s.buildKey(key)
+
+	s.mu.Lock()
+	events, err := s.lockedRefresh()
+	s.mu.Unlock()
+	s.notifyAll(events)
+	if err != nil {
+		atomic.AddUint64(&s.metrics.errors, 1)
+		return nil, err
+	}
+
+	// Create watch instance
+	w := k1sstorage.NewSimpleWatch()
+
+	s.watchMu.Lock()
+	// Add to watchers
+	s.watchers[key] = append(s.watchers[key], w)
+	atomic.AddUint64(&s.metrics.watchers, 1)
+	s.watchMu.Unlock()
+
+	// Start background cleanup when context is cancelled
+	go func() {
+		<-ctx.Done()
+		s.removeWatcher(key, w)
+		w.Stop()
+	}()
+
+	// Send initial events if requested
+	if opts.SendInitialEvents != nil && *opts.SendInitialEvents {
+		s.mu.Lock()
+		var initial []runtime.Object
+		for _, rel := range s.lockedMatch(key, opts.Recursive) {
+			data, err := s.readObject(rel, s.index.Entries[rel].ResourceVersion)
+			if err != nil {
+				// Log error but continue with other objects
+				log.Printf("Warning: failed to read object for watch event: %v", err)
+				continue
+			}
+			obj, err := k1sstorage.DecodeUnstructured(data)
+			if err != nil {
+				log.Printf("Warning: failed to unmarshal object for watch event: %v", err)
+				continue
+			}
+			initial = append(initial, obj)
+		}
+		s.mu.Unlock()
+
+		for _, obj := range initial {
+			w.Send(watch.Added, obj)
+		}
+	}
+
+	return w, nil
+}
+
+// Close closes the storage backend and cleans up resources
+func (s *filesystemStorage) Close() error {
+	s.closed.Store(true)
+
+	// Stop all watchers first so that a directory watcher blocked in Send is released
+	s.watchMu.Lock()
+	for _, watchList := range s.watchers {
+		for _, w := range watchList {
+			w.Stop()
+		}
+	}
+
+	// Clear watchers
+	s.watchers = make(map[string][]*k1sstorage.SimpleWatch)
+	s.watchMu.Unlock()
+
+	// Stop the directory watcher
+	s.mu.Lock()
+	stop, done := s.watcherStop, s.watcherDone
+	s.watcherStop, s.watcherDone = nil, nil
+	s.mu.Unlock()
+	if stop != nil {
+		close(stop)
+		<-done
+	}
+
+	return nil
+}
+
+// Compact removes the files of expired objects and prunes the sidecar index
+func (s *filesystemStorage) Compact(ctx context.Context) error {
+	if ctx.Err() != nil {
+		return k1sstorage.NewContextCancelledError(ctx)
+	}
+
+	s.mu.Lock()
+	events, err := s.lockedRefresh()
+	if err != nil {
+		s.mu.Unlock()
+		return err
+	}
+
+	now := time.Now()
+	for rel, entry := range s.index.Entries {
+		if !entry.expired(now) {
+			continue
+		}
+		obj, decodeErr := k1sstorage.DecodeUnstructured(s.objects[rel])
+		if err = s.lockedRemove(rel); err != nil {
+			break
+		}
+		if decodeErr == nil {
+			events = append(events, event{key: entry.Key, eventType: watch.Deleted, obj: obj})
+		}
+	}
+	s.mu.Unlock()
+	s.notifyAll(events)
+
+	return err
+}
+
+// Count returns the number of objects stored under the given key prefix
+func (s *filesystemStorage) Count(ctx context.Context, key string) (int64, error) {
+	if ctx.Err() != nil {
+		return 0, k1sstorage.NewContextCancelledError(ctx)
+	}
+
+	// Apply key normalization
+	key = s.buildKey(key)
+
+	s.mu.Lock()
+	events, err := s.lockedRefresh()
+	count := int64(len(s.lockedMatch(key, true)))
+	s.mu.Unlock()
+	s.notifyAll(events)
+	if err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
+// GuaranteedUpdate implements storage.Interface. The read, tryUpdate and write
+// all happen while holding the storage lock, so no conflict retry loop is needed.
+func (s *filesystemStorage) GuaranteedUpdate(ctx context.Context, key string, destination runtime.Object, ignoreNotFound bool,
+	preconditions *storage.Preconditions, tryUpdate storage.UpdateFunc, cachedExistingObject runtime.Object) error {
+	if ctx.Err() != nil {
+		return k1sstorage.NewContextCancelledError(ctx)
+	}
+
+	// Apply key normalization
+	key = s.buildKey(key)
+	rel, err := keyToPath(key)
+	if err != nil {
+		atomic.AddUint64(&s.metrics.errors, 1)
+		return err
+	}
+
+	s.mu.Lock()
+	events, err := s.lockedRefresh()
+	if err != nil {
+		s.mu.Unlock()
+		atomic.AddUint64(&s.metrics.errors, 1)
+		return err
+	}
+
+	updated, data, exists, err := s.lockedUpdate(key, rel, destination, ignoreNotFound, preconditions, tryUpdate)
+	s.mu.Unlock()
+	s.notifyAll(events)
+	if err != nil {
+		atomic.AddUint64(&s.metrics.errors, 1)
+		return err
+	}
+
+	// Copy to destination
+	if err := json.Unmarshal(data, destination); err != nil {
+		atomic.AddUint64(&s.metrics.errors, 1)
+		return fmt.Errorf("failed to copy to destination: %w", err)
+	}
+
+	// Notify watchers
+	eventType := watch.Modified
+	if !exists {
+		eventType = watch.Added
+	}
+	s.notifyWatchers(key, eventType, k1sstorage.WatchObject(data, updated))
+
+	atomic.AddUint64(&s.metrics.operations, 1)
+	return nil
+}
+
+// lockedUpdate applies tryUpdate to the current object and writes the result. Callers must hold mu.
+func (s *filesystemStorage) lockedUpdate(key, rel string, destination runtime.Object, ignoreNotFound bool,
+	preconditions *storage.Preconditions, tryUpdate storage.UpdateFunc) (runtime.Object, []byte, bool, error) {
+	// Get current object
+	current := destination.DeepCopyObject()
+	entry, exists := s.index.Entries[rel]
+	exists = exists && !entry.expired(time.Now())
+
+	var storedVersion uint64
+	if exists {
+		storedVersion = entry.ResourceVersion
+		data, err := s.readObject(rel, storedVersion)
+		if err != nil {
+			return nil, nil, false, err
+		}
+		if err := json.Unmarshal(data, current); err != nil {
+			return nil, nil, false, fmt.Errorf("failed to deserialize current object: %w", err)
+		}
+
+		// Check preconditions if provided
+		if err := k1sstorage.CheckPreconditions(key, current, preconditions); err != nil {
+			return nil, nil, false, err
+		}
+	} else if !ignoreNotFound {
+		// Use Kubernetes standard error type for not found
+		gr := schema.GroupResource{Resource: "objects"} // Generic resource for storage
+		return nil, nil, false, apierrors.NewNotFound(gr, key)
+	} else if err := s.lockedCheckQuota(key); err != nil {
+		// Creating the object counts towards the tenant's object quota
+		return nil, nil, false, err
+	}
+
+	// Try the update
+	updated, ttl, err := tryUpdate(current, storage.ResponseMeta{ResourceVersion: storedVersion})
+	if err != nil {
+		return nil, nil, false, err
+	}
+
+	var expires int64
+	if ttl != nil {
+		expires = expiresAt(*ttl)
+	}
+	data, err := s.lockedWrite(key, rel, updated, expires)
+	if err != nil {
+		return nil, nil, false, err
+	}
+
+	return updated, data, exists, nil
+}
+
+// RequestWatchProgress implements storage.Interface
+func (s *filesystemStorage) RequestWatchProgress(ctx context.Context) error {
+	// Rescan the tree so that watchers observe every change made so far,
+	// even if the corresponding inotify events are still in flight
+	if s.closed.Load() {
+		return nil
+	}
+	s.resync()
+	return nil
+}
+
+// RequestProgress implements storage.Interface
+func (s *filesystemStorage) RequestProgress(ctx context.Context) error {
+	// Rescan the tree on the next read, so that it observes every change made
+	// so far, even if the corresponding inotify events are still in flight
+	s.stale.Store(true)
+	return nil
+}
+
+// GetMetrics returns current performance metrics
+func (s *filesystemStorage) GetMetrics() (operations, errors, watchers uint64) {
+	return atomic.LoadUint64(&s.metrics.operations),
+		atomic.LoadUint64(&s.metrics.errors),
+		atomic.LoadUint64(&s.metrics.watchers)
+}
+
+// lockedInit creates the base directory and loads the sidecar index. Callers must hold mu.
+func (s *filesystemStorage) lockedInit() error {
+	if s.closed.Load() {
+		return errors.New(errStorageIsClosed)
+	}
+
+	if s.index != nil {
+		return nil
+	}
+
+	if err := os.MkdirAll(filepath.Join(s.baseDir, metadataDir), 0o750); err != nil {
+		return fmt.Errorf("failed to create storage directory %s: %w", s.baseDir, err)
+	}
+
+	index := &fileIndex{Entries: make(map[string]*indexEntry)}
+	data, err := os.ReadFile(s.indexPath())
+	switch {
+	case errors.Is(err, fs.ErrNotExist):
+		// Fresh directory, start with an empty index
+	case err != nil:
+		return fmt.Errorf("failed to read index: %w", err)
+	default:
+		if err := json.Unmarshal(data, index); err != nil {
+			return fmt.Errorf("failed to parse index %s: %w", s.indexPath(), err)
+		}
+		if index.Entries == nil {
+			index.Entries = make(map[string]*indexEntry)
+		}
+	}
+
+	s.index = index
+	return nil
+}
+
+// lockedSync reconciles the sidecar index with the files on disk and returns
+// watch events for changes made outside of this backend. Callers must hold mu.
+func (s *filesystemStorage) lockedSync() ([]event, error) {
+	if err := s.lockedInit(); err != nil {
+		return nil, err
+	}
+	persisted, unlock, err := s.lockedLockIndex()
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	seen := make(map[string]struct{}, len(s.index.Entries))
+	var events []event
+	var changed []string
+
+	err = filepath.WalkDir(s.baseDir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		// Skip hidden files and directories, including the metadata
+		// directory and temporary files of in-flight atomic writes
+		if path != s.baseDir && strings.HasPrefix(d.Name(), ".") {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if d.IsDir() || !strings.HasSuffix(d.Name(), fileExtension) {
+			return nil
+		}
+
+		relPath, err := filepath.Rel(s.baseDir, path)
+		if err != nil {
+			return err
+		}
+		rel := filepath.ToSlash(relPath)
+		seen[rel] = struct{}{}
+
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+
+		entry, exists := s.index.Entries[rel]
+		if exists && entry.Size == info.Size() && entry.ModTime == info.ModTime().UnixNano() {
+			return nil
+		}
+
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %w", path, err)
+		}
+		digest := digestOf(content)
+		changed = append(changed, rel)
+
+		if exists && entry.Digest == digest {
+			// Touched but not modified
+			entry.Size, entry.ModTime = info.Size(), info.ModTime().UnixNano()
+			return nil
+		}
+
+		eventType := watch.Modified
+		if !exists {
+			eventType = watch.Added
+			entry = &indexEntry{Key: pathToKey(rel)}
+			s.index.Entries[rel] = entry
+		}
+		if shared, ok := persisted.Entries[rel]; ok && shared.Digest == digest {
+			// Written by another process sharing the directory, which
+			// already handed out its revision
+			entry.ResourceVersion, entry.ExpiresAt = shared.ResourceVersion, shared.ExpiresAt
+		} else {
+			s.index.Revision++
+			entry.ResourceVersion = s.index.Revision
+		}
+		entry.Digest = digest
+		entry.Size, entry.ModTime = info.Size(), info.ModTime().UnixNano()
+
+		data, err := encodeJSON(content, entry.ResourceVersion)
+		if err != nil {
+			log.Printf("Warning: failed to decode resource file %s: %v", path, err)
+			delete(s.objects, rel)
+			return nil
+		}
+		s.objects[rel] = data
+
+		if obj, err := k1sstorage.DecodeUnstructured(data); err == nil {
+			events = append(events, event{key: entry.Key, eventType: eventType, obj: obj})
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan storage directory: %w", err)
+	}
+
+	// Files that disappeared were deleted outside of this backend
+	for rel, entry := range s.index.Entries {
+		if _, ok := seen[rel]; ok {
+			continue
+		}
+		changed = append(changed, rel)
+		if obj, err := k1sstorage.DecodeUnstructured(s.objects[rel]); err == nil {
+			events = append(events, event{key: entry.Key, eventType: watch.Deleted, obj: obj})
+		}
+		delete(s.index.Entries, rel)
+		delete(s.objects, rel)
+	}
+
+	if len(changed) > 0 {
+		if err := s.lockedSaveIndex(persisted, changed...); err != nil {
+			return nil, err
+		}
+	}
+
+	return events, nil
+}
+
+// lockedWrite writes obj to its resource file with the next revision and
+// records it in the index. It returns the JSON encoding of the stored object
+// including its resourceVersion. Callers must hold mu.
+func (s *filesystemStorage) lockedWrite(key, rel string, obj runtime.Object, expires int64) ([]byte, error) {
+	persisted, unlock, err := s.lockedLockIndex()
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	revision := s.index.Revision + 1
+	if err := s.versioner.UpdateObject(obj, revision); err != nil {
+		return nil, fmt.Errorf("failed to update resource version: %w", err)
+	}
+
+	// Serialize object without its resourceVersion, which lives in the index
+	data, err := json.Marshal(obj)
+	if err != nil {
+		return nil, fmt.Errorf("failed to serialize object: %w", err)
+	}
+	content, err := encodeYAML(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to serialize object: %w", err)
+	}
+
+	path := filepath.Join(s.baseDir, filepath.FromSlash(rel))
+	if err := os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+		return nil, fmt.Errorf("failed to create directory for %s: %w", key, err)
+	}
+	if err := writeFileAtomic(path, content); err != nil {
+		return nil, fmt.Errorf("failed to write %s: %w", key, err)
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to stat %s: %w", key, err)
+	}
+
+	s.index.Revision = revision
+	s.index.Entries[rel] = &indexEntry{
+		Key:             key,
+		ResourceVersion: revision,
+		Digest:          digestOf(content),
+		Size:            info.Size(),
+		ModTime:         info.ModTime().UnixNano(),
+		ExpiresAt:       expires,
+	}
+	s.objects[rel] = data
+
+	if err := s.lockedSaveIndex(persisted, rel); err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+// lockedRemove deletes a resource file along with any directories left empty. Callers must hold mu.
+func (s *filesystemStorage) lockedRemove(rel string) error {
+	persisted, unlock, err := s.lockedLockIndex()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	path := filepath.Join(s.baseDir, filepath.FromSlash(rel))
+	if err := os.Remove(path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("failed to delete %s: %w", path, err)
+	}
+
+	// Remove now-empty parent directories to keep the tree tidy for git
+	for dir := filepath.Dir(path); dir != s.baseDir && strings.HasPrefix(dir, s.baseDir); dir = filepath.Dir(dir) {
+		if err := os.Remove(dir); err != nil {
+			break
+		}
+	}
+
+	s.index.Revision++
+	delete(s.index.Entries, rel)
+	delete(s.objects, rel)
+
+	return s.lockedSaveIndex(persisted, rel)
+}
+
+// lockedRefresh brings the index up to date with the files on disk and
+// returns watch events for changes made outside of this backend. The tree is
+// scanned when the index is loaded, and afterwards only once the directory
+// watcher reported such changes. Callers must hold mu.
+func (s *filesystemStorage) lockedRefresh() ([]event, error) {
+	if s.index != nil && s.watcherStop != nil && !s.stale.Swap(false) {
+		return nil, nil
+	}
+
+	events, err := s.lockedSync()
+	if err != nil {
+		return nil, err
+	}
+	s.lockedStartWatcher()
+	return events, nil
+}
+
+// lockedLockIndex locks the index file, so that processes sharing the
+// directory never hand out the same revision, and catches the revision
+// counter up with the persisted index. It returns the persisted index, which
+// holds the entries of all processes, and a function that releases the lock.
+// Callers must hold mu.
+func (s *filesystemStorage) lockedLockIndex() (*fileIndex, func(), error) {
+	f, err := os.OpenFile(filepath.Join(s.baseDir, metadataDir, indexLockFile), os.O_RDWR|os.O_CREATE, 0o600)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to open index lock: %w", err)
+	}
+	if err := lockFile(f); err != nil {
+		_ = f.Close()
+		return nil, nil, fmt.Errorf("failed to lock index: %w", err)
+	}
+	unlock := func() {
+		if err := unlockFile(f); err != nil {
+			log.Printf("Warning: failed to unlock index: %v", err)
+		}
+		if err := f.Close(); err != nil {
+			log.Printf("Warning: %s index lock: %v", errFailedToClose, err)
+		}
+	}
+
+	persisted := &fileIndex{}
+	data, err := os.ReadFile(s.indexPath())
+	if err == nil {
+		err = json.Unmarshal(data, persisted)
+	}
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		unlock()
+		return nil, nil, fmt.Errorf("failed to read index: %w", err)
+	}
+	if persisted.Entries == nil {
+		persisted.Entries = make(map[string]*indexEntry)
+	}
+	s.index.Revision = max(s.index.Revision, persisted.Revision)
+	return persisted, unlock, nil
+}
+
+// lockedSaveIndex atomically persists the sidecar index. It updates the
+// entries of the changed files in the persisted index and keeps all other
+// entries, which may have been written by other processes sharing the
+// directory. Callers must hold mu and the index lock.
+func (s *filesystemStorage) lockedSaveIndex(persisted *fileIndex, changed ...string) error {
+	persisted.Revision = s.index.Revision
+	for _, rel := range changed {
+		if entry, ok := s.index.Entries[rel]; ok {
+			persisted.Entries[rel] = entry
+		} else {
+			delete(persisted.Entries, rel)
+		}
+	}
+
+	data, err := json.MarshalIndent(persisted, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to serialize index: %w", err)
+	}
+	if err := writeFileAtomic(s.indexPath(), data); err != nil {
+		return fmt.Errorf("failed to write index: %w", err)
+	}
+	return nil
+}
+
+// lockedMatch returns the sorted files whose keys match key. Callers must hold mu.
+func (s *filesystemStorage) lockedMatch(key string, recursive bool) []string {
+	now := time.Now()
+	var matches []string
+	for rel, entry := range s.index.Entries {
+		if entry.expired(now) {
+			continue
+		}
+		if (recursive && strings.HasPrefix(entry.Key, key)) || entry.Key == key {
+			matches = append(matches, rel)
+		}
+	}
+	sort.Slice(matches, func(i, j int) bool {
+		return s.index.Entries[matches[i]].Key < s.index.Entries[matches[j]].Key
+	})
+	return matches
+}
+
+// readObject returns the JSON encoding of a resource file with its
+// resourceVersion set. Callers must hold mu.
+func (s *filesystemStorage) readObject(rel string, resourceVersion uint64) ([]byte, error) {
+	if data, ok := s.objects[rel]; ok {
+		return data, nil
+	}
+
+	content, err := os.ReadFile(filepath.Join(s.baseDir, filepath.FromSlash(rel)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", rel, err)
+	}
+	data, err := encodeJSON(content, resourceVersion)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode %s: %w", rel, err)
+	}
+	s.objects[rel] = data
+	return data, nil
+}
+
+// indexPath returns the location of the sidecar index
+func (s *filesystemStorage) indexPath() string {
+	return filepath.Join(s.baseDir, metadataDir, indexFile)
+}
+
+// lockedStartWatcher starts the directory watcher if it is not already running. Callers must hold mu.
+func (s *filesystemStorage) lockedStartWatcher() {
+	if s.watcherStop != nil {
+		return
+	}
+	s.watcherStop = make(chan struct{})
+	s.watcherDone = make(chan struct{})
+	go s.runWatcher(s.watcherStop, s.watcherDone)
+}
+
+// runWatcher rescans the tree whenever inotify reports a change, and
+// periodically in case some changes were not reported. If inotify cannot be
+// used, it falls back to rescanning on a short fixed interval.
+func (s *filesystemStorage) runWatcher(stopCh <-chan struct{}, doneCh chan<- struct{}) {
+	defer close(doneCh)
+
+	fsWatcher, err := fsnotify.NewWatcher()
+	if err == nil {
+		if err = addWatchDirs(fsWatcher, s.baseDir); err != nil {
+			_ = fsWatcher.Close()
+		}
+	}
+	if err != nil {
+		log.Printf("Warning: inotify unavailable, polling %s for changes: %v", s.baseDir, err)
+		s.pollChanges(stopCh)
+		return
+	}
+	defer func() {
+		if err := fsWatcher.Close(); err != nil {
+			log.Printf("Warning: %s file watcher: %v", errFailedToClose, err)
+		}
+	}()
+
+	// Changes made before the directories were watched went unnoticed
+	s.resync()
+
+	resync := time.NewTicker(watchResyncInterval)
+	defer resync.Stop()
+
+	var debounce <-chan time.Time
+	for {
+		select {
+		case <-stopCh:
+			return
+		case <-resync.C:
+			s.resync()
+		case ev, ok := <-fsWatcher.Events:
+			if !ok {
+				s.pollChanges(stopCh)
+				return
+			}
+			if strings.HasPrefix(filepath.Base(ev.Name), ".") {
+				continue
+			}
+			// Watch directories created after startup, e.g. for new namespaces
+			if ev.Has(fsnotify.Create) {
+				if info, err := os.Stat(ev.Name); err == nil && info.IsDir() {
+					if err := addWatchDirs(fsWatcher, ev.Name); err != nil {
+						log.Printf("Warning: failed to watch %s: %v", ev.Name, err)
+					}
+				}
+			}
+			if s.ownChange(ev.Name) {
+				continue
+			}
+			s.stale.Store(true)
+			if debounce == nil {
+				debounce = time.After(watchDebounce)
+			}
+		case err, ok := <-fsWatcher.Errors:
+			if !ok {
+				s.pollChanges(stopCh)
+				return
+			}
+			// Events may have been lost, so rescan to be safe
+			log.Printf("Warning: file watcher error: %v", err)
+			s.stale.Store(true)
+			if debounce == nil {
+				debounce = time.After(watchDebounce)
+			}
+		case <-debounce:
+			debounce = nil
+			s.resync()
+		}
+	}
+}
+
+// ownChange reports whether the resource file at path is in the state this
+// backend last wrote, so that inotify events for its own writes do not cause
+// rescans
+func (s *filesystemStorage) ownChange(path string) bool {
+	relPath, err := filepath.Rel(s.baseDir, path)
+	if err != nil || !strings.HasSuffix(relPath, fileExtension) {
+		return false
+	}
+	info, statErr := os.Stat(path)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.index == nil {
+		return false
+	}
+	entry, indexed := s.index.Entries[filepath.ToSlash(relPath)]
+	if errors.Is(statErr, fs.ErrNotExist) {
+		return !indexed
+	}
+	return statErr == nil && indexed && entry.Size == info.Size() && entry.ModTime == info.ModTime().UnixNano()
+}
+
+// pollChanges rescans the tree on a fixed interval until stopCh is closed
+func (s *filesystemStorage) pollChanges(stopCh <-chan struct{}) {
+	ticker := time.NewTicker(watchPollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stopCh:
+			return
+		case <-ticker.C:
+			s.resync()
+		}
+	}
+}
+
+// resync rescans the tree and notifies watchers about external changes
+func (s *filesystemStorage) resync() {
+	if s.closed.Load() {
+		return
+	}
+
+	s.mu.Lock()
+	events, err := s.lockedSync()
+	s.mu.Unlock()
+	if err != nil {
+		log.Printf("Warning: failed to rescan %s: %v", s.baseDir, err)
+	}
+	s.notifyAll(events)
+}
+
+// prefixSegments returns the tenant/namespace directories configured for this backend
+func (s *filesystemStorage) prefixSegments() []string {
+	parts := []string{}
+
+	// Add tenant prefix if configured
+	if s.config.TenantID != "" {
+		parts = append(parts, k1sstorage.TenantsKeyPrefix, k1sstorage.EscapeTenantID(s.config.TenantID))
+	}
+
+	// Add custom key prefix if configured
+	if s.config.KeyPrefix != "" {
+		parts = append(parts, s.config.KeyPrefix)
+	}
+
+	// Add namespace prefix if configured
+	if s.config.Namespace != "" {
+		parts = append(parts, "namespaces", s.config.Namespace)
+	}
+
+	return parts
+}
+
+// buildKey normalizes a key relative to the base directory. Tenant and
+// namespace prefixes are applied through the base directory instead.
+func (s *filesystemStorage) buildKey(key string) string {
+	return strings.TrimPrefix(key, "/")
+}
+
+// notifyAll delivers collected watch events
+func (s *filesystemStorage) notifyAll(events []event) {
+	for _, e := range events {
+		s.notifyWatchers(e.key, e.eventType, e.obj)
+	}
+}
+
+// notifyWatchers sends watch events to all registered watchers
+func (s *filesystemStorage) notifyWatchers(key string, eventType watch.EventType, obj runtime.Object) {
+	s.watchMu.RLock()
+	defer s.watchMu.RUnlock()
+
+	// Notify direct key watchers
+	if watchers, exists := s.watchers[key]; exists {
+		for _, w := range watchers {
+			w.Send(eventType, obj)
+		}
+	}
+
+	// Notify prefix watchers
+	for watchKey, watchers := range s.watchers {
+		if watchKey != key && strings.HasPrefix(key, watchKey) {
+			for _, w := range watchers {
+				w.Send(eventType, obj)
+			}
+		}
+	}
+}
+
+// removeWatcher removes a watcher from the watchers map
+func (s *filesystemStorage) removeWatcher(key string, watcher *k1sstorage.SimpleWatch) {
+	s.watchMu.Lock()
+	defer s.watchMu.Unlock()
+
+	if watchers, exists := s.watchers[key]; exists {
+		for i, w := range watchers {
+			if w == watcher {
+				// Remove watcher from slice
+				s.watchers[key] = append(watchers[:i], watchers[i+1:]...)
+				atomic.AddUint64(&s.metrics.watchers, ^uint64(0)) // atomic decrement
+				break
+			}
+		}
+
+		// Clean up empty watcher lists
+		if len(s.watchers[key]) == 0 {
+			delete(s.watchers, key)
+		}
+	}
+}
+
+// keyToPath maps a normalized key to a slash-separated file path relative to
+// the base directory. The leading empty segment of core group keys such as
+// "/v1/configmaps/default/foo" is stored in the "core" directory.
+func keyToPath(key string) (string, error) {
+	segments := strings.Split(key, "/")
+	for i, segment := range segments {
+		switch {
+		case segment == "" && i == 0 && len(segments) > 1:
+			segments[i] = coreGroupDir
+		case i == 0 && segment == coreGroupDir:
+			return "", fmt.Errorf("invalid key %q: %q is reserved for the core API group", key, coreGroupDir)
+		case segment == "" || strings.HasPrefix(segment, ".") || strings.ContainsAny(segment, `\`):
+			return "", fmt.Errorf("invalid key %q: segment %q cannot be stored as a file", key, segment)
+		}
+	}
+	return strings.Join(segments, "/") + fileExtension, nil
+}
+
+// pathToKey is the inverse of keyToPath
+func pathToKey(rel string) string {
+	segments := strings.Split(strings.TrimSuffix(rel, fileExtension), "/")
+	if len(segments) > 1 && segments[0] == coreGroupDir {
+		segments[0] = ""
+	}
+	return strings.Join(segments, "/")
+}
+
+// addWatchDirs adds dir and all non-hidden subdirectories to the watcher
+func addWatchDirs(w *fsnotify.Watcher, dir string) error {
+	return filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.IsDir() {
+			return nil
+		}
+		if path != dir && strings.HasPrefix(d.Name(), ".") {
+			return filepath.SkipDir
+		}
+		return w.Add(path)
+	})
+}
+
+// writeFileAtomic writes data to a temporary file next to path and renames
+// it into place, so readers never observe a partially written file
+func writeFileAtomic(path string, data []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return err
+	}
+	tmpPath := tmp.Name()
+
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		_ = os.Remove(tmpPath)
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		_ = tmp.Close()
+		_ = os.Remove(tmpPath)
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		_ = os.Remove(tmpPath)
+		return err
+	}
+	if err := os.Chmod(tmpPath, 0o644); err != nil { //nolint:gosec // resource files are meant to be shared via git
+		_ = os.Remove(tmpPath)
+		return err
+	}
+
+	if err := os.Rename(tmpPath, path); err != nil {
+		_ = os.Remove(tmpPath)
+		return err
+	}
+	return nil
+}
+
+// encodeYAML converts a JSON object into YAML without its resourceVersion
+func encodeYAML(data []byte) ([]byte, error) {
+	content := map[string]interface{}{}
+	if err := json.Unmarshal(data, &content); err != nil {
+		return nil, err
+	}
+	if metadata, ok := content["metadata"].(map[string]interface{}); ok {
+		delete(metadata, "resourceVersion")
+	}
+	return yaml.Marshal(content)
+}
+
+// encodeJSON converts a YAML resource file into JSON with the given resourceVersion
+func encodeJSON(content []byte, resourceVersion uint64) ([]byte, error) {
+	data, err := yaml.YAMLToJSON(content)
+	if err != nil {
+		return nil, err
+	}
+	object := map[string]interface{}{}
+	if err := json.Unmarshal(data, &object); err != nil {
+		return nil, err
+	}
+	metadata, ok := object["metadata"].(map[string]interface{})
+	if !ok {
+		metadata = map[string]interface{}{}
+		object["metadata"] = metadata
+	}
+	metadata["resourceVersion"] = strconv.FormatUint(resourceVersion, 10)
+	return json.Marshal(object)
+}
+
+// digestOf returns the hex encoded SHA-256 digest of content
+func digestOf(content []byte) string {
+	sum := sha256.Sum256(content)
+	return hex.EncodeToString(sum[:])
+}
+
+// expiresAt converts a TTL in seconds into an absolute unix expiry time
+func expiresAt(ttl uint64) int64 {
+	if ttl == 0 {
+		return 0
+	}
+	return time.Now().Unix() + int64(ttl)
+}
@@ -0,0 +1,477 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/watch"
+	k8storage "k8s.io/apiserver/pkg/storage"
+
+	k1sstorage "github.com/dtomasi/k1s/core/storage"
+)
+
+// Test constants to avoid goconst violations
+const (
+	testAPIVersion   = "test.k1s.io/v1"
+	testObjectKind   = "TestObject"
+	testNamespace    = "default"
+	testObjectName   = "test-object"
+	testObjectsKey   = "/test.k1s.io/v1/testobjects/"
+	testObjectKey    = "/test.k1s.io/v1/testobjects/default/test-object"
+	testObjectFile   = "test.k1s.io/v1/testobjects/default/test-object.yaml"
+	testObjectsNoKey = "/test.k1s.io/v1/testobjects/default/non-existent"
+)
+
+// TestObject is a simple test object for storage tests
+type TestObject struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec TestSpec `json:"spec,omitempty"`
+}
+
+type TestSpec struct {
+	Name string `json:"name"`
+}
+
+// DeepCopyObject implements runtime.Object
+func (t *TestObject) DeepCopyObject() runtime.Object {
+	if t == nil {
+		return nil
+	}
+	return &TestObject{
+		TypeMeta:   t.TypeMeta,
+		ObjectMeta: *t.DeepCopy(),
+		Spec:       t.Spec,
+	}
+}
+
+// TestObjectList represents a list of test objects
+type TestObjectList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+
+	Items []TestObject `json:"items"`
+}
+
+// DeepCopyObject implements runtime.Object
+func (t *TestObjectList) DeepCopyObject() runtime.Object {
+	if t == nil {
+		return nil
+	}
+	out := &TestObjectList{
+		TypeMeta: t.TypeMeta,
+		ListMeta: *t.DeepCopy(),
+	}
+	if t.Items != nil {
+		out.Items = make([]TestObject, len(t.Items))
+		for i := range t.Items {
+			out.Items[i] = *t.Items[i].DeepCopyObject().(*TestObject)
+		}
+	}
+	return out
+}
+
+func newTestObject(name string) *TestObject {
+	return &TestObject{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: testAPIVersion,
+			Kind:       testObjectKind,
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: testNamespace,
+		},
+		Spec: TestSpec{Name: "Test Object"},
+	}
+}
+
+// expectEvent waits for the next event on ch. SimpleWatch.ResultChan starts a
+// new forwarder on every call, so tests must read from a single channel.
+func expectEvent(ch <-chan watch.Event, eventType watch.EventType) watch.Event {
+	var event watch.Event
+	Eventually(ch, 5*time.Second).Should(Receive(&event))
+	Expect(event.Type).To(Equal(eventType))
+	return event
+}
+
+var _ = Describe("FilesystemStorage", func() {
+	var (
+		storage k1sstorage.Backend
+		ctx     context.Context
+		cancel  context.CancelFunc
+		root    string
+	)
+
+	BeforeEach(func() {
+		ctx, cancel = context.WithTimeout(context.Background(), 30*time.Second)
+
+		root = GinkgoT().TempDir()
+		storage = NewFilesystemStorageWithPath(root, k1sstorage.Config{})
+		Expect(storage).NotTo(BeNil())
+		Expect(storage.Name()).To(Equal("filesystem"))
+	})
+
+	AfterEach(func() {
+		Expect(storage.Close()).To(Succeed())
+		cancel()
+	})
+
+	Describe("File Layout", func() {
+		It("should store objects as YAML without a resourceVersion", func() {
+			Expect(storage.Create(ctx, testObjectKey, newTestObject(testObjectName), nil, 0)).To(Succeed())
+
+			content, err := os.ReadFile(filepath.Join(root, testObjectFile))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(content)).To(ContainSubstring("name: test-object"))
+			Expect(string(content)).NotTo(ContainSubstring("resourceVersion"))
+
+			_, err = os.Stat(filepath.Join(root, metadataDir, indexFile))
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("should store the core group in a core directory", func() {
+			Expect(storage.Create(ctx, "//v1/configmaps/default/settings", newTestObject("settings"), nil, 0)).To(Succeed())
+
+			_, err := os.Stat(filepath.Join(root, "core", "v1", "configmaps", "default", "settings.yaml"))
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("should not leave temporary files or empty directories behind", func() {
+			Expect(storage.Create(ctx, testObjectKey, newTestObject(testObjectName), nil, 0)).To(Succeed())
+			Expect(storage.Delete(ctx, testObjectKey, nil, nil, nil, nil)).To(Succeed())
+
+			entries, err := os.ReadDir(root)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(entries).To(HaveLen(1))
+			Expect(entries[0].Name()).To(Equal(metadataDir))
+		})
+
+		It("should keep resourceVersions across restarts", func() {
+			out := &TestObject{}
+			Expect(storage.Create(ctx, testObjectKey, newTestObject(testObjectName), out, 0)).To(Succeed())
+
+			reopened := NewFilesystemStorageWithPath(root, k1sstorage.Config{})
+			defer func() { Expect(reopened.Close()).To(Succeed()) }()
+
+			retrieved := &TestObject{}
+			Expect(reopened.Get(ctx, testObjectKey, k8storage.GetOptions{}, retrieved)).To(Succeed())
+			Expect(retrieved.ResourceVersion).To(Equal(out.ResourceVersion))
+		})
+
+		It("should reject keys that cannot be mapped to files", func() {
+			err := storage.Create(ctx, "/test.k1s.io/v1/testobjects/default/.hidden", newTestObject(testObjectName), nil, 0)
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Describe("Basic Operations", func() {
+		It("should create and get an object", func() {
+			out := &TestObject{}
+			Expect(storage.Create(ctx, testObjectKey, newTestObject(testObjectName), out, 0)).To(Succeed())
+			Expect(out.ResourceVersion).NotTo(BeEmpty())
+
+			retrieved := &TestObject{}
+			Expect(storage.Get(ctx, testObjectKey, k8storage.GetOptions{}, retrieved)).To(Succeed())
+			Expect(retrieved.Spec.Name).To(Equal("Test Object"))
+			Expect(retrieved.ResourceVersion).To(Equal(out.ResourceVersion))
+		})
+
+		It("should fail to create duplicate objects", func() {
+			Expect(storage.Create(ctx, testObjectKey, newTestObject(testObjectName), nil, 0)).To(Succeed())
+
+			err := storage.Create(ctx, testObjectKey, newTestObject(testObjectName), nil, 0)
+			Expect(apierrors.IsAlreadyExists(err)).To(BeTrue())
+		})
+
+		It("should return not found for missing objects", func() {
+			err := storage.Get(ctx, testObjectsNoKey, k8storage.GetOptions{}, &TestObject{})
+			Expect(apierrors.IsNotFound(err)).To(BeTrue())
+
+			Expect(storage.Get(ctx, testObjectsNoKey, k8storage.GetOptions{IgnoreNotFound: true}, &TestObject{})).To(Succeed())
+
+			err = storage.Delete(ctx, testObjectsNoKey, nil, nil, nil, nil)
+			Expect(apierrors.IsNotFound(err)).To(BeTrue())
+		})
+
+		It("should delete an object and return its last state", func() {
+			Expect(storage.Create(ctx, testObjectKey, newTestObject(testObjectName), nil, 0)).To(Succeed())
+
+			out := &TestObject{}
+			Expect(storage.Delete(ctx, testObjectKey, out, nil, nil, nil)).To(Succeed())
+			Expect(out.Name).To(Equal(testObjectName))
+
+			err := storage.Get(ctx, testObjectKey, k8storage.GetOptions{}, &TestObject{})
+			Expect(apierrors.IsNotFound(err)).To(BeTrue())
+		})
+
+		It("should list and count objects under a prefix", func() {
+			for i := 0; i < 3; i++ {
+				name := fmt.Sprintf("item-%d", i)
+				Expect(storage.Create(ctx, testObjectsKey+"default/"+name, newTestObject(name), nil, 0)).To(Succeed())
+			}
+
+			list := &TestObjectList{}
+			Expect(storage.List(ctx, testObjectsKey, k8storage.ListOptions{Recursive: true}, list)).To(Succeed())
+			Expect(list.Items).To(HaveLen(3))
+			Expect(list.Items[0].Name).To(Equal("item-0"))
+			Expect(list.ResourceVersion).NotTo(BeEmpty())
+
+			count, err := storage.Count(ctx, testObjectsKey)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(count).To(Equal(int64(3)))
+		})
+	})
+
+	Describe("GuaranteedUpdate", func() {
+		It("should update an existing object", func() {
+			Expect(storage.Create(ctx, testObjectKey, newTestObject(testObjectName), nil, 0)).To(Succeed())
+
+			destination := &TestObject{}
+			err := storage.GuaranteedUpdate(ctx, testObjectKey, destination, false, nil,
+				func(input runtime.Object, _ k8storage.ResponseMeta) (runtime.Object, *uint64, error) {
+					obj := input.(*TestObject)
+					obj.Spec.Name = "updated-name"
+					return obj, nil, nil
+				}, nil)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(destination.Spec.Name).To(Equal("updated-name"))
+
+			content, err := os.ReadFile(filepath.Join(root, testObjectFile))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(content)).To(ContainSubstring("name: updated-name"))
+		})
+
+		It("should return a conflict when preconditions fail", func() {
+			Expect(storage.Create(ctx, testObjectKey, newTestObject(testObjectName), nil, 0)).To(Succeed())
+
+			uid := types.UID("wrong-uid")
+			err := storage.GuaranteedUpdate(ctx, testObjectKey, &TestObject{}, false,
+				&k8storage.Preconditions{UID: &uid},
+				func(input runtime.Object, _ k8storage.ResponseMeta) (runtime.Object, *uint64, error) {
+					return input, nil, nil
+				}, nil)
+			Expect(apierrors.IsConflict(err)).To(BeTrue())
+		})
+	})
+
+	Describe("External Changes", func() {
+		BeforeEach(func() {
+			Expect(storage.Create(ctx, testObjectKey, newTestObject(testObjectName), nil, 0)).To(Succeed())
+		})
+
+		It("should pick up edited files with a new resourceVersion", func() {
+			before := &TestObject{}
+			Expect(storage.Get(ctx, testObjectKey, k8storage.GetOptions{}, before)).To(Succeed())
+
+			path := filepath.Join(root, testObjectFile)
+			content, err := os.ReadFile(path)
+			Expect(err).NotTo(HaveOccurred())
+			edited := []byte(string(content) + "status:\n  phase: Edited\n")
+			Expect(os.WriteFile(path, edited, 0o600)).To(Succeed())
+
+			// The edit is picked up once the directory watcher reports it
+			after := &unstructured.Unstructured{}
+			Eventually(func() interface{} {
+				Expect(storage.Get(ctx, testObjectKey, k8storage.GetOptions{}, after)).To(Succeed())
+				return after.Object["status"]
+			}, 5*time.Second).Should(HaveKeyWithValue("phase", "Edited"))
+			Expect(after.GetResourceVersion()).NotTo(Equal(before.ResourceVersion))
+		})
+
+		It("should pick up files added and removed outside of k1s", func() {
+			dir := filepath.Join(root, "core", "v1", "configmaps", "default")
+			Expect(os.MkdirAll(dir, 0o750)).To(Succeed())
+			Expect(os.WriteFile(filepath.Join(dir, "external.yaml"),
+				[]byte("apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: external\n  namespace: default\n"), 0o600)).To(Succeed())
+
+			retrieved := &unstructured.Unstructured{}
+			Eventually(func() error {
+				return storage.Get(ctx, "//v1/configmaps/default/external", k8storage.GetOptions{}, retrieved)
+			}, 5*time.Second).Should(Succeed())
+			Expect(retrieved.GetName()).To(Equal("external"))
+
+			Expect(os.Remove(filepath.Join(root, testObjectFile))).To(Succeed())
+			Eventually(func() bool {
+				err := storage.Get(ctx, testObjectKey, k8storage.GetOptions{}, &TestObject{})
+				return apierrors.IsNotFound(err)
+			}, 5*time.Second).Should(BeTrue())
+		})
+
+		It("should not rescan the tree for its own writes", func() {
+			fsStorage := storage.(*filesystemStorage)
+			// Wait for the directory watcher to settle after the first write
+			Eventually(func() bool {
+				_, err := storage.Count(ctx, testObjectsKey)
+				Expect(err).NotTo(HaveOccurred())
+				return fsStorage.stale.Load()
+			}, 5*time.Second).Should(BeFalse())
+
+			Expect(storage.Create(ctx, testObjectsKey+"default/other", newTestObject("other"), nil, 0)).To(Succeed())
+			Expect(storage.Delete(ctx, testObjectsKey+"default/other", nil, nil, nil, nil)).To(Succeed())
+			Consistently(fsStorage.stale.Load, 5*watchDebounce).Should(BeFalse())
+		})
+
+		It("should hand out distinct revisions to processes sharing the directory", func() {
+			other := NewFilesystemStorageWithPath(root, k1sstorage.Config{})
+			defer func() { Expect(other.Close()).To(Succeed()) }()
+			Expect(other.Get(ctx, testObjectKey, k8storage.GetOptions{}, &TestObject{})).To(Succeed())
+
+			first := &TestObject{}
+			Expect(storage.Create(ctx, testObjectsKey+"default/first", newTestObject("first"), first, 0)).To(Succeed())
+			second := &TestObject{}
+			Expect(other.Create(ctx, testObjectsKey+"default/second", newTestObject("second"), second, 0)).To(Succeed())
+			Expect(second.ResourceVersion).NotTo(Equal(first.ResourceVersion))
+		})
+
+		It("should keep the index entries of processes sharing the directory", func() {
+			other := NewFilesystemStorageWithPath(root, k1sstorage.Config{})
+			defer func() { Expect(other.Close()).To(Succeed()) }()
+			Expect(other.Get(ctx, testObjectKey, k8storage.GetOptions{}, &TestObject{})).To(Succeed())
+
+			first := &TestObject{}
+			Expect(storage.Create(ctx, testObjectsKey+"default/first", newTestObject("first"), first, 0)).To(Succeed())
+			second := &TestObject{}
+			Expect(other.Create(ctx, testObjectsKey+"default/second", newTestObject("second"), second, 0)).To(Succeed())
+
+			// A process started later reads the revisions of both writers
+			restarted := NewFilesystemStorageWithPath(root, k1sstorage.Config{})
+			defer func() { Expect(restarted.Close()).To(Succeed()) }()
+			for _, written := range []*TestObject{first, second} {
+				retrieved := &TestObject{}
+				Expect(restarted.Get(ctx, testObjectsKey+"default/"+written.Name, k8storage.GetOptions{}, retrieved)).
+					To(Succeed())
+				Expect(retrieved.ResourceVersion).To(Equal(written.ResourceVersion))
+			}
+
+			// The writing processes pick up each other's writes with the same revision
+			Eventually(func() string {
+				retrieved := &TestObject{}
+				if err := storage.Get(ctx, testObjectsKey+"default/second", k8storage.GetOptions{}, retrieved); err != nil {
+					return err.Error()
+				}
+				return retrieved.ResourceVersion
+			}, 5*time.Second).Should(Equal(second.ResourceVersion))
+		})
+	})
+
+	Describe("Watch Operations", func() {
+		It("should deliver local writes once", func() {
+			w, err := storage.Watch(ctx, testObjectsKey, k8storage.ListOptions{Recursive: true})
+			Expect(err).NotTo(HaveOccurred())
+			defer w.Stop()
+			events := w.ResultChan()
+
+			Expect(storage.Create(ctx, testObjectKey, newTestObject(testObjectName), nil, 0)).To(Succeed())
+			event := expectEvent(events, watch.Added)
+			Expect(event.Object).To(BeAssignableToTypeOf(&TestObject{}))
+
+			// The directory watcher must not report our own write again
+			Consistently(events, 3*watchDebounce+100*time.Millisecond).ShouldNot(Receive())
+		})
+
+		It("should deliver external edits", func() {
+			Expect(storage.Create(ctx, testObjectKey, newTestObject(testObjectName), nil, 0)).To(Succeed())
+
+			w, err := storage.Watch(ctx, testObjectsKey, k8storage.ListOptions{Recursive: true})
+			Expect(err).NotTo(HaveOccurred())
+			defer w.Stop()
+			events := w.ResultChan()
+
+			path := filepath.Join(root, testObjectFile)
+			content, err := os.ReadFile(path)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(os.WriteFile(path, append(content, []byte("status:\n  phase: Edited\n")...), 0o600)).To(Succeed())
+
+			event := expectEvent(events, watch.Modified)
+			Expect(event.Object.(*unstructured.Unstructured).GetName()).To(Equal(testObjectName))
+
+			Expect(os.Remove(path)).To(Succeed())
+			expectEvent(events, watch.Deleted)
+		})
+
+		It("should deliver files created in new directories", func() {
+			w, err := storage.Watch(ctx, testObjectsKey, k8storage.ListOptions{Recursive: true})
+			Expect(err).NotTo(HaveOccurred())
+			defer w.Stop()
+			events := w.ResultChan()
+
+			dir := filepath.Join(root, "test.k1s.io", "v1", "testobjects", "other")
+			Expect(os.MkdirAll(dir, 0o750)).To(Succeed())
+			Expect(os.WriteFile(filepath.Join(dir, "new.yaml"),
+				[]byte("apiVersion: test.k1s.io/v1\nkind: TestObject\nmetadata:\n  name: new\n  namespace: other\n"), 0o600)).To(Succeed())
+
+			event := expectEvent(events, watch.Added)
+			Expect(event.Object.(*unstructured.Unstructured).GetName()).To(Equal("new"))
+		})
+
+		It("should send initial events when requested", func() {
+			Expect(storage.Create(ctx, testObjectKey, newTestObject(testObjectName), nil, 0)).To(Succeed())
+
+			sendInitial := true
+			w, err := storage.Watch(ctx, testObjectsKey, k8storage.ListOptions{
+				Recursive:         true,
+				SendInitialEvents: &sendInitial,
+			})
+			Expect(err).NotTo(HaveOccurred())
+			defer w.Stop()
+
+			expectEvent(w.ResultChan(), watch.Added)
+		})
+	})
+
+	Describe("TTL and Compaction", func() {
+		It("should expire objects and remove their files on compaction", func() {
+			Expect(storage.Create(ctx, testObjectKey, newTestObject(testObjectName), nil, 1)).To(Succeed())
+
+			Eventually(func() bool {
+				err := storage.Get(ctx, testObjectKey, k8storage.GetOptions{}, &TestObject{})
+				return apierrors.IsNotFound(err)
+			}, 3*time.Second, 100*time.Millisecond).Should(BeTrue())
+
+			Expect(storage.Compact(ctx)).To(Succeed())
+			_, err := os.Stat(filepath.Join(root, testObjectFile))
+			Expect(os.IsNotExist(err)).To(BeTrue())
+		})
+	})
+
+	Describe("Multi-tenancy Support", func() {
+		It("should keep each tenant in its own directory", func() {
+			tenant := NewFilesystemStorageWithPath(root, k1sstorage.Config{TenantID: "tenant-a"})
+			defer func() { Expect(tenant.Close()).To(Succeed()) }()
+
+			Expect(tenant.Create(ctx, testObjectKey, newTestObject(testObjectName), nil, 0)).To(Succeed())
+
+			_, err := os.Stat(filepath.Join(root, "tenants", "tenant-a", testObjectFile))
+			Expect(err).NotTo(HaveOccurred())
+		})
+	})
+
+	Describe("Error Conditions", func() {
+		It("should reject operations on closed storage", func() {
+			Expect(storage.Close()).To(Succeed())
+
+			err := storage.Create(ctx, testObjectKey, newTestObject(testObjectName), nil, 0)
+			Expect(err).To(MatchError(errStorageIsClosed))
+		})
+
+		It("should handle cancelled context", func() {
+			cancelledCtx, cancelFn := context.WithCancel(context.Background())
+			cancelFn()
+
+			err := storage.Create(cancelledCtx, testObjectKey, newTestObject(testObjectName), nil, 0)
+			Expect(err).To(HaveOccurred())
+		})
+	})
+})
@@ -0,0 +1,15 @@
+//go:build !unix && !windows
+
+package storage
+
+import "os"
+
+// lockFile is a no-op on platforms without file locking
+func lockFile(_ *os.File) error {
+	return nil
+}
+
+// unlockFile is a no-op on platforms without file locking
+func unlockFile(_ *os.File) error {
+	return nil
+}
@@ -0,0 +1,13 @@
+package storage
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestFilesystemStorage(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Filesystem Storage Suite")
+}
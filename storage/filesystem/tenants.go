@@ -0,0 +1,303 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/watch"
+
+	k1sstorage "github.com/dtomasi/k1s/core/storage"
+)
+
+// tenantFile is the registration record inside a tenant's metadata directory
+const tenantFile = "tenant.json"
+
+// Tenants are stored in <root>/tenants/<escaped id>/. Their registration
+// record lives in the hidden metadata directory, so it is never mistaken for
+// a resource file.
+var _ k1sstorage.TenantManager = &filesystemStorage{}
+
+// CreateTenant registers a tenant
+func (s *filesystemStorage) CreateTenant(ctx context.Context, tenant k1sstorage.Tenant) error {
+	if err := s.tenantReady(ctx); err != nil {
+		return err
+	}
+
+	if err := k1sstorage.ValidateTenantID(tenant.ID); err != nil {
+		return apierrors.NewBadRequest(err.Error())
+	}
+
+	if tenant.CreationTimestamp.IsZero() {
+		tenant.CreationTimestamp = metav1.Time{Time: time.Now()}
+	}
+	data, err := k1sstorage.EncodeTenant(tenant)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	path := s.tenantRecordPath(tenant.ID)
+	if _, err := os.Stat(path); err == nil {
+		return apierrors.NewAlreadyExists(k1sstorage.TenantsResource, tenant.ID)
+	} else if !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("failed to check tenant existence: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+		return fmt.Errorf("failed to create tenant directory: %w", err)
+	}
+	if err := writeFileAtomic(path, data); err != nil {
+		return fmt.Errorf("failed to store tenant: %w", err)
+	}
+
+	atomic.AddUint64(&s.metrics.operations, 1)
+	return nil
+}
+
+// GetTenant returns a tenant
+func (s *filesystemStorage) GetTenant(ctx context.Context, tenantID string) (*k1sstorage.Tenant, error) {
+	if err := s.tenantReady(ctx); err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	tenant, err := s.readTenant(tenantID)
+	if err != nil || tenant != nil {
+		return tenant, err
+	}
+
+	count, err := countResourceFiles(s.tenantDir(tenantID))
+	if err != nil {
+		return nil, err
+	}
+	if count > 0 {
+		return &k1sstorage.Tenant{ID: tenantID}, nil
+	}
+	return nil, apierrors.NewNotFound(k1sstorage.TenantsResource, tenantID)
+}
+
+// ListTenants returns all registered tenants and all tenants holding data
+func (s *filesystemStorage) ListTenants(ctx context.Context) ([]k1sstorage.Tenant, error) {
+	if err := s.tenantReady(ctx); err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entries, err := os.ReadDir(filepath.Join(s.root, k1sstorage.TenantsKeyPrefix))
+	if errors.Is(err, fs.ErrNotExist) {
+		return []k1sstorage.Tenant{}, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to list tenants: %w", err)
+	}
+
+	result := []k1sstorage.Tenant{}
+	for _, entry := range entries {
+		if !entry.IsDir() || strings.HasPrefix(entry.Name(), ".") {
+			continue
+		}
+		tenantID, err := k1sstorage.UnescapeTenantID(entry.Name())
+		if err != nil {
+			continue
+		}
+
+		tenant, err := s.readTenant(tenantID)
+		if err != nil {
+			return nil, err
+		}
+		if tenant == nil {
+			// Directories without resource files are left behind by
+			// backends opened for a tenant that never stored data
+			count, err := countResourceFiles(s.tenantDir(tenantID))
+			if err != nil {
+				return nil, err
+			}
+			if count == 0 {
+				continue
+			}
+			tenant = &k1sstorage.Tenant{ID: tenantID}
+		}
+		result = append(result, *tenant)
+	}
+
+	sort.Slice(result, func(i, j int) bool { return result[i].ID < result[j].ID })
+	return result, nil
+}
+
+// DeleteTenant removes a tenant directory together with all of its data
+func (s *filesystemStorage) DeleteTenant(ctx context.Context, tenantID string) error {
+	if err := s.tenantReady(ctx); err != nil {
+		return err
+	}
+
+	dir := s.tenantDir(tenantID)
+
+	s.mu.Lock()
+	registered, err := s.readTenant(tenantID)
+	if err != nil {
+		s.mu.Unlock()
+		return err
+	}
+	count, err := countResourceFiles(dir)
+	if err != nil {
+		s.mu.Unlock()
+		return err
+	}
+	if registered == nil && count == 0 {
+		s.mu.Unlock()
+		return apierrors.NewNotFound(k1sstorage.TenantsResource, tenantID)
+	}
+
+	if err := os.RemoveAll(dir); err != nil {
+		s.mu.Unlock()
+		atomic.AddUint64(&s.metrics.errors, 1)
+		return fmt.Errorf("failed to delete tenant %s: %w", tenantID, err)
+	}
+
+	events, err := s.lockedForgetTenantDir(dir)
+	s.mu.Unlock()
+	s.notifyAll(events)
+	if err != nil {
+		atomic.AddUint64(&s.metrics.errors, 1)
+		return err
+	}
+
+	atomic.AddUint64(&s.metrics.operations, 1)
+	return nil
+}
+
+// CountTenant returns the number of resource files stored by a tenant
+func (s *filesystemStorage) CountTenant(ctx context.Context, tenantID string) (int64, error) {
+	if err := s.tenantReady(ctx); err != nil {
+		return 0, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return countResourceFiles(s.tenantDir(tenantID))
+}
+
+// tenantReady checks the context and storage state for tenant operations
+func (s *filesystemStorage) tenantReady(ctx context.Context) error {
+	if ctx.Err() != nil {
+		return k1sstorage.NewContextCancelledError(ctx)
+	}
+
+	if s.closed.Load() {
+		return errors.New(errStorageIsClosed)
+	}
+
+	return nil
+}
+
+// tenantDir returns the directory holding a tenant's data
+func (s *filesystemStorage) tenantDir(tenantID string) string {
+	return filepath.Join(s.root, k1sstorage.TenantsKeyPrefix, k1sstorage.EscapeTenantID(tenantID))
+}
+
+// tenantRecordPath returns the location of a tenant's registration record
+func (s *filesystemStorage) tenantRecordPath(tenantID string) string {
+	return filepath.Join(s.tenantDir(tenantID), metadataDir, tenantFile)
+}
+
+// readTenant returns the registration record of a tenant, or nil if it is not registered
+func (s *filesystemStorage) readTenant(tenantID string) (*k1sstorage.Tenant, error) {
+	data, err := os.ReadFile(s.tenantRecordPath(tenantID))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to get tenant: %w", err)
+	}
+	return k1sstorage.DecodeTenant(data)
+}
+
+// lockedForgetTenantDir updates the index after a tenant directory was
+// removed and returns the resulting watch events. Callers must hold mu.
+func (s *filesystemStorage) lockedForgetTenantDir(dir string) ([]event, error) {
+	if s.index == nil {
+		return nil, nil
+	}
+
+	// This backend belongs to the deleted tenant; its whole tree is gone
+	if rel, err := filepath.Rel(dir, s.baseDir); err == nil && !strings.HasPrefix(rel, "..") {
+		var events []event
+		for rel, entry := range s.index.Entries {
+			if obj, err := k1sstorage.DecodeUnstructured(s.objects[rel]); err == nil {
+				events = append(events, event{key: entry.Key, eventType: watch.Deleted, obj: obj})
+			}
+		}
+		s.index = nil
+		s.objects = make(map[string][]byte)
+		return events, nil
+	}
+
+	// The tenant directory was inside this backend's tree; a rescan
+	// reports its resource files as deleted
+	if rel, err := filepath.Rel(s.baseDir, dir); err == nil && !strings.HasPrefix(rel, "..") {
+		return s.lockedSync()
+	}
+
+	return nil, nil
+}
+
+// lockedCheckQuota enforces the object quota of the configured tenant. Callers must hold mu.
+func (s *filesystemStorage) lockedCheckQuota(key string) error {
+	if s.config.TenantID == "" {
+		return nil
+	}
+
+	tenant, err := s.readTenant(s.config.TenantID)
+	if err != nil || tenant == nil || tenant.MaxObjects <= 0 {
+		return err
+	}
+
+	count, err := countResourceFiles(s.tenantDir(tenant.ID))
+	if err != nil {
+		return err
+	}
+	return k1sstorage.CheckTenantQuota(tenant, count, key)
+}
+
+// countResourceFiles counts the resource files below dir, skipping hidden
+// files and directories the same way lockedSync does
+func countResourceFiles(dir string) (int64, error) {
+	var count int64
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) && path == dir {
+				return filepath.SkipDir
+			}
+			return err
+		}
+		if path != dir && strings.HasPrefix(d.Name(), ".") {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !d.IsDir() && strings.HasSuffix(d.Name(), fileExtension) {
+			count++
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to count resource files: %w", err)
+	}
+	return count, nil
+}
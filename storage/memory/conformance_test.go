@@ -0,0 +1,11 @@
+package storage_test
+
+import (
+	"github.com/dtomasi/k1s/storage/storagetest"
+
+	storage "github.com/dtomasi/k1s/storage/memory"
+)
+
+var _ = storagetest.DescribeBackend("memory", func() storagetest.Factory {
+	return storage.NewMemoryStorage
+})
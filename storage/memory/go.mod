@@ -4,6 +4,7 @@ go 1.25.1
 
 require (
 	github.com/dtomasi/k1s/core v0.0.0
+	github.com/dtomasi/k1s/storage/storagetest v0.0.0
 	github.com/onsi/ginkgo/v2 v2.25.3
 	github.com/onsi/gomega v1.38.2
 	k8s.io/apimachinery v0.34.0
@@ -52,3 +53,5 @@ require (
 )
 
 replace github.com/dtomasi/k1s/core => ../../core
+
+replace github.com/dtomasi/k1s/storage/storagetest => ../storagetest
@@ -5,12 +5,13 @@ import (
 	"encoding/json"
 	"fmt"
 	"log"
+	"sort"
 	"strings"
 	"sync"
 	"sync/atomic"
 
 	"k8s.io/apimachinery/pkg/api/errors"
-	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/watch"
@@ -99,6 +100,12 @@ func (s *memoryStorage) Create(ctx context.Context, key string, obj, out runtime
 		return errors.NewAlreadyExists(gr, key)
 	}
 
+	// Enforce the tenant's object quota
+	if err := s.lockedCheckQuota(key); err != nil {
+		atomic.AddUint64(&s.metrics.errors, 1)
+		return err
+	}
+
 	// Prepare object for storage
 	if err := s.versioner.PrepareObjectForStorage(obj); err != nil {
 		atomic.AddUint64(&s.metrics.errors, 1)
@@ -123,13 +130,16 @@ func (s *memoryStorage) Create(ctx context.Context, key string, obj, out runtime
 	s.data[key] = data
 	s.resourceVersions[key] = resourceVersion
 
-	// Copy to output object if provided (simplified for now)
-	// In a real implementation, we would properly deserialize from stored data
-	// For now, we'll skip this to avoid interface conversion issues
-	_ = out
+	// Copy to output object if provided
+	if out != nil {
+		if err := json.Unmarshal(data, out); err != nil {
+			atomic.AddUint64(&s.metrics.errors, 1)
+			return fmt.Errorf("failed to unmarshal to output object: %w", err)
+		}
+	}
 
 	// Notify watchers
-	s.notifyWatchers(key, watch.Added, obj)
+	s.notifyWatchers(key, watch.Added, k1sstorage.WatchObject(data, obj))
 
 	atomic.AddUint64(&s.metrics.operations, 1)
 	return nil
@@ -151,7 +161,7 @@ func (s *memoryStorage) Delete(ctx context.Context, key string, out runtime.Obje
 	defer s.mu.Unlock()
 
 	// Check if key exists
-	_, exists := s.data[key]
+	data, exists := s.data[key]
 	if !exists {
 		atomic.AddUint64(&s.metrics.errors, 1)
 		// Use Kubernetes standard error type for not found
@@ -159,17 +169,20 @@ func (s *memoryStorage) Delete(ctx context.Context, key string, out runtime.Obje
 		return errors.NewNotFound(gr, key)
 	}
 
-	// Use cached object if available, otherwise create a placeholder
-	var existingObj runtime.Object
-	if cachedExistingObject != nil {
-		existingObj = cachedExistingObject
-	} else {
-		existingObj = &runtime.Unknown{}
+	// Decode the stored object so preconditions are checked against
+	// the stored state rather than a possibly stale cached copy
+	var existingObj runtime.Object = &metav1.PartialObjectMetadata{}
+	if out != nil {
+		existingObj = out
+	}
+	if err := json.Unmarshal(data, existingObj); err != nil {
+		atomic.AddUint64(&s.metrics.errors, 1)
+		return fmt.Errorf("failed to deserialize existing object: %w", err)
 	}
 
 	// Validate preconditions if provided
 	if preconditions != nil {
-		if err := s.checkPreconditions(existingObj, preconditions); err != nil {
+		if err := k1sstorage.CheckPreconditions(key, existingObj, preconditions); err != nil {
 			atomic.AddUint64(&s.metrics.errors, 1)
 			return err
 		}
@@ -183,17 +196,16 @@ func (s *memoryStorage) Delete(ctx context.Context, key string, out runtime.Obje
 		}
 	}
 
-	// Copy existing object to output if provided (simplified for now)
-	// In a real implementation, we would properly deserialize from stored data
-	// For now, we'll skip this to avoid interface conversion issues
-	_ = out
-
 	// Remove from storage
 	delete(s.data, key)
 	delete(s.resourceVersions, key)
 
-	// Notify watchers
-	s.notifyWatchers(key, watch.Deleted, existingObj)
+	// Notify watchers, preferring the type of the cached object when available
+	like := existingObj
+	if cachedExistingObject != nil {
+		like = cachedExistingObject
+	}
+	s.notifyWatchers(key, watch.Deleted, k1sstorage.WatchObject(data, like))
 
 	atomic.AddUint64(&s.metrics.operations, 1)
 	return nil
@@ -261,7 +273,7 @@ func (s *memoryStorage) List(ctx context.Context, key string, opts storage.ListO
 	s.mu.RLock()
 	defer s.mu.RUnlock()
 
-	var matchedObjects []runtime.Object
+	var matchedKeys []string
 	maxResourceVersion := uint64(0)
 
 	// Find matching keys
@@ -278,9 +290,7 @@ func (s *memoryStorage) List(ctx context.Context, key string, opts storage.ListO
 			continue
 		}
 
-		// Create placeholder object
-		obj := &runtime.Unknown{}
-		matchedObjects = append(matchedObjects, obj)
+		matchedKeys = append(matchedKeys, storageKey)
 
 		// Track max resource version
 		if objVersion := s.resourceVersions[storageKey]; objVersion > maxResourceVersion {
@@ -297,8 +307,15 @@ func (s *memoryStorage) List(ctx context.Context, key string, opts storage.ListO
 		return fmt.Errorf("failed to update list metadata: %w", err)
 	}
 
+	// Return items in key order, matching the ordered backends
+	sort.Strings(matchedKeys)
+	items := make([][]byte, len(matchedKeys))
+	for i, storageKey := range matchedKeys {
+		items[i] = s.data[storageKey]
+	}
+
 	// Set the items in the list
-	if err := meta.SetList(listObj, matchedObjects); err != nil {
+	if err := k1sstorage.SetListItems(listObj, items); err != nil {
 		atomic.AddUint64(&s.metrics.errors, 1)
 		return fmt.Errorf("failed to set list items: %w", err)
 	}
@@ -416,7 +433,7 @@ func (s *memoryStorage) buildKey(key string) string {
 
 	// Add tenant prefix if configured
 	if s.config.TenantID != "" {
-		parts = append(parts, "tenants", s.config.TenantID)
+		parts = append(parts, k1sstorage.TenantKey(s.config.TenantID))
 	}
 
 	// Add custom key prefix if configured
@@ -435,38 +452,6 @@ func (s *memoryStorage) buildKey(key string) string {
 	return strings.Join(parts, "/")
 }
 
-// checkPreconditions validates storage preconditions
-func (s *memoryStorage) checkPreconditions(obj runtime.Object, preconditions *storage.Preconditions) error {
-	if preconditions == nil {
-		return nil
-	}
-
-	// Check UID precondition
-	if preconditions.UID != nil {
-		accessor, err := meta.Accessor(obj)
-		if err != nil {
-			return fmt.Errorf("failed to get object accessor: %w", err)
-		}
-		if accessor.GetUID() != *preconditions.UID {
-			return fmt.Errorf("UID mismatch: expected %s, got %s", *preconditions.UID, accessor.GetUID())
-		}
-	}
-
-	// Check ResourceVersion precondition
-	if preconditions.ResourceVersion != nil {
-		accessor, err := meta.Accessor(obj)
-		if err != nil {
-			return fmt.Errorf("failed to get object accessor: %w", err)
-		}
-		if accessor.GetResourceVersion() != *preconditions.ResourceVersion {
-			return fmt.Errorf("resource version mismatch: expected %s, got %s",
-				*preconditions.ResourceVersion, accessor.GetResourceVersion())
-		}
-	}
-
-	return nil
-}
-
 // notifyWatchers sends watch events to all registered watchers
 func (s *memoryStorage) notifyWatchers(key string, eventType watch.EventType, obj runtime.Object) {
 	s.watchMu.RLock()
@@ -536,6 +521,12 @@ func (s *memoryStorage) GuaranteedUpdate(ctx context.Context, key string, destin
 			gr := schema.GroupResource{Resource: "objects"} // Generic resource for storage
 			return errors.NewNotFound(gr, key)
 		}
+
+		// Creating the object counts towards the tenant's object quota
+		if err := s.lockedCheckQuota(key); err != nil {
+			atomic.AddUint64(&s.metrics.errors, 1)
+			return err
+		}
 		current = destination.DeepCopyObject()
 	} else {
 		current = destination.DeepCopyObject()
@@ -546,8 +537,8 @@ func (s *memoryStorage) GuaranteedUpdate(ctx context.Context, key string, destin
 	}
 
 	// Check preconditions if provided
-	if preconditions != nil {
-		if err := s.checkPreconditions(current, preconditions); err != nil {
+	if exists && preconditions != nil {
+		if err := k1sstorage.CheckPreconditions(key, current, preconditions); err != nil {
 			atomic.AddUint64(&s.metrics.errors, 1)
 			return err
 		}
@@ -590,7 +581,7 @@ func (s *memoryStorage) GuaranteedUpdate(ctx context.Context, key string, destin
 	}
 	// Unlock before notifying watchers to avoid holding locks during callbacks
 	s.mu.Unlock()
-	s.notifyWatchers(key, eventType, updated)
+	s.notifyWatchers(key, eventType, k1sstorage.WatchObject(updatedData, updated))
 	s.mu.Lock() // Reacquire for defer unlock
 
 	atomic.AddUint64(&s.metrics.operations, 1)
@@ -0,0 +1,190 @@
+package storage
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/watch"
+
+	k1sstorage "github.com/dtomasi/k1s/core/storage"
+)
+
+// Memory storage instances do not share data, so tenant management only
+// sees the tenants stored in the instance it is called on.
+var _ k1sstorage.TenantManager = &memoryStorage{}
+
+// CreateTenant registers a tenant
+func (s *memoryStorage) CreateTenant(ctx context.Context, tenant k1sstorage.Tenant) error {
+	if ctx.Err() != nil {
+		return k1sstorage.NewContextCancelledError(ctx)
+	}
+
+	if err := k1sstorage.ValidateTenantID(tenant.ID); err != nil {
+		return errors.NewBadRequest(err.Error())
+	}
+
+	if tenant.CreationTimestamp.IsZero() {
+		tenant.CreationTimestamp = metav1.Time{Time: time.Now()}
+	}
+	data, err := k1sstorage.EncodeTenant(tenant)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key := k1sstorage.TenantKey(tenant.ID)
+	if _, exists := s.data[key]; exists {
+		return errors.NewAlreadyExists(k1sstorage.TenantsResource, tenant.ID)
+	}
+	s.data[key] = data
+
+	atomic.AddUint64(&s.metrics.operations, 1)
+	return nil
+}
+
+// GetTenant returns a tenant
+func (s *memoryStorage) GetTenant(ctx context.Context, tenantID string) (*k1sstorage.Tenant, error) {
+	if ctx.Err() != nil {
+		return nil, k1sstorage.NewContextCancelledError(ctx)
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if data, exists := s.data[k1sstorage.TenantKey(tenantID)]; exists {
+		return k1sstorage.DecodeTenant(data)
+	}
+	if s.lockedCountPrefix(k1sstorage.TenantKey(tenantID)+"/") > 0 {
+		return &k1sstorage.Tenant{ID: tenantID}, nil
+	}
+	return nil, errors.NewNotFound(k1sstorage.TenantsResource, tenantID)
+}
+
+// ListTenants returns all registered tenants and all tenants holding data
+func (s *memoryStorage) ListTenants(ctx context.Context) ([]k1sstorage.Tenant, error) {
+	if ctx.Err() != nil {
+		return nil, k1sstorage.NewContextCancelledError(ctx)
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	tenants := map[string]k1sstorage.Tenant{}
+	for key, data := range s.data {
+		tenantID, ok := k1sstorage.TenantIDFromKey(key)
+		if !ok {
+			continue
+		}
+		if key == k1sstorage.TenantKey(tenantID) {
+			tenant, err := k1sstorage.DecodeTenant(data)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read tenant %s: %w", tenantID, err)
+			}
+			tenants[tenantID] = *tenant
+		} else if _, seen := tenants[tenantID]; !seen {
+			tenants[tenantID] = k1sstorage.Tenant{ID: tenantID}
+		}
+	}
+
+	result := make([]k1sstorage.Tenant, 0, len(tenants))
+	for _, tenant := range tenants {
+		result = append(result, tenant)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].ID < result[j].ID })
+	return result, nil
+}
+
+// DeleteTenant removes a tenant together with all of its data
+func (s *memoryStorage) DeleteTenant(ctx context.Context, tenantID string) error {
+	if ctx.Err() != nil {
+		return k1sstorage.NewContextCancelledError(ctx)
+	}
+
+	tenantKey := k1sstorage.TenantKey(tenantID)
+	prefix := tenantKey + "/"
+
+	s.mu.Lock()
+	deleted := map[string][]byte{}
+	for key, data := range s.data {
+		if key == tenantKey || strings.HasPrefix(key, prefix) {
+			deleted[key] = data
+			delete(s.data, key)
+			delete(s.resourceVersions, key)
+		}
+	}
+	s.mu.Unlock()
+
+	if len(deleted) == 0 {
+		return errors.NewNotFound(k1sstorage.TenantsResource, tenantID)
+	}
+
+	// Notify watchers of the tenant's objects
+	for key, data := range deleted {
+		if key == tenantKey {
+			continue
+		}
+		obj := &metav1.PartialObjectMetadata{}
+		if err := json.Unmarshal(data, obj); err != nil {
+			log.Printf("Warning: failed to unmarshal object for watch event: %v", err)
+			continue
+		}
+		s.notifyWatchers(key, watch.Deleted, obj)
+	}
+
+	atomic.AddUint64(&s.metrics.operations, 1)
+	return nil
+}
+
+// CountTenant returns the number of objects stored by a tenant
+func (s *memoryStorage) CountTenant(ctx context.Context, tenantID string) (int64, error) {
+	if ctx.Err() != nil {
+		return 0, k1sstorage.NewContextCancelledError(ctx)
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.lockedCountPrefix(k1sstorage.TenantKey(tenantID) + "/"), nil
+}
+
+// lockedCheckQuota enforces the object quota of the configured tenant. Callers must hold mu.
+func (s *memoryStorage) lockedCheckQuota(key string) error {
+	if s.config.TenantID == "" {
+		return nil
+	}
+
+	data, exists := s.data[k1sstorage.TenantKey(s.config.TenantID)]
+	if !exists {
+		return nil
+	}
+	tenant, err := k1sstorage.DecodeTenant(data)
+	if err != nil {
+		return err
+	}
+	if tenant.MaxObjects <= 0 {
+		return nil
+	}
+
+	return k1sstorage.CheckTenantQuota(tenant, s.lockedCountPrefix(k1sstorage.TenantKey(tenant.ID)+"/"), key)
+}
+
+// lockedCountPrefix counts the keys starting with prefix. Callers must hold mu.
+func (s *memoryStorage) lockedCountPrefix(prefix string) int64 {
+	count := int64(0)
+	for key := range s.data {
+		if strings.HasPrefix(key, prefix) {
+			count++
+		}
+	}
+	return count
+}
@@ -0,0 +1,24 @@
+package storage_test
+
+import (
+	"fmt"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+
+	k1sstorage "github.com/dtomasi/k1s/core/storage"
+	"github.com/dtomasi/k1s/storage/storagetest"
+
+	storage "github.com/dtomasi/k1s/storage/pebble"
+)
+
+var _ = storagetest.DescribeBackend("pebble", func() storagetest.Factory {
+	// Pebble holds an exclusive lock on its directory, so every backend
+	// created for a spec gets its own database
+	dir := GinkgoT().TempDir()
+	count := 0
+	return func(config k1sstorage.Config) k1sstorage.Backend {
+		count++
+		return storage.NewPebbleStorageWithPath(filepath.Join(dir, fmt.Sprintf("db-%d", count)), config)
+	}
+})
@@ -71,3 +71,5 @@ require (
 )
 
 replace github.com/dtomasi/k1s/core => ../../core
+
+replace github.com/dtomasi/k1s/storage/storagetest => ../storagetest
@@ -13,7 +13,6 @@ import (
 
 	"github.com/cockroachdb/pebble/v2"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
-	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
@@ -55,6 +54,15 @@ type pebbleStorage struct {
 	// initMu protects database initialization
 	initMu sync.Mutex
 
+	// writeMu serializes read-modify-write operations so that existence
+	// checks, preconditions and updates see a consistent view of a key
+	writeMu sync.Mutex
+
+	// tenantObjects is the number of objects of the configured tenant, valid
+	// once tenantCounted is set. Both are protected by writeMu.
+	tenantObjects int64
+	tenantCounted bool
+
 	// versioner handles resource version management
 	versioner k1sstorage.SimpleVersioner
 
@@ -172,6 +180,9 @@ func (s *pebbleStorage) Create(ctx context.Context, key string, obj, out runtime
 	// Apply tenant/namespace prefix
 	key = s.buildKey(key)
 
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+
 	// Check if key already exists
 	_, closer, err := s.db.Get([]byte(key))
 	if err == nil {
@@ -187,6 +198,12 @@ func (s *pebbleStorage) Create(ctx context.Context, key string, obj, out runtime
 		return fmt.Errorf("failed to check key existence: %w", err)
 	}
 
+	// Enforce the tenant's object quota
+	if err := s.checkQuota(key); err != nil {
+		atomic.AddUint64(&s.metrics.errors, 1)
+		return err
+	}
+
 	// Prepare object for storage
 	if err := s.versioner.PrepareObjectForStorage(obj); err != nil {
 		atomic.AddUint64(&s.metrics.errors, 1)
@@ -244,6 +261,7 @@ func (s *pebbleStorage) Create(ctx context.Context, key string, obj, out runtime
 	s.versionMu.Lock()
 	s.resourceVersions[key] = resourceVersion
 	s.versionMu.Unlock()
+	s.trackTenantObjects(1)
 
 	// Copy to output object if provided
 	if out != nil {
@@ -254,7 +272,7 @@ func (s *pebbleStorage) Create(ctx context.Context, key string, obj, out runtime
 	}
 
 	// Notify watchers
-	s.notifyWatchers(key, watch.Added, obj)
+	s.notifyWatchers(key, watch.Added, k1sstorage.WatchObject(data, obj))
 
 	atomic.AddUint64(&s.metrics.operations, 1)
 	return nil
@@ -282,37 +300,40 @@ func (s *pebbleStorage) Delete(ctx context.Context, key string, out runtime.Obje
 	// Apply tenant/namespace prefix
 	key = s.buildKey(key)
 
-	// Get existing object
-	var existingObj runtime.Object
-	if cachedExistingObject != nil {
-		existingObj = cachedExistingObject
-	} else {
-		data, closer, err := s.db.Get([]byte(key))
-		if err == pebble.ErrNotFound {
-			atomic.AddUint64(&s.metrics.errors, 1)
-			// Use Kubernetes standard error type for not found
-			gr := schema.GroupResource{Resource: "objects"} // Generic resource for storage
-			return apierrors.NewNotFound(gr, key)
-		} else if err != nil {
-			atomic.AddUint64(&s.metrics.errors, 1)
-			return fmt.Errorf("failed to get existing object: %w", err)
-		}
-		defer func() {
-			if err := closer.Close(); err != nil {
-				log.Printf("Warning: %s closer: %v", errFailedToClose, err)
-			}
-		}()
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
 
-		existingObj = &runtime.Unknown{}
-		if err := json.Unmarshal(data, existingObj); err != nil {
-			atomic.AddUint64(&s.metrics.errors, 1)
-			return fmt.Errorf("failed to unmarshal existing object: %w", err)
-		}
+	// Get existing object from storage so preconditions are checked against
+	// the stored state rather than a possibly stale cached copy
+	data, closer, err := s.db.Get([]byte(key))
+	if err == pebble.ErrNotFound {
+		atomic.AddUint64(&s.metrics.errors, 1)
+		// Use Kubernetes standard error type for not found
+		gr := schema.GroupResource{Resource: "objects"} // Generic resource for storage
+		return apierrors.NewNotFound(gr, key)
+	} else if err != nil {
+		atomic.AddUint64(&s.metrics.errors, 1)
+		return fmt.Errorf("failed to get existing object: %w", err)
+	}
+
+	var existingObj runtime.Object = &metav1.PartialObjectMetadata{}
+	if out != nil {
+		existingObj = out
+	}
+	err = json.Unmarshal(data, existingObj)
+	// The data is only valid until the closer is closed
+	existingData := append([]byte(nil), data...)
+	if closeErr := closer.Close(); closeErr != nil {
+		log.Printf("Warning: %s closer: %v", errFailedToClose, closeErr)
+	}
+	if err != nil {
+		atomic.AddUint64(&s.metrics.errors, 1)
+		return fmt.Errorf("failed to unmarshal existing object: %w", err)
 	}
 
 	// Validate preconditions if provided
 	if preconditions != nil {
-		if err := s.checkPreconditions(existingObj, preconditions); err != nil {
+		if err := k1sstorage.CheckPreconditions(key, existingObj, preconditions); err != nil {
 			atomic.AddUint64(&s.metrics.errors, 1)
 			return err
 		}
@@ -326,14 +347,6 @@ func (s *pebbleStorage) Delete(ctx context.Context, key string, out runtime.Obje
 		}
 	}
 
-	// Copy existing object to output if provided
-	if out != nil {
-		if err := s.copyObject(existingObj, out); err != nil {
-			atomic.AddUint64(&s.metrics.errors, 1)
-			return fmt.Errorf("failed to copy to output object: %w", err)
-		}
-	}
-
 	// Delete from PebbleDB with atomic transaction
 	batch := s.db.NewBatch()
 	if err := batch.Delete([]byte(key), pebble.Sync); err != nil {
@@ -370,9 +383,14 @@ func (s *pebbleStorage) Delete(ctx context.Context, key string, out runtime.Obje
 	s.versionMu.Lock()
 	delete(s.resourceVersions, key)
 	s.versionMu.Unlock()
+	s.trackTenantObjects(-1)
 
-	// Notify watchers
-	s.notifyWatchers(key, watch.Deleted, existingObj)
+	// Notify watchers, preferring the type of the cached object when available
+	like := existingObj
+	if cachedExistingObject != nil {
+		like = cachedExistingObject
+	}
+	s.notifyWatchers(key, watch.Deleted, k1sstorage.WatchObject(existingData, like))
 
 	atomic.AddUint64(&s.metrics.operations, 1)
 	return nil
@@ -464,7 +482,7 @@ func (s *pebbleStorage) List(ctx context.Context, key string, opts storage.ListO
 	// Apply tenant/namespace prefix
 	key = s.buildKey(key)
 
-	var matchedObjects []runtime.Object
+	var matchedItems [][]byte
 	var maxResourceVersion uint64
 
 	// Create iterator for efficient prefix scanning
@@ -509,12 +527,8 @@ func (s *pebbleStorage) List(ctx context.Context, key string, opts storage.ListO
 			continue
 		}
 
-		// Create placeholder object with actual data from storage
-		obj := &runtime.Unknown{
-			Raw:         iter.Value(),
-			ContentType: runtime.ContentTypeJSON,
-		}
-		matchedObjects = append(matchedObjects, obj)
+		// Copy the value, the iterator reuses its buffer on Next
+		matchedItems = append(matchedItems, append([]byte(nil), iter.Value()...))
 
 		// Track max resource version
 		s.versionMu.RLock()
@@ -537,24 +551,9 @@ func (s *pebbleStorage) List(ctx context.Context, key string, opts storage.ListO
 	}
 
 	// Set the items in the list
-	// Handle different list types differently
-	switch typedList := listObj.(type) {
-	case *metav1.List:
-		// For generic lists, directly set items as RawExtension
-		typedList.Items = make([]runtime.RawExtension, len(matchedObjects))
-		for i, obj := range matchedObjects {
-			if unknown, ok := obj.(*runtime.Unknown); ok {
-				typedList.Items[i] = runtime.RawExtension{
-					Raw: unknown.Raw,
-				}
-			}
-		}
-	default:
-		// Use meta.SetList for typed lists
-		if err := meta.SetList(listObj, matchedObjects); err != nil {
-			atomic.AddUint64(&s.metrics.errors, 1)
-			return fmt.Errorf("failed to set list items: %w", err)
-		}
+	if err := k1sstorage.SetListItems(listObj, matchedItems); err != nil {
+		atomic.AddUint64(&s.metrics.errors, 1)
+		return fmt.Errorf("failed to set list items: %w", err)
 	}
 
 	atomic.AddUint64(&s.metrics.operations, 1)
@@ -710,12 +709,17 @@ func (s *pebbleStorage) Count(ctx context.Context, key string) (int64, error) {
 	// Apply tenant/namespace prefix
 	key = s.buildKey(key)
 
+	return s.countPrefix(key)
+}
+
+// countPrefix counts the objects whose keys start with prefix
+func (s *pebbleStorage) countPrefix(prefix string) (int64, error) {
 	var count int64
 
 	// Create iterator for efficient prefix scanning
 	prefixIterOptions := &pebble.IterOptions{
-		LowerBound: []byte(key),
-		UpperBound: []byte(key + "\xFF"),
+		LowerBound: []byte(prefix),
+		UpperBound: []byte(prefix + "\xFF"),
 	}
 
 	iter, err := s.db.NewIter(prefixIterOptions)
@@ -737,7 +741,7 @@ func (s *pebbleStorage) Count(ctx context.Context, key string) (int64, error) {
 			continue
 		}
 
-		if strings.HasPrefix(storageKey, key) {
+		if strings.HasPrefix(storageKey, prefix) {
 			count++
 		}
 	}
@@ -756,7 +760,7 @@ func (s *pebbleStorage) buildKey(key string) string {
 
 	// Add tenant prefix if configured
 	if s.config.TenantID != "" {
-		parts = append(parts, "tenants", s.config.TenantID)
+		parts = append(parts, k1sstorage.TenantKey(s.config.TenantID))
 	}
 
 	// Add custom key prefix if configured
@@ -775,52 +779,6 @@ func (s *pebbleStorage) buildKey(key string) string {
 	return strings.Join(parts, "/")
 }
 
-// checkPreconditions validates storage preconditions
-func (s *pebbleStorage) checkPreconditions(obj runtime.Object, preconditions *storage.Preconditions) error {
-	if preconditions == nil {
-		return nil
-	}
-
-	// Check UID precondition
-	if preconditions.UID != nil {
-		accessor, err := meta.Accessor(obj)
-		if err != nil {
-			return fmt.Errorf("failed to get object accessor: %w", err)
-		}
-		if accessor.GetUID() != *preconditions.UID {
-			return fmt.Errorf("UID mismatch: expected %s, got %s", *preconditions.UID, accessor.GetUID())
-		}
-	}
-
-	// Check ResourceVersion precondition
-	if preconditions.ResourceVersion != nil {
-		accessor, err := meta.Accessor(obj)
-		if err != nil {
-			return fmt.Errorf("failed to get object accessor: %w", err)
-		}
-		if accessor.GetResourceVersion() != *preconditions.ResourceVersion {
-			return fmt.Errorf("resource version mismatch: expected %s, got %s",
-				*preconditions.ResourceVersion, accessor.GetResourceVersion())
-		}
-	}
-
-	return nil
-}
-
-// copyObject copies source object to destination
-func (s *pebbleStorage) copyObject(src, dst runtime.Object) error {
-	data, err := json.Marshal(src)
-	if err != nil {
-		return fmt.Errorf("failed to marshal source object: %w", err)
-	}
-
-	if err := json.Unmarshal(data, dst); err != nil {
-		return fmt.Errorf("failed to unmarshal to destination object: %w", err)
-	}
-
-	return nil
-}
-
 // notifyWatchers sends watch events to all registered watchers
 func (s *pebbleStorage) notifyWatchers(key string, eventType watch.EventType, obj runtime.Object) {
 	s.watchMu.RLock()
@@ -881,6 +839,9 @@ func (s *pebbleStorage) GuaranteedUpdate(ctx context.Context, key string, destin
 	// Apply tenant/namespace prefix
 	key = s.buildKey(key)
 
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+
 	// Get current object
 	value, closer, err := s.db.Get([]byte(key))
 	var current runtime.Object
@@ -915,8 +876,16 @@ func (s *pebbleStorage) GuaranteedUpdate(ctx context.Context, key string, destin
 	}
 
 	// Check preconditions if provided
-	if preconditions != nil {
-		if err := s.checkPreconditions(current, preconditions); err != nil {
+	if exists && preconditions != nil {
+		if err := k1sstorage.CheckPreconditions(key, current, preconditions); err != nil {
+			atomic.AddUint64(&s.metrics.errors, 1)
+			return err
+		}
+	}
+
+	// Creating the object counts towards the tenant's object quota
+	if !exists {
+		if err := s.checkQuota(key); err != nil {
 			atomic.AddUint64(&s.metrics.errors, 1)
 			return err
 		}
@@ -969,6 +938,9 @@ func (s *pebbleStorage) GuaranteedUpdate(ctx context.Context, key string, destin
 		atomic.AddUint64(&s.metrics.errors, 1)
 		return fmt.Errorf("failed to commit batch: %w", err)
 	}
+	if !exists {
+		s.trackTenantObjects(1)
+	}
 
 	// Copy to destination
 	if err := json.Unmarshal(updatedData, destination); err != nil {
@@ -981,7 +953,7 @@ func (s *pebbleStorage) GuaranteedUpdate(ctx context.Context, key string, destin
 	if !exists {
 		eventType = watch.Added
 	}
-	s.notifyWatchers(key, eventType, updated)
+	s.notifyWatchers(key, eventType, k1sstorage.WatchObject(updatedData, updated))
 
 	atomic.AddUint64(&s.metrics.operations, 1)
 	_ = ttl // TTL not implemented in pebble storage
@@ -0,0 +1,302 @@
+package storage
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/cockroachdb/pebble/v2"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/watch"
+
+	k1sstorage "github.com/dtomasi/k1s/core/storage"
+)
+
+var _ k1sstorage.TenantManager = &pebbleStorage{}
+
+// CreateTenant registers a tenant
+func (s *pebbleStorage) CreateTenant(ctx context.Context, tenant k1sstorage.Tenant) error {
+	if err := s.tenantReady(ctx); err != nil {
+		return err
+	}
+
+	if err := k1sstorage.ValidateTenantID(tenant.ID); err != nil {
+		return apierrors.NewBadRequest(err.Error())
+	}
+
+	if tenant.CreationTimestamp.IsZero() {
+		tenant.CreationTimestamp = metav1.Time{Time: time.Now()}
+	}
+	data, err := k1sstorage.EncodeTenant(tenant)
+	if err != nil {
+		return err
+	}
+
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+
+	key := []byte(k1sstorage.TenantKey(tenant.ID))
+	if _, closer, err := s.db.Get(key); err == nil {
+		if err := closer.Close(); err != nil {
+			log.Printf("Warning: %s closer: %v", errFailedToClose, err)
+		}
+		return apierrors.NewAlreadyExists(k1sstorage.TenantsResource, tenant.ID)
+	} else if err != pebble.ErrNotFound {
+		return fmt.Errorf("failed to check tenant existence: %w", err)
+	}
+
+	if err := s.db.Set(key, data, pebble.Sync); err != nil {
+		return fmt.Errorf("failed to store tenant: %w", err)
+	}
+
+	atomic.AddUint64(&s.metrics.operations, 1)
+	return nil
+}
+
+// GetTenant returns a tenant
+func (s *pebbleStorage) GetTenant(ctx context.Context, tenantID string) (*k1sstorage.Tenant, error) {
+	if err := s.tenantReady(ctx); err != nil {
+		return nil, err
+	}
+
+	tenant, err := s.readTenant(tenantID)
+	if err != nil || tenant != nil {
+		return tenant, err
+	}
+
+	count, err := s.countPrefix(k1sstorage.TenantKey(tenantID) + "/")
+	if err != nil {
+		return nil, err
+	}
+	if count > 0 {
+		return &k1sstorage.Tenant{ID: tenantID}, nil
+	}
+	return nil, apierrors.NewNotFound(k1sstorage.TenantsResource, tenantID)
+}
+
+// ListTenants returns all registered tenants and all tenants holding data
+func (s *pebbleStorage) ListTenants(ctx context.Context) ([]k1sstorage.Tenant, error) {
+	if err := s.tenantReady(ctx); err != nil {
+		return nil, err
+	}
+
+	prefix := k1sstorage.TenantsKeyPrefix + "/"
+	iter, err := s.db.NewIter(&pebble.IterOptions{
+		LowerBound: []byte(prefix),
+		UpperBound: []byte(prefix + "\xFF"),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create iterator: %w", err)
+	}
+	defer func() {
+		if err := iter.Close(); err != nil {
+			log.Printf("Warning: %s iterator: %v", errFailedToClose, err)
+		}
+	}()
+
+	tenants := map[string]k1sstorage.Tenant{}
+	for iter.First(); iter.Valid(); iter.Next() {
+		key := string(iter.Key())
+		tenantID, ok := k1sstorage.TenantIDFromKey(key)
+		if !ok {
+			continue
+		}
+		if key == k1sstorage.TenantKey(tenantID) {
+			tenant, err := k1sstorage.DecodeTenant(iter.Value())
+			if err != nil {
+				return nil, fmt.Errorf("failed to read tenant %s: %w", tenantID, err)
+			}
+			tenants[tenantID] = *tenant
+		} else if _, seen := tenants[tenantID]; !seen {
+			tenants[tenantID] = k1sstorage.Tenant{ID: tenantID}
+		}
+	}
+	if err := iter.Error(); err != nil {
+		return nil, fmt.Errorf("iterator error: %w", err)
+	}
+
+	result := make([]k1sstorage.Tenant, 0, len(tenants))
+	for _, tenant := range tenants {
+		result = append(result, tenant)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].ID < result[j].ID })
+	return result, nil
+}
+
+// DeleteTenant removes a tenant together with all of its data
+func (s *pebbleStorage) DeleteTenant(ctx context.Context, tenantID string) error {
+	if err := s.tenantReady(ctx); err != nil {
+		return err
+	}
+
+	tenantKey := k1sstorage.TenantKey(tenantID)
+	prefix := tenantKey + "/"
+
+	s.writeMu.Lock()
+
+	// Collect the tenant's objects so that watchers can be notified
+	deleted, err := s.readPrefix(prefix)
+	if err != nil {
+		s.writeMu.Unlock()
+		return err
+	}
+
+	registered, err := s.readTenant(tenantID)
+	if err != nil {
+		s.writeMu.Unlock()
+		return err
+	}
+	if registered == nil && len(deleted) == 0 {
+		s.writeMu.Unlock()
+		return apierrors.NewNotFound(k1sstorage.TenantsResource, tenantID)
+	}
+
+	batch := s.db.NewBatch()
+	err = errors.Join(
+		batch.Delete([]byte(tenantKey), pebble.NoSync),
+		batch.DeleteRange([]byte(prefix), []byte(prefix+"\xFF"), pebble.NoSync),
+	)
+	if err == nil {
+		err = batch.Commit(pebble.Sync)
+	}
+	if closeErr := batch.Close(); closeErr != nil {
+		log.Printf("Warning: %s batch: %v", errFailedToClose, closeErr)
+	}
+	if err != nil {
+		s.writeMu.Unlock()
+		atomic.AddUint64(&s.metrics.errors, 1)
+		return fmt.Errorf("failed to delete tenant %s: %w", tenantID, err)
+	}
+
+	s.versionMu.Lock()
+	for key := range s.resourceVersions {
+		if strings.HasPrefix(key, prefix) {
+			delete(s.resourceVersions, key)
+		}
+	}
+	s.versionMu.Unlock()
+	if tenantID == s.config.TenantID {
+		s.tenantObjects = 0
+	}
+	s.writeMu.Unlock()
+
+	// Notify watchers of the tenant's objects
+	for key, data := range deleted {
+		obj := &metav1.PartialObjectMetadata{}
+		if err := json.Unmarshal(data, obj); err != nil {
+			log.Printf("Warning: failed to unmarshal object for watch event: %v", err)
+			continue
+		}
+		s.notifyWatchers(key, watch.Deleted, obj)
+	}
+
+	atomic.AddUint64(&s.metrics.operations, 1)
+	return nil
+}
+
+// CountTenant returns the number of objects stored by a tenant
+func (s *pebbleStorage) CountTenant(ctx context.Context, tenantID string) (int64, error) {
+	if err := s.tenantReady(ctx); err != nil {
+		return 0, err
+	}
+
+	return s.countPrefix(k1sstorage.TenantKey(tenantID) + "/")
+}
+
+// tenantReady checks the context and opens the database for tenant operations
+func (s *pebbleStorage) tenantReady(ctx context.Context) error {
+	if ctx.Err() != nil {
+		return k1sstorage.NewContextCancelledError(ctx)
+	}
+
+	if s.closed.Load() {
+		return errors.New(errStorageIsClosed)
+	}
+
+	return s.initDB()
+}
+
+// readTenant returns the registration record of a tenant, or nil if it is not registered
+func (s *pebbleStorage) readTenant(tenantID string) (*k1sstorage.Tenant, error) {
+	data, closer, err := s.db.Get([]byte(k1sstorage.TenantKey(tenantID)))
+	if err == pebble.ErrNotFound {
+		return nil, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to get tenant: %w", err)
+	}
+	defer func() {
+		if err := closer.Close(); err != nil {
+			log.Printf("Warning: %s closer: %v", errFailedToClose, err)
+		}
+	}()
+
+	return k1sstorage.DecodeTenant(data)
+}
+
+// readPrefix returns the stored objects whose keys start with prefix
+func (s *pebbleStorage) readPrefix(prefix string) (map[string][]byte, error) {
+	iter, err := s.db.NewIter(&pebble.IterOptions{
+		LowerBound: []byte(prefix),
+		UpperBound: []byte(prefix + "\xFF"),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create iterator: %w", err)
+	}
+	defer func() {
+		if err := iter.Close(); err != nil {
+			log.Printf("Warning: %s iterator: %v", errFailedToClose, err)
+		}
+	}()
+
+	objects := map[string][]byte{}
+	for iter.First(); iter.Valid(); iter.Next() {
+		key := string(iter.Key())
+		if strings.HasSuffix(key, errVersionSuffix) {
+			continue
+		}
+		objects[key] = append([]byte(nil), iter.Value()...)
+	}
+	if err := iter.Error(); err != nil {
+		return nil, fmt.Errorf("iterator error: %w", err)
+	}
+	return objects, nil
+}
+
+// checkQuota enforces the object quota of the configured tenant. The
+// tenant's objects are counted on the first check and tracked by every write
+// afterwards, so that creating an object does not scan the tenant's keys.
+// Callers must hold writeMu.
+func (s *pebbleStorage) checkQuota(key string) error {
+	if s.config.TenantID == "" {
+		return nil
+	}
+
+	tenant, err := s.readTenant(s.config.TenantID)
+	if err != nil || tenant == nil || tenant.MaxObjects <= 0 {
+		return err
+	}
+
+	if !s.tenantCounted {
+		count, err := s.countPrefix(k1sstorage.TenantKey(tenant.ID) + "/")
+		if err != nil {
+			return err
+		}
+		s.tenantObjects, s.tenantCounted = count, true
+	}
+	return k1sstorage.CheckTenantQuota(tenant, s.tenantObjects, key)
+}
+
+// trackTenantObjects adjusts the number of objects of the configured tenant
+// once it has been counted. Callers must hold writeMu.
+func (s *pebbleStorage) trackTenantObjects(delta int64) {
+	if s.tenantCounted {
+		s.tenantObjects += delta
+	}
+}
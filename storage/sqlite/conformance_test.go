@@ -0,0 +1,19 @@
+package storage_test
+
+import (
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+
+	k1sstorage "github.com/dtomasi/k1s/core/storage"
+	"github.com/dtomasi/k1s/storage/storagetest"
+
+	storage "github.com/dtomasi/k1s/storage/sqlite"
+)
+
+var _ = storagetest.DescribeBackend("sqlite", func() storagetest.Factory {
+	dbPath := filepath.Join(GinkgoT().TempDir(), "k1s.sqlite")
+	return func(config k1sstorage.Config) k1sstorage.Backend {
+		return storage.NewSQLiteStorageWithPath(dbPath, config)
+	}
+})
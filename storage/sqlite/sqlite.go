@@ -0,0 +1,1142 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/apiserver/pkg/storage"
+
+	// Register the pure-Go SQLite driver
+	_ "modernc.org/sqlite"
+
+	k1sstorage "github.com/dtomasi/k1s/core/storage"
+)
+
+// Error constants for goconst linter
+const (
+	errStorageIsClosed = "storage is closed"
+	errFailedToClose   = "failed to close"
+)
+
+const (
+	// DefaultDBPath is the database file used when no path is configured
+	DefaultDBPath = "./data/k1s.sqlite"
+
+	// busyTimeoutMillis is how long a connection waits for another process
+	// to release the database lock before failing with SQLITE_BUSY
+	busyTimeoutMillis = 5000
+
+	// watchPollInterval is how often the change-log is polled for writes
+	// performed by other processes
+	watchPollInterval = 100 * time.Millisecond
+
+	// changelogRetention is the number of change-log entries kept by Compact
+	changelogRetention = 10000
+)
+
+// schema creates the tables used by the SQLite backend. Every write appends
+// a row to the change-log; its revision is the object's resourceVersion, so
+// revisions are monotonic across all processes sharing the database file.
+const schemaSQL = `
+CREATE TABLE IF NOT EXISTS objects (
+	key              TEXT PRIMARY KEY,
+	value            BLOB NOT NULL,
+	resource_version INTEGER NOT NULL,
+	expires_at       INTEGER
+);
+CREATE TABLE IF NOT EXISTS changelog (
+	revision   INTEGER PRIMARY KEY AUTOINCREMENT,
+	key        TEXT NOT NULL,
+	event_type TEXT NOT NULL,
+	value      BLOB
+);
+CREATE INDEX IF NOT EXISTS changelog_key ON changelog(key);
+CREATE TABLE IF NOT EXISTS tenants (
+	id    TEXT PRIMARY KEY,
+	value BLOB NOT NULL
+);
+`
+
+// sqliteStorage implements a single-file storage backend for k1s using an
+// embedded pure-Go SQLite driver. The database runs in WAL mode and relies on
+// SQLite's own locking, so several CLI processes can open the same file safely.
+type sqliteStorage struct {
+	// db is the underlying SQLite connection pool
+	db *sql.DB
+
+	// path is the location of the database file
+	path string
+
+	// initMu protects database initialization
+	initMu sync.Mutex
+
+	// watchers maintains active watches for keys/prefixes
+	watchers map[string][]*queuedWatch
+
+	// watchMu protects watcher operations
+	watchMu sync.RWMutex
+
+	// pollMu serializes change-log polling and watch registration
+	pollMu sync.Mutex
+
+	// lastRevision is the last change-log revision delivered by the poller
+	lastRevision uint64
+
+	// localRevisions tracks revisions written by this process. Their watch
+	// events are delivered directly, so the poller skips them.
+	localRevisions map[uint64]struct{}
+
+	// localMu protects localRevisions
+	localMu sync.Mutex
+
+	// pollerStop stops the change-log poller
+	pollerStop chan struct{}
+
+	// pollerDone is closed when the change-log poller exits
+	pollerDone chan struct{}
+
+	// versioner handles resource version management
+	versioner k1sstorage.SimpleVersioner
+
+	// config contains storage configuration
+	config k1sstorage.Config
+
+	// metrics tracks operation statistics
+	metrics *sqliteMetrics
+
+	// closed indicates if the storage is closed
+	closed atomic.Bool
+}
+
+// sqliteMetrics tracks performance and operational metrics
+type sqliteMetrics struct {
+	operations uint64
+	errors     uint64
+	watchers   uint64
+}
+
+// NewSQLiteStorage creates a new SQLite storage backend using the default database file
+func NewSQLiteStorage(config k1sstorage.Config) k1sstorage.Backend {
+	return NewSQLiteStorageWithPath(DefaultDBPath, config)
+}
+
+// NewSQLiteStorageWithPath creates a new SQLite storage backend with a custom database file
+func NewSQLiteStorageWithPath(path string, config k1sstorage.Config) k1sstorage.Backend {
+	if path == "" {
+		path = DefaultDBPath
+	}
+	return &sqliteStorage{
+		path:           path,
+		watchers:       make(map[string][]*queuedWatch),
+		localRevisions: make(map[uint64]struct{}),
+		versioner:      k1sstorage.SimpleVersioner{},
+		config:         config,
+		metrics:        &sqliteMetrics{},
+	}
+}
+
+// initDB opens the SQLite database and creates the schema if not already initialized
+func (s *sqliteStorage) initDB() error {
+	s.initMu.Lock()
+	defer s.initMu.Unlock()
+
+	// Double-check after acquiring lock
+	if s.db != nil {
+		return nil
+	}
+
+	if dir := filepath.Dir(s.path); dir != "" {
+		if err := os.MkdirAll(dir, 0o750); err != nil {
+			return fmt.Errorf("failed to create database directory %s: %w", dir, err)
+		}
+	}
+
+	// WAL mode allows readers to proceed while another process writes, and
+	// immediate transactions take the write lock up front so that concurrent
+	// writers queue on busy_timeout instead of failing on lock upgrade.
+	query := url.Values{}
+	query.Add("_pragma", fmt.Sprintf("busy_timeout(%d)", busyTimeoutMillis))
+	query.Add("_pragma", "journal_mode(WAL)")
+	query.Add("_pragma", "synchronous(NORMAL)")
+	query.Set("_txlock", "immediate")
+	dsn := "file:" + s.path + "?" + query.Encode()
+
+	db, err := sql.Open("sqlite", dsn)
+	if err != nil {
+		return fmt.Errorf("failed to open sqlite database at %s: %w", s.path, err)
+	}
+
+	if _, err := db.Exec(schemaSQL); err != nil {
+		if closeErr := db.Close(); closeErr != nil {
+			log.Printf("Warning: %s database: %v", errFailedToClose, closeErr)
+		}
+		return fmt.Errorf("failed to open sqlite database at %s: %w", s.path, err)
+	}
+
+	s.db = db
+	return nil
+}
+
+// ready checks the context and storage state and lazily opens the database
+func (s *sqliteStorage) ready(ctx context.Context) error {
+	if ctx.Err() != nil {
+		return k1sstorage.NewContextCancelledError(ctx)
+	}
+
+	if s.closed.Load() {
+		atomic.AddUint64(&s.metrics.errors, 1)
+		return errors.New(errStorageIsClosed)
+	}
+
+	if err := s.initDB(); err != nil {
+		atomic.AddUint64(&s.metrics.errors, 1)
+		return err
+	}
+
+	return nil
+}
+
+// Name returns the name of this storage backend
+func (s *sqliteStorage) Name() string {
+	return "sqlite"
+}
+
+// Versioner returns the storage versioner
+func (s *sqliteStorage) Versioner() storage.Versioner {
+	return s.versioner
+}
+
+// Create adds a new object at a key unless it already exists
+func (s *sqliteStorage) Create(ctx context.Context, key string, obj, out runtime.Object, ttl uint64) error {
+	if err := s.ready(ctx); err != nil {
+		return err
+	}
+
+	// Apply tenant/namespace prefix
+	key = s.buildKey(key)
+
+	// Prepare object for storage
+	if err := s.versioner.PrepareObjectForStorage(obj); err != nil {
+		atomic.AddUint64(&s.metrics.errors, 1)
+		return fmt.Errorf("failed to prepare object for storage: %w", err)
+	}
+
+	var data []byte
+	revision, err := s.write(ctx, key, watch.Added, func(tx *sql.Tx, revision uint64) error {
+		// Check if key already exists
+		if _, _, found, err := s.getRow(ctx, tx, key); err != nil {
+			return err
+		} else if found {
+			// Use Kubernetes standard error type for already exists
+			gr := schema.GroupResource{Resource: "objects"} // Generic resource for storage
+			return apierrors.NewAlreadyExists(gr, key)
+		}
+
+		// Enforce the tenant's object quota
+		if err := s.checkQuota(ctx, tx, key); err != nil {
+			return err
+		}
+
+		if err := s.versioner.UpdateObject(obj, revision); err != nil {
+			return fmt.Errorf("failed to update resource version: %w", err)
+		}
+
+		var err error
+		data, err = json.Marshal(obj)
+		if err != nil {
+			return fmt.Errorf("failed to serialize object: %w", err)
+		}
+
+		return s.putRow(ctx, tx, key, data, revision, expiresAt(ttl))
+	})
+	if err != nil {
+		atomic.AddUint64(&s.metrics.errors, 1)
+		return err
+	}
+
+	// Copy to output object if provided
+	if out != nil {
+		if err := json.Unmarshal(data, out); err != nil {
+			atomic.AddUint64(&s.metrics.errors, 1)
+			return fmt.Errorf("failed to unmarshal to output object: %w", err)
+		}
+	}
+
+	// Notify watchers
+	s.notifyLocal(revision, key, watch.Added, k1sstorage.WatchObject(data, obj))
+
+	atomic.AddUint64(&s.metrics.operations, 1)
+	return nil
+}
+
+// Delete removes the specified key and returns the value that existed at that key
+func (s *sqliteStorage) Delete(ctx context.Context, key string, out runtime.Object,
+	preconditions *storage.Preconditions, validateDeletion storage.ValidateObjectFunc,
+	cachedExistingObject runtime.Object) error {
+
+	if err := s.ready(ctx); err != nil {
+		return err
+	}
+
+	// Apply tenant/namespace prefix
+	key = s.buildKey(key)
+
+	var existingObj runtime.Object
+	var existingData []byte
+	revision, err := s.write(ctx, key, watch.Deleted, func(tx *sql.Tx, revision uint64) error {
+		data, _, found, err := s.getRow(ctx, tx, key)
+		if err != nil {
+			return err
+		}
+		if !found {
+			// Use Kubernetes standard error type for not found
+			gr := schema.GroupResource{Resource: "objects"} // Generic resource for storage
+			return apierrors.NewNotFound(gr, key)
+		}
+
+		// Decode the stored object so preconditions are checked against
+		// the persisted state rather than a possibly stale cached copy
+		if out != nil {
+			existingObj = out
+		} else {
+			existingObj = &metav1.PartialObjectMetadata{}
+		}
+		if err := json.Unmarshal(data, existingObj); err != nil {
+			return fmt.Errorf("failed to unmarshal existing object: %w", err)
+		}
+		existingData = data
+
+		// Validate preconditions if provided
+		if err := k1sstorage.CheckPreconditions(key, existingObj, preconditions); err != nil {
+			return err
+		}
+
+		// Validate deletion if provided
+		if validateDeletion != nil {
+			if err := validateDeletion(ctx, existingObj); err != nil {
+				return err
+			}
+		}
+
+		if _, err := tx.ExecContext(ctx, `DELETE FROM objects WHERE key = ?`, key); err != nil {
+			return fmt.Errorf("failed to delete key: %w", err)
+		}
+
+		return s.setChangeValue(ctx, tx, revision, data)
+	})
+	if err != nil {
+		atomic.AddUint64(&s.metrics.errors, 1)
+		return err
+	}
+
+	// Notify watchers, preferring the type of the cached object when available
+	like := existingObj
+	if cachedExistingObject != nil {
+		like = cachedExistingObject
+	}
+	s.notifyLocal(revision, key, watch.Deleted, k1sstorage.WatchObject(existingData, like))
+
+	atomic.AddUint64(&s.metrics.operations, 1)
+	return nil
+}
+
+// Get unmarshals object found at key into objPtr
+func (s *sqliteStorage) Get(ctx context.Context, key string, opts storage.GetOptions, objPtr runtime.Object) error {
+	if err := s.ready(ctx); err != nil {
+		return err
+	}
+
+	// Apply tenant/namespace prefix
+	key = s.buildKey(key)
+
+	data, storedVersion, found, err := s.getRow(ctx, s.db, key)
+	if err != nil {
+		atomic.AddUint64(&s.metrics.errors, 1)
+		return err
+	}
+	if !found {
+		atomic.AddUint64(&s.metrics.errors, 1)
+		if opts.IgnoreNotFound {
+			return nil
+		}
+		// Use Kubernetes standard error type for not found
+		gr := schema.GroupResource{Resource: "objects"} // Generic resource for storage
+		return apierrors.NewNotFound(gr, key)
+	}
+
+	// Check resource version if specified
+	if opts.ResourceVersion != "" {
+		requestedVersion, err := s.versioner.ParseWatchResourceVersion(opts.ResourceVersion)
+		if err != nil {
+			atomic.AddUint64(&s.metrics.errors, 1)
+			return fmt.Errorf("failed to parse resource version %s: %w", opts.ResourceVersion, err)
+		}
+
+		if requestedVersion != 0 && requestedVersion != storedVersion {
+			atomic.AddUint64(&s.metrics.errors, 1)
+			return fmt.Errorf("resource version mismatch: requested %s, stored %d", opts.ResourceVersion, storedVersion)
+		}
+	}
+
+	// Unmarshal data into objPtr
+	if err := json.Unmarshal(data, objPtr); err != nil {
+		atomic.AddUint64(&s.metrics.errors, 1)
+		return fmt.Errorf("failed to unmarshal object: %w", err)
+	}
+
+	atomic.AddUint64(&s.metrics.operations, 1)
+	return nil
+}
+
+// List unmarshalls objects found at key into a List api object
+func (s *sqliteStorage) List(ctx context.Context, key string, opts storage.ListOptions, listObj runtime.Object) error {
+	if err := s.ready(ctx); err != nil {
+		return err
+	}
+
+	// Apply tenant/namespace prefix
+	key = s.buildKey(key)
+
+	query := `SELECT value, resource_version FROM objects WHERE key = ? AND ` + notExpired + ` ORDER BY key`
+	args := []interface{}{key, time.Now().Unix()}
+	if opts.Recursive {
+		query = `SELECT value, resource_version FROM objects WHERE key >= ? AND key < ? AND ` + notExpired + ` ORDER BY key`
+		args = []interface{}{key, prefixEnd(key), time.Now().Unix()}
+	}
+
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		atomic.AddUint64(&s.metrics.errors, 1)
+		return fmt.Errorf("failed to query objects: %w", err)
+	}
+	defer func() {
+		if err := rows.Close(); err != nil {
+			log.Printf("Warning: %s rows: %v", errFailedToClose, err)
+		}
+	}()
+
+	var items [][]byte
+	var maxResourceVersion uint64
+	for rows.Next() {
+		var value []byte
+		var resourceVersion uint64
+		if err := rows.Scan(&value, &resourceVersion); err != nil {
+			atomic.AddUint64(&s.metrics.errors, 1)
+			return fmt.Errorf("failed to scan object: %w", err)
+		}
+		items = append(items, value)
+
+		// Track max resource version
+		if resourceVersion > maxResourceVersion {
+			maxResourceVersion = resourceVersion
+		}
+	}
+	if err := rows.Err(); err != nil {
+		atomic.AddUint64(&s.metrics.errors, 1)
+		return fmt.Errorf("failed to iterate objects: %w", err)
+	}
+
+	// Set list metadata
+	if err := s.versioner.UpdateList(listObj, maxResourceVersion, "", nil); err != nil {
+		atomic.AddUint64(&s.metrics.errors, 1)
+		return fmt.Errorf("failed to update list metadata: %w", err)
+	}
+
+	// Set the items in the list
+	if err := k1sstorage.SetListItems(listObj, items); err != nil {
+		atomic.AddUint64(&s.metrics.errors, 1)
+		return fmt.Errorf("failed to set list items: %w", err)
+	}
+
+	atomic.AddUint64(&s.metrics.operations, 1)
+	return nil
+}
+
+// Watch begins watching a specific key or key prefix for changes. Writes made
+// by this process are delivered immediately; writes made by other processes
+// are picked up from the change-log and delivered as unstructured objects.
+// A non-zero opts.ResourceVersion replays the change-log since that revision.
+func (s *sqliteStorage) Watch(ctx context.Context, key string, opts storage.ListOptions) (watch.Interface, error) {
+	if err := s.ready(ctx); err != nil {
+		return nil, err
+	}
+
+	startRevision, err := s.versioner.ParseWatchResourceVersion(opts.ResourceVersion)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse resource version %s: %w", opts.ResourceVersion, err)
+	}
+
+	// Apply tenant/namespace prefix
+	key = s.buildKey(key)
+
+	if err := s.ensurePoller(ctx); err != nil {
+		return nil, err
+	}
+
+	// Create watch instance. Its queue is unbounded, so queueing the replay
+	// and the initial events never blocks on the consumer, which has not
+	// even received the watcher yet.
+	w := newQueuedWatch()
+
+	// Catch the poller up before registering so that replayed change-log
+	// entries and polled entries never overlap for the new watcher
+	s.pollMu.Lock()
+	s.pollChanges(ctx)
+	var replay []watch.Event
+	if startRevision > 0 {
+		replay = s.replayChanges(ctx, key, opts.Recursive, startRevision, s.lastRevision)
+	}
+
+	s.watchMu.Lock()
+	// Add to watchers. The replay is queued before the watcher can receive
+	// any newer event, which are sent while holding watchMu.
+	s.watchers[key] = append(s.watchers[key], w)
+	atomic.AddUint64(&s.metrics.watchers, 1)
+	for _, event := range replay {
+		w.Send(event.Type, event.Object)
+	}
+	s.watchMu.Unlock()
+	s.pollMu.Unlock()
+
+	// Start background cleanup when context is cancelled
+	go func() {
+		<-ctx.Done()
+		s.removeWatcher(key, w)
+		w.Stop()
+	}()
+
+	// Send initial events if requested
+	if opts.SendInitialEvents != nil && *opts.SendInitialEvents {
+		list := &metav1.List{}
+		if err := s.List(ctx, strings.TrimPrefix(key, s.buildKey("")), opts, list); err != nil {
+			log.Printf("Warning: failed to list objects for initial watch events: %v", err)
+		}
+		for _, item := range list.Items {
+			obj, err := k1sstorage.DecodeUnstructured(item.Raw)
+			if err != nil {
+				// Log error but continue with other objects
+				log.Printf("Warning: failed to unmarshal object for watch event: %v", err)
+				continue
+			}
+			w.Send(watch.Added, obj)
+		}
+	}
+
+	return w, nil
+}
+
+// Close closes the storage backend and cleans up resources
+func (s *sqliteStorage) Close() error {
+	s.closed.Store(true)
+
+	// Stop all watchers first so that a poller blocked in Send is released
+	s.watchMu.Lock()
+	for _, watchList := range s.watchers {
+		for _, w := range watchList {
+			w.Stop()
+		}
+	}
+	// Clear watchers
+	s.watchers = make(map[string][]*queuedWatch)
+	s.watchMu.Unlock()
+
+	// Stop the change-log poller. Wait without holding pollMu, the poller
+	// acquires it on every tick.
+	s.pollMu.Lock()
+	pollerStop, pollerDone := s.pollerStop, s.pollerDone
+	s.pollerStop = nil
+	s.pollMu.Unlock()
+	if pollerStop != nil {
+		close(pollerStop)
+		<-pollerDone
+	}
+
+	s.initMu.Lock()
+	defer s.initMu.Unlock()
+
+	// Close SQLite
+	if s.db != nil {
+		if err := s.db.Close(); err != nil {
+			return fmt.Errorf("failed to close sqlite database: %w", err)
+		}
+		s.db = nil
+	}
+
+	return nil
+}
+
+// Compact removes expired objects, trims the change-log to the most recent
+// entries and checkpoints the WAL into the main database file
+func (s *sqliteStorage) Compact(ctx context.Context) error {
+	if err := s.ready(ctx); err != nil {
+		return err
+	}
+
+	now := time.Now().Unix()
+	rows, err := s.db.QueryContext(ctx, `SELECT key FROM objects WHERE expires_at IS NOT NULL AND expires_at <= ?`, now)
+	if err != nil {
+		return fmt.Errorf("failed to query expired objects: %w", err)
+	}
+	var expired []string
+	for rows.Next() {
+		var key string
+		if err := rows.Scan(&key); err != nil {
+			_ = rows.Close()
+			return fmt.Errorf("failed to scan expired object: %w", err)
+		}
+		expired = append(expired, key)
+	}
+	if err := rows.Close(); err != nil {
+		log.Printf("Warning: %s rows: %v", errFailedToClose, err)
+	}
+
+	// Expire objects through the change-log so that watchers observe the deletion
+	for _, key := range expired {
+		if err := s.expire(ctx, key, now); err != nil {
+			return err
+		}
+	}
+
+	if _, err := s.db.ExecContext(ctx, `DELETE FROM changelog WHERE revision <= (SELECT MAX(revision) FROM changelog) - ?`,
+		changelogRetention); err != nil {
+		return fmt.Errorf("failed to compact change-log: %w", err)
+	}
+
+	if _, err := s.db.ExecContext(ctx, `PRAGMA wal_checkpoint(TRUNCATE)`); err != nil {
+		return fmt.Errorf("failed to compact database: %w", err)
+	}
+
+	return nil
+}
+
+// Count returns the number of objects stored under the given key prefix
+func (s *sqliteStorage) Count(ctx context.Context, key string) (int64, error) {
+	if err := s.ready(ctx); err != nil {
+		return 0, err
+	}
+
+	// Apply tenant/namespace prefix
+	key = s.buildKey(key)
+
+	var count int64
+	row := s.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM objects WHERE key >= ? AND key < ? AND `+notExpired,
+		key, prefixEnd(key), time.Now().Unix())
+	if err := row.Scan(&count); err != nil {
+		return 0, fmt.Errorf("failed to count objects: %w", err)
+	}
+
+	return count, nil
+}
+
+// GuaranteedUpdate implements storage.Interface. The read, tryUpdate and write
+// all run inside a single immediate transaction, so no other process can
+// modify the key in between and no conflict retry loop is required.
+func (s *sqliteStorage) GuaranteedUpdate(ctx context.Context, key string, destination runtime.Object, ignoreNotFound bool,
+	preconditions *storage.Preconditions, tryUpdate storage.UpdateFunc, cachedExistingObject runtime.Object) error {
+	if err := s.ready(ctx); err != nil {
+		return err
+	}
+
+	// Apply tenant/namespace prefix
+	key = s.buildKey(key)
+
+	var updated runtime.Object
+	var updatedData []byte
+	exists := true
+	revision, err := s.write(ctx, key, watch.Modified, func(tx *sql.Tx, revision uint64) error {
+		data, storedVersion, found, err := s.getRow(ctx, tx, key)
+		if err != nil {
+			return err
+		}
+
+		// Get current object
+		current := destination.DeepCopyObject()
+		if !found {
+			exists = false
+			if !ignoreNotFound {
+				// Use Kubernetes standard error type for not found
+				gr := schema.GroupResource{Resource: "objects"} // Generic resource for storage
+				return apierrors.NewNotFound(gr, key)
+			}
+			if err := s.setEventType(ctx, tx, revision, watch.Added); err != nil {
+				return err
+			}
+
+			// Creating the object counts towards the tenant's object quota
+			if err := s.checkQuota(ctx, tx, key); err != nil {
+				return err
+			}
+		} else if err := json.Unmarshal(data, current); err != nil {
+			return fmt.Errorf("failed to deserialize current object: %w", err)
+		}
+
+		// Check preconditions if provided
+		if found {
+			if err := k1sstorage.CheckPreconditions(key, current, preconditions); err != nil {
+				return err
+			}
+		}
+
+		// Try the update
+		var ttl *uint64
+		updated, ttl, err = tryUpdate(current, storage.ResponseMeta{ResourceVersion: storedVersion})
+		if err != nil {
+			return err
+		}
+
+		if err := s.versioner.UpdateObject(updated, revision); err != nil {
+			return fmt.Errorf("failed to update resource version: %w", err)
+		}
+
+		// Serialize updated object
+		updatedData, err = json.Marshal(updated)
+		if err != nil {
+			return fmt.Errorf("failed to serialize updated object: %w", err)
+		}
+
+		var expires sql.NullInt64
+		if ttl != nil {
+			expires = expiresAt(*ttl)
+		}
+		return s.putRow(ctx, tx, key, updatedData, revision, expires)
+	})
+	if err != nil {
+		atomic.AddUint64(&s.metrics.errors, 1)
+		return err
+	}
+
+	// Copy to destination
+	if err := json.Unmarshal(updatedData, destination); err != nil {
+		atomic.AddUint64(&s.metrics.errors, 1)
+		return fmt.Errorf("failed to copy to destination: %w", err)
+	}
+
+	// Notify watchers
+	eventType := watch.Modified
+	if !exists {
+		eventType = watch.Added
+	}
+	s.notifyLocal(revision, key, eventType, k1sstorage.WatchObject(updatedData, updated))
+
+	atomic.AddUint64(&s.metrics.operations, 1)
+	return nil
+}
+
+// RequestWatchProgress implements storage.Interface
+func (s *sqliteStorage) RequestWatchProgress(ctx context.Context) error {
+	// Trigger an immediate change-log poll so that watchers observe every
+	// revision committed so far, including those from other processes
+	if s.closed.Load() || s.db == nil {
+		return nil
+	}
+	s.pollMu.Lock()
+	defer s.pollMu.Unlock()
+	if s.pollerStop != nil {
+		s.pollChanges(ctx)
+	}
+	return nil
+}
+
+// RequestProgress implements storage.Interface
+func (s *sqliteStorage) RequestProgress(ctx context.Context) error {
+	// SQLite provides serializable transactions, so every committed write is
+	// immediately visible to subsequent reads from any process
+	return nil
+}
+
+// GetMetrics returns current performance metrics
+func (s *sqliteStorage) GetMetrics() (operations, errors, watchers uint64) {
+	return atomic.LoadUint64(&s.metrics.operations),
+		atomic.LoadUint64(&s.metrics.errors),
+		atomic.LoadUint64(&s.metrics.watchers)
+}
+
+// Path returns the location of the database file
+func (s *sqliteStorage) Path() string {
+	return s.path
+}
+
+// notExpired is the SQL condition that filters out objects whose TTL has elapsed
+const notExpired = `(expires_at IS NULL OR expires_at > ?)`
+
+// queryer is implemented by both *sql.DB and *sql.Tx
+type queryer interface {
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
+// getRow reads the stored value and resource version for a key, ignoring expired objects
+func (s *sqliteStorage) getRow(ctx context.Context, q queryer, key string) ([]byte, uint64, bool, error) {
+	var data []byte
+	var resourceVersion uint64
+	row := q.QueryRowContext(ctx, `SELECT value, resource_version FROM objects WHERE key = ? AND `+notExpired,
+		key, time.Now().Unix())
+	if err := row.Scan(&data, &resourceVersion); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, 0, false, nil
+		}
+		return nil, 0, false, fmt.Errorf("failed to get key: %w", err)
+	}
+	return data, resourceVersion, true, nil
+}
+
+// putRow inserts or replaces the stored value for a key and records it in the change-log
+func (s *sqliteStorage) putRow(ctx context.Context, tx *sql.Tx, key string, data []byte, revision uint64, expires sql.NullInt64) error {
+	if _, err := tx.ExecContext(ctx,
+		`INSERT OR REPLACE INTO objects (key, value, resource_version, expires_at) VALUES (?, ?, ?, ?)`,
+		key, data, revision, expires); err != nil {
+		return fmt.Errorf("failed to set key: %w", err)
+	}
+	return s.setChangeValue(ctx, tx, revision, data)
+}
+
+// setChangeValue stores the object payload of a change-log entry
+func (s *sqliteStorage) setChangeValue(ctx context.Context, tx *sql.Tx, revision uint64, data []byte) error {
+	if _, err := tx.ExecContext(ctx, `UPDATE changelog SET value = ? WHERE revision = ?`, data, revision); err != nil {
+		return fmt.Errorf("failed to update change-log: %w", err)
+	}
+	return nil
+}
+
+// setEventType changes the event type of a change-log entry
+func (s *sqliteStorage) setEventType(ctx context.Context, tx *sql.Tx, revision uint64, eventType watch.EventType) error {
+	if _, err := tx.ExecContext(ctx, `UPDATE changelog SET event_type = ? WHERE revision = ?`, string(eventType), revision); err != nil {
+		return fmt.Errorf("failed to update change-log: %w", err)
+	}
+	return nil
+}
+
+// write runs fn inside an immediate transaction after allocating the next
+// revision from the change-log. The revision is registered as local before
+// commit so that the poller never delivers this process' own writes twice.
+func (s *sqliteStorage) write(ctx context.Context, key string, eventType watch.EventType,
+	fn func(tx *sql.Tx, revision uint64) error) (uint64, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+
+	revision, err := s.appendChange(ctx, tx, key, eventType)
+	if err != nil {
+		s.rollback(tx)
+		return 0, err
+	}
+
+	if err := fn(tx, revision); err != nil {
+		s.rollback(tx)
+		return 0, err
+	}
+
+	s.localMu.Lock()
+	s.localRevisions[revision] = struct{}{}
+	s.localMu.Unlock()
+
+	if err := tx.Commit(); err != nil {
+		s.localMu.Lock()
+		delete(s.localRevisions, revision)
+		s.localMu.Unlock()
+		return 0, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return revision, nil
+}
+
+// appendChange allocates a new revision by appending an entry to the change-log
+func (s *sqliteStorage) appendChange(ctx context.Context, tx *sql.Tx, key string, eventType watch.EventType) (uint64, error) {
+	result, err := tx.ExecContext(ctx, `INSERT INTO changelog (key, event_type) VALUES (?, ?)`, key, string(eventType))
+	if err != nil {
+		return 0, fmt.Errorf("failed to append to change-log: %w", err)
+	}
+	revision, err := result.LastInsertId()
+	if err != nil {
+		return 0, fmt.Errorf("failed to read change-log revision: %w", err)
+	}
+	return uint64(revision), nil
+}
+
+// rollback aborts a transaction, logging failures
+func (s *sqliteStorage) rollback(tx *sql.Tx) {
+	if err := tx.Rollback(); err != nil && !errors.Is(err, sql.ErrTxDone) {
+		log.Printf("Warning: failed to rollback transaction: %v", err)
+	}
+}
+
+// expire deletes an object whose TTL has elapsed and records the deletion
+func (s *sqliteStorage) expire(ctx context.Context, key string, now int64) error {
+	var data []byte
+	revision, err := s.write(ctx, key, watch.Deleted, func(tx *sql.Tx, revision uint64) error {
+		row := tx.QueryRowContext(ctx, `SELECT value FROM objects WHERE key = ? AND expires_at IS NOT NULL AND expires_at <= ?`, key, now)
+		if err := row.Scan(&data); err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				// Already removed or renewed by someone else
+				return nil
+			}
+			return fmt.Errorf("failed to get expired key: %w", err)
+		}
+		if _, err := tx.ExecContext(ctx, `DELETE FROM objects WHERE key = ?`, key); err != nil {
+			return fmt.Errorf("failed to delete expired key: %w", err)
+		}
+		return s.setChangeValue(ctx, tx, revision, data)
+	})
+	if err != nil {
+		return err
+	}
+
+	if data != nil {
+		if obj, err := k1sstorage.DecodeUnstructured(data); err == nil {
+			s.notifyLocal(revision, key, watch.Deleted, obj)
+		}
+	}
+	return nil
+}
+
+// ensurePoller starts the change-log poller if it is not already running
+func (s *sqliteStorage) ensurePoller(ctx context.Context) error {
+	s.pollMu.Lock()
+	defer s.pollMu.Unlock()
+
+	if s.pollerStop != nil {
+		return nil
+	}
+
+	var lastRevision sql.NullInt64
+	if err := s.db.QueryRowContext(ctx, `SELECT MAX(revision) FROM changelog`).Scan(&lastRevision); err != nil {
+		return fmt.Errorf("failed to read change-log revision: %w", err)
+	}
+	s.lastRevision = uint64(lastRevision.Int64)
+
+	s.pollerStop = make(chan struct{})
+	s.pollerDone = make(chan struct{})
+	go s.runPoller(s.pollerStop, s.pollerDone)
+	return nil
+}
+
+// runPoller periodically delivers change-log entries written by other processes
+func (s *sqliteStorage) runPoller(stopCh <-chan struct{}, doneCh chan<- struct{}) {
+	defer close(doneCh)
+
+	ticker := time.NewTicker(watchPollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stopCh:
+			return
+		case <-ticker.C:
+			s.pollMu.Lock()
+			s.pollChanges(context.Background())
+			s.pollMu.Unlock()
+		}
+	}
+}
+
+// pollChanges delivers all change-log entries after lastRevision. Callers must hold pollMu.
+func (s *sqliteStorage) pollChanges(ctx context.Context) {
+	changes, err := s.readChanges(ctx, "", true, s.lastRevision, 0)
+	if err != nil {
+		log.Printf("Warning: failed to poll change-log: %v", err)
+		return
+	}
+
+	for _, change := range changes {
+		s.lastRevision = change.revision
+
+		// Skip writes from this process, they were already delivered
+		s.localMu.Lock()
+		_, local := s.localRevisions[change.revision]
+		delete(s.localRevisions, change.revision)
+		s.localMu.Unlock()
+		if local {
+			continue
+		}
+
+		obj, err := k1sstorage.DecodeUnstructured(change.value)
+		if err != nil {
+			log.Printf("Warning: failed to unmarshal object for watch event: %v", err)
+			continue
+		}
+		s.notifyWatchers(change.key, change.eventType, obj)
+	}
+}
+
+// replayChanges returns the watch events of the change-log entries in (from, until] matching key
+func (s *sqliteStorage) replayChanges(ctx context.Context, key string, recursive bool, from, until uint64) []watch.Event {
+	changes, err := s.readChanges(ctx, key, recursive, from, until)
+	if err != nil {
+		log.Printf("Warning: failed to replay change-log: %v", err)
+		return nil
+	}
+
+	events := make([]watch.Event, 0, len(changes))
+	for _, change := range changes {
+		obj, err := k1sstorage.DecodeUnstructured(change.value)
+		if err != nil {
+			log.Printf("Warning: failed to unmarshal object for watch event: %v", err)
+			continue
+		}
+		events = append(events, watch.Event{Type: change.eventType, Object: obj})
+	}
+	return events
+}
+
+// change is a single change-log entry
+type change struct {
+	revision  uint64
+	key       string
+	eventType watch.EventType
+	value     []byte
+}
+
+// readChanges reads change-log entries after revision from, up to until (0 = no upper bound)
+func (s *sqliteStorage) readChanges(ctx context.Context, key string, recursive bool, from, until uint64) ([]change, error) {
+	query := `SELECT revision, key, event_type, value FROM changelog WHERE revision > ? AND value IS NOT NULL`
+	args := []interface{}{from}
+	if until > 0 {
+		query += ` AND revision <= ?`
+		args = append(args, until)
+	}
+	if key != "" {
+		if recursive {
+			query += ` AND key >= ? AND key < ?`
+			args = append(args, key, prefixEnd(key))
+		} else {
+			query += ` AND key = ?`
+			args = append(args, key)
+		}
+	}
+	query += ` ORDER BY revision`
+
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query change-log: %w", err)
+	}
+	defer func() {
+		if err := rows.Close(); err != nil {
+			log.Printf("Warning: %s rows: %v", errFailedToClose, err)
+		}
+	}()
+
+	var changes []change
+	for rows.Next() {
+		var c change
+		var eventType string
+		if err := rows.Scan(&c.revision, &c.key, &eventType, &c.value); err != nil {
+			return nil, fmt.Errorf("failed to scan change-log entry: %w", err)
+		}
+		c.eventType = watch.EventType(eventType)
+		changes = append(changes, c)
+	}
+	return changes, rows.Err()
+}
+
+// notifyLocal delivers a watch event for a write made by this process
+func (s *sqliteStorage) notifyLocal(revision uint64, key string, eventType watch.EventType, obj runtime.Object) {
+	// If no poller is running the revision will never be polled; forget it
+	// right away so that localRevisions does not grow without bound
+	s.pollMu.Lock()
+	if s.pollerStop == nil {
+		s.localMu.Lock()
+		delete(s.localRevisions, revision)
+		s.localMu.Unlock()
+	}
+	s.pollMu.Unlock()
+
+	s.notifyWatchers(key, eventType, obj)
+}
+
+// buildKey constructs the final storage key with tenant/namespace prefixes
+func (s *sqliteStorage) buildKey(key string) string {
+	parts := []string{}
+
+	// Add tenant prefix if configured
+	if s.config.TenantID != "" {
+		parts = append(parts, k1sstorage.TenantKey(s.config.TenantID))
+	}
+
+	// Add custom key prefix if configured
+	if s.config.KeyPrefix != "" {
+		parts = append(parts, s.config.KeyPrefix)
+	}
+
+	// Add namespace prefix if configured
+	if s.config.Namespace != "" {
+		parts = append(parts, "namespaces", s.config.Namespace)
+	}
+
+	// Add the actual key
+	parts = append(parts, key)
+
+	return strings.Join(parts, "/")
+}
+
+// notifyWatchers sends watch events to all registered watchers
+func (s *sqliteStorage) notifyWatchers(key string, eventType watch.EventType, obj runtime.Object) {
+	s.watchMu.RLock()
+	defer s.watchMu.RUnlock()
+
+	// Notify direct key watchers
+	if watchers, exists := s.watchers[key]; exists {
+		for _, w := range watchers {
+			w.Send(eventType, obj)
+		}
+	}
+
+	// Notify prefix watchers
+	for watchKey, watchers := range s.watchers {
+		if watchKey != key && strings.HasPrefix(key, watchKey) {
+			for _, w := range watchers {
+				w.Send(eventType, obj)
+			}
+		}
+	}
+}
+
+// removeWatcher removes a watcher from the watchers map
+func (s *sqliteStorage) removeWatcher(key string, watcher *queuedWatch) {
+	s.watchMu.Lock()
+	defer s.watchMu.Unlock()
+
+	if watchers, exists := s.watchers[key]; exists {
+		for i, w := range watchers {
+			if w == watcher {
+				// Remove watcher from slice
+				s.watchers[key] = append(watchers[:i], watchers[i+1:]...)
+				atomic.AddUint64(&s.metrics.watchers, ^uint64(0)) // atomic decrement
+				break
+			}
+		}
+
+		// Clean up empty watcher lists
+		if len(s.watchers[key]) == 0 {
+			delete(s.watchers, key)
+		}
+	}
+}
+
+// expiresAt converts a TTL in seconds into an absolute unix expiry time
+func expiresAt(ttl uint64) sql.NullInt64 {
+	if ttl == 0 {
+		return sql.NullInt64{}
+	}
+	return sql.NullInt64{Int64: time.Now().Unix() + int64(ttl), Valid: true}
+}
+
+// prefixEnd returns the exclusive upper bound for a key prefix range scan
+func prefixEnd(prefix string) string {
+	return prefix + "\xFF"
+}
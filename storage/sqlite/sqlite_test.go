@@ -0,0 +1,518 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/watch"
+	k8storage "k8s.io/apiserver/pkg/storage"
+
+	k1sstorage "github.com/dtomasi/k1s/core/storage"
+)
+
+// Test constants to avoid goconst violations
+const (
+	testAPIVersion      = "test/v1"
+	testObjectKind      = "TestObject"
+	testNamespace       = "default"
+	testObjectName      = "test-object"
+	testObjects         = "test-objects"
+	testObjectsTestName = "test-objects/test-object"
+	testObjectsNonExist = "test-objects/non-existent"
+)
+
+// TestObject is a simple test object for storage tests
+type TestObject struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec TestSpec `json:"spec,omitempty"`
+}
+
+type TestSpec struct {
+	Name string `json:"name"`
+}
+
+// DeepCopyObject implements runtime.Object
+func (t *TestObject) DeepCopyObject() runtime.Object {
+	if t == nil {
+		return nil
+	}
+	return &TestObject{
+		TypeMeta:   t.TypeMeta,
+		ObjectMeta: *t.DeepCopy(),
+		Spec:       t.Spec,
+	}
+}
+
+// TestObjectList represents a list of test objects
+type TestObjectList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+
+	Items []TestObject `json:"items"`
+}
+
+// DeepCopyObject implements runtime.Object
+func (t *TestObjectList) DeepCopyObject() runtime.Object {
+	if t == nil {
+		return nil
+	}
+	out := &TestObjectList{
+		TypeMeta: t.TypeMeta,
+		ListMeta: *t.DeepCopy(),
+	}
+	if t.Items != nil {
+		out.Items = make([]TestObject, len(t.Items))
+		for i := range t.Items {
+			out.Items[i] = *t.Items[i].DeepCopyObject().(*TestObject)
+		}
+	}
+	return out
+}
+
+func newTestObject(name string) *TestObject {
+	return &TestObject{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: testAPIVersion,
+			Kind:       testObjectKind,
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: testNamespace,
+		},
+		Spec: TestSpec{Name: "Test Object"},
+	}
+}
+
+// expectEvent waits for the next event on ch. SimpleWatch.ResultChan starts a
+// new forwarder on every call, so tests must read from a single channel.
+func expectEvent(ch <-chan watch.Event, eventType watch.EventType) watch.Event {
+	var event watch.Event
+	Eventually(ch, 5*time.Second).Should(Receive(&event))
+	Expect(event.Type).To(Equal(eventType))
+	return event
+}
+
+var _ = Describe("SQLiteStorage", func() {
+	var (
+		storage k1sstorage.Backend
+		ctx     context.Context
+		cancel  context.CancelFunc
+		dbPath  string
+	)
+
+	BeforeEach(func() {
+		ctx, cancel = context.WithTimeout(context.Background(), 30*time.Second)
+
+		dbPath = filepath.Join(GinkgoT().TempDir(), "k1s.sqlite")
+		storage = NewSQLiteStorageWithPath(dbPath, k1sstorage.Config{})
+		Expect(storage).NotTo(BeNil())
+		Expect(storage.Name()).To(Equal("sqlite"))
+	})
+
+	AfterEach(func() {
+		Expect(storage.Close()).To(Succeed())
+		cancel()
+	})
+
+	Describe("Basic Operations", func() {
+		It("should create and get an object", func() {
+			out := &TestObject{}
+			Expect(storage.Create(ctx, testObjectsTestName, newTestObject(testObjectName), out, 0)).To(Succeed())
+			Expect(out.Name).To(Equal(testObjectName))
+			Expect(out.ResourceVersion).NotTo(BeEmpty())
+
+			retrieved := &TestObject{}
+			Expect(storage.Get(ctx, testObjectsTestName, k8storage.GetOptions{}, retrieved)).To(Succeed())
+			Expect(retrieved.Spec.Name).To(Equal("Test Object"))
+			Expect(retrieved.ResourceVersion).To(Equal(out.ResourceVersion))
+		})
+
+		It("should create the database file and parent directories", func() {
+			nested := filepath.Join(filepath.Dir(dbPath), "nested", "dir", "k1s.sqlite")
+			s := NewSQLiteStorageWithPath(nested, k1sstorage.Config{})
+			defer func() { Expect(s.Close()).To(Succeed()) }()
+
+			Expect(s.Create(ctx, testObjectsTestName, newTestObject(testObjectName), nil, 0)).To(Succeed())
+			_, err := os.Stat(nested)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("should fail to create duplicate objects", func() {
+			Expect(storage.Create(ctx, testObjectsTestName, newTestObject(testObjectName), nil, 0)).To(Succeed())
+
+			err := storage.Create(ctx, testObjectsTestName, newTestObject(testObjectName), nil, 0)
+			Expect(apierrors.IsAlreadyExists(err)).To(BeTrue())
+		})
+
+		It("should return not found for missing objects", func() {
+			err := storage.Get(ctx, testObjectsNonExist, k8storage.GetOptions{}, &TestObject{})
+			Expect(apierrors.IsNotFound(err)).To(BeTrue())
+
+			Expect(storage.Get(ctx, testObjectsNonExist, k8storage.GetOptions{IgnoreNotFound: true}, &TestObject{})).To(Succeed())
+
+			err = storage.Delete(ctx, testObjectsNonExist, &TestObject{}, nil, nil, nil)
+			Expect(apierrors.IsNotFound(err)).To(BeTrue())
+		})
+
+		It("should delete an object and return its last state", func() {
+			Expect(storage.Create(ctx, testObjectsTestName, newTestObject(testObjectName), nil, 0)).To(Succeed())
+
+			out := &TestObject{}
+			Expect(storage.Delete(ctx, testObjectsTestName, out, nil, nil, nil)).To(Succeed())
+			Expect(out.Name).To(Equal(testObjectName))
+
+			err := storage.Get(ctx, testObjectsTestName, k8storage.GetOptions{}, &TestObject{})
+			Expect(apierrors.IsNotFound(err)).To(BeTrue())
+		})
+
+		It("should assign increasing resource versions", func() {
+			first := &TestObject{}
+			Expect(storage.Create(ctx, "test-objects/a", newTestObject("a"), first, 0)).To(Succeed())
+			second := &TestObject{}
+			Expect(storage.Create(ctx, "test-objects/b", newTestObject("b"), second, 0)).To(Succeed())
+
+			v1, err := storage.Versioner().ObjectResourceVersion(first)
+			Expect(err).NotTo(HaveOccurred())
+			v2, err := storage.Versioner().ObjectResourceVersion(second)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(v2).To(BeNumerically(">", v1))
+		})
+	})
+
+	Describe("List Operations", func() {
+		BeforeEach(func() {
+			for i := 0; i < 5; i++ {
+				key := fmt.Sprintf("test-objects/test-object-%d", i)
+				Expect(storage.Create(ctx, key, newTestObject(fmt.Sprintf("test-object-%d", i)), nil, 0)).To(Succeed())
+			}
+			Expect(storage.Create(ctx, "other-objects/other", newTestObject("other"), nil, 0)).To(Succeed())
+		})
+
+		It("should decode items into typed lists ordered by key", func() {
+			list := &TestObjectList{}
+			Expect(storage.List(ctx, testObjects, k8storage.ListOptions{Recursive: true}, list)).To(Succeed())
+			Expect(list.Items).To(HaveLen(5))
+			Expect(list.Items[0].Name).To(Equal("test-object-0"))
+			Expect(list.Items[4].Name).To(Equal("test-object-4"))
+			Expect(list.ResourceVersion).NotTo(BeEmpty())
+		})
+
+		It("should support generic lists", func() {
+			list := &metav1.List{}
+			Expect(storage.List(ctx, testObjects, k8storage.ListOptions{Recursive: true}, list)).To(Succeed())
+			Expect(list.Items).To(HaveLen(5))
+		})
+
+		It("should count objects under a prefix", func() {
+			count, err := storage.Count(ctx, testObjects)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(count).To(Equal(int64(5)))
+
+			count, err = storage.Count(ctx, "non-existent")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(count).To(BeZero())
+		})
+	})
+
+	Describe("GuaranteedUpdate", func() {
+		It("should update an existing object", func() {
+			Expect(storage.Create(ctx, testObjectsTestName, newTestObject(testObjectName), nil, 0)).To(Succeed())
+
+			destination := &TestObject{}
+			err := storage.GuaranteedUpdate(ctx, testObjectsTestName, destination, false, nil,
+				func(input runtime.Object, _ k8storage.ResponseMeta) (runtime.Object, *uint64, error) {
+					obj := input.(*TestObject)
+					obj.Spec.Name = "updated-name"
+					return obj, nil, nil
+				}, nil)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(destination.Spec.Name).To(Equal("updated-name"))
+
+			retrieved := &TestObject{}
+			Expect(storage.Get(ctx, testObjectsTestName, k8storage.GetOptions{}, retrieved)).To(Succeed())
+			Expect(retrieved.Spec.Name).To(Equal("updated-name"))
+		})
+
+		It("should return a conflict when preconditions fail", func() {
+			Expect(storage.Create(ctx, testObjectsTestName, newTestObject(testObjectName), nil, 0)).To(Succeed())
+
+			uid := types.UID("wrong-uid")
+			err := storage.GuaranteedUpdate(ctx, testObjectsTestName, &TestObject{}, false,
+				&k8storage.Preconditions{UID: &uid},
+				func(input runtime.Object, _ k8storage.ResponseMeta) (runtime.Object, *uint64, error) {
+					return input, nil, nil
+				}, nil)
+			Expect(apierrors.IsConflict(err)).To(BeTrue())
+
+			err = storage.Delete(ctx, testObjectsTestName, nil, &k8storage.Preconditions{UID: &uid}, nil, nil)
+			Expect(apierrors.IsConflict(err)).To(BeTrue())
+		})
+
+		It("should serialize concurrent updates", func() {
+			Expect(storage.Create(ctx, testObjectsTestName, newTestObject(testObjectName), nil, 0)).To(Succeed())
+
+			var wg sync.WaitGroup
+			for i := 0; i < 10; i++ {
+				wg.Add(1)
+				go func() {
+					defer GinkgoRecover()
+					defer wg.Done()
+					err := storage.GuaranteedUpdate(ctx, testObjectsTestName, &TestObject{}, false, nil,
+						func(input runtime.Object, _ k8storage.ResponseMeta) (runtime.Object, *uint64, error) {
+							obj := input.(*TestObject)
+							obj.Spec.Name += "+"
+							return obj, nil, nil
+						}, nil)
+					Expect(err).NotTo(HaveOccurred())
+				}()
+			}
+			wg.Wait()
+
+			retrieved := &TestObject{}
+			Expect(storage.Get(ctx, testObjectsTestName, k8storage.GetOptions{}, retrieved)).To(Succeed())
+			Expect(retrieved.Spec.Name).To(Equal("Test Object++++++++++"))
+		})
+	})
+
+	Describe("Watch Operations", func() {
+		It("should deliver local writes", func() {
+			w, err := storage.Watch(ctx, testObjects, k8storage.ListOptions{Recursive: true})
+			Expect(err).NotTo(HaveOccurred())
+			defer w.Stop()
+			events := w.ResultChan()
+
+			Expect(storage.Create(ctx, testObjectsTestName, newTestObject(testObjectName), nil, 0)).To(Succeed())
+			event := expectEvent(events, watch.Added)
+			Expect(event.Object).To(BeAssignableToTypeOf(&TestObject{}))
+
+			Expect(storage.Delete(ctx, testObjectsTestName, nil, nil, nil, nil)).To(Succeed())
+			expectEvent(events, watch.Deleted)
+
+			// Local writes must not be delivered a second time by the poller
+			Consistently(events, 3*watchPollInterval).ShouldNot(Receive())
+		})
+
+		It("should deliver writes from another process sharing the database", func() {
+			w, err := storage.Watch(ctx, testObjects, k8storage.ListOptions{Recursive: true})
+			Expect(err).NotTo(HaveOccurred())
+			defer w.Stop()
+			events := w.ResultChan()
+
+			other := NewSQLiteStorageWithPath(dbPath, k1sstorage.Config{})
+			defer func() { Expect(other.Close()).To(Succeed()) }()
+
+			Expect(other.Create(ctx, testObjectsTestName, newTestObject(testObjectName), nil, 0)).To(Succeed())
+			event := expectEvent(events, watch.Added)
+			obj, ok := event.Object.(*unstructured.Unstructured)
+			Expect(ok).To(BeTrue())
+			Expect(obj.GetName()).To(Equal(testObjectName))
+
+			// Writes outside the watched prefix are not delivered
+			Expect(other.Create(ctx, "other-objects/other", newTestObject("other"), nil, 0)).To(Succeed())
+			Consistently(events, 3*watchPollInterval).ShouldNot(Receive())
+		})
+
+		It("should replay changes since the requested resource version", func() {
+			first := &TestObject{}
+			Expect(storage.Create(ctx, "test-objects/a", newTestObject("a"), first, 0)).To(Succeed())
+			Expect(storage.Create(ctx, "test-objects/b", newTestObject("b"), nil, 0)).To(Succeed())
+			Expect(storage.Delete(ctx, "test-objects/a", nil, nil, nil, nil)).To(Succeed())
+
+			w, err := storage.Watch(ctx, testObjects, k8storage.ListOptions{
+				Recursive:       true,
+				ResourceVersion: first.ResourceVersion,
+			})
+			Expect(err).NotTo(HaveOccurred())
+			defer w.Stop()
+			events := w.ResultChan()
+
+			event := expectEvent(events, watch.Added)
+			Expect(event.Object.(*unstructured.Unstructured).GetName()).To(Equal("b"))
+			event = expectEvent(events, watch.Deleted)
+			Expect(event.Object.(*unstructured.Unstructured).GetName()).To(Equal("a"))
+		})
+
+		It("should not block on more events than the watch buffer holds", func() {
+			const writes = 150
+			createObjects := func(prefix string) *TestObject {
+				first := &TestObject{}
+				for i := 0; i < writes; i++ {
+					name := fmt.Sprintf("%s-%d", prefix, i)
+					out := &TestObject{}
+					Expect(storage.Create(ctx, "test-objects/"+name, newTestObject(name), out, 0)).To(Succeed())
+					if i == 0 {
+						first = out
+					}
+				}
+				return first
+			}
+			first := createObjects("before")
+
+			sendInitial := true
+			initial, err := storage.Watch(ctx, testObjects, k8storage.ListOptions{
+				Recursive:         true,
+				SendInitialEvents: &sendInitial,
+			})
+			Expect(err).NotTo(HaveOccurred())
+			defer initial.Stop()
+
+			// Replays all writes but the first
+			replay, err := storage.Watch(ctx, testObjects, k8storage.ListOptions{
+				Recursive:       true,
+				ResourceVersion: first.ResourceVersion,
+			})
+			Expect(err).NotTo(HaveOccurred())
+			defer replay.Stop()
+
+			// Writes must not wait for the watchers to be read
+			createObjects("after")
+
+			for w, expected := range map[watch.Interface]int{initial: 2 * writes, replay: 2*writes - 1} {
+				events := w.ResultChan()
+				for i := 0; i < expected; i++ {
+					expectEvent(events, watch.Added)
+				}
+				Consistently(events, 3*watchPollInterval).ShouldNot(Receive())
+			}
+		})
+
+		It("should send initial events when requested", func() {
+			Expect(storage.Create(ctx, testObjectsTestName, newTestObject(testObjectName), nil, 0)).To(Succeed())
+
+			sendInitial := true
+			w, err := storage.Watch(ctx, testObjects, k8storage.ListOptions{
+				Recursive:         true,
+				SendInitialEvents: &sendInitial,
+			})
+			Expect(err).NotTo(HaveOccurred())
+			defer w.Stop()
+			events := w.ResultChan()
+
+			expectEvent(events, watch.Added)
+		})
+	})
+
+	Describe("Multi-process Access", func() {
+		It("should share objects and revisions between processes", func() {
+			other := NewSQLiteStorageWithPath(dbPath, k1sstorage.Config{})
+			defer func() { Expect(other.Close()).To(Succeed()) }()
+
+			first := &TestObject{}
+			Expect(storage.Create(ctx, "test-objects/a", newTestObject("a"), first, 0)).To(Succeed())
+			second := &TestObject{}
+			Expect(other.Create(ctx, "test-objects/b", newTestObject("b"), second, 0)).To(Succeed())
+
+			v1, err := storage.Versioner().ObjectResourceVersion(first)
+			Expect(err).NotTo(HaveOccurred())
+			v2, err := other.Versioner().ObjectResourceVersion(second)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(v2).To(BeNumerically(">", v1))
+
+			count, err := storage.Count(ctx, testObjects)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(count).To(Equal(int64(2)))
+		})
+
+		It("should handle concurrent writers without lock errors", func() {
+			other := NewSQLiteStorageWithPath(dbPath, k1sstorage.Config{})
+			defer func() { Expect(other.Close()).To(Succeed()) }()
+
+			var wg sync.WaitGroup
+			for i, backend := range []k1sstorage.Backend{storage, other} {
+				wg.Add(1)
+				go func(i int, backend k1sstorage.Backend) {
+					defer GinkgoRecover()
+					defer wg.Done()
+					for j := 0; j < 20; j++ {
+						key := fmt.Sprintf("test-objects/%d-%d", i, j)
+						Expect(backend.Create(ctx, key, newTestObject(key), nil, 0)).To(Succeed())
+					}
+				}(i, backend)
+			}
+			wg.Wait()
+
+			count, err := storage.Count(ctx, testObjects)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(count).To(Equal(int64(40)))
+		})
+	})
+
+	Describe("TTL and Compaction", func() {
+		It("should expire objects and remove them on compaction", func() {
+			Expect(storage.Create(ctx, testObjectsTestName, newTestObject(testObjectName), nil, 1)).To(Succeed())
+			Expect(storage.Get(ctx, testObjectsTestName, k8storage.GetOptions{}, &TestObject{})).To(Succeed())
+
+			w, err := storage.Watch(ctx, testObjects, k8storage.ListOptions{Recursive: true})
+			Expect(err).NotTo(HaveOccurred())
+			defer w.Stop()
+			events := w.ResultChan()
+
+			Eventually(func() bool {
+				err := storage.Get(ctx, testObjectsTestName, k8storage.GetOptions{}, &TestObject{})
+				return apierrors.IsNotFound(err)
+			}, 3*time.Second, 100*time.Millisecond).Should(BeTrue())
+
+			// An expired key can be created again
+			Expect(storage.Compact(ctx)).To(Succeed())
+			expectEvent(events, watch.Deleted)
+			Expect(storage.Create(ctx, testObjectsTestName, newTestObject(testObjectName), nil, 0)).To(Succeed())
+		})
+	})
+
+	Describe("Multi-tenancy Support", func() {
+		It("should isolate data between tenants", func() {
+			tenantA := NewSQLiteStorageWithPath(dbPath, k1sstorage.Config{TenantID: "tenant-a"})
+			defer func() { Expect(tenantA.Close()).To(Succeed()) }()
+			tenantB := NewSQLiteStorageWithPath(dbPath, k1sstorage.Config{TenantID: "tenant-b"})
+			defer func() { Expect(tenantB.Close()).To(Succeed()) }()
+
+			Expect(tenantA.Create(ctx, testObjectsTestName, newTestObject(testObjectName), nil, 0)).To(Succeed())
+
+			err := tenantB.Get(ctx, testObjectsTestName, k8storage.GetOptions{}, &TestObject{})
+			Expect(apierrors.IsNotFound(err)).To(BeTrue())
+			Expect(tenantB.Create(ctx, testObjectsTestName, newTestObject(testObjectName), nil, 0)).To(Succeed())
+		})
+	})
+
+	Describe("Error Conditions", func() {
+		It("should reject operations on closed storage", func() {
+			Expect(storage.Close()).To(Succeed())
+
+			err := storage.Create(ctx, testObjectsTestName, newTestObject(testObjectName), nil, 0)
+			Expect(err).To(MatchError(errStorageIsClosed))
+			_, err = storage.Count(ctx, testObjects)
+			Expect(err).To(MatchError(errStorageIsClosed))
+		})
+
+		It("should handle cancelled context", func() {
+			cancelledCtx, cancelFn := context.WithCancel(context.Background())
+			cancelFn()
+
+			err := storage.Create(cancelledCtx, testObjectsTestName, newTestObject(testObjectName), nil, 0)
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("should track metrics", func() {
+			Expect(storage.Create(ctx, testObjectsTestName, newTestObject(testObjectName), nil, 0)).To(Succeed())
+			_ = storage.Get(ctx, testObjectsNonExist, k8storage.GetOptions{}, &TestObject{})
+
+			operations, errs, _ := storage.(*sqliteStorage).GetMetrics()
+			Expect(operations).To(Equal(uint64(1)))
+			Expect(errs).To(Equal(uint64(1)))
+		})
+	})
+})
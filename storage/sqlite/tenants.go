@@ -0,0 +1,302 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"sync/atomic"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/watch"
+
+	k1sstorage "github.com/dtomasi/k1s/core/storage"
+)
+
+var _ k1sstorage.TenantManager = &sqliteStorage{}
+
+// CreateTenant registers a tenant
+func (s *sqliteStorage) CreateTenant(ctx context.Context, tenant k1sstorage.Tenant) error {
+	if err := s.ready(ctx); err != nil {
+		return err
+	}
+
+	if err := k1sstorage.ValidateTenantID(tenant.ID); err != nil {
+		return apierrors.NewBadRequest(err.Error())
+	}
+
+	if tenant.CreationTimestamp.IsZero() {
+		tenant.CreationTimestamp = metav1.Time{Time: time.Now()}
+	}
+	data, err := k1sstorage.EncodeTenant(tenant)
+	if err != nil {
+		return err
+	}
+
+	result, err := s.db.ExecContext(ctx, `INSERT INTO tenants (id, value) VALUES (?, ?) ON CONFLICT(id) DO NOTHING`,
+		tenant.ID, data)
+	if err != nil {
+		atomic.AddUint64(&s.metrics.errors, 1)
+		return fmt.Errorf("failed to store tenant: %w", err)
+	}
+	if rows, err := result.RowsAffected(); err != nil {
+		return fmt.Errorf("failed to store tenant: %w", err)
+	} else if rows == 0 {
+		return apierrors.NewAlreadyExists(k1sstorage.TenantsResource, tenant.ID)
+	}
+
+	atomic.AddUint64(&s.metrics.operations, 1)
+	return nil
+}
+
+// GetTenant returns a tenant
+func (s *sqliteStorage) GetTenant(ctx context.Context, tenantID string) (*k1sstorage.Tenant, error) {
+	if err := s.ready(ctx); err != nil {
+		return nil, err
+	}
+
+	tenant, err := s.readTenant(ctx, s.db, tenantID)
+	if err != nil || tenant != nil {
+		return tenant, err
+	}
+
+	count, err := s.CountTenant(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	if count > 0 {
+		return &k1sstorage.Tenant{ID: tenantID}, nil
+	}
+	return nil, apierrors.NewNotFound(k1sstorage.TenantsResource, tenantID)
+}
+
+// ListTenants returns all registered tenants and all tenants holding data
+func (s *sqliteStorage) ListTenants(ctx context.Context) ([]k1sstorage.Tenant, error) {
+	if err := s.ready(ctx); err != nil {
+		return nil, err
+	}
+
+	tenants := map[string]k1sstorage.Tenant{}
+
+	// Registered tenants
+	rows, err := s.db.QueryContext(ctx, `SELECT value FROM tenants`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tenants: %w", err)
+	}
+	for rows.Next() {
+		var data []byte
+		if err := rows.Scan(&data); err != nil {
+			s.closeRows(rows)
+			return nil, fmt.Errorf("failed to scan tenant: %w", err)
+		}
+		tenant, err := k1sstorage.DecodeTenant(data)
+		if err != nil {
+			s.closeRows(rows)
+			return nil, err
+		}
+		tenants[tenant.ID] = *tenant
+	}
+	s.closeRows(rows)
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list tenants: %w", err)
+	}
+
+	// Tenants holding data
+	prefix := k1sstorage.TenantsKeyPrefix + "/"
+	rows, err = s.db.QueryContext(ctx, `SELECT key FROM objects WHERE key >= ? AND key < ? AND `+notExpired,
+		prefix, prefixEnd(prefix), time.Now().Unix())
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tenants: %w", err)
+	}
+	for rows.Next() {
+		var key string
+		if err := rows.Scan(&key); err != nil {
+			s.closeRows(rows)
+			return nil, fmt.Errorf("failed to scan key: %w", err)
+		}
+		if tenantID, ok := k1sstorage.TenantIDFromKey(key); ok {
+			if _, seen := tenants[tenantID]; !seen {
+				tenants[tenantID] = k1sstorage.Tenant{ID: tenantID}
+			}
+		}
+	}
+	s.closeRows(rows)
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list tenants: %w", err)
+	}
+
+	result := make([]k1sstorage.Tenant, 0, len(tenants))
+	for _, tenant := range tenants {
+		result = append(result, tenant)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].ID < result[j].ID })
+	return result, nil
+}
+
+// DeleteTenant removes a tenant together with all of its data. Every removed
+// object is recorded in the change-log, so that watchers in other processes
+// observe the deletions.
+func (s *sqliteStorage) DeleteTenant(ctx context.Context, tenantID string) error {
+	if err := s.ready(ctx); err != nil {
+		return err
+	}
+
+	prefix := k1sstorage.TenantKey(tenantID) + "/"
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+
+	deleted, err := s.deleteTenantTx(ctx, tx, tenantID, prefix)
+	if err != nil {
+		s.rollback(tx)
+		s.forgetLocal(deleted)
+		atomic.AddUint64(&s.metrics.errors, 1)
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		s.forgetLocal(deleted)
+		atomic.AddUint64(&s.metrics.errors, 1)
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	for _, c := range deleted {
+		obj, err := k1sstorage.DecodeUnstructured(c.value)
+		if err != nil {
+			log.Printf("Warning: failed to unmarshal object for watch event: %v", err)
+			continue
+		}
+		s.notifyLocal(c.revision, c.key, watch.Deleted, obj)
+	}
+
+	atomic.AddUint64(&s.metrics.operations, 1)
+	return nil
+}
+
+// deleteTenantTx removes the tenant record and objects inside tx and returns
+// the change-log entries recorded for the removed objects
+func (s *sqliteStorage) deleteTenantTx(ctx context.Context, tx *sql.Tx, tenantID, prefix string) ([]change, error) {
+	result, err := tx.ExecContext(ctx, `DELETE FROM tenants WHERE id = ?`, tenantID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to delete tenant: %w", err)
+	}
+	registered, err := result.RowsAffected()
+	if err != nil {
+		return nil, fmt.Errorf("failed to delete tenant: %w", err)
+	}
+
+	rows, err := tx.QueryContext(ctx, `SELECT key, value FROM objects WHERE key >= ? AND key < ?`, prefix, prefixEnd(prefix))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read tenant objects: %w", err)
+	}
+	var deleted []change
+	for rows.Next() {
+		c := change{eventType: watch.Deleted}
+		if err := rows.Scan(&c.key, &c.value); err != nil {
+			s.closeRows(rows)
+			return nil, fmt.Errorf("failed to scan tenant object: %w", err)
+		}
+		deleted = append(deleted, c)
+	}
+	s.closeRows(rows)
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read tenant objects: %w", err)
+	}
+
+	if registered == 0 && len(deleted) == 0 {
+		return nil, apierrors.NewNotFound(k1sstorage.TenantsResource, tenantID)
+	}
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM objects WHERE key >= ? AND key < ?`, prefix, prefixEnd(prefix)); err != nil {
+		return nil, fmt.Errorf("failed to delete tenant objects: %w", err)
+	}
+
+	for i := range deleted {
+		revision, err := s.appendChange(ctx, tx, deleted[i].key, watch.Deleted)
+		if err != nil {
+			return deleted[:i], err
+		}
+		if err := s.setChangeValue(ctx, tx, revision, deleted[i].value); err != nil {
+			return deleted[:i], err
+		}
+		deleted[i].revision = revision
+
+		s.localMu.Lock()
+		s.localRevisions[revision] = struct{}{}
+		s.localMu.Unlock()
+	}
+
+	return deleted, nil
+}
+
+// CountTenant returns the number of objects stored by a tenant
+func (s *sqliteStorage) CountTenant(ctx context.Context, tenantID string) (int64, error) {
+	if err := s.ready(ctx); err != nil {
+		return 0, err
+	}
+
+	return s.countPrefix(ctx, s.db, k1sstorage.TenantKey(tenantID)+"/")
+}
+
+// readTenant returns the registration record of a tenant, or nil if it is not registered
+func (s *sqliteStorage) readTenant(ctx context.Context, q queryer, tenantID string) (*k1sstorage.Tenant, error) {
+	var data []byte
+	if err := q.QueryRowContext(ctx, `SELECT value FROM tenants WHERE id = ?`, tenantID).Scan(&data); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get tenant: %w", err)
+	}
+	return k1sstorage.DecodeTenant(data)
+}
+
+// countPrefix counts the live objects whose keys start with prefix
+func (s *sqliteStorage) countPrefix(ctx context.Context, q queryer, prefix string) (int64, error) {
+	var count int64
+	row := q.QueryRowContext(ctx, `SELECT COUNT(*) FROM objects WHERE key >= ? AND key < ? AND `+notExpired,
+		prefix, prefixEnd(prefix), time.Now().Unix())
+	if err := row.Scan(&count); err != nil {
+		return 0, fmt.Errorf("failed to count objects: %w", err)
+	}
+	return count, nil
+}
+
+// checkQuota enforces the object quota of the configured tenant inside a write transaction
+func (s *sqliteStorage) checkQuota(ctx context.Context, tx *sql.Tx, key string) error {
+	if s.config.TenantID == "" {
+		return nil
+	}
+
+	tenant, err := s.readTenant(ctx, tx, s.config.TenantID)
+	if err != nil || tenant == nil || tenant.MaxObjects <= 0 {
+		return err
+	}
+
+	count, err := s.countPrefix(ctx, tx, k1sstorage.TenantKey(tenant.ID)+"/")
+	if err != nil {
+		return err
+	}
+	return k1sstorage.CheckTenantQuota(tenant, count, key)
+}
+
+// forgetLocal unregisters the revisions of changes that were never committed
+func (s *sqliteStorage) forgetLocal(changes []change) {
+	s.localMu.Lock()
+	defer s.localMu.Unlock()
+	for _, c := range changes {
+		delete(s.localRevisions, c.revision)
+	}
+}
+
+// closeRows closes a result set, logging failures
+func (s *sqliteStorage) closeRows(rows *sql.Rows) {
+	if err := rows.Close(); err != nil {
+		log.Printf("Warning: %s rows: %v", errFailedToClose, err)
+	}
+}
@@ -0,0 +1,81 @@
+package storage
+
+import (
+	"sync"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/watch"
+
+	k1sstorage "github.com/dtomasi/k1s/core/storage"
+)
+
+// queuedWatch is a watcher that queues its events without bound. Send never
+// blocks, so a slow or not yet started consumer cannot stall writers, the
+// change-log poller or Watch itself. A goroutine forwards the queued events
+// to the underlying SimpleWatch in order.
+type queuedWatch struct {
+	*k1sstorage.SimpleWatch
+
+	// mu protects queue
+	mu    sync.Mutex
+	queue []watch.Event
+
+	// wake signals the forwarder that events were queued
+	wake chan struct{}
+
+	// stopped is closed when the watcher is stopped
+	stopped  chan struct{}
+	stopOnce sync.Once
+}
+
+// newQueuedWatch creates a queuedWatch and starts its forwarder
+func newQueuedWatch() *queuedWatch {
+	w := &queuedWatch{
+		SimpleWatch: k1sstorage.NewSimpleWatch(),
+		wake:        make(chan struct{}, 1),
+		stopped:     make(chan struct{}),
+	}
+	go w.forward()
+	return w
+}
+
+// Send queues an event for the consumer
+func (w *queuedWatch) Send(eventType watch.EventType, obj runtime.Object) {
+	w.mu.Lock()
+	w.queue = append(w.queue, watch.Event{Type: eventType, Object: obj})
+	w.mu.Unlock()
+
+	select {
+	case w.wake <- struct{}{}:
+	default:
+	}
+}
+
+// Stop implements watch.Interface
+func (w *queuedWatch) Stop() {
+	w.stopOnce.Do(func() {
+		close(w.stopped)
+		w.SimpleWatch.Stop()
+	})
+}
+
+// forward delivers queued events to the SimpleWatch until the watcher is stopped
+func (w *queuedWatch) forward() {
+	for {
+		select {
+		case <-w.wake:
+		case <-w.stopped:
+			return
+		}
+
+		w.mu.Lock()
+		events := w.queue
+		w.queue = nil
+		w.mu.Unlock()
+
+		for _, event := range events {
+			// Returns without sending once the watcher is stopped
+			w.SimpleWatch.Send(event.Type, event.Object)
+		}
+	}
+}
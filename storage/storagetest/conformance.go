@@ -0,0 +1,617 @@
+package storagetest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2" //nolint:staticcheck // Ginkgo DSL
+	. "github.com/onsi/gomega"    //nolint:staticcheck // Gomega DSL
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/apiserver/pkg/storage"
+
+	k1sstorage "github.com/dtomasi/k1s/core/storage"
+)
+
+const (
+	// prefix is the key prefix under which the conformance specs store objects
+	prefix = "/storagetest.k1s.io/v1/testobjects/"
+
+	// otherPrefix is a sibling prefix used to verify prefix scoping
+	otherPrefix = "/storagetest.k1s.io/v1/otherobjects/"
+
+	// eventTimeout bounds how long the specs wait for a watch event
+	eventTimeout = 10 * time.Second
+
+	// concurrency is the number of goroutines used by the concurrency specs
+	concurrency = 10
+)
+
+// Factory creates the backends used by a single conformance spec. Backends
+// created by the same Factory should share their underlying data where the
+// backend supports it (for example the same database file), so that tenant
+// isolation is exercised against a common store.
+type Factory func(config k1sstorage.Config) k1sstorage.Backend
+
+// Key returns the storage key of the conformance object with the given name
+func Key(name string) string {
+	return prefix + "default/" + name
+}
+
+// DescribeBackend registers the storage.Backend conformance specs with Ginkgo.
+// newFactory is called before every spec so that each spec starts from an
+// empty store; backends created through the factory are closed after the spec.
+//
+// Backend test suites use it at package level:
+//
+//	var _ = storagetest.DescribeBackend("memory", func() storagetest.Factory {
+//		return NewMemoryStorage
+//	})
+func DescribeBackend(name string, newFactory func() Factory) bool {
+	return Describe(name+" backend conformance", func() {
+		var (
+			ctx      context.Context
+			cancel   context.CancelFunc
+			backends []k1sstorage.Backend
+			factory  Factory
+			backend  k1sstorage.Backend
+		)
+
+		newBackend := func(config k1sstorage.Config) k1sstorage.Backend {
+			b := factory(config)
+			Expect(b).NotTo(BeNil())
+			backends = append(backends, b)
+			return b
+		}
+
+		create := func(name, value string) *TestObject {
+			out := &TestObject{}
+			Expect(backend.Create(ctx, Key(name), NewTestObject(name, value), out, 0)).To(Succeed())
+			return out
+		}
+
+		setValue := func(value string) storage.UpdateFunc {
+			return func(input runtime.Object, _ storage.ResponseMeta) (runtime.Object, *uint64, error) {
+				obj := input.(*TestObject)
+				obj.Spec.Value = value
+				return obj, nil, nil
+			}
+		}
+
+		BeforeEach(func() {
+			ctx, cancel = context.WithTimeout(context.Background(), time.Minute)
+			backends = nil
+			factory = newFactory()
+			backend = newBackend(k1sstorage.Config{})
+		})
+
+		AfterEach(func() {
+			for _, b := range backends {
+				Expect(b.Close()).To(Succeed())
+			}
+			cancel()
+		})
+
+		Describe("Create", func() {
+			It("should store the object and fill out", func() {
+				out := create("a", "value-a")
+				Expect(out.Name).To(Equal("a"))
+				Expect(out.Spec.Value).To(Equal("value-a"))
+				Expect(out.ResourceVersion).NotTo(BeEmpty())
+			})
+
+			It("should return AlreadyExists for an existing key", func() {
+				create("a", "value-a")
+
+				err := backend.Create(ctx, Key("a"), NewTestObject("a", "other"), nil, 0)
+				Expect(apierrors.IsAlreadyExists(err)).To(BeTrue(), "unexpected error: %v", err)
+
+				// The original object is unchanged
+				got := &TestObject{}
+				Expect(backend.Get(ctx, Key("a"), storage.GetOptions{}, got)).To(Succeed())
+				Expect(got.Spec.Value).To(Equal("value-a"))
+			})
+
+			It("should assign increasing resource versions", func() {
+				first := create("a", "value-a")
+				second := create("b", "value-b")
+
+				Expect(resourceVersion(backend, second)).To(BeNumerically(">", resourceVersion(backend, first)))
+			})
+
+			It("should fail with a cancelled context", func() {
+				cancelled, cancelFn := context.WithCancel(context.Background())
+				cancelFn()
+
+				Expect(backend.Create(cancelled, Key("a"), NewTestObject("a", "value-a"), nil, 0)).NotTo(Succeed())
+			})
+		})
+
+		Describe("Get", func() {
+			It("should return the stored object", func() {
+				created := create("a", "value-a")
+
+				got := &TestObject{}
+				Expect(backend.Get(ctx, Key("a"), storage.GetOptions{}, got)).To(Succeed())
+				Expect(got.Name).To(Equal("a"))
+				Expect(got.Spec.Value).To(Equal("value-a"))
+				Expect(got.ResourceVersion).To(Equal(created.ResourceVersion))
+			})
+
+			It("should return NotFound for a missing key", func() {
+				err := backend.Get(ctx, Key("missing"), storage.GetOptions{}, &TestObject{})
+				Expect(apierrors.IsNotFound(err)).To(BeTrue(), "unexpected error: %v", err)
+			})
+
+			It("should not fail for a missing key with IgnoreNotFound", func() {
+				Expect(backend.Get(ctx, Key("missing"), storage.GetOptions{IgnoreNotFound: true}, &TestObject{})).To(Succeed())
+			})
+		})
+
+		Describe("List", func() {
+			BeforeEach(func() {
+				create("a", "value-a")
+				create("b", "value-b")
+				create("c", "value-c")
+				Expect(backend.Create(ctx, otherPrefix+"default/x", NewTestObject("x", "value-x"), nil, 0)).To(Succeed())
+			})
+
+			It("should decode matching objects into a typed list", func() {
+				list := &TestObjectList{}
+				Expect(backend.List(ctx, prefix, storage.ListOptions{Recursive: true}, list)).To(Succeed())
+
+				names := make([]string, 0, len(list.Items))
+				var maxVersion uint64
+				for i := range list.Items {
+					names = append(names, list.Items[i].Name)
+					Expect(list.Items[i].Spec.Value).To(Equal("value-" + list.Items[i].Name))
+					if v := resourceVersion(backend, &list.Items[i]); v > maxVersion {
+						maxVersion = v
+					}
+				}
+				Expect(names).To(ConsistOf("a", "b", "c"))
+
+				listVersion, err := backend.Versioner().ParseResourceVersion(list.ResourceVersion)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(listVersion).To(BeNumerically(">=", maxVersion))
+			})
+
+			It("should support generic lists", func() {
+				list := &metav1.List{}
+				Expect(backend.List(ctx, prefix, storage.ListOptions{Recursive: true}, list)).To(Succeed())
+				Expect(list.Items).To(HaveLen(3))
+			})
+
+			It("should only return the exact key when not recursive", func() {
+				list := &TestObjectList{}
+				Expect(backend.List(ctx, Key("a"), storage.ListOptions{}, list)).To(Succeed())
+				Expect(list.Items).To(HaveLen(1))
+				Expect(list.Items[0].Name).To(Equal("a"))
+			})
+
+			It("should return an empty list for an unknown prefix", func() {
+				list := &TestObjectList{}
+				Expect(backend.List(ctx, "/storagetest.k1s.io/v1/unknown/", storage.ListOptions{Recursive: true}, list)).To(Succeed())
+				Expect(list.Items).To(BeEmpty())
+			})
+
+			It("should count objects under a prefix", func() {
+				count, err := backend.Count(ctx, prefix)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(count).To(Equal(int64(3)))
+			})
+		})
+
+		Describe("Delete", func() {
+			It("should remove the object and fill out with its last state", func() {
+				create("a", "value-a")
+
+				out := &TestObject{}
+				Expect(backend.Delete(ctx, Key("a"), out, nil, nil, nil)).To(Succeed())
+				Expect(out.Name).To(Equal("a"))
+				Expect(out.Spec.Value).To(Equal("value-a"))
+
+				err := backend.Get(ctx, Key("a"), storage.GetOptions{}, &TestObject{})
+				Expect(apierrors.IsNotFound(err)).To(BeTrue(), "unexpected error: %v", err)
+			})
+
+			It("should return NotFound for a missing key", func() {
+				err := backend.Delete(ctx, Key("missing"), &TestObject{}, nil, nil, nil)
+				Expect(apierrors.IsNotFound(err)).To(BeTrue(), "unexpected error: %v", err)
+			})
+
+			It("should honour matching preconditions", func() {
+				created := create("a", "value-a")
+
+				uid := created.UID
+				rv := created.ResourceVersion
+				preconditions := &storage.Preconditions{UID: &uid, ResourceVersion: &rv}
+				Expect(backend.Delete(ctx, Key("a"), &TestObject{}, preconditions, nil, nil)).To(Succeed())
+			})
+
+			It("should return Conflict for a mismatched UID", func() {
+				create("a", "value-a")
+
+				uid := types.UID("wrong")
+				err := backend.Delete(ctx, Key("a"), &TestObject{}, &storage.Preconditions{UID: &uid}, nil, nil)
+				Expect(apierrors.IsConflict(err)).To(BeTrue(), "unexpected error: %v", err)
+				Expect(backend.Get(ctx, Key("a"), storage.GetOptions{}, &TestObject{})).To(Succeed())
+			})
+
+			It("should check preconditions against the stored object, not the cached one", func() {
+				create("a", "value-a")
+
+				// A stale cached copy must not satisfy a precondition that the stored object fails
+				stale := NewTestObject("a", "value-a")
+				stale.ResourceVersion = "999999"
+				rv := "999999"
+				err := backend.Delete(ctx, Key("a"), &TestObject{}, &storage.Preconditions{ResourceVersion: &rv}, nil, stale)
+				Expect(apierrors.IsConflict(err)).To(BeTrue(), "unexpected error: %v", err)
+			})
+
+			It("should keep the object when validation fails", func() {
+				create("a", "value-a")
+
+				validationErr := errors.New("deletion rejected")
+				err := backend.Delete(ctx, Key("a"), &TestObject{}, nil,
+					func(_ context.Context, _ runtime.Object) error { return validationErr }, nil)
+				Expect(err).To(MatchError(validationErr))
+				Expect(backend.Get(ctx, Key("a"), storage.GetOptions{}, &TestObject{})).To(Succeed())
+			})
+		})
+
+		Describe("GuaranteedUpdate", func() {
+			It("should apply the update and bump the resource version", func() {
+				created := create("a", "value-a")
+
+				dest := &TestObject{}
+				Expect(backend.GuaranteedUpdate(ctx, Key("a"), dest, false, nil, setValue("updated"), nil)).To(Succeed())
+				Expect(dest.Spec.Value).To(Equal("updated"))
+				Expect(resourceVersion(backend, dest)).To(BeNumerically(">", resourceVersion(backend, created)))
+
+				got := &TestObject{}
+				Expect(backend.Get(ctx, Key("a"), storage.GetOptions{}, got)).To(Succeed())
+				Expect(got.Spec.Value).To(Equal("updated"))
+				Expect(got.ResourceVersion).To(Equal(dest.ResourceVersion))
+			})
+
+			It("should return NotFound for a missing key", func() {
+				err := backend.GuaranteedUpdate(ctx, Key("missing"), &TestObject{}, false, nil, setValue("updated"), nil)
+				Expect(apierrors.IsNotFound(err)).To(BeTrue(), "unexpected error: %v", err)
+			})
+
+			It("should create the object when ignoreNotFound is set", func() {
+				update := func(input runtime.Object, _ storage.ResponseMeta) (runtime.Object, *uint64, error) {
+					obj := NewTestObject("a", "created")
+					return obj, nil, nil
+				}
+				Expect(backend.GuaranteedUpdate(ctx, Key("a"), &TestObject{}, true, nil, update, nil)).To(Succeed())
+
+				got := &TestObject{}
+				Expect(backend.Get(ctx, Key("a"), storage.GetOptions{}, got)).To(Succeed())
+				Expect(got.Spec.Value).To(Equal("created"))
+			})
+
+			It("should return Conflict for a mismatched resource version", func() {
+				create("a", "value-a")
+
+				rv := "999999"
+				err := backend.GuaranteedUpdate(ctx, Key("a"), &TestObject{}, false,
+					&storage.Preconditions{ResourceVersion: &rv}, setValue("updated"), nil)
+				Expect(apierrors.IsConflict(err)).To(BeTrue(), "unexpected error: %v", err)
+			})
+
+			It("should leave the object unchanged when tryUpdate fails", func() {
+				create("a", "value-a")
+
+				updateErr := errors.New("update rejected")
+				err := backend.GuaranteedUpdate(ctx, Key("a"), &TestObject{}, false, nil,
+					func(_ runtime.Object, _ storage.ResponseMeta) (runtime.Object, *uint64, error) {
+						return nil, nil, updateErr
+					}, nil)
+				Expect(err).To(MatchError(updateErr))
+
+				got := &TestObject{}
+				Expect(backend.Get(ctx, Key("a"), storage.GetOptions{}, got)).To(Succeed())
+				Expect(got.Spec.Value).To(Equal("value-a"))
+			})
+		})
+
+		Describe("Watch", func() {
+			It("should deliver events for a prefix in order", func() {
+				w, err := backend.Watch(ctx, prefix, storage.ListOptions{Recursive: true})
+				Expect(err).NotTo(HaveOccurred())
+				defer w.Stop()
+				events := w.ResultChan()
+
+				// Writes outside the watched prefix must not be delivered
+				Expect(backend.Create(ctx, otherPrefix+"default/x", NewTestObject("x", "value-x"), nil, 0)).To(Succeed())
+
+				create("a", "value-a")
+				Expect(backend.GuaranteedUpdate(ctx, Key("a"), &TestObject{}, false, nil,
+					func(input runtime.Object, _ storage.ResponseMeta) (runtime.Object, *uint64, error) {
+						obj := input.(*TestObject)
+						obj.Spec.Counter++
+						return obj, nil, nil
+					}, nil)).To(Succeed())
+				Expect(backend.Delete(ctx, Key("a"), nil, nil, nil, nil)).To(Succeed())
+
+				for _, expected := range []watch.EventType{watch.Added, watch.Modified, watch.Deleted} {
+					var event watch.Event
+					Eventually(events, eventTimeout).Should(Receive(&event))
+					Expect(event.Type).To(Equal(expected))
+					accessor, err := meta.Accessor(event.Object)
+					Expect(err).NotTo(HaveOccurred())
+					Expect(accessor.GetName()).To(Equal("a"))
+				}
+			})
+
+			It("should not share the written objects with watchers", func() {
+				w, err := backend.Watch(ctx, prefix, storage.ListOptions{Recursive: true})
+				Expect(err).NotTo(HaveOccurred())
+				defer w.Stop()
+				events := w.ResultChan()
+
+				obj := NewTestObject("a", "value-a")
+				Expect(backend.Create(ctx, Key("a"), obj, nil, 0)).To(Succeed())
+				// Writers may reuse their object as soon as the write returns
+				obj.Spec.Value = "changed"
+				var updated *TestObject
+				Expect(backend.GuaranteedUpdate(ctx, Key("a"), &TestObject{}, false, nil,
+					func(input runtime.Object, _ storage.ResponseMeta) (runtime.Object, *uint64, error) {
+						updated = input.(*TestObject)
+						updated.Spec.Counter = 1
+						return updated, nil, nil
+					}, nil)).To(Succeed())
+				updated.Spec.Counter = 2
+
+				for _, expected := range []int{0, 1} {
+					var event watch.Event
+					Eventually(events, eventTimeout).Should(Receive(&event))
+					Expect(event.Object).NotTo(BeIdenticalTo(obj))
+					Expect(event.Object).NotTo(BeIdenticalTo(updated))
+					Expect(event.Object.(*TestObject).Spec.Value).To(Equal("value-a"))
+					Expect(event.Object.(*TestObject).Spec.Counter).To(Equal(expected))
+				}
+			})
+
+			It("should send initial events when requested", func() {
+				create("a", "value-a")
+
+				sendInitial := true
+				w, err := backend.Watch(ctx, prefix, storage.ListOptions{Recursive: true, SendInitialEvents: &sendInitial})
+				Expect(err).NotTo(HaveOccurred())
+				defer w.Stop()
+
+				var event watch.Event
+				Eventually(w.ResultChan(), eventTimeout).Should(Receive(&event))
+				Expect(event.Type).To(Equal(watch.Added))
+			})
+		})
+
+		Describe("Tenant isolation", func() {
+			It("should keep tenants apart", func() {
+				tenantA := newBackend(k1sstorage.Config{TenantID: "tenant-a"})
+				tenantB := newBackend(k1sstorage.Config{TenantID: "tenant-b"})
+
+				Expect(tenantA.Create(ctx, Key("a"), NewTestObject("a", "from-a"), nil, 0)).To(Succeed())
+				Expect(tenantB.Create(ctx, Key("a"), NewTestObject("a", "from-b"), nil, 0)).To(Succeed())
+
+				got := &TestObject{}
+				Expect(tenantA.Get(ctx, Key("a"), storage.GetOptions{}, got)).To(Succeed())
+				Expect(got.Spec.Value).To(Equal("from-a"))
+				Expect(tenantB.Get(ctx, Key("a"), storage.GetOptions{}, got)).To(Succeed())
+				Expect(got.Spec.Value).To(Equal("from-b"))
+
+				Expect(tenantA.Delete(ctx, Key("a"), nil, nil, nil, nil)).To(Succeed())
+				Expect(tenantB.Get(ctx, Key("a"), storage.GetOptions{}, got)).To(Succeed())
+
+				list := &TestObjectList{}
+				Expect(tenantA.List(ctx, prefix, storage.ListOptions{Recursive: true}, list)).To(Succeed())
+				Expect(list.Items).To(BeEmpty())
+			})
+		})
+
+		Describe("Tenant management", func() {
+			var (
+				tenant  k1sstorage.Backend
+				manager k1sstorage.TenantManager
+			)
+
+			// All specs use a single backend configured for tenant-a, because
+			// some backends cannot share data between instances. Tenant
+			// management operates on the whole store regardless.
+			BeforeEach(func() {
+				tenant = newBackend(k1sstorage.Config{TenantID: "tenant-a"})
+				var ok bool
+				manager, ok = tenant.(k1sstorage.TenantManager)
+				if !ok {
+					Skip(name + " does not implement TenantManager")
+				}
+			})
+
+			It("should register, list and count tenants", func() {
+				Expect(manager.CreateTenant(ctx, k1sstorage.Tenant{ID: "tenant-b", MaxObjects: 10})).To(Succeed())
+				err := manager.CreateTenant(ctx, k1sstorage.Tenant{ID: "tenant-b"})
+				Expect(apierrors.IsAlreadyExists(err)).To(BeTrue(), "unexpected error: %v", err)
+
+				Expect(tenant.Create(ctx, Key("a"), NewTestObject("a", "value-a"), nil, 0)).To(Succeed())
+				Expect(tenant.Create(ctx, Key("b"), NewTestObject("b", "value-b"), nil, 0)).To(Succeed())
+
+				tenants, err := manager.ListTenants(ctx)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(tenants).To(HaveLen(2))
+				Expect(tenants[0].ID).To(Equal("tenant-a"))
+				Expect(tenants[0].CreationTimestamp.IsZero()).To(BeTrue())
+				Expect(tenants[1].ID).To(Equal("tenant-b"))
+				Expect(tenants[1].MaxObjects).To(Equal(int64(10)))
+				Expect(tenants[1].CreationTimestamp.IsZero()).To(BeFalse())
+
+				count, err := manager.CountTenant(ctx, "tenant-a")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(count).To(Equal(int64(2)))
+				count, err = manager.CountTenant(ctx, "tenant-b")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(count).To(BeZero())
+
+				got, err := manager.GetTenant(ctx, "tenant-b")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(got.MaxObjects).To(Equal(int64(10)))
+				_, err = manager.GetTenant(ctx, "missing")
+				Expect(apierrors.IsNotFound(err)).To(BeTrue(), "unexpected error: %v", err)
+			})
+
+			It("should reject invalid tenant IDs", func() {
+				err := manager.CreateTenant(ctx, k1sstorage.Tenant{})
+				Expect(apierrors.IsBadRequest(err)).To(BeTrue(), "unexpected error: %v", err)
+			})
+
+			It("should keep tenants with separator characters apart", func() {
+				Expect(manager.CreateTenant(ctx, k1sstorage.Tenant{ID: "tenant-a/b"})).To(Succeed())
+				Expect(tenant.Create(ctx, Key("a"), NewTestObject("a", "value-a"), nil, 0)).To(Succeed())
+
+				count, err := manager.CountTenant(ctx, "tenant-a/b")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(count).To(BeZero())
+
+				Expect(manager.DeleteTenant(ctx, "tenant-a/b")).To(Succeed())
+				Expect(tenant.Get(ctx, Key("a"), storage.GetOptions{}, &TestObject{})).To(Succeed())
+			})
+
+			It("should delete a tenant with all of its data", func() {
+				Expect(manager.CreateTenant(ctx, k1sstorage.Tenant{ID: "tenant-a"})).To(Succeed())
+				Expect(tenant.Create(ctx, Key("a"), NewTestObject("a", "value-a"), nil, 0)).To(Succeed())
+				Expect(backend.Create(ctx, Key("a"), NewTestObject("a", "untenanted"), nil, 0)).To(Succeed())
+
+				w, err := tenant.Watch(ctx, prefix, storage.ListOptions{Recursive: true})
+				Expect(err).NotTo(HaveOccurred())
+				defer w.Stop()
+				events := w.ResultChan()
+
+				Expect(manager.DeleteTenant(ctx, "tenant-a")).To(Succeed())
+
+				var event watch.Event
+				Eventually(events, eventTimeout).Should(Receive(&event))
+				Expect(event.Type).To(Equal(watch.Deleted))
+
+				err = tenant.Get(ctx, Key("a"), storage.GetOptions{}, &TestObject{})
+				Expect(apierrors.IsNotFound(err)).To(BeTrue(), "unexpected error: %v", err)
+				_, err = manager.GetTenant(ctx, "tenant-a")
+				Expect(apierrors.IsNotFound(err)).To(BeTrue(), "unexpected error: %v", err)
+
+				err = manager.DeleteTenant(ctx, "tenant-a")
+				Expect(apierrors.IsNotFound(err)).To(BeTrue(), "unexpected error: %v", err)
+			})
+
+			It("should enforce the object quota", func() {
+				Expect(manager.CreateTenant(ctx, k1sstorage.Tenant{ID: "tenant-a", MaxObjects: 2})).To(Succeed())
+				Expect(tenant.Create(ctx, Key("a"), NewTestObject("a", "value-a"), nil, 0)).To(Succeed())
+				Expect(tenant.Create(ctx, Key("b"), NewTestObject("b", "value-b"), nil, 0)).To(Succeed())
+
+				err := tenant.Create(ctx, Key("c"), NewTestObject("c", "value-c"), nil, 0)
+				Expect(apierrors.IsForbidden(err)).To(BeTrue(), "unexpected error: %v", err)
+
+				// Creating through GuaranteedUpdate counts as well, updating does not
+				err = tenant.GuaranteedUpdate(ctx, Key("c"), &TestObject{}, true, nil, setValue("value-c"), nil)
+				Expect(apierrors.IsForbidden(err)).To(BeTrue(), "unexpected error: %v", err)
+				Expect(tenant.GuaranteedUpdate(ctx, Key("a"), &TestObject{}, true, nil, setValue("updated"), nil)).To(Succeed())
+
+				Expect(tenant.Delete(ctx, Key("a"), nil, nil, nil, nil)).To(Succeed())
+				Expect(tenant.Create(ctx, Key("c"), NewTestObject("c", "value-c"), nil, 0)).To(Succeed())
+
+				// A recreated tenant starts from zero objects
+				Expect(manager.DeleteTenant(ctx, "tenant-a")).To(Succeed())
+				Expect(manager.CreateTenant(ctx, k1sstorage.Tenant{ID: "tenant-a", MaxObjects: 1})).To(Succeed())
+				Expect(tenant.GuaranteedUpdate(ctx, Key("a"), &TestObject{}, true, nil, setValue("value-a"), nil)).To(Succeed())
+				err = tenant.Create(ctx, Key("b"), NewTestObject("b", "value-b"), nil, 0)
+				Expect(apierrors.IsForbidden(err)).To(BeTrue(), "unexpected error: %v", err)
+			})
+		})
+
+		Describe("Concurrency", func() {
+			It("should create distinct keys concurrently", func() {
+				var wg sync.WaitGroup
+				for i := 0; i < concurrency; i++ {
+					wg.Add(1)
+					go func(i int) {
+						defer GinkgoRecover()
+						defer wg.Done()
+						name := fmt.Sprintf("obj-%d", i)
+						Expect(backend.Create(ctx, Key(name), NewTestObject(name, name), nil, 0)).To(Succeed())
+					}(i)
+				}
+				wg.Wait()
+
+				count, err := backend.Count(ctx, prefix)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(count).To(Equal(int64(concurrency)))
+			})
+
+			It("should let exactly one concurrent Create of the same key win", func() {
+				var wg sync.WaitGroup
+				var succeeded, conflicted int32
+				for i := 0; i < concurrency; i++ {
+					wg.Add(1)
+					go func() {
+						defer GinkgoRecover()
+						defer wg.Done()
+						err := backend.Create(ctx, Key("a"), NewTestObject("a", "value-a"), nil, 0)
+						switch {
+						case err == nil:
+							atomic.AddInt32(&succeeded, 1)
+						case apierrors.IsAlreadyExists(err):
+							atomic.AddInt32(&conflicted, 1)
+						default:
+							Fail(fmt.Sprintf("unexpected error: %v", err))
+						}
+					}()
+				}
+				wg.Wait()
+
+				Expect(succeeded).To(Equal(int32(1)))
+				Expect(conflicted).To(Equal(int32(concurrency - 1)))
+			})
+
+			It("should not lose concurrent GuaranteedUpdates", func() {
+				create("a", "value-a")
+
+				var wg sync.WaitGroup
+				for i := 0; i < concurrency; i++ {
+					wg.Add(1)
+					go func() {
+						defer GinkgoRecover()
+						defer wg.Done()
+						Expect(backend.GuaranteedUpdate(ctx, Key("a"), &TestObject{}, false, nil,
+							func(input runtime.Object, _ storage.ResponseMeta) (runtime.Object, *uint64, error) {
+								obj := input.(*TestObject)
+								obj.Spec.Counter++
+								return obj, nil, nil
+							}, nil)).To(Succeed())
+					}()
+				}
+				wg.Wait()
+
+				got := &TestObject{}
+				Expect(backend.Get(ctx, Key("a"), storage.GetOptions{}, got)).To(Succeed())
+				Expect(got.Spec.Counter).To(Equal(concurrency))
+			})
+		})
+	})
+}
+
+// resourceVersion returns the numeric resource version of obj
+func resourceVersion(backend k1sstorage.Backend, obj runtime.Object) uint64 {
+	GinkgoHelper()
+	version, err := backend.Versioner().ObjectResourceVersion(obj)
+	Expect(err).NotTo(HaveOccurred())
+	return version
+}
@@ -0,0 +1,75 @@
+package storagetest
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// TestObject is the object stored by the conformance specs
+type TestObject struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec TestSpec `json:"spec,omitempty"`
+}
+
+// TestSpec holds the payload of a TestObject
+type TestSpec struct {
+	Value   string `json:"value,omitempty"`
+	Counter int    `json:"counter,omitempty"`
+}
+
+// DeepCopyObject implements runtime.Object
+func (t *TestObject) DeepCopyObject() runtime.Object {
+	if t == nil {
+		return nil
+	}
+	return &TestObject{
+		TypeMeta:   t.TypeMeta,
+		ObjectMeta: *t.ObjectMeta.DeepCopy(), //nolint:staticcheck // QF1008: embedded field access needed for proper DeepCopy
+		Spec:       t.Spec,
+	}
+}
+
+// TestObjectList is a list of TestObjects
+type TestObjectList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+
+	Items []TestObject `json:"items"`
+}
+
+// DeepCopyObject implements runtime.Object
+func (t *TestObjectList) DeepCopyObject() runtime.Object {
+	if t == nil {
+		return nil
+	}
+	out := &TestObjectList{
+		TypeMeta: t.TypeMeta,
+		ListMeta: *t.ListMeta.DeepCopy(), //nolint:staticcheck // QF1008: embedded field access needed for proper DeepCopy
+	}
+	if t.Items != nil {
+		out.Items = make([]TestObject, len(t.Items))
+		for i := range t.Items {
+			out.Items[i] = *t.Items[i].DeepCopyObject().(*TestObject)
+		}
+	}
+	return out
+}
+
+// NewTestObject returns a TestObject with the given name and value
+func NewTestObject(name, value string) *TestObject {
+	return &TestObject{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "storagetest.k1s.io/v1",
+			Kind:       "TestObject",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: "default",
+			UID:       types.UID("uid-" + name),
+		},
+		Spec: TestSpec{Value: value},
+	}
+}